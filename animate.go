@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// frameDelay is how long each frame of an animation shows.
+const frameDelay = 120 * time.Millisecond
+
+// animated reports whether s draws animations: only on the REPL's
+// terminal, when it can redraw a line, and unless the fast setting, the
+// -fast flag or accessibility mode skips them.
+func (s *session) animated() bool {
+	return s.tty != nil && s.out == s.tty && !s.app.plain && !s.a11y && !s.fast && !s.app.fast
+}
+
+// animate draws frames over each other on one line, leaving the last.
+// Without animations only the last is written, so the output reads the
+// same either way.
+func (s *session) animate(frames ...string) {
+	if len(frames) == 0 {
+		return
+	}
+	if !s.animated() {
+		fmt.Fprintln(s.out, frames[len(frames)-1])
+		return
+	}
+	for i, frame := range frames {
+		fmt.Fprint(s.out, "\r\x1b[K"+frame)
+		if i < len(frames)-1 {
+			time.Sleep(frameDelay)
+		}
+	}
+	fmt.Fprintln(s.out)
+}
+
+// throwFrames has the ball flying towards the pokemon before line.
+func throwFrames(line string) []string {
+	prefix := strings.TrimSuffix(line, "...")
+	frames := make([]string, 0, 7)
+	for i := range 6 {
+		frames = append(frames, prefix+" "+strings.Repeat("-", i)+"o")
+	}
+	return append(frames, line)
+}
+
+// shakeFrames rocks the ball from side to side before line.
+func shakeFrames(line string) []string {
+	return []string{"  (o)", " (o) ", "  (o)", "   (o)", "  (o)", line}
+}
+
+// outcomeFrames has the ball click shut, or burst open, before line.
+func outcomeFrames(caught bool, line string) []string {
+	if caught {
+		return []string{"  (o)", "  (o) click!", "  * (o) *", line}
+	}
+	return []string{"  (o)", "  (O)", "  \\(  )/ pop!", line}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnimate(t *testing.T) {
+	s := newTestSession(t)
+	s.app.configPath = filepath.Join(t.TempDir(), "config.json")
+	out := &bytes.Buffer{}
+	s.out = out
+
+	s.animate("(o)", "done")
+	if got := out.String(); got != "done\n" {
+		t.Errorf("Expected only the last frame away from the terminal, got %q", got)
+	}
+
+	s.tty = out
+	out.Reset()
+	s.animate("(o)", "done")
+	if got := out.String(); got != "\r\x1b[K(o)\r\x1b[Kdone\n" {
+		t.Errorf("Expected the frames drawn over each other, got %q", got)
+	}
+
+	if err := s.run("config fast on", out); err != nil {
+		t.Fatalf("config returned error: %v", err)
+	}
+	s.tty, s.out = out, out
+	out.Reset()
+	s.animate("(o)", "done")
+	if got := out.String(); got != "done\n" {
+		t.Errorf("Expected the fast setting to skip animations, got %q", got)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// maxAutoTurns ends an auto battle that drags on, as if the player gave
+// up.
+const maxAutoTurns = 100
+
+// autoPotions are the potions an auto battle drinks, cheapest first.
+var autoPotions = []string{"potion", "super-potion", "hyper-potion", "max-potion"}
+
+// autoAction picks the action of the player's pokemon in slot: a potion
+// from the bag once it's down to a quarter of its HP, and otherwise the
+// move that does the most damage.
+func autoAction(s *session, b *battle.Battle, slot int) battle.Action {
+	p := b.Sides[0].InSlot(slot)
+	if p.Fainted() {
+		return battle.Action{}
+	}
+	if p.HP*4 < p.Stats.HP {
+		for _, item := range autoPotions {
+			if s.profile.Use(item) {
+				return battle.Action{Kind: battle.UseItem, Item: ballName(item), Member: b.Sides[0].Active[slot], Heal: medicines[item].heal(p.Stats.HP)}
+			}
+		}
+	}
+	return battle.Gimmicks(b, 0, slot, battle.Greedy(b, 0, slot))
+}
+
+// autoPlay fights a battle through without asking the player anything,
+// returning it once it's over or has run for maxAutoTurns turns.
+func autoPlay(s *session, player, opponent *battle.Side, format battle.Format, ai battle.AI, onEnd func(s *session, b *battle.Battle) error) *activeBattle {
+	b := &activeBattle{
+		Battle: battle.New(player, opponent, format, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))),
+		ai:     ai,
+		onEnd:  onEnd,
+	}
+	b.Rules = s.rules[format]
+	for !b.Over() && b.Turn < maxAutoTurns {
+		var actions [2][]battle.Action
+		for side := range b.Sides {
+			for slot := range b.Sides[side].Active {
+				if side == 0 {
+					actions[0] = append(actions[0], autoAction(s, b.Battle, slot))
+				} else {
+					actions[1] = append(actions[1], battle.Gimmicks(b.Battle, 1, slot, ai(b.Battle, 1, slot)))
+				}
+			}
+		}
+		b.Play(actions)
+	}
+	return b
+}
+
+// autoReport sums up what auto battles did to the player's pokemon and
+// bag.
+type autoReport struct {
+	before map[int]profile.Pokemon
+	order  []int
+	bag    map[string]int
+}
+
+func newAutoReport(s *session, team []*profile.Pokemon) *autoReport {
+	r := &autoReport{before: map[int]profile.Pokemon{}, bag: map[string]int{}}
+	for _, p := range team {
+		r.before[p.ID] = *p
+		r.order = append(r.order, p.ID)
+	}
+	for item := range medicines {
+		r.bag[item] = s.profile.Inventory[item]
+	}
+	return r
+}
+
+func (r *autoReport) print(s *session) {
+	for _, id := range r.order {
+		p, was := s.profile.Get(id), r.before[id]
+		if p == nil {
+			continue
+		}
+		exp := p.Exp - max(was.Exp, expForLevel(growthRate(s, p), was.Level))
+		line := fmt.Sprintf("  %s: +%d exp", p.Species, max(exp, 0))
+		if p.Level > was.Level {
+			line += fmt.Sprintf(" (Lv. %d → %d)", was.Level, p.Level)
+		}
+		switch lost := p.Damage - was.Damage; {
+		case lost > 0:
+			line += fmt.Sprintf(", lost %d HP", lost)
+		case lost < 0:
+			line += fmt.Sprintf(", recovered %d HP", -lost)
+		}
+		if hp, _, err := hpOf(s, p); err == nil && hp == 0 {
+			line += ", fainted"
+		}
+		fmt.Fprintln(s.out, line)
+	}
+	var used []string
+	for _, item := range slices.Concat(autoPotions, []string{"revive", "max-revive"}) {
+		if n := r.bag[item] - s.profile.Inventory[item]; n > 0 {
+			used = append(used, fmt.Sprintf("%d %s", n, ballName(item)))
+		}
+	}
+	fmt.Fprintf(s.out, "  Items used: %s\n", listOrNone(used))
+}
+
+// autoResult describes how an auto battle ended for the player.
+func autoResult(b *battle.Battle) string {
+	switch b.Winner {
+	case 0:
+		return "won"
+	case 1:
+		return "lost"
+	}
+	return "called off"
+}
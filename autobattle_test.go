@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestAutoBattle(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Party = []int{s.profile.Pokemon[0].ID}
+	if err := meetPokemon(s, pokeapi.PokemonType{Name: "magikarp"}, 5); err != nil {
+		t.Fatalf("meetPokemon returned error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("battle --auto", out); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if s.battle != nil {
+		t.Errorf("Expected the auto battle to be over")
+	}
+	if !strings.Contains(out.String(), "Auto battle against the wild magikarp: won after") {
+		t.Errorf("Expected pikachu to win, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "  pikachu: +") || !strings.Contains(out.String(), "  Items used: none") {
+		t.Errorf("Expected a summary of the battle, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "What will pikachu do?") {
+		t.Errorf("Expected the auto battle not to ask for moves, got %q", out.String())
+	}
+}
+
+func TestAutoBattleDrinksPotions(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Give("potion", 1)
+
+	player := &battle.Side{Team: []*battle.Pokemon{battler(s.profile.Pokedex["pikachu"], 50)}}
+	player.Team[0].HP = 5
+	opponent := &battle.Side{Team: []*battle.Pokemon{battler(pokeapi.PokemonType{Name: "magikarp"}, 5)}}
+	b := battle.New(player, opponent, battle.Single, nil)
+	action := autoAction(s, b, 0)
+	if action.Kind != battle.UseItem || action.Item != "potion" {
+		t.Errorf("Expected a potion at low HP, got %+v", action)
+	}
+	if s.profile.Inventory["potion"] != 0 {
+		t.Errorf("Expected the potion to be used up, got %d", s.profile.Inventory["potion"])
+	}
+	if action := autoAction(s, b, 0); action.Kind == battle.UseItem {
+		t.Errorf("Expected a move once out of potions, got %+v", action)
+	}
+}
+
+func TestAutoTrain(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Location = "rock-tunnel-1f"
+	if err := s.run("train pikachu --stat attack --auto 0", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected training for no battles to fail")
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("train pikachu --stat attack --auto 3", out); err != nil {
+		t.Fatalf("train returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pikachu won 3 of 3 battles against wild machop, gaining 3 attack EVs (3/252)") {
+		t.Errorf("Expected pikachu to beat machop three times, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "  Items used:") {
+		t.Errorf("Expected a summary of the training, got %q", out.String())
+	}
+}
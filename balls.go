@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// masterBallBonus marks a ball that never fails.
+const masterBallBonus = 255
+
+// ballBonuses multiply a pokemon's catch rate, by PokeAPI item name.
+var ballBonuses = map[string]float64{
+	"poke-ball":    1,
+	"premier-ball": 1,
+	"great-ball":   1.5,
+	"ultra-ball":   2,
+	"master-ball":  masterBallBonus,
+}
+
+// ballOrder is the order balls are picked in when the player doesn't say.
+// Master Balls are never thrown unless asked for.
+var ballOrder = []string{"poke-ball", "premier-ball", "great-ball", "ultra-ball"}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "bag",
+		description: "Show the items in your bag",
+		callback:    commandBag,
+	})
+}
+
+// parseBall accepts "great-ball", "greatball" or just "great".
+func parseBall(arg string) (string, bool) {
+	name := strings.TrimSuffix(strings.TrimSuffix(arg, "-ball"), "ball") + "-ball"
+	_, ok := ballBonuses[name]
+	return name, ok
+}
+
+func ballName(item string) string {
+	return strings.ReplaceAll(item, "-", " ")
+}
+
+// chooseBall picks the ball named in args, or the most basic one in the bag.
+func chooseBall(s *session, args ...string) (string, error) {
+	if len(args) > 0 {
+		ball, ok := parseBall(args[0])
+		if !ok {
+			return "", fmt.Errorf("%s isn't a ball", args[0])
+		}
+		if s.profile.Inventory[ball] == 0 {
+			return "", fmt.Errorf("you don't have any %ss", ballName(ball))
+		}
+		return ball, nil
+	}
+	for _, ball := range ballOrder {
+		if s.profile.Inventory[ball] > 0 {
+			return ball, nil
+		}
+	}
+	return "", errors.New("you're out of balls")
+}
+
+func completeBalls(s *session, args []string) []string {
+	balls := []string{}
+	for item, n := range s.profile.Inventory {
+		if _, ok := ballBonuses[item]; ok && n > 0 {
+			balls = append(balls, item)
+		}
+	}
+	return balls
+}
+
+func commandBag(s *session, args ...string) error {
+	fmt.Fprintf(s.out, "Money: %d Pokédollars\n", s.profile.Money)
+	if len(s.profile.Inventory) == 0 {
+		fmt.Fprintln(s.out, "Your bag is empty")
+		return nil
+	}
+	items := make([]string, 0, len(s.profile.Inventory))
+	for item := range s.profile.Inventory {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	fmt.Fprintln(s.out, "Your bag:")
+	for _, item := range items {
+		name := item
+		if _, ok := ballBonuses[item]; ok {
+			name = iconPrefix(s.icons().ball, item)
+		}
+		fmt.Fprintf(s.out, " - %s x%d\n", name, s.profile.Inventory[item])
+	}
+	return nil
+}
@@ -0,0 +1,632 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/battlelog"
+	"github.com/azs06/pokedexcli/internal/link"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// battleCommands are the only commands accepted during a battle.
+var battleCommands = map[string]bool{
+	"fight":   true,
+	"switch":  true,
+	"forfeit": true,
+	"use":     true,
+	"party":   true,
+	"help":    true,
+	"exit":    true,
+}
+
+// Key items that let one pokemon a battle mega evolve or dynamax.
+const (
+	megaRing    = "mega-ring"
+	dynamaxBand = "dynamax-band"
+)
+
+// battleFormats are the formats by name, as in the config file.
+var battleFormats = map[string]battle.Format{
+	"single": battle.Single,
+	"double": battle.Double,
+}
+
+// defaultRules allow mega evolution and dynamaxing in every format; the
+// config file can turn them off.
+var defaultRules = map[battle.Format]battle.Rules{
+	battle.Single: {Mega: true, Dynamax: true},
+	battle.Double: {Mega: true, Dynamax: true},
+}
+
+// activeBattle is a battle in progress, with ai picking the opponent's
+// actions. onEnd runs once it is over; a battle the player gave up has no
+// winner.
+type activeBattle struct {
+	*battle.Battle
+	ai    battle.AI
+	onEnd func(s *session, b *battle.Battle) error
+	// friendly battles give no experience and leave the player's pokemon
+	// as they were.
+	friendly bool
+	// seat is the player's side. It's only 1 for a player who joined a
+	// link battle, which runs with the host's side first.
+	seat int
+	// link is the connection to the other player in a link battle, who
+	// picks the actions of the other side in place of ai.
+	link *link.Conn
+	// spectators are watching a link battle the player is hosting.
+	spectators *link.Listener
+	// pending has the actions picked so far this turn, one per slot. The
+	// turn is played once every slot has one.
+	pending []battle.Action
+}
+
+// mine is the player's side, and foes the other one.
+func (b *activeBattle) mine() *battle.Side {
+	return b.Sides[b.seat]
+}
+
+func (b *activeBattle) foes() *battle.Side {
+	return b.Sides[1-b.seat]
+}
+
+// choosing is the player's pokemon that picks the next action.
+func (b *activeBattle) choosing() *battle.Pokemon {
+	return b.mine().InSlot(len(b.pending))
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "fight",
+		usage:       "fight [move] [target] [--mega|--dynamax]",
+		description: "Use a move, or list your pokemon's moves",
+		maxArgs:     3,
+		callback:    commandFight,
+		complete: func(s *session, args []string) []string {
+			if s.battle == nil || len(args) > 0 {
+				return nil
+			}
+			names := []string{}
+			for _, m := range s.battle.choosing().Moves {
+				names = append(names, m.Name)
+			}
+			return names
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "switch",
+		usage:       "switch <party slot>",
+		description: "Send out another pokemon from your party",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandSwitch,
+	})
+	registerCommand(cliCommand{
+		name:        "battle",
+		usage:       "battle [host [addr] | connect <addr> | watch <addr>] [--difficulty <level>] [--double] [--auto | --vs <profile>]",
+		description: "Fight the wild pokemon to weaken it, or another player",
+		maxArgs:     5,
+		callback:    commandBattle,
+		complete: func(s *session, args []string) []string {
+			if len(args) > 0 && args[len(args)-1] == "--vs" {
+				return completeRivals(s)
+			}
+			if len(args) > 0 && args[0] == "host" {
+				return []string{"--double"}
+			}
+			if len(args) > 0 && (args[0] == "connect" || args[0] == "watch") {
+				return nil
+			}
+			options := completeBattleArgs(s, args)
+			if options[0] == "--difficulty" {
+				options = append(options, "--auto", "--vs")
+			}
+			if len(args) == 0 {
+				options = append(options, "host", "connect", "watch")
+			}
+			return options
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "forfeit",
+		description: "Give up the battle",
+		callback:    commandForfeit,
+	})
+}
+
+// battler turns species data into a battler at full health.
+func battler(p pokeapi.PokemonType, level int) *battle.Pokemon {
+	types := typeNames(p)
+	stats := trainedStats(p, level, nil, nil)
+	return &battle.Pokemon{
+		Name:           p.Name,
+		BaseExperience: p.BaseExperience,
+		EffortYield:    effortYield(p),
+		Level:          level,
+		Types:          types,
+		Ability:        abilityName(p),
+		Stats:          stats,
+		HP:             stats.HP,
+		Moves:          battle.DefaultMoves(types, level),
+	}
+}
+
+// abilityName is the pokemon's first regular ability; hidden abilities
+// aren't handed out.
+func abilityName(p pokeapi.PokemonType) string {
+	name, slot := "", 0
+	for _, a := range p.Abilities {
+		if !a.IsHidden && (name == "" || a.Slot < slot) {
+			name, slot = a.Ability.Name, a.Slot
+		}
+	}
+	return name
+}
+
+// playerSide is the player's party, ready for battle.
+func playerSide(s *session) (*battle.Side, error) {
+	side, err := partySide(s, s.profile)
+	if err != nil {
+		return nil, err
+	}
+	if len(side.Team) > 0 && side.Defeated() {
+		return nil, errors.New("all your pokemon have fainted; heal them at a Pokémon Center in a town or city")
+	}
+	return side, nil
+}
+
+// partySide is owner's party, ready for battle.
+func partySide(s *session, owner *profile.Profile) (*battle.Side, error) {
+	side := &battle.Side{
+		Name:        owner.Name,
+		MegaRing:    owner.Inventory[megaRing] > 0,
+		DynamaxBand: owner.Inventory[dynamaxBand] > 0,
+	}
+	for _, p := range owner.PartyPokemon() {
+		member, err := ownBattler(s, owner, p)
+		if err != nil {
+			return nil, err
+		}
+		side.Team = append(side.Team, member)
+	}
+	return side, nil
+}
+
+// ownBattler is one of owner's pokemon, ready for battle with the HP it has
+// left.
+func ownBattler(s *session, owner *profile.Profile, p *profile.Pokemon) (*battle.Pokemon, error) {
+	species := owner.Pokedex[p.Species]
+	nature, err := natureOf(s, p.Nature)
+	if err != nil {
+		return nil, err
+	}
+	member := battler(species, p.Level)
+	member.ID, member.Item = p.ID, p.Item
+	member.Stats = ownStats(species, p, nature)
+	member.HP = max(member.Stats.HP-p.Damage, 0)
+	if len(p.Moves) > 0 {
+		member.Moves = slices.Clone(p.Moves)
+	}
+	if member.Mega, err = megaForm(s, species, p, nature); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// megaForm is the form mon, a p, can mega evolve into by holding its item,
+// or nil if the item isn't p's mega stone. Its IVs, EVs and nature carry
+// over.
+func megaForm(s *session, p pokeapi.PokemonType, mon *profile.Pokemon, nature pokeapi.NatureDetail) (*battle.Form, error) {
+	name, ok := battle.MegaStones[mon.Item]
+	species := p.Species.Name
+	if species == "" {
+		species = p.Name
+	}
+	if !ok || !strings.HasPrefix(name, species+"-mega") {
+		return nil, nil
+	}
+	mega, err := s.source.Pokemon(name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := battler(mega, mon.Level)
+	return &battle.Form{Name: m.Name, Types: m.Types, Ability: m.Ability, Stats: ownStats(mega, mon, nature)}, nil
+}
+
+// parseBattleArgs reads the "--difficulty <level>" and "--double" options,
+// falling back to the difficulty def and single battles.
+func parseBattleArgs(args []string, def string) (battle.AI, battle.Format, error) {
+	level, format := def, battle.Single
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--double":
+			format = battle.Double
+		case args[i] == "--difficulty" && i+1 < len(args):
+			i++
+			level = args[i]
+		default:
+			return nil, 0, errors.New("expected --difficulty <level> or --double")
+		}
+	}
+	ai, ok := battle.ParseDifficulty(level)
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown difficulty %q; pick one of %s", level, strings.Join(difficultyNames(), ", "))
+	}
+	return ai, format, nil
+}
+
+func difficultyNames() []string {
+	names := []string{}
+	for _, d := range battle.Difficulties {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func completeBattleArgs(s *session, args []string) []string {
+	if len(args) > 0 && args[len(args)-1] == "--difficulty" {
+		return difficultyNames()
+	}
+	return []string{"--difficulty", "--double"}
+}
+
+func startBattle(s *session, player, opponent *battle.Side, format battle.Format, ai battle.AI, onEnd func(s *session, b *battle.Battle) error) {
+	b := &activeBattle{
+		Battle: battle.New(player, opponent, format, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))),
+		ai:     ai,
+		onEnd:  onEnd,
+	}
+	b.Rules = s.rules[format]
+	openBattle(s, b)
+}
+
+// openBattle makes b the battle being fought and asks for the first
+// actions.
+func openBattle(s *session, b *activeBattle) {
+	s.battle = b
+	for _, e := range s.battle.Log {
+		fmt.Fprintln(s.out, e)
+	}
+	askAction(s)
+}
+
+// printHUD shows the field and the pokemon on it.
+func printHUD(s *session) {
+	b := s.battle
+	var field []string
+	if b.Weather != battle.Clear {
+		field = append(field, fmt.Sprintf("%s (%d turns left)", b.Weather, b.WeatherTurns))
+	}
+	if b.Terrain != battle.NoTerrain {
+		field = append(field, fmt.Sprintf("%s terrain (%d turns left)", b.Terrain, b.TerrainTurns))
+	}
+	if len(field) > 0 {
+		fmt.Fprintf(s.out, "-- %s --\n", strings.Join(field, ", "))
+	}
+	var status []string
+	for i, side := range b.Sides {
+		for slot := range side.Active {
+			p := side.InSlot(slot)
+			owner := "Your"
+			if i != b.seat {
+				owner = "Foe"
+			}
+			text := fmt.Sprintf("%s %s: %d/%d HP", owner, p.Name, p.HP, p.Stats.HP)
+			if p.Dynamaxed() {
+				text += fmt.Sprintf(" (dynamaxed, %d turns left)", p.DynamaxTurns())
+			}
+			status = append(status, text)
+		}
+	}
+	fmt.Fprintln(s.out, strings.Join(status, " | "))
+}
+
+// askAction prompts for the next action, skipping slots whose pokemon
+// fainted with nobody left to replace it.
+func askAction(s *session) {
+	b := s.battle
+	if len(b.pending) == 0 {
+		printHUD(s)
+	}
+	for len(b.pending) < len(b.mine().Active) && b.choosing().Fainted() {
+		b.pending = append(b.pending, battle.Action{})
+	}
+	switch {
+	case len(b.pending) == len(b.mine().Active):
+		return
+	case len(b.mine().Active) == 1:
+		fmt.Fprintln(s.out, "What will you do? fight, switch, use or forfeit")
+	default:
+		fmt.Fprintf(s.out, "What will %s do? fight, switch, use or forfeit\n", b.choosing().Name)
+	}
+}
+
+// chooseAction sets the action of the pokemon whose turn it is to choose,
+// and plays the turn once every slot has one.
+func chooseAction(s *session, action battle.Action) error {
+	b := s.battle
+	b.pending = append(b.pending, action)
+	if len(b.pending) < len(b.mine().Active) {
+		askAction(s)
+		if len(b.pending) < len(b.mine().Active) {
+			return nil
+		}
+	}
+
+	var actions [2][]battle.Action
+	actions[b.seat] = b.pending
+	b.pending = nil
+	if b.link != nil {
+		foe, err := linkTurn(s, b, actions[b.seat])
+		if err != nil || b.Over() {
+			return errors.Join(err, finishBattle(s, b, false))
+		}
+		actions[1-b.seat] = foe
+	} else {
+		actions[1] = make([]battle.Action, len(b.Sides[1].Active))
+		for slot := range actions[1] {
+			actions[1][slot] = battle.Gimmicks(b.Battle, 1, slot, b.ai(b.Battle, 1, slot))
+		}
+	}
+	entries := b.Play(actions)
+	for _, e := range entries {
+		fmt.Fprintln(s.out, e)
+	}
+	broadcast(b, entries)
+	if !b.Over() {
+		askAction(s)
+		return nil
+	}
+	return finishBattle(s, b, false)
+}
+
+// finishBattle hands out the experience of a battle that's over, or that
+// the player gave up, and keeps what it did to the player's pokemon.
+func finishBattle(s *session, b *activeBattle, forfeit bool) error {
+	s.battle = nil
+	if b.link != nil {
+		b.link.Close()
+	}
+	if b.spectators != nil {
+		b.spectators.Close()
+	}
+	if !b.friendly {
+		awardExperience(s, b.Battle)
+		keepHP(s, b.Battle)
+	}
+	return errors.Join(recordBattle(s, b, forfeit), b.onEnd(s, b.Battle))
+}
+
+// parseTarget reads which pokemon a move is aimed at in a double battle:
+// an opposing slot number or name, or "ally".
+func parseTarget(b *activeBattle, arg string) (battle.Action, error) {
+	if arg == "ally" {
+		return battle.Action{Ally: true}, nil
+	}
+	foes := b.foes()
+	for slot := range foes.Active {
+		if arg == strconv.Itoa(slot+1) || arg == foes.InSlot(slot).Name {
+			return battle.Action{Target: slot}, nil
+		}
+	}
+	return battle.Action{}, fmt.Errorf("there's no %s to aim at", arg)
+}
+
+func commandFight(s *session, args ...string) error {
+	if s.battle == nil {
+		return errors.New("you aren't in a battle")
+	}
+	p, slot := s.battle.choosing(), len(s.battle.pending)
+	if len(args) == 0 {
+		fmt.Fprintf(s.out, "%s (%d/%d HP) knows:\n", p.Name, p.HP, p.Stats.HP)
+		for i, m := range p.Moves {
+			if p.Dynamaxed() {
+				m = battle.MaxMove(m)
+			}
+			if m.Class == battle.Status {
+				fmt.Fprintf(s.out, "%d. %s (%s, status)\n", i+1, m.Name, m.Type)
+				continue
+			}
+			fmt.Fprintf(s.out, "%d. %s (%s, power %d)\n", i+1, m.Name, m.Type, m.Power)
+		}
+		if foes := s.battle.foes(); len(foes.Active) > 1 {
+			fmt.Fprintf(s.out, "Targets: 1. %s, 2. %s, or ally\n", foes.InSlot(0).Name, foes.InSlot(1).Name)
+		}
+		if s.battle.CanMega(s.battle.seat, slot) {
+			fmt.Fprintf(s.out, "%s can mega evolve: add --mega\n", p.Name)
+		} else if s.battle.CanDynamax(s.battle.seat, slot) {
+			fmt.Fprintf(s.out, "%s can dynamax: add --dynamax\n", p.Name)
+		}
+		return nil
+	}
+
+	mega, dynamax := slices.Contains(args, "--mega"), slices.Contains(args, "--dynamax")
+	args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == "--mega" || arg == "--dynamax" })
+	if err := checkGimmick(s.battle, slot, mega, dynamax); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("which move? fight lists them")
+	}
+	action := battle.Action{}
+	if len(args) > 1 {
+		var err error
+		if action, err = parseTarget(s.battle, args[1]); err != nil {
+			return err
+		}
+	}
+	action.Mega, action.Dynamax = mega, dynamax
+	for i, m := range p.Moves {
+		if args[0] == m.Name || args[0] == strconv.Itoa(i+1) {
+			action.Kind, action.Move = battle.Fight, i
+			return chooseAction(s, action)
+		}
+	}
+	return fmt.Errorf("%s doesn't know %s", p.Name, args[0])
+}
+
+// checkGimmick makes sure the pokemon in slot can mega evolve or dynamax
+// this turn, if asked to, and that no other one already is.
+func checkGimmick(b *activeBattle, slot int, mega, dynamax bool) error {
+	p := b.mine().InSlot(slot)
+	switch {
+	case mega && dynamax:
+		return errors.New("a pokemon can't mega evolve and dynamax at once")
+	case mega && slices.ContainsFunc(b.pending, func(a battle.Action) bool { return a.Mega }),
+		dynamax && slices.ContainsFunc(b.pending, func(a battle.Action) bool { return a.Dynamax }):
+		return errors.New("only one pokemon a battle can do that")
+	case mega && !b.CanMega(b.seat, slot):
+		return fmt.Errorf("%s can't mega evolve: it needs its mega stone, you need a mega ring, and it only works once a battle", p.Name)
+	case dynamax && !b.CanDynamax(b.seat, slot):
+		return fmt.Errorf("%s can't dynamax: you need a dynamax band, it only works once a battle, and pokemon holding a mega stone can't", p.Name)
+	}
+	return nil
+}
+
+func commandSwitch(s *session, args ...string) error {
+	if s.battle == nil {
+		return errors.New("you aren't in a battle")
+	}
+	side := s.battle.mine()
+	slot, err := strconv.Atoi(args[0])
+	if err != nil || slot < 1 || slot > len(side.Team) {
+		return fmt.Errorf("pick a party slot from 1 to %d", len(side.Team))
+	}
+	switching := func(a battle.Action) bool { return a.Kind == battle.Switch && a.Switch == slot-1 }
+	switch p := side.Team[slot-1]; {
+	case p.Fainted():
+		return fmt.Errorf("%s has fainted", p.Name)
+	case slices.Contains(side.Active, slot-1):
+		return fmt.Errorf("%s is already out", p.Name)
+	case slices.ContainsFunc(s.battle.pending, switching):
+		return fmt.Errorf("%s is already coming in", p.Name)
+	}
+	return chooseAction(s, battle.Action{Kind: battle.Switch, Switch: slot - 1})
+}
+
+func commandForfeit(s *session, args ...string) error {
+	if s.battle == nil {
+		return errors.New("you aren't in a battle")
+	}
+	fmt.Fprintln(s.out, "You gave up the battle.")
+	if s.battle.link != nil {
+		// The other player reads this in place of this turn's actions. If
+		// they've already gone there's no one left to tell.
+		s.battle.link.Send(link.Message{Kind: link.Forfeit})
+		s.battle.Winner = 1 - s.battle.seat
+	}
+	return finishBattle(s, s.battle, true)
+}
+
+// recordBattle saves a finished battle so it can be replayed.
+func recordBattle(s *session, b *activeBattle, forfeit bool) error {
+	if s.battles == nil {
+		return nil
+	}
+	opponent := b.foes().Name
+	if opponent == "" {
+		opponent = "wild " + b.foes().Team[0].Name
+	}
+	rec := &battlelog.Record{
+		Time:     s.now(),
+		Player:   s.profile.Name,
+		Opponent: opponent,
+		Won:      b.Winner == b.seat,
+		Forfeit:  forfeit,
+		Turns:    b.Turn,
+		Log:      b.Log,
+	}
+	if err := s.battles.Save(rec); err != nil {
+		return fmt.Errorf("failed to record the battle: %w", err)
+	}
+	return nil
+}
+
+// commandBattle fights the wild pokemon in front of the player. Wild
+// pokemon fight randomly unless a harder difficulty is asked for. With
+// --auto the battle plays itself out and only the result is shown; with
+// --vs the opponent is another saved profile's party instead; host and
+// connect battle another player's game over the network, and watch looks on
+// at one.
+func commandBattle(s *session, args ...string) error {
+	if len(args) > 0 && slices.Contains([]string{"host", "connect", "watch"}, args[0]) {
+		return linkBattle(s, args[0], args[1:])
+	}
+	if i := slices.Index(args, "--vs"); i >= 0 {
+		if i+1 == len(args) {
+			return errors.New("usage: battle --vs <profile>")
+		}
+		return rivalBattle(s, args[i+1], slices.Delete(slices.Clone(args), i, i+2))
+	}
+	if s.encounter == nil {
+		return errors.New("there's nothing to battle")
+	}
+	if len(s.profile.Party) == 0 {
+		return errors.New("you don't have any pokemon to battle with")
+	}
+	auto := slices.Contains(args, "--auto")
+	args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == "--auto" })
+	ai, format, err := parseBattleArgs(args, "easy")
+	if err != nil {
+		return err
+	}
+	if s.encounter.roamer {
+		fmt.Fprintf(s.out, "%s won't stay to fight!\n", s.encounter.species.Name)
+		flee(s)
+		return nil
+	}
+	nature, err := natureOf(s, s.encounter.nature)
+	if err != nil {
+		return err
+	}
+	wild := battler(s.encounter.species, s.encounter.level)
+	wild.HP = s.encounter.hp
+	applyNature(&wild.Stats, nature)
+	opponent := &battle.Side{Team: []*battle.Pokemon{wild}}
+	if format == battle.Double {
+		// In a double battle another one of its kind joins in.
+		opponent.Team = append(opponent.Team, battler(s.encounter.species, s.encounter.level))
+	}
+	player, err := playerSide(s)
+	if err != nil {
+		return err
+	}
+	if !auto {
+		startBattle(s, player, opponent, format, ai, wildBattleResult)
+		return nil
+	}
+	report := newAutoReport(s, s.profile.PartyPokemon())
+	b := autoPlay(s, player, opponent, format, ai, wildBattleResult)
+	fmt.Fprintf(s.out, "Auto battle against the wild %s: %s after %d turns\n", wild.Name, autoResult(b.Battle), b.Turn)
+	err = finishBattle(s, b, !b.Over())
+	report.print(s)
+	return err
+}
+
+// wildBattleResult ends the encounter unless the player stopped fighting,
+// in which case the wild pokemon stays, as weak as the battle left it.
+func wildBattleResult(s *session, b *battle.Battle) error {
+	wild := b.Sides[1].Team[0]
+	switch b.Winner {
+	case 0:
+		fmt.Fprintf(s.out, "The wild %s fainted!\n", wild.Name)
+		s.encounter = nil
+	case 1:
+		fmt.Fprintln(s.out, "You're out of usable pokemon! You hurried back to safety.")
+		s.encounter = nil
+	default:
+		s.encounter.hp = wild.HP
+		fmt.Fprintf(s.out, "The wild %s (%d/%d HP) is still in front of you.\n", wild.Name, wild.HP, wild.Stats.HP)
+	}
+	return nil
+}
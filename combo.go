@@ -0,0 +1,108 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// maxIV is the highest, perfect, IV.
+const maxIV = 31
+
+// defaultShinyOdds is the one-in-n chance of a wild pokemon being shiny,
+// unless the settings change it.
+const defaultShinyOdds = 4096
+
+// shinyOdds is the one-in-n chance of a wild pokemon being shiny, base
+// without a combo, which gets better the longer the player's catch combo of
+// its species, as in Let's Go.
+func shinyOdds(base, combo int) int {
+	switch {
+	case combo > 30:
+		return max(base/7, 1)
+	case combo > 20:
+		return max(base/5, 1)
+	case combo > 10:
+		return max(base/3, 1)
+	}
+	return base
+}
+
+// perfectIVs is how many of its stats a pokemon caught during a combo is
+// sure to have a perfect IV in.
+func perfectIVs(combo int) int {
+	switch {
+	case combo > 30:
+		return 4
+	case combo > 20:
+		return 3
+	case combo > 10:
+		return 2
+	case combo > 5:
+		return 1
+	}
+	return 0
+}
+
+// comboFor is the player's catch combo of species, or 0 if the combo is of
+// another one.
+func comboFor(s *session, species string) int {
+	if s.profile.ComboSpecies != species {
+		return 0
+	}
+	return s.profile.Combo
+}
+
+// rollIVs picks random IVs, with perfect of the stats, picked at random,
+// maxed out.
+func rollIVs(perfect int) map[string]int {
+	ivs := map[string]int{}
+	for _, stat := range statNames {
+		ivs[stat] = rand.IntN(maxIV + 1)
+	}
+	for _, i := range rand.Perm(len(statNames))[:perfect] {
+		ivs[statNames[i]] = maxIV
+	}
+	return ivs
+}
+
+// continueCombo counts a catch of species towards the combo, starting a new
+// one if it's of another species.
+func continueCombo(s *session, species string) {
+	if s.profile.ComboSpecies != species {
+		s.profile.ComboSpecies, s.profile.Combo = species, 0
+	}
+	s.profile.Combo++
+	if s.profile.Combo > 1 {
+		fmt.Fprintf(s.out, "Catch combo: %s x%d\n", species, s.profile.Combo)
+	}
+}
+
+// breakCombo ends the catch combo, as a pokemon fleeing does.
+func breakCombo(s *session) {
+	if s.profile.Combo > 1 {
+		fmt.Fprintf(s.out, "Your %s catch combo of %d is over\n", s.profile.ComboSpecies, s.profile.Combo)
+	}
+	s.profile.ComboSpecies, s.profile.Combo = "", 0
+}
+
+// shinyMark is shown after a shiny pokemon's name.
+func shinyMark(s *session, shiny bool) string {
+	if shiny && s.a11y {
+		return " (shiny)"
+	}
+	if shiny {
+		return s.paint(s.theme.Highlight, " "+cmp.Or(s.icons().shiny, "★"))
+	}
+	return ""
+}
+
+// ivsText lists IVs in stat order, e.g. "hp 31, attack 12, ...".
+func ivsText(ivs map[string]int) string {
+	parts := []string{}
+	for _, stat := range statNames {
+		parts = append(parts, fmt.Sprintf("%s %d", stat, ivs[stat]))
+	}
+	return strings.Join(parts, ", ")
+}
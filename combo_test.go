@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCatchCombo(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 4)
+	for range 3 {
+		if err := s.run("catch pidgey master", &bytes.Buffer{}); err != nil {
+			t.Fatalf("catch returned error: %v", err)
+		}
+	}
+	if s.profile.ComboSpecies != "pidgey" || s.profile.Combo != 3 {
+		t.Errorf("Expected a pidgey combo of 3, got %s x%d", s.profile.ComboSpecies, s.profile.Combo)
+	}
+	if got := s.prompt(); got != "Pokedex (pidgey combo x3) > " {
+		t.Errorf("Expected the prompt to show the combo, got %q", got)
+	}
+	for _, p := range s.profile.Pokemon {
+		if len(p.IVs) != len(statNames) {
+			t.Errorf("Expected every caught pokemon to have IVs, got %v", p.IVs)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("catch rattata master", out); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	if s.profile.ComboSpecies != "rattata" || s.profile.Combo != 1 {
+		t.Errorf("Expected catching another species to start a new combo, got %s x%d", s.profile.ComboSpecies, s.profile.Combo)
+	}
+	if strings.Contains(out.String(), "Catch combo") {
+		t.Errorf("Expected a combo of one not to be announced, got %q", out.String())
+	}
+
+	breakCombo(s)
+	if s.profile.Combo != 0 || s.profile.ComboSpecies != "" {
+		t.Errorf("Expected the combo to be broken")
+	}
+}
+
+func TestComboOdds(t *testing.T) {
+	if shinyOdds(4096, 0) != 4096 || shinyOdds(4096, 11) != 1365 || shinyOdds(4096, 31) != 585 {
+		t.Errorf("Expected shiny odds to improve with the combo")
+	}
+	ivs := rollIVs(perfectIVs(31))
+	perfect := 0
+	for _, iv := range ivs {
+		if iv == maxIV {
+			perfect++
+		}
+	}
+	if perfect < 4 {
+		t.Errorf("Expected at least 4 perfect IVs after a combo of 31, got %v", ivs)
+	}
+}
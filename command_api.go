@@ -0,0 +1,39 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "api",
+		usage:       "api status",
+		description: "Check that the configured PokeAPI answers, and how fast",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandAPI,
+		complete: func(s *session, args []string) []string {
+			return []string{"status"}
+		},
+	})
+}
+
+func commandAPI(s *session, args ...string) error {
+	if args[0] != "status" {
+		return errors.New("usage: api status")
+	}
+	s.app.mu.Lock()
+	url := cmp.Or(s.app.config.API, pokeapi.DefaultUrl)
+	s.app.mu.Unlock()
+	st, err := pokeapi.CheckStatus(url)
+	if err != nil {
+		return fmt.Errorf("%s is down: %w", url, err)
+	}
+	fmt.Fprintf(s.out, "%s is up: API %s, %d resources, %d pokemon, answered in %s\n", st.URL, st.Version, st.Resources, st.Pokemon, st.Latency.Round(time.Millisecond))
+	return nil
+}
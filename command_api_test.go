@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAPI answers like PokeAPI's root and pokemon list.
+func fakeAPI(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/":
+			w.Write([]byte(`{"pokemon": "/api/v2/pokemon/", "move": "/api/v2/move/"}`))
+		case "/api/v2/pokemon":
+			w.Write([]byte(`{"count": 1302, "results": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAPIStatus(t *testing.T) {
+	s := newTestSession(t)
+	server := fakeAPI(t)
+	s.app.config.API = server.URL + "/api/v2/"
+	out := &bytes.Buffer{}
+	if err := s.run("api status", out); err != nil {
+		t.Fatalf("api status returned error: %v", err)
+	}
+	if want := "is up: API v2, 2 resources, 1302 pokemon, answered in "; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	s.app.config.API = server.URL + "/elsewhere/"
+	if err := s.run("api status", out); err == nil || !strings.Contains(err.Error(), "is down") {
+		t.Errorf("Expected the API reported down, got %v", err)
+	}
+}
+
+func TestConfigAPIChecksURL(t *testing.T) {
+	s := newTestSession(t)
+	s.app.configPath = filepath.Join(t.TempDir(), "config.json")
+	server := fakeAPI(t)
+	out := &bytes.Buffer{}
+	if err := s.run("config api ftp://example.com", out); err == nil {
+		t.Errorf("Expected an error for a URL that isn't http")
+	}
+	if err := s.run("config api "+server.URL+"/nothing/", out); err == nil {
+		t.Errorf("Expected an error for a URL that isn't PokeAPI")
+	}
+	if err := s.run("config --profile api "+server.URL+"/api/v2/", out); err == nil {
+		t.Errorf("Expected an error setting api for one profile")
+	}
+	out.Reset()
+	if err := s.run("config api "+server.URL+"/api/v2/", out); err != nil {
+		t.Fatalf("config api returned error: %v", err)
+	}
+	if want := "api = " + server.URL + "/api/v2/ (config)\n"; !strings.HasPrefix(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if s.app.config.API != server.URL+"/api/v2/" {
+		t.Errorf("Expected the URL kept, got %q", s.app.config.API)
+	}
+}
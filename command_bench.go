@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// benchRuns is how many of each resource bench fetches unless told, and
+// maxBenchRuns the most it will.
+const (
+	benchRuns    = 10
+	maxBenchRuns = 200
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "bench",
+		usage:       "bench [n]",
+		description: "Time fetching n pokemon, species and areas from the API, then from the cache",
+		maxArgs:     1,
+		callback:    commandBench,
+	})
+}
+
+// benchFetch is one of the requests bench times.
+type benchFetch struct {
+	resource string
+	fetch    func(s pokeapi.DataSource, name string) error
+}
+
+// benchFetches are what the game fetches most: pokemon met and inspected,
+// their species, and the areas explored.
+var benchFetches = []benchFetch{
+	{"pokemon", func(s pokeapi.DataSource, name string) error { _, err := s.Pokemon(name); return err }},
+	{"pokemon-species", func(s pokeapi.DataSource, name string) error { _, err := s.Species(name); return err }},
+	{"location-area", func(s pokeapi.DataSource, name string) error { _, err := s.LocationArea(name); return err }},
+}
+
+func commandBench(s *session, args ...string) error {
+	n := benchRuns
+	if len(args) == 1 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 || n > maxBenchRuns {
+			return fmt.Errorf("n is a number from 1 to %d", maxBenchRuns)
+		}
+	}
+	source, ok := s.app.source.(pokeapi.Forgetter)
+	if !ok {
+		return errors.New("this data source keeps no cache to compare against")
+	}
+
+	// The first pass forgets each resource before fetching it, so it goes to
+	// the network; the second finds all of them cached.
+	var cold, cached []time.Duration
+	for _, pass := range []struct {
+		times  *[]time.Duration
+		forget bool
+	}{{&cold, true}, {&cached, false}} {
+		for id := 1; id <= n; id++ {
+			name := strconv.Itoa(id)
+			for _, f := range benchFetches {
+				if pass.forget {
+					source.Forget(f.resource, name)
+				}
+				start := time.Now()
+				if err := f.fetch(s.app.source, name); err != nil {
+					return fmt.Errorf("%s %s: %w", f.resource, name, err)
+				}
+				*pass.times = append(*pass.times, time.Since(start))
+			}
+		}
+	}
+	fmt.Fprintf(s.out, "%d fetches each of pokemon, species and location areas:\n", n)
+	benchLine(s, "API", cold)
+	benchLine(s, "Cached", cached)
+	if c, w := percentile(cold, 0.5), percentile(cached, 0.5); w > 0 {
+		fmt.Fprintf(s.out, "The cache answers %.0fx faster at the median.\n", float64(c)/float64(w))
+	}
+	return nil
+}
+
+func benchLine(s *session, name string, times []time.Duration) {
+	var total time.Duration
+	for _, t := range times {
+		total += t
+	}
+	rate := math.Inf(1)
+	if total > 0 {
+		rate = float64(len(times)) / total.Seconds()
+	}
+	fmt.Fprintf(s.out, "  %-7s p50 %s, p95 %s, %.1f fetches/s\n", name+":", benchDuration(percentile(times, 0.5)), benchDuration(percentile(times, 0.95)), rate)
+}
+
+// percentile is the duration q of times are no slower than.
+func percentile(times []time.Duration, q float64) time.Duration {
+	if len(times) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	return sorted[max(int(math.Ceil(q*float64(len(sorted))))-1, 0)]
+}
+
+// benchDuration rounds d to a few significant digits.
+func benchDuration(d time.Duration) string {
+	switch {
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	case d >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond).String()
+	}
+	return d.String()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// benchSource is slow to fetch what it was told to forget, as if it went to
+// the network.
+type benchSource struct {
+	fakeSource
+	forgotten map[string]bool
+}
+
+func (b benchSource) Forget(resource, name string) {
+	b.forgotten[resource+"/"+name] = true
+}
+
+func (b benchSource) Pokemon(name string) (pokeapi.PokemonType, error) {
+	if b.forgotten["pokemon/"+name] {
+		delete(b.forgotten, "pokemon/"+name)
+		time.Sleep(time.Millisecond)
+	}
+	return b.fakeSource.Pokemon(name)
+}
+
+func TestBench(t *testing.T) {
+	s := newTestSession(t)
+	source := benchSource{forgotten: map[string]bool{}}
+	s.app.source = source
+	out := &bytes.Buffer{}
+	if err := s.run("bench 3", out); err != nil {
+		t.Fatalf("bench returned error: %v", err)
+	}
+	for _, want := range []string{"3 fetches each", "  API:    p50 ", "  Cached: p50 ", "faster at the median"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q, got %q", want, out.String())
+		}
+	}
+	if len(source.forgotten) != 6 {
+		t.Errorf("Expected species and areas forgotten too, got %v", source.forgotten)
+	}
+
+	s.app.source = fakeSource{}
+	if err := s.run("bench", out); err == nil {
+		t.Errorf("Expected an error from a source without a cache")
+	}
+	if err := s.run("bench 0", out); err == nil {
+		t.Errorf("Expected an error for 0 fetches")
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const (
+	// maxPlots is how many berries the garden has room for at once.
+	maxPlots = 4
+	// A berry is ripe growCommands commands or growTime after it's
+	// planted, whichever comes first.
+	growCommands = 30
+	growTime     = 4 * time.Hour
+	// maxFriendship is as friendly as a pokemon gets.
+	maxFriendship = 255
+	// berryEVs is how many EVs an EV-lowering berry takes off.
+	berryEVs = 10
+)
+
+// berries are the berries the player can grow, by PokeAPI item name. The
+// ones that lower EVs name the stat; feeding any other just makes a pokemon
+// a little friendlier.
+var berries = map[string]string{
+	"cheri-berry":  "",
+	"oran-berry":   "",
+	"razz-berry":   "",
+	"pomeg-berry":  "hp",
+	"kelpsy-berry": "attack",
+	"qualot-berry": "defense",
+	"hondew-berry": "special-attack",
+	"grepa-berry":  "special-defense",
+	"tamato-berry": "speed",
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "plant",
+		usage:       "plant <berry>",
+		description: "Plant a berry from your bag in your garden",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandPlant,
+		complete:    completeBerries,
+	})
+	registerCommand(cliCommand{
+		name:        "garden",
+		description: "Show the berries growing in your garden",
+		callback:    commandGarden,
+	})
+	registerCommand(cliCommand{
+		name:        "harvest",
+		description: "Pick the ripe berries in your garden",
+		callback:    commandHarvest,
+	})
+	registerCommand(cliCommand{
+		name:        "feed",
+		usage:       "feed <pokemon> <berry>",
+		description: "Feed a pokemon a berry, lowering its EVs, making it friendlier and raising its condition",
+		minArgs:     2,
+		maxArgs:     2,
+		callback:    commandFeed,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return completeCaught(s, args)
+			}
+			return completeBerries(s, args)
+		},
+	})
+}
+
+func completeBerries(s *session, args []string) []string {
+	return slices.Sorted(maps.Keys(berries))
+}
+
+// parseBerry accepts "cheri" or "cheri-berry", checking there's one in the
+// bag.
+func parseBerry(s *session, arg string) (string, error) {
+	berry := strings.TrimSuffix(arg, "-berry") + "-berry"
+	if _, ok := berries[berry]; !ok {
+		return "", fmt.Errorf("%s isn't a berry", arg)
+	}
+	if s.profile.Inventory[berry] == 0 {
+		return "", fmt.Errorf("you don't have any %ss", ballName(berry))
+	}
+	return berry, nil
+}
+
+// ripeIn is how many commands and how long until plot is ripe; it's ripe
+// when either is zero.
+func ripeIn(s *session, plot profile.Plot) (int, time.Duration) {
+	commands := max(growCommands-(s.profile.Commands-plot.Command), 0)
+	wait := max(growTime-s.now().Sub(plot.Time), 0)
+	return commands, wait
+}
+
+func ripe(s *session, plot profile.Plot) bool {
+	commands, wait := ripeIn(s, plot)
+	return commands == 0 || wait == 0
+}
+
+func commandPlant(s *session, args ...string) error {
+	berry, err := parseBerry(s, args[0])
+	if err != nil {
+		return err
+	}
+	if len(s.profile.Plots) >= maxPlots {
+		return errors.New("your garden is full; harvest something first")
+	}
+	s.profile.Use(berry)
+	s.profile.Plots = append(s.profile.Plots, profile.Plot{Berry: berry, Command: s.profile.Commands, Time: s.now()})
+	fmt.Fprintf(s.out, "You planted a %s. It'll be ripe in %d commands or %s.\n", ballName(berry), growCommands, growTime)
+	return nil
+}
+
+func commandGarden(s *session, args ...string) error {
+	if len(s.profile.Plots) == 0 {
+		fmt.Fprintln(s.out, "Nothing is growing in your garden. Plant a berry with plant <berry>.")
+		return nil
+	}
+	fmt.Fprintf(s.out, "Your garden (%d/%d plots):\n", len(s.profile.Plots), maxPlots)
+	for i, plot := range s.profile.Plots {
+		if ripe(s, plot) {
+			fmt.Fprintf(s.out, "%d. %s: ripe\n", i+1, ballName(plot.Berry))
+			continue
+		}
+		commands, wait := ripeIn(s, plot)
+		fmt.Fprintf(s.out, "%d. %s: ripe in %d commands or %s\n", i+1, ballName(plot.Berry), commands, wait.Round(time.Minute))
+	}
+	return nil
+}
+
+func commandHarvest(s *session, args ...string) error {
+	growing := []profile.Plot{}
+	harvested := false
+	for _, plot := range s.profile.Plots {
+		if !ripe(s, plot) {
+			growing = append(growing, plot)
+			continue
+		}
+		n := 2 + rand.IntN(4)
+		s.profile.Give(plot.Berry, n)
+		fmt.Fprintf(s.out, "You harvested %d %ss\n", n, ballName(plot.Berry))
+		harvested = true
+	}
+	s.profile.Plots = growing
+	if !harvested {
+		fmt.Fprintln(s.out, "Nothing is ripe yet")
+	}
+	return nil
+}
+
+// commandFeed feeds a pokemon a berry. The EV-lowering berries take 10 EVs
+// off their stat and make it much friendlier; the others make it a little
+// friendlier. Every berry raises the contest conditions of its flavors.
+func commandFeed(s *session, args ...string) error {
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	berry, err := parseBerry(s, args[1])
+	if err != nil {
+		return err
+	}
+	stat, friendship := berries[berry], 2
+	if stat != "" {
+		friendship = 10
+	}
+	gains, err := conditionGains(s, p, berry)
+	if err != nil {
+		return err
+	}
+	if p.Friendship >= maxFriendship && (stat == "" || p.EVs[stat] == 0) && len(gains) == 0 {
+		return fmt.Errorf("it won't have any effect on %s", p.Species)
+	}
+	s.profile.Use(berry)
+	fmt.Fprintf(s.out, "%s ate the %s!\n", p.Species, ballName(berry))
+	raiseCondition(s, p, gains)
+	if lost := min(p.EVs[stat], berryEVs); lost > 0 {
+		p.EVs[stat] -= lost
+		fmt.Fprintf(s.out, "%s lost %d %s EVs (%d/%d)\n", p.Species, lost, stat, p.EVs[stat], maxStatEVs)
+	}
+	p.Friendship = min(p.Friendship+friendship, maxFriendship)
+	fmt.Fprintf(s.out, "%s's friendship is %d/%d\n", p.Species, p.Friendship, maxFriendship)
+	return nil
+}
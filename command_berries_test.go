@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestGarden(t *testing.T) {
+	s := newTestSession(t)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+	if err := s.run("plant cheri", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected planting a berry not in the bag to fail")
+	}
+	s.profile.Give("cheri-berry", 1)
+	s.profile.Give("oran-berry", 1)
+	if err := s.run("plant cheri", &bytes.Buffer{}); err != nil {
+		t.Fatalf("plant returned error: %v", err)
+	}
+	if s.profile.Inventory["cheri-berry"] != 0 {
+		t.Errorf("Expected planting to use the berry up, got %d left", s.profile.Inventory["cheri-berry"])
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("harvest", out); err != nil {
+		t.Fatalf("harvest returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing is ripe yet") {
+		t.Errorf("Expected nothing to be ripe, got %q", out.String())
+	}
+
+	// A berry ripens after enough commands...
+	s.profile.Commands += growCommands
+	if err := s.run("plant oran-berry", &bytes.Buffer{}); err != nil {
+		t.Fatalf("plant returned error: %v", err)
+	}
+	out.Reset()
+	if err := s.run("garden", out); err != nil {
+		t.Fatalf("garden returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1. cheri berry: ripe\n") || !strings.Contains(out.String(), "2. oran berry: ripe in 29 commands or 4h0m0s\n") {
+		t.Errorf("Expected the cheri berry to be ripe and the oran berry growing, got %q", out.String())
+	}
+	if err := s.run("harvest", &bytes.Buffer{}); err != nil {
+		t.Fatalf("harvest returned error: %v", err)
+	}
+	if n := s.profile.Inventory["cheri-berry"]; n < 2 || n > 5 {
+		t.Errorf("Expected 2 to 5 cheri berries, got %d", n)
+	}
+	if len(s.profile.Plots) != 1 {
+		t.Fatalf("Expected the oran berry to keep growing, got %d plots", len(s.profile.Plots))
+	}
+
+	// ...or after enough time.
+	s.now = func() time.Time { return start.Add(growTime) }
+	if err := s.run("harvest", &bytes.Buffer{}); err != nil {
+		t.Fatalf("harvest returned error: %v", err)
+	}
+	if len(s.profile.Plots) != 0 || s.profile.Inventory["oran-berry"] < 2 {
+		t.Errorf("Expected the oran berry to be harvested, got %+v", s.profile.Plots)
+	}
+}
+
+func TestFeed(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	p := &s.profile.Pokemon[0]
+	p.EVs = map[string]int{"attack": 14}
+	s.profile.Give("kelpsy-berry", 2)
+	for range 2 {
+		if err := s.run("feed pikachu kelpsy", &bytes.Buffer{}); err != nil {
+			t.Fatalf("feed returned error: %v", err)
+		}
+	}
+	if p.EVs["attack"] != 0 || p.Friendship != 20 {
+		t.Errorf("Expected 0 attack EVs and 20 friendship, got %d and %d", p.EVs["attack"], p.Friendship)
+	}
+
+	p.Friendship = maxFriendship
+	p.Condition = map[string]int{"cool": maxCondition, "beauty": maxCondition}
+	s.profile.Give("razz-berry", 1)
+	if err := s.run("feed pikachu razz", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected feeding a berry that does nothing to fail")
+	}
+	if s.profile.Inventory["razz-berry"] != 1 {
+		t.Errorf("Expected the berry not to be used up")
+	}
+}
+
+func TestBaitWithBerry(t *testing.T) {
+	s := newTestSession(t)
+	if err := meetPokemon(s, pokeapi.PokemonType{Name: "pidgey"}, 5); err != nil {
+		t.Fatalf("meetPokemon returned error: %v", err)
+	}
+	s.profile.Give("razz-berry", 1)
+	out := &bytes.Buffer{}
+	if err := s.run("bait razz", out); err != nil {
+		t.Fatalf("bait returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pidgey is busy eating the razz berry!") {
+		t.Errorf("Expected pidgey to eat the berry, got %q", out.String())
+	}
+	if s.encounter.baited != maxBait || s.profile.Inventory["razz-berry"] != 0 {
+		t.Errorf("Expected the berry to be eaten and count as full bait, got %d", s.encounter.baited)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const (
+	// dittoGroup is Ditto's own egg group; it breeds with anything that can
+	// breed at all.
+	dittoGroup = "ditto"
+	// noEggsGroup is the Undiscovered group of legendaries and babies, which
+	// can't breed.
+	noEggsGroup = "no-eggs"
+	genderless  = -1
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "egg-group",
+		usage:       "egg-group <name>",
+		description: "List the pokemon in an egg group",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandEggGroup,
+		paged:       true,
+	})
+	registerCommand(cliCommand{
+		name:        "compatible",
+		usage:       "compatible <pokemon> <pokemon>",
+		description: "Check whether two pokemon can breed",
+		minArgs:     2,
+		maxArgs:     2,
+		callback:    commandCompatible,
+		complete:    completeCaught,
+	})
+}
+
+func commandEggGroup(s *session, args ...string) error {
+	group, err := s.source.EggGroup(args[0])
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("there's no egg group called %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s egg group (%d pokemon):\n", group.Name, len(group.PokemonSpecies))
+	for _, species := range group.PokemonSpecies {
+		fmt.Fprintf(s.out, "  - %s\n", species.Name)
+	}
+	return nil
+}
+
+func eggGroupNames(species pokeapi.PokemonSpecies) []string {
+	names := []string{}
+	for _, group := range species.EggGroups {
+		names = append(names, group.Name)
+	}
+	return names
+}
+
+// canBeMale and canBeFemale read a species' gender rate.
+func canBeMale(species pokeapi.PokemonSpecies) bool {
+	return species.GenderRate != genderless && species.GenderRate < 8
+}
+
+func canBeFemale(species pokeapi.PokemonSpecies) bool {
+	return species.GenderRate > 0
+}
+
+// breedable reports whether a pokemon of species a and one of species b
+// could produce an egg, and why. Neither may be in the Undiscovered group;
+// Ditto breeds with anything else, but not with another Ditto; otherwise
+// they need an egg group in common and to be able to be a male and a female.
+func breedable(a, b pokeapi.PokemonSpecies) (bool, string) {
+	groupsA, groupsB := eggGroupNames(a), eggGroupNames(b)
+	for _, species := range []pokeapi.PokemonSpecies{a, b} {
+		if len(species.EggGroups) == 0 || slices.Contains(eggGroupNames(species), noEggsGroup) {
+			return false, species.Name + " can't breed at all"
+		}
+	}
+	dittoA, dittoB := slices.Contains(groupsA, dittoGroup), slices.Contains(groupsB, dittoGroup)
+	switch {
+	case dittoA && dittoB:
+		return false, "two ditto can't breed with each other"
+	case dittoA || dittoB:
+		return true, "ditto breeds with anything"
+	}
+	if a.GenderRate == genderless || b.GenderRate == genderless {
+		return false, "genderless pokemon only breed with ditto"
+	}
+	if !(canBeMale(a) && canBeFemale(b)) && !(canBeFemale(a) && canBeMale(b)) {
+		return false, "they can't be a male and a female"
+	}
+	for _, group := range groupsA {
+		if slices.Contains(groupsB, group) {
+			return true, "they share the " + group + " egg group"
+		}
+	}
+	return false, fmt.Sprintf("they share no egg group (%s vs %s)", strings.Join(groupsA, ", "), strings.Join(groupsB, ", "))
+}
+
+// speciesOf looks up the species of a pokemon, which may be named after a
+// form.
+func speciesOf(s *session, name string) (pokeapi.PokemonSpecies, error) {
+	species, err := s.source.Species(name)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return species, err
+	}
+	p, err := s.source.Pokemon(name)
+	if errors.Is(err, pokeapi.ErrNotFound) || (err == nil && p.Species.Name == "") {
+		return pokeapi.PokemonSpecies{}, fmt.Errorf("there's no pokemon called %s", name)
+	}
+	if err != nil {
+		return pokeapi.PokemonSpecies{}, err
+	}
+	return s.source.Species(p.Species.Name)
+}
+
+func commandCompatible(s *session, args ...string) error {
+	a, err := speciesOf(s, args[0])
+	if err != nil {
+		return err
+	}
+	b, err := speciesOf(s, args[1])
+	if err != nil {
+		return err
+	}
+	ok, reason := breedable(a, b)
+	if ok {
+		fmt.Fprintf(s.out, "%s and %s can breed: %s.\n", args[0], args[1], reason)
+	} else {
+		fmt.Fprintf(s.out, "%s and %s can't breed: %s.\n", args[0], args[1], reason)
+	}
+	return nil
+}
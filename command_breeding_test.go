@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEggGroup(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("egg-group field", out); err != nil {
+		t.Fatalf("egg-group returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "field egg group (3 pokemon):\n  - pikachu\n") {
+		t.Errorf("Expected the field group's members, got %q", out.String())
+	}
+	if err := s.run("egg-group nonsense", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown egg group to fail")
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"pikachu", "eevee", true},
+		{"tauros", "miltank", true},
+		{"tauros", "tauros", false},
+		{"pikachu", "pidgey", false},
+		{"magnemite", "ditto", true},
+		{"magnemite", "magnemite", false},
+		{"mewtwo", "ditto", false},
+		{"ditto", "ditto", false},
+	}
+	s := newTestSession(t)
+	for _, c := range cases {
+		out := &bytes.Buffer{}
+		if err := s.run("compatible "+c.a+" "+c.b, out); err != nil {
+			t.Fatalf("compatible returned error: %v", err)
+		}
+		if got := strings.Contains(out.String(), " can breed"); got != c.want {
+			t.Errorf("Expected %s and %s compatible = %v, got %q", c.a, c.b, c.want, out.String())
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/filecache"
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+// defaultCacheMB is how many megabytes of API responses are kept in memory
+// unless the config says otherwise.
+const defaultCacheMB = 64
+
+// compressAbove is the size from which responses are compressed, when the
+// config asks; smaller ones barely shrink.
+const compressAbove = 4 << 10
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "cache",
+		usage:       "cache <stats|clear [namespace]>",
+		description: "Show how much the game keeps cached, or clear one namespace of it, or all",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandCache,
+		complete: func(s *session, args []string) []string {
+			switch {
+			case len(args) == 0:
+				return []string{"stats", "clear"}
+			case args[0] == "clear":
+				return namespaceNames()
+			}
+			return nil
+		},
+	})
+}
+
+func commandCache(s *session, args ...string) error {
+	switch {
+	case args[0] == "stats" && len(args) == 1:
+		return cacheStats(s)
+	case args[0] == "clear":
+		return cacheClear(s, args[1:]...)
+	}
+	return errors.New("usage: cache <stats|clear [namespace]>")
+}
+
+// namespaceNames are pokecache's namespaces, as the player types them.
+func namespaceNames() []string {
+	names := []string{}
+	for _, ns := range pokecache.Namespaces {
+		names = append(names, string(ns))
+	}
+	return names
+}
+
+// diskCache is where downloads of ns are kept on disk, if anywhere.
+func diskCache(a *app, ns pokecache.Namespace) *filecache.Cache {
+	switch ns {
+	case pokecache.Sprites:
+		return a.sprites
+	case pokecache.Audio:
+		return a.cries
+	}
+	return nil
+}
+
+func cacheStats(s *session) error {
+	a := s.app
+	if a.cache == nil {
+		fmt.Fprintln(s.out, "In memory: not cached")
+	} else {
+		st := a.cache.Stats()
+		limit := "no limit"
+		if st.Limit > 0 {
+			limit = "limit " + byteSize(st.Limit)
+		}
+		counts := []string{}
+		for _, ns := range pokecache.Namespaces {
+			if n := st.ByNamespace[ns]; n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", n, ns))
+			}
+		}
+		fmt.Fprintf(s.out, "In memory: %s, %s for %s of data (%s)\n", listOrNone(counts), byteSize(st.Bytes), byteSize(st.Size), limit)
+		fmt.Fprintf(s.out, "  %d hits, %d misses, %d evicted\n", st.Hits, st.Misses, st.Evictions)
+	}
+	for _, disk := range []struct {
+		name  string
+		cache *filecache.Cache
+	}{{"Sprites", a.sprites}, {"Cries", a.cries}} {
+		if disk.cache == nil {
+			continue
+		}
+		files, size, err := disk.cache.Usage()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%s on disk: %d files, %s\n", disk.name, files, byteSize(size))
+	}
+	return nil
+}
+
+// cacheClear drops one namespace, in memory and on disk, or all of them
+// when none is named.
+func cacheClear(s *session, namespace ...string) error {
+	clearing := pokecache.Namespaces
+	if len(namespace) > 0 {
+		ns := pokecache.Namespace(namespace[0])
+		if !slices.Contains(pokecache.Namespaces, ns) {
+			return fmt.Errorf("the cache's namespaces are %s", strings.Join(namespaceNames(), ", "))
+		}
+		clearing = []pokecache.Namespace{ns}
+	}
+	a := s.app
+	for _, ns := range clearing {
+		entries, files := 0, 0
+		if a.cache != nil {
+			entries = a.cache.Invalidate(string(ns) + ":")
+		}
+		if disk := diskCache(a, ns); disk != nil {
+			var err error
+			if files, err = disk.Clear(); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(s.out, "Cleared %s: %d in memory, %d files on disk\n", ns, entries, files)
+	}
+	return nil
+}
+
+// byteSize writes n bytes the way people read them, like 1.5 MB.
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
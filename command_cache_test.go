@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/filecache"
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+func TestCacheStats(t *testing.T) {
+	s := newTestSession(t)
+	s.app.cache = pokecache.NewCache(time.Hour)
+	t.Cleanup(s.app.cache.Close)
+	s.app.cache.Limit(defaultCacheMB << 20)
+	s.app.cache.Add("pokemon/pikachu", make([]byte, 2048))
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "25.png"), make([]byte, 100), 0o644)
+	s.app.sprites = filecache.New(dir)
+
+	out := &bytes.Buffer{}
+	if err := s.run("cache stats", out); err != nil {
+		t.Fatalf("cache stats returned error: %v", err)
+	}
+	for _, want := range []string{"In memory: 1 api, 2.0 KB for 2.0 KB of data (limit 64.0 MB)\n", "Sprites on disk: 1 files, 100 B\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q, got %q", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Cries") {
+		t.Errorf("Expected caches that aren't set up left out, got %q", out.String())
+	}
+}
+
+func TestCacheClearNamespace(t *testing.T) {
+	s := newTestSession(t)
+	s.app.cache = pokecache.NewCache(time.Hour)
+	t.Cleanup(s.app.cache.Close)
+	s.app.cache.Add("pokemon/pikachu", []byte("{}"))
+	s.app.cache.AddTo(pokecache.Sprites, "25.png", []byte("png"))
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "25.png"), make([]byte, 100), 0o644)
+	s.app.sprites = filecache.New(dir)
+
+	out := &bytes.Buffer{}
+	if err := s.run("cache clear sprites", out); err != nil {
+		t.Fatalf("cache clear returned error: %v", err)
+	}
+	if want := "Cleared sprites: 1 in memory, 1 files on disk\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if _, ok := s.app.cache.GetFrom(pokecache.Sprites, "25.png"); ok {
+		t.Errorf("Expected the sprite dropped from memory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "25.png")); err == nil {
+		t.Errorf("Expected the sprite deleted from disk")
+	}
+	if _, ok := s.app.cache.Get("pokemon/pikachu"); !ok {
+		t.Errorf("Expected API responses kept")
+	}
+
+	if err := s.run("cache clear everything", out); err == nil {
+		t.Errorf("Expected an error for a namespace that doesn't exist")
+	}
+}
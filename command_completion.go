@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+var completionScripts = map[string]string{
+	"bash": `_pokedexcli() {
+	local IFS=$'\n'
+	COMPREPLY=($(pokedexcli __complete "${COMP_WORDS[@]:1:$COMP_CWORD}"))
+}
+complete -F _pokedexcli pokedexcli
+`,
+	"zsh": `#compdef pokedexcli
+_pokedexcli() {
+	local -a candidates
+	candidates=("${(@f)$(pokedexcli __complete "${(@)words[2,CURRENT]}")}")
+	compadd -a candidates
+}
+compdef _pokedexcli pokedexcli
+`,
+	"fish": `complete -c pokedexcli -f -a '(pokedexcli __complete (commandline -opc)[2..-1] "$(commandline -ct)")'
+`,
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "completion",
+		usage:       "completion bash|zsh|fish",
+		description: "Print a shell completion script",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandCompletion,
+		complete: func(s *session, args []string) []string {
+			return []string{"bash", "fish", "zsh"}
+		},
+	})
+}
+
+func commandCompletion(s *session, args ...string) error {
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q, use bash, zsh or fish", args[0])
+	}
+	fmt.Fprint(s.out, script)
+	return nil
+}
+
+// completions returns the candidates for the last of words, which is the
+// word being typed. Earlier words are the command and its finished args.
+func completions(s *session, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	prefix := strings.ToLower(words[len(words)-1])
+
+	candidates := []string{}
+	if len(words) == 1 {
+		for name, cmd := range commands {
+			if !cmd.hidden {
+				candidates = append(candidates, name)
+			}
+		}
+	} else if cmd, ok := commands[strings.ToLower(words[0])]; ok && cmd.complete != nil {
+		args := words[1 : len(words)-1]
+		if cmd.maxArgs < 0 || len(args) < cmd.maxArgs {
+			candidates = cmd.complete(s, args)
+		}
+	}
+
+	matches := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return slices.Compact(matches)
+}
+
+func completeCaught(s *session, args []string) []string {
+	names := []string{}
+	for name := range s.profile.Pokedex {
+		names = append(names, name)
+	}
+	return names
+}
+
+// completeKnown offers the names the data source has on hand, without
+// going to the network.
+func completeKnown(resource string) func(s *session, args []string) []string {
+	return func(s *session, args []string) []string {
+		lister, ok := s.source.(pokeapi.Lister)
+		if !ok {
+			return nil
+		}
+		names, _ := lister.Names(resource)
+		return names
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/fulltext"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/prompt"
+	"github.com/azs06/pokedexcli/internal/sprite"
+	"github.com/azs06/pokedexcli/internal/theme"
+)
+
+// setting is one of config.Settings, as the config command shows and
+// changes it.
+type setting struct {
+	name string
+	// def is what it is when nothing sets it.
+	def string
+	// get is its value in st, empty if st leaves it unset.
+	get func(st config.Settings) string
+	// set changes it in st, unsetting it for an empty value. It's nil for
+	// settings that can only be edited in the file.
+	set func(st *config.Settings, value string) error
+	// getGlobal and setGlobal take the place of get and set for settings
+	// that are the same for every profile, at the top level of the config.
+	// They take effect the next time the game starts.
+	getGlobal func(cfg config.Config) string
+	setGlobal func(cfg *config.Config, value string) error
+}
+
+var settings = []setting{
+	{
+		name: "prompt",
+		def:  prompt.Default,
+		get:  func(st config.Settings) string { return st.Prompt },
+	},
+	{
+		name: "version_group",
+		def:  "newest",
+		get:  func(st config.Settings) string { return st.VersionGroup },
+		set: func(st *config.Settings, value string) error {
+			st.VersionGroup = value
+			return nil
+		},
+	},
+	{
+		name: "shiny_odds",
+		def:  strconv.Itoa(defaultShinyOdds),
+		get: func(st config.Settings) string {
+			if st.ShinyOdds == 0 {
+				return ""
+			}
+			return strconv.Itoa(st.ShinyOdds)
+		},
+		set: func(st *config.Settings, value string) error {
+			if value == "" {
+				st.ShinyOdds = 0
+				return nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("shiny_odds is one in how many, not %s", value)
+			}
+			st.ShinyOdds = n
+			return nil
+		},
+	},
+	{
+		name: "theme",
+		def:  theme.Default,
+		get:  func(st config.Settings) string { return st.Theme },
+		set: func(st *config.Settings, value string) error {
+			st.Theme = value
+			return nil
+		},
+	},
+	{
+		name: "icons",
+		def:  "none",
+		get:  func(st config.Settings) string { return st.Icons },
+		set: func(st *config.Settings, value string) error {
+			if _, ok := iconSets[value]; !ok && value != "" {
+				return fmt.Errorf("icons are %s, not %s", strings.Join(iconStyles(), ", "), value)
+			}
+			st.Icons = value
+			return nil
+		},
+	},
+	{
+		name: "sprites",
+		def:  "auto",
+		get:  func(st config.Settings) string { return st.Sprites },
+		set: func(st *config.Settings, value string) error {
+			if value != "" && value != "auto" && !slices.Contains(sprite.Protocols, sprite.Protocol(value)) {
+				return fmt.Errorf("sprites are auto, kitty, iterm, sixel, ascii or off, not %s", value)
+			}
+			st.Sprites = value
+			return nil
+		},
+	},
+	{
+		name: "pager",
+		def:  "auto",
+		get:  func(st config.Settings) string { return st.Pager },
+		set: func(st *config.Settings, value string) error {
+			st.Pager = value
+			return nil
+		},
+	},
+	{
+		name: "language",
+		def:  fulltext.DefaultLanguage,
+		get:  func(st config.Settings) string { return st.Language },
+		set: func(st *config.Settings, value string) error {
+			st.Language = value
+			return nil
+		},
+	},
+	onOff("a11y", func(st *config.Settings) **bool { return &st.A11y }),
+	onOff("fast", func(st *config.Settings) **bool { return &st.Fast }),
+	onOff("sound", func(st *config.Settings) **bool { return &st.Sound }),
+	onOff("timing", func(st *config.Settings) **bool { return &st.Timing }),
+	{
+		name:      "api",
+		def:       pokeapi.DefaultUrl,
+		getGlobal: func(cfg config.Config) string { return cfg.API },
+		setGlobal: func(cfg *config.Config, value string) error {
+			// Only an API that answers like PokeAPI is taken.
+			if value != "" {
+				if _, err := pokeapi.CheckStatus(value); err != nil {
+					return err
+				}
+			}
+			cfg.API = value
+			return nil
+		},
+	},
+	{
+		name:      "graphql",
+		def:       pokeapi.DefaultGraphQLUrl,
+		getGlobal: func(cfg config.Config) string { return cfg.GraphQL },
+		setGlobal: func(cfg *config.Config, value string) error {
+			if value != "" {
+				if err := pokeapi.CheckURL(value); err != nil {
+					return err
+				}
+			}
+			cfg.GraphQL = value
+			return nil
+		},
+	},
+}
+
+// onOff is a setting that's on or off, off by default, kept in the field
+// of config.Settings that field points to.
+func onOff(name string, field func(st *config.Settings) **bool) setting {
+	return setting{
+		name: name,
+		def:  "off",
+		get: func(st config.Settings) string {
+			switch on := *field(&st); {
+			case on == nil:
+				return ""
+			case *on:
+				return "on"
+			}
+			return "off"
+		},
+		set: func(st *config.Settings, value string) error {
+			switch value {
+			case "":
+				*field(st) = nil
+			case "on", "off":
+				on := value == "on"
+				*field(st) = &on
+			default:
+				return fmt.Errorf("%s is on or off, not %s", name, value)
+			}
+			return nil
+		},
+	}
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "config",
+		usage:       "config [--profile] [<setting> [<value>|--unset]]",
+		description: "Show or change settings, for everyone or with --profile just this profile",
+		maxArgs:     3,
+		callback:    commandConfig,
+		complete: func(s *session, args []string) []string {
+			if len(args) > 0 && args[0] != "--profile" {
+				return nil
+			}
+			names := []string{"--profile"}
+			for _, st := range settings {
+				names = append(names, st.name)
+			}
+			return names
+		},
+	})
+}
+
+func commandConfig(s *session, args ...string) error {
+	own := len(args) > 0 && args[0] == "--profile"
+	if own {
+		args = args[1:]
+	}
+	a := s.app
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(args) == 0 {
+		for _, st := range settings {
+			showSetting(s, a.config, st)
+		}
+		return nil
+	}
+	st, ok := findSetting(args[0])
+	if !ok {
+		return fmt.Errorf("there's no %s setting", args[0])
+	}
+	switch len(args) {
+	case 1:
+		showSetting(s, a.config, st)
+		return nil
+	case 3:
+		return errors.New("usage: config [--profile] [<setting> [<value>|--unset]]")
+	}
+	if st.set == nil && st.setGlobal == nil {
+		return fmt.Errorf("%s can only be changed in the config file", st.name)
+	}
+	if st.setGlobal != nil && own {
+		return fmt.Errorf("%s is the same for every profile", st.name)
+	}
+	if a.configPath == "" {
+		return errors.New("there's no config file to save settings to")
+	}
+	value := args[1]
+	if value == "--unset" {
+		value = ""
+	}
+
+	cfg := a.config
+	cfg.Profiles = maps.Clone(cfg.Profiles)
+	if own {
+		ownSettings := cfg.Profiles[s.id]
+		if err := st.set(&ownSettings, value); err != nil {
+			return err
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]config.Settings{}
+		}
+		cfg.Profiles[s.id] = ownSettings
+		if ownSettings == (config.Settings{}) {
+			delete(cfg.Profiles, s.id)
+		}
+	} else if st.setGlobal != nil {
+		if err := st.setGlobal(&cfg, value); err != nil {
+			return err
+		}
+	} else if err := st.set(&cfg.Settings, value); err != nil {
+		return err
+	}
+	if err := s.applySettings(cfg.For(s.id)); err != nil {
+		s.applySettings(a.config.For(s.id))
+		return err
+	}
+	if err := config.Save(a.configPath, cfg); err != nil {
+		s.applySettings(a.config.For(s.id))
+		return err
+	}
+	a.config = cfg
+	showSetting(s, cfg, st)
+	if st.setGlobal != nil {
+		fmt.Fprintln(s.out, "This takes effect the next time the game starts.")
+	}
+	return nil
+}
+
+func findSetting(name string) (setting, bool) {
+	for _, st := range settings {
+		if st.name == name {
+			return st, true
+		}
+	}
+	return setting{}, false
+}
+
+// showSetting prints st's value for s's profile and where it comes from:
+// the profile's own settings, the global ones or the default.
+func showSetting(s *session, cfg config.Config, st setting) {
+	value, source := st.def, "default"
+	if st.getGlobal != nil {
+		if v := st.getGlobal(cfg); v != "" {
+			value, source = v, "config"
+		}
+		fmt.Fprintf(s.out, "%s = %s (%s)\n", st.name, value, source)
+		return
+	}
+	if v := st.get(cfg.Settings); v != "" {
+		value, source = v, "config"
+	}
+	if v := st.get(cfg.Profiles[s.id]); v != "" {
+		value, source = v, "profile "+s.id
+	}
+	fmt.Fprintf(s.out, "%s = %s (%s)\n", st.name, value, source)
+}
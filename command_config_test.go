@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/config"
+)
+
+func TestConfigCommand(t *testing.T) {
+	s := newTestSession(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	s.app.configPath = path
+
+	out := &bytes.Buffer{}
+	if err := s.run("config shiny_odds", out); err != nil {
+		t.Fatalf("config returned error: %v", err)
+	}
+	if out.String() != "shiny_odds = 4096 (default)\n" {
+		t.Errorf("Expected the default shiny odds, got %q", out.String())
+	}
+
+	out.Reset()
+	s.run("config shiny_odds 1024", out)
+	s.run("config --profile shiny_odds 8", out)
+	s.run("config --profile version_group red-blue", out)
+	if !strings.HasSuffix(out.String(), "version_group = red-blue (profile local)\n") {
+		t.Errorf("Expected the profile's own setting to show, got %q", out.String())
+	}
+	if s.shinyOdds != 8 || s.versionGroup != "red-blue" {
+		t.Errorf("Expected the session to play with its own settings, got odds %d and %q", s.shinyOdds, s.versionGroup)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.ShinyOdds != 1024 || cfg.Profiles["local"].ShinyOdds != 8 || cfg.For("misty").ShinyOdds != 1024 {
+		t.Errorf("Expected global and profile settings to be saved apart, got %+v", cfg)
+	}
+
+	out.Reset()
+	s.run("config --profile shiny_odds --unset", out)
+	if out.String() != "shiny_odds = 1024 (config)\n" || s.shinyOdds != 1024 {
+		t.Errorf("Expected unsetting the profile's odds to fall back to the global ones, got %q", out.String())
+	}
+	if err := s.run("config shiny_odds lots", out); err == nil {
+		t.Errorf("Expected odds that aren't a number to be refused")
+	}
+	if err := s.run("config prompt x", out); err == nil {
+		t.Errorf("Expected the prompt to only be set in the file")
+	}
+}
+
+func TestA11y(t *testing.T) {
+	s := newTestSession(t)
+	s.app.configPath = filepath.Join(t.TempDir(), "config.json")
+	s.profile.Give("master-ball", 1)
+	s.run("catch pikachu master", &bytes.Buffer{})
+	s.profile.Party = []int{s.profile.Pokemon[0].ID}
+	s.profile.Pokemon[0].Gender = "male"
+
+	out := &bytes.Buffer{}
+	s.run("config --profile a11y on", out)
+	if out.String() != "a11y = on (profile local)\n" || !s.a11y {
+		t.Fatalf("Expected a11y to be turned on, got %q", out.String())
+	}
+	out.Reset()
+	s.run("party", out)
+	if !strings.Contains(out.String(), "1. pikachu (male). Level 5. HP ") || strings.Contains(out.String(), "♂") {
+		t.Errorf("Expected the party in plain sentences, got %q", out.String())
+	}
+	out.Reset()
+	s.run("inspect pikachu", out)
+	if !strings.HasPrefix(out.String(), "pikachu.\n") || strings.Contains(out.String(), "█") {
+		t.Errorf("Expected inspect to start with a summary and leave out the bar, got %q", out.String())
+	}
+}
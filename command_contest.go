@@ -0,0 +1,203 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const (
+	// maxCondition is as high as a contest condition goes.
+	maxCondition = 255
+	// moveAppeal is what each move a pokemon knows in the contest's
+	// category adds to its score.
+	moveAppeal = 30
+	// judgesRoll is the most the judges' whim adds to a score.
+	judgesRoll = 30
+	// Rivals score rivalBase plus up to rivalRoll.
+	rivalBase = 40
+	rivalRoll = 110
+)
+
+// contestCategories are the five kinds of contest, each judging the
+// condition a flavor of berry raises.
+var contestCategories = []string{"cool", "beauty", "cute", "smart", "tough"}
+
+var contestFlavors = map[string]string{
+	"cool":   "spicy",
+	"beauty": "dry",
+	"cute":   "sweet",
+	"smart":  "bitter",
+	"tough":  "sour",
+}
+
+// berryFlavors are the flavors of the berries the player can grow, as
+// PokeAPI has their potency.
+var berryFlavors = map[string]map[string]int{
+	"cheri-berry":  {"spicy": 10},
+	"oran-berry":   {"spicy": 10, "dry": 10, "bitter": 10, "sour": 10},
+	"razz-berry":   {"spicy": 10, "dry": 10},
+	"pomeg-berry":  {"spicy": 10, "sweet": 10, "bitter": 10},
+	"kelpsy-berry": {"dry": 10, "bitter": 10, "sour": 10},
+	"qualot-berry": {"spicy": 10, "sweet": 10, "sour": 10},
+	"hondew-berry": {"spicy": 10, "dry": 10, "bitter": 10},
+	"grepa-berry":  {"dry": 10, "sweet": 10, "sour": 10},
+	"tamato-berry": {"spicy": 20, "dry": 10},
+}
+
+// contestRivals are the coordinators the player's pokemon competes with.
+var contestRivals = []string{"Coordinator Lila", "Coordinator Nando", "Coordinator Wallace"}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "contest",
+		usage:       "contest <cool|beauty|cute|smart|tough>",
+		description: "Enter your party's best pokemon in a contest",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandContest,
+		complete: func(s *session, args []string) []string {
+			return contestCategories
+		},
+	})
+}
+
+// conditionGains is how much a berry raises each of p's conditions: by its
+// flavors' potency, half as much again for a flavor its nature likes and
+// half as much for one it hates, up to maxCondition.
+func conditionGains(s *session, p *profile.Pokemon, berry string) (map[string]int, error) {
+	nature, err := natureOf(s, p.Nature)
+	if err != nil {
+		return nil, err
+	}
+	gains := map[string]int{}
+	for _, category := range contestCategories {
+		flavor := contestFlavors[category]
+		gain := berryFlavors[berry][flavor]
+		switch flavor {
+		case nature.LikesFlavor.Name:
+			gain += gain / 2
+		case nature.HatesFlavor.Name:
+			gain -= gain / 2
+		}
+		if gain = min(gain, maxCondition-p.Condition[category]); gain > 0 {
+			gains[category] = gain
+		}
+	}
+	return gains, nil
+}
+
+// raiseCondition applies gains to p, saying what changed.
+func raiseCondition(s *session, p *profile.Pokemon, gains map[string]int) {
+	if len(gains) == 0 {
+		return
+	}
+	if p.Condition == nil {
+		p.Condition = map[string]int{}
+	}
+	var raised []string
+	for _, category := range contestCategories {
+		if gain := gains[category]; gain > 0 {
+			p.Condition[category] += gain
+			raised = append(raised, fmt.Sprintf("%s %d", category, p.Condition[category]))
+		}
+	}
+	fmt.Fprintf(s.out, "%s's condition rose: %s\n", p.Species, strings.Join(raised, ", "))
+}
+
+// contestEntry is a pokemon's score in a contest.
+type contestEntry struct {
+	name    string
+	pokemon *profile.Pokemon
+	score   int
+}
+
+// appeal is what p's moves add in category: moveAppeal for each it knows
+// of the category. Pokemon with no moves taught have none.
+func appeal(s *session, p *profile.Pokemon, category string) (int, error) {
+	total := 0
+	for _, m := range p.Moves {
+		move, err := s.source.Move(m.Name)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if move.ContestType.Name == category {
+			total += moveAppeal
+		}
+	}
+	return total, nil
+}
+
+// commandContest enters whichever party pokemon would score best in the
+// category, by its condition and moves, against three rivals. Winning earns
+// the category's ribbon.
+func commandContest(s *session, args ...string) error {
+	category := args[0]
+	if !slices.Contains(contestCategories, category) {
+		return fmt.Errorf("there's no %s contest; try %s", category, strings.Join(contestCategories, ", "))
+	}
+	party := s.profile.PartyPokemon()
+	if len(party) == 0 {
+		return errors.New("you have no pokemon to enter")
+	}
+
+	var best contestEntry
+	for _, p := range party {
+		bonus, err := appeal(s, p, category)
+		if err != nil {
+			return err
+		}
+		if score := p.Condition[category] + bonus; best.pokemon == nil || score > best.score {
+			best = contestEntry{name: "Your " + p.Species, pokemon: p, score: score}
+		}
+	}
+	best.score += rand.IntN(judgesRoll + 1)
+	entries := []contestEntry{best}
+	for _, rival := range contestRivals {
+		entries = append(entries, contestEntry{name: rival, score: rivalBase + rand.IntN(rivalRoll+1)})
+	}
+	slices.SortStableFunc(entries, func(a, b contestEntry) int { return cmp.Compare(b.score, a.score) })
+
+	p := best.pokemon
+	fmt.Fprintf(s.out, "%s enters the %s contest with %d %s condition!\n", p.Species, category, p.Condition[category], category)
+	place := 0
+	for i, e := range entries {
+		fmt.Fprintf(s.out, "%d. %s: %d points\n", i+1, e.name, e.score)
+		if e.pokemon == p {
+			place = i + 1
+		}
+	}
+	if place != 1 {
+		fmt.Fprintf(s.out, "%s came %s. Feed it %s berries to improve its %s condition.\n", p.Species, ordinal(place), contestFlavors[category], category)
+		return nil
+	}
+	ribbon := category + "-ribbon"
+	if !awardRibbon(p, ribbon) {
+		fmt.Fprintf(s.out, "%s won the %s contest again!\n", p.Species, category)
+		return nil
+	}
+	fmt.Fprintf(s.out, "%s won the %s contest and the %s!\n", p.Species, category, ribbonName(ribbon))
+	return nil
+}
+
+// ordinal is place as "1st", "2nd" and so on, for the places of a contest.
+func ordinal(place int) string {
+	switch place {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", place)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestFeedRaisesCondition(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	p := &s.profile.Pokemon[0]
+	p.Nature = "adamant"
+	s.profile.Give("tamato-berry", 1)
+	out := &bytes.Buffer{}
+	if err := s.run("feed pikachu tamato", out); err != nil {
+		t.Fatalf("feed returned error: %v", err)
+	}
+	// Adamant pokemon like spicy food, so the cool condition rises by half
+	// as much again.
+	if p.Condition["cool"] != 30 || p.Condition["beauty"] != 5 {
+		t.Errorf("Expected cool 30 and beauty 5, got %v", p.Condition)
+	}
+	if !strings.Contains(out.String(), "pikachu's condition rose: cool 30, beauty 5\n") {
+		t.Errorf("Expected the condition to be shown, got %q", out.String())
+	}
+
+	out.Reset()
+	s.run("inspect pikachu", out)
+	if !strings.Contains(out.String(), "  Condition: cool 30, beauty 5, cute 0, smart 0, tough 0\n") {
+		t.Errorf("Expected inspect to show the condition, got %q", out.String())
+	}
+}
+
+func TestContest(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("contest cool", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a contest without pokemon to fail")
+	}
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	s.profile.Add(pokeapi.PokemonType{Name: "bulbasaur"}, 10)
+	if err := s.run("contest clever", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown category to fail")
+	}
+
+	// No rival can beat a pokemon in perfect condition, and none can lose to
+	// one with no condition at all.
+	bulbasaur := &s.profile.Pokemon[1]
+	bulbasaur.Condition = map[string]int{"cute": maxCondition}
+	out := &bytes.Buffer{}
+	if err := s.run("contest cute", out); err != nil {
+		t.Fatalf("contest returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "bulbasaur won the cute contest and the Cute Ribbon!") {
+		t.Errorf("Expected bulbasaur to win, got %q", out.String())
+	}
+	if !slices.Equal(bulbasaur.Ribbons, []string{"cute-ribbon"}) {
+		t.Errorf("Expected bulbasaur to get the cute ribbon, got %v", bulbasaur.Ribbons)
+	}
+
+	out.Reset()
+	if err := s.run("contest tough", out); err != nil {
+		t.Fatalf("contest returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "came 4th. Feed it sour berries") {
+		t.Errorf("Expected to come last, got %q", out.String())
+	}
+}
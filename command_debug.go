@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// pprofAddr is where debug pprof on serves unless told otherwise. It's only
+// ever on this machine.
+const pprofAddr = "localhost:6060"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "debug",
+		usage:       "debug <pprof on [port]|pprof off|gc|mem>",
+		description: "Profile the game with pprof, or show its memory and goroutines",
+		minArgs:     1,
+		maxArgs:     3,
+		callback:    commandDebug,
+		hidden:      true,
+		complete: func(s *session, args []string) []string {
+			switch {
+			case len(args) == 0:
+				return []string{"pprof", "gc", "mem"}
+			case len(args) == 1 && args[0] == "pprof":
+				return []string{"on", "off"}
+			}
+			return nil
+		},
+	})
+}
+
+func commandDebug(s *session, args ...string) error {
+	switch {
+	case args[0] == "pprof" && len(args) >= 2 && args[1] == "on":
+		addr := pprofAddr
+		if len(args) == 3 {
+			addr = net.JoinHostPort("localhost", args[2])
+		}
+		return pprofOn(s, addr)
+	case args[0] == "pprof" && len(args) == 2 && args[1] == "off":
+		return pprofOff(s)
+	case args[0] == "gc" && len(args) == 1:
+		return debugGC(s)
+	case args[0] == "mem" && len(args) == 1:
+		return debugMem(s)
+	}
+	return errors.New("usage: debug <pprof on [port]|pprof off|gc|mem>")
+}
+
+// pprofOn serves net/http/pprof's handlers on addr until pprofOff.
+func pprofOn(s *session, addr string) error {
+	a := s.app
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pprof != nil {
+		return fmt.Errorf("pprof is already on at http://%s/debug/pprof/", a.pprof.Addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	a.pprof = &http.Server{Addr: ln.Addr().String(), Handler: mux}
+	go a.pprof.Serve(ln)
+	fmt.Fprintf(s.out, "pprof is on at http://%s/debug/pprof/\n", a.pprof.Addr)
+	return nil
+}
+
+func pprofOff(s *session) error {
+	a := s.app
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pprof == nil {
+		return errors.New("pprof isn't on")
+	}
+	err := a.pprof.Close()
+	a.pprof = nil
+	fmt.Fprintln(s.out, "pprof is off")
+	return err
+}
+
+// debugGC collects garbage now, and shows what it freed and how long the
+// collector has paused the game so far.
+func debugGC(s *session) error {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	runtime.GC()
+	took := time.Since(start)
+	runtime.ReadMemStats(&after)
+	freed := int64(before.HeapAlloc) - int64(after.HeapAlloc)
+	fmt.Fprintf(s.out, "Collected in %s: heap %s -> %s, %s freed\n", took.Round(time.Microsecond), byteSize(int64(before.HeapAlloc)), byteSize(int64(after.HeapAlloc)), byteSize(max(freed, 0)))
+	fmt.Fprintf(s.out, "%d collections so far, paused %s in all\n", after.NumGC, time.Duration(after.PauseTotalNs).Round(time.Microsecond))
+	return nil
+}
+
+func debugMem(s *session) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Fprintf(s.out, "Heap: %s in use of %s, %d objects\n", byteSize(int64(m.HeapAlloc)), byteSize(int64(m.HeapSys)), m.HeapObjects)
+	fmt.Fprintf(s.out, "Allocated: %s in all, %d times\n", byteSize(int64(m.TotalAlloc)), m.Mallocs)
+	fmt.Fprintf(s.out, "From the OS: %s\n", byteSize(int64(m.Sys)))
+	fmt.Fprintf(s.out, "Goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(s.out, "Collections: %d, next at %s\n", m.NumGC, byteSize(int64(m.NextGC)))
+	return nil
+}
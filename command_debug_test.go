@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDebugHidden(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("help", out); err != nil {
+		t.Fatalf("help returned error: %v", err)
+	}
+	if strings.Contains(out.String(), "debug") {
+		t.Errorf("Expected debug left out of help, got %q", out.String())
+	}
+	if got := completions(s, []string{"deb"}); len(got) != 0 {
+		t.Errorf("Expected no completions for debug, got %v", got)
+	}
+
+	out.Reset()
+	if err := s.run("debug mem", out); err != nil {
+		t.Fatalf("debug mem returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Goroutines: ") {
+		t.Errorf("Expected goroutines counted, got %q", out.String())
+	}
+}
+
+func TestDebugPprof(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("debug pprof on 0", out); err != nil {
+		t.Fatalf("debug pprof on returned error: %v", err)
+	}
+	defer s.run("debug pprof off", out)
+	url := strings.TrimPrefix(strings.TrimSpace(out.String()), "pprof is on at ")
+	resp, err := http.Get(url + "goroutine?debug=1")
+	if err != nil {
+		t.Fatalf("Expected pprof served at %s, got %v", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if err := s.run("debug pprof on", out); err == nil {
+		t.Errorf("Expected an error turning pprof on twice")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+type trainerPokemon struct {
+	species string
+	level   int
+}
+
+type trainer struct {
+	name  string
+	title string
+	team  []trainerPokemon
+}
+
+// eliteFour is the Kanto league from FireRed and LeafGreen, champion last.
+var eliteFour = []trainer{
+	{"Lorelei", "Elite Four", []trainerPokemon{{"dewgong", 52}, {"cloyster", 51}, {"slowbro", 52}, {"jynx", 54}, {"lapras", 54}}},
+	{"Bruno", "Elite Four", []trainerPokemon{{"onix", 51}, {"hitmonchan", 53}, {"hitmonlee", 53}, {"onix", 54}, {"machamp", 56}}},
+	{"Agatha", "Elite Four", []trainerPokemon{{"gengar", 54}, {"golbat", 54}, {"haunter", 53}, {"arbok", 56}, {"gengar", 58}}},
+	{"Lance", "Elite Four", []trainerPokemon{{"gyarados", 56}, {"dragonair", 54}, {"dragonair", 54}, {"aerodactyl", 58}, {"dragonite", 60}}},
+	{"Blue", "Champion", []trainerPokemon{{"pidgeot", 59}, {"alakazam", 57}, {"rhydon", 59}, {"exeggutor", 59}, {"gyarados", 61}, {"charizard", 63}}},
+}
+
+// challenge is a run at the Elite Four. The party's health carries over from
+// one battle to the next.
+type challenge struct {
+	stage  int
+	team   *battle.Side
+	format battle.Format
+	ai     battle.AI
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "elitefour",
+		usage:       "elitefour [--difficulty <level>] [--double]",
+		description: "Challenge the Elite Four and the Champion with a full party",
+		maxArgs:     3,
+		callback:    commandEliteFour,
+		complete:    completeBattleArgs,
+	})
+	registerCommand(cliCommand{
+		name:        "halloffame",
+		description: "Show the teams that became Champion or won a tournament",
+		callback:    commandHallOfFame,
+		paged:       true,
+	})
+}
+
+func commandEliteFour(s *session, args ...string) error {
+	if len(s.profile.Party) < profile.PartySize {
+		return fmt.Errorf("the Elite Four only accept challengers with a full party of %d", profile.PartySize)
+	}
+	ai, format, err := parseBattleArgs(args, "normal")
+	if err != nil {
+		return err
+	}
+	team, err := playerSide(s)
+	if err != nil {
+		return err
+	}
+	s.challenge = &challenge{team: team, format: format, ai: ai}
+	fmt.Fprintln(s.out, "Welcome to the Pokémon League! Four battles and the Champion stand between you and the Hall of Fame.")
+	return nextChallenger(s)
+}
+
+// trainerSide is t's team, ready for battle.
+func trainerSide(s *session, t trainer) (*battle.Side, error) {
+	side := &battle.Side{Name: t.name}
+	for _, member := range t.team {
+		p, err := s.source.Pokemon(member.species)
+		if err != nil {
+			return nil, err
+		}
+		side.Team = append(side.Team, battler(p, member.level))
+	}
+	return side, nil
+}
+
+func nextChallenger(s *session) error {
+	t := eliteFour[s.challenge.stage]
+	opponent, err := trainerSide(s, t)
+	if err != nil {
+		s.challenge = nil
+		return err
+	}
+	fmt.Fprintf(s.out, "%s %s wants to battle!\n", t.title, t.name)
+	startBattle(s, s.challenge.team, opponent, s.challenge.format, s.challenge.ai, challengeResult)
+	return nil
+}
+
+func challengeResult(s *session, b *battle.Battle) error {
+	if b.Winner != 0 {
+		s.challenge = nil
+		fmt.Fprintln(s.out, "Your challenge is over. Train up and try again!")
+		return nil
+	}
+	t := eliteFour[s.challenge.stage]
+	prize := 0
+	for _, member := range t.team {
+		prize = max(prize, 100*member.level)
+	}
+	s.profile.Money += prize
+	fmt.Fprintf(s.out, "You beat %s %s and got %d Pokédollars!\n", t.title, t.name, prize)
+
+	s.challenge.stage++
+	if s.challenge.stage < len(eliteFour) {
+		return nextChallenger(s)
+	}
+	s.challenge = nil
+	enterHallOfFame(s, "")
+	fmt.Fprintln(s.out, "Congratulations! You are the new Champion. Your team has been entered into the Hall of Fame.")
+	awardTeamRibbon(s, championRibbon)
+	for _, item := range []string{megaRing, dynamaxBand} {
+		if s.profile.Inventory[item] == 0 {
+			s.profile.Give(item, 1)
+			fmt.Fprintf(s.out, "You received a %s!\n", ballName(item))
+		}
+	}
+	return nil
+}
+
+// enterHallOfFame records the party as the winners of event, or of the
+// Elite Four when event is empty.
+func enterHallOfFame(s *session, event string) {
+	entry := profile.HallOfFameEntry{Time: s.now(), Event: event}
+	var team []string
+	for _, p := range s.profile.PartyPokemon() {
+		entry.Team = append(entry.Team, *p)
+		team = append(team, p.Species)
+	}
+	s.profile.HallOfFame = append(s.profile.HallOfFame, entry)
+	s.publish(events.Event{Kind: events.HallOfFame, Competition: event, Team: team})
+}
+
+func commandHallOfFame(s *session, args ...string) error {
+	if len(s.profile.HallOfFame) == 0 {
+		fmt.Fprintln(s.out, "The Hall of Fame is empty. Beat the Elite Four to get your team in.")
+		return nil
+	}
+	for i, entry := range s.profile.HallOfFame {
+		team := make([]string, len(entry.Team))
+		for j, p := range entry.Team {
+			team[j] = fmt.Sprintf("%s (Lv. %d)", p.Species, p.Level)
+		}
+		event := ""
+		if entry.Event != "" {
+			event = " (" + entry.Event + ")"
+		}
+		fmt.Fprintf(s.out, "#%d %s%s: %s\n", i+1, entry.Time.Format("2006-01-02"), event, strings.Join(team, ", "))
+	}
+	return nil
+}
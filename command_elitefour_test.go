@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battlelog"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func TestEliteFourNeedsFullParty(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 100)
+	if err := s.run("elitefour", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a party of one to be turned away")
+	}
+	if s.battle != nil {
+		t.Errorf("Expected no battle to start")
+	}
+}
+
+func TestEliteFourHallOfFame(t *testing.T) {
+	s := newTestSession(t)
+	mew := pokeapi.PokemonType{Name: "mew"}
+	for _, stat := range []string{"hp", "attack", "defense", "speed"} {
+		mew.Stats = append(mew.Stats, pokeapi.StatDetail{BaseStat: 100, Stat: pokeapi.Stat{Name: stat}})
+	}
+	for range profile.PartySize {
+		s.profile.Add(mew, 100)
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("elitefour", out); err != nil {
+		t.Fatalf("elitefour returned error: %v", err)
+	}
+	if s.state() != stateBattle {
+		t.Fatalf("Expected a battle, got %q", out.String())
+	}
+	out.Reset()
+	s.run("map", out)
+	if !strings.Contains(out.String(), "You're in a battle") {
+		t.Errorf("Expected map to be refused during a battle, got %q", out.String())
+	}
+
+	for turn := 0; s.battle != nil; turn++ {
+		if turn == 500 {
+			t.Fatalf("Expected the challenge to end")
+		}
+		if err := s.run("fight tackle", &bytes.Buffer{}); err != nil {
+			t.Fatalf("fight returned error: %v", err)
+		}
+	}
+	if len(s.profile.HallOfFame) != 1 || len(s.profile.HallOfFame[0].Team) != profile.PartySize {
+		t.Fatalf("Expected the team in the Hall of Fame, got %+v", s.profile.HallOfFame)
+	}
+	for _, p := range s.profile.PartyPokemon() {
+		if !slices.Contains(p.Ribbons, championRibbon) {
+			t.Errorf("Expected %s to get the champion ribbon, got %v", p.Species, p.Ribbons)
+		}
+	}
+	out.Reset()
+	s.run("halloffame", out)
+	if !strings.Contains(out.String(), "mew (Lv. 100)") {
+		t.Errorf("Expected the team to be listed, got %q", out.String())
+	}
+}
+
+func TestForfeitEndsChallenge(t *testing.T) {
+	s := newTestSession(t)
+	for range profile.PartySize {
+		s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	}
+	s.run("elitefour", &bytes.Buffer{})
+	s.run("forfeit", &bytes.Buffer{})
+	if s.battle != nil || s.challenge != nil {
+		t.Errorf("Expected forfeiting to end the challenge")
+	}
+}
+
+func TestBattlesAreRecorded(t *testing.T) {
+	s := newTestSession(t)
+	s.battles = battlelog.NewStore(t.TempDir())
+	for range profile.PartySize {
+		s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	}
+	s.run("elitefour", &bytes.Buffer{})
+	s.run("fight 1", &bytes.Buffer{})
+	s.run("forfeit", &bytes.Buffer{})
+
+	out := &bytes.Buffer{}
+	if err := s.run("battles list", out); err != nil {
+		t.Fatalf("battles list returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1. ") || !strings.Contains(out.String(), "vs Lorelei: forfeited in 1 turns") {
+		t.Errorf("Unexpected battle list %q", out.String())
+	}
+	out.Reset()
+	if err := s.run("replay 1 --fast", out); err != nil {
+		t.Fatalf("replay returned error: %v", err)
+	}
+	for _, want := range []string{"Lorelei sent out dewgong!", "-- Turn 1 --", "used tackle!"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the replay to show %q, got %q", want, out.String())
+		}
+	}
+	if err := s.run("replay 2", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown battle to be an error")
+	}
+}
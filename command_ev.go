@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const (
+	// maxEVs is the most EVs a pokemon can have in all, and maxStatEVs the
+	// most in any one stat.
+	maxEVs     = 510
+	maxStatEVs = 252
+	// vitaminEVs is what one vitamin adds.
+	vitaminEVs = 10
+)
+
+// statNames are the stats in the order the games show them.
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// statAliases are the short names players use for stats.
+var statAliases = map[string]string{
+	"atk":   "attack",
+	"def":   "defense",
+	"spa":   "special-attack",
+	"spatk": "special-attack",
+	"spd":   "special-defense",
+	"spdef": "special-defense",
+	"spe":   "speed",
+}
+
+// vitamins each add EVs to one stat.
+var vitamins = map[string]string{
+	"hp-up":   "hp",
+	"protein": "attack",
+	"iron":    "defense",
+	"calcium": "special-attack",
+	"zinc":    "special-defense",
+	"carbos":  "speed",
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "ev",
+		usage:       "ev <pokemon>",
+		description: "Show a pokemon's effort values",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandEV,
+		complete:    completeCaught,
+	})
+	registerCommand(cliCommand{
+		name:        "train",
+		usage:       "train <pokemon> --stat <stat> [--auto <battles>]",
+		description: "Knock out the wild pokemon here that give the most EVs in a stat",
+		minArgs:     3,
+		maxArgs:     5,
+		callback:    commandTrain,
+		complete: func(s *session, args []string) []string {
+			switch len(args) {
+			case 0:
+				return completeCaught(s, args)
+			case 1:
+				return []string{"--stat"}
+			case 2:
+				return statNames
+			case 3:
+				return []string{"--auto"}
+			}
+			return nil
+		},
+	})
+}
+
+func parseStat(arg string) (string, error) {
+	if stat, ok := statAliases[arg]; ok {
+		return stat, nil
+	}
+	for _, stat := range statNames {
+		if stat == arg {
+			return stat, nil
+		}
+	}
+	return "", fmt.Errorf("%s isn't a stat", arg)
+}
+
+// trainedStats are p's stats at level with ivs and evs. Each IV adds a
+// point at level 100, and so does every four EVs.
+func trainedStats(p pokeapi.PokemonType, level int, ivs, evs map[string]int) battle.Stats {
+	stat := func(name string) int {
+		return statAt(baseStat(p, name), ivs[name], evs[name], level)
+	}
+	return battle.Stats{
+		HP:        hpAt(baseStat(p, "hp"), ivs["hp"], evs["hp"], level),
+		Attack:    stat("attack"),
+		Defense:   stat("defense"),
+		SpAttack:  stat("special-attack"),
+		SpDefense: stat("special-defense"),
+		Speed:     stat("speed"),
+	}
+}
+
+// ownStats are a caught pokemon's stats as a p, with its IVs, EVs and
+// nature.
+func ownStats(p pokeapi.PokemonType, mon *profile.Pokemon, nature pokeapi.NatureDetail) battle.Stats {
+	stats := trainedStats(p, mon.Level, mon.IVs, mon.EVs)
+	applyNature(&stats, nature)
+	return stats
+}
+
+// effortYield is the EVs knocking p out gives, by stat.
+func effortYield(p pokeapi.PokemonType) map[string]int {
+	yield := map[string]int{}
+	for _, stat := range p.Stats {
+		if stat.Effort > 0 {
+			yield[stat.Stat.Name] = stat.Effort
+		}
+	}
+	return yield
+}
+
+func totalEVs(p *profile.Pokemon) int {
+	total := 0
+	for _, n := range p.EVs {
+		total += n
+	}
+	return total
+}
+
+// addEVs gives p up to n EVs in stat, as far as the caps allow, returning
+// how many it got.
+func addEVs(p *profile.Pokemon, stat string, n int) int {
+	n = min(n, maxStatEVs-p.EVs[stat], maxEVs-totalEVs(p))
+	if n <= 0 {
+		return 0
+	}
+	if p.EVs == nil {
+		p.EVs = map[string]int{}
+	}
+	p.EVs[stat] += n
+	return n
+}
+
+// gainEVs gives p a foe's yield, returning what it got by stat.
+func gainEVs(p *profile.Pokemon, yield map[string]int) map[string]int {
+	gained := map[string]int{}
+	for _, stat := range statNames {
+		if n := addEVs(p, stat, yield[stat]); n > 0 {
+			gained[stat] = n
+		}
+	}
+	return gained
+}
+
+func commandEV(s *session, args ...string) error {
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s's EVs (%d/%d):\n", p.Species, totalEVs(p), maxEVs)
+	for _, stat := range statNames {
+		fmt.Fprintf(s.out, "  %s: %d/%d\n", stat, p.EVs[stat], maxStatEVs)
+	}
+	return nil
+}
+
+// commandTrain has a pokemon knock out one of the wild pokemon in the
+// current area that yield the most EVs in a stat, earning its EVs and
+// experience. With --auto it battles them for real instead, as many times
+// as asked, and the results are summed up at the end.
+func commandTrain(s *session, args ...string) error {
+	if args[1] != "--stat" || len(args) == 4 || len(args) == 5 && args[3] != "--auto" {
+		return errors.New("usage: train <pokemon> --stat <stat> [--auto <battles>]")
+	}
+	stat, err := parseStat(args[2])
+	if err != nil {
+		return err
+	}
+	battles := 0
+	if len(args) == 5 {
+		if battles, err = strconv.Atoi(args[4]); err != nil || battles < 1 {
+			return fmt.Errorf("%s isn't a number of battles", args[4])
+		}
+	}
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	if p.EVs[stat] >= maxStatEVs || totalEVs(p) >= maxEVs {
+		return fmt.Errorf("%s can't gain any more %s EVs", p.Species, stat)
+	}
+	if s.profile.Location == "" {
+		return errors.New("travel somewhere first to find pokemon to train on")
+	}
+	area, err := s.source.LocationArea(s.profile.Location)
+	if err != nil {
+		return err
+	}
+
+	var best pokeapi.PokemonType
+	var bestEnc pokeapi.PokemonEncounter
+	for _, enc := range area.PokemonEncounters {
+		foe, err := s.source.Pokemon(enc.Pokemon.Name)
+		if err != nil {
+			return err
+		}
+		if effortYield(foe)[stat] > effortYield(best)[stat] {
+			best, bestEnc = foe, enc
+		}
+	}
+	if best.Name == "" {
+		return fmt.Errorf("no pokemon in %s give %s EVs", s.profile.Location, stat)
+	}
+	if battles > 0 {
+		return autoTrain(s, p, stat, best, bestEnc, battles)
+	}
+
+	foe := battler(best, encounterLevel(bestEnc))
+	fmt.Fprintf(s.out, "%s knocked out a wild %s (Lv. %d)\n", p.Species, foe.Name, foe.Level)
+	gained := gainEVs(p, foe.EffortYield)
+	for _, name := range statNames {
+		if n := gained[name]; n > 0 {
+			fmt.Fprintf(s.out, "%s gained %d %s EVs (%d/%d)\n", p.Species, n, name, p.EVs[name], maxStatEVs)
+		}
+	}
+	exp := defeatExp(foe, false)
+	if p.Item == battle.LuckyEgg {
+		exp = exp * 3 / 2
+	}
+	gainExp(s, p, exp)
+	return nil
+}
+
+// autoTrain has p battle wild foes, one after another, until it has fought
+// battles of them, can't gain any more EVs in stat or faints.
+func autoTrain(s *session, p *profile.Pokemon, stat string, foe pokeapi.PokemonType, enc pokeapi.PokemonEncounter, battles int) error {
+	report := newAutoReport(s, []*profile.Pokemon{p})
+	evs, won := p.EVs[stat], 0
+	fought := 0
+	for ; fought < battles; fought++ {
+		if p.EVs[stat] >= maxStatEVs || totalEVs(p) >= maxEVs {
+			break
+		}
+		member, err := ownBattler(s, s.profile, p)
+		if err != nil {
+			return err
+		}
+		if member.Fainted() {
+			break
+		}
+		player := &battle.Side{Name: s.profile.Name, Team: []*battle.Pokemon{member}}
+		opponent := &battle.Side{Team: []*battle.Pokemon{battler(foe, encounterLevel(enc))}}
+		b := autoPlay(s, player, opponent, battle.Single, battle.Random, nil)
+		if b.Winner == 0 {
+			won++
+		}
+		awardExperience(s, b.Battle)
+		keepHP(s, b.Battle)
+	}
+	fmt.Fprintf(s.out, "%s won %d of %d battles against wild %s, gaining %d %s EVs (%d/%d)\n", p.Species, won, fought, foe.Name, p.EVs[stat]-evs, stat, p.EVs[stat], maxStatEVs)
+	report.print(s)
+	return nil
+}
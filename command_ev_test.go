@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestTrain(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	if err := s.run("train pikachu --stat attack", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected training before setting off to fail")
+	}
+
+	s.profile.Location = "rock-tunnel-1f"
+	out := &bytes.Buffer{}
+	if err := s.run("train pikachu --stat atk", out); err != nil {
+		t.Fatalf("train returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "knocked out a wild machop") || !strings.Contains(out.String(), "gained 1 attack EVs (1/252)") {
+		t.Errorf("Expected pikachu to train on machop, got %q", out.String())
+	}
+	if err := s.run("train pikachu --stat special-attack", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected training a stat nothing here gives to fail")
+	}
+}
+
+func TestEVCaps(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	p := &s.profile.Pokemon[0]
+	p.EVs = map[string]int{"attack": 250, "speed": 252}
+	if got := addEVs(p, "attack", 10); got != 2 {
+		t.Errorf("Expected attack to stop at 252, got %d more", got)
+	}
+	if got := addEVs(p, "defense", 10); got != 6 {
+		t.Errorf("Expected the total to stop at 510, got %d more", got)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("ev pikachu", out); err != nil {
+		t.Fatalf("ev returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pikachu's EVs (510/510):\n") || !strings.Contains(out.String(), "  attack: 252/252\n") {
+		t.Errorf("Expected the EV spread, got %q", out.String())
+	}
+}
+
+func TestVitamins(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Money = 25000
+	// Nothing is on sale this day.
+	s.now = func() time.Time { return saleDay() }
+	if err := s.run("buy protein 3", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected buying more than the player can afford to fail")
+	}
+	if err := s.run("buy protein 2", &bytes.Buffer{}); err != nil {
+		t.Fatalf("buy returned error: %v", err)
+	}
+	if s.profile.Money != 5000 || s.profile.Inventory["protein"] != 2 {
+		t.Errorf("Expected 2 proteins for 20000, got %d and %d left", s.profile.Inventory["protein"], s.profile.Money)
+	}
+
+	if err := s.run("use protein pikachu", &bytes.Buffer{}); err != nil {
+		t.Fatalf("use returned error: %v", err)
+	}
+	if got := s.profile.Pokemon[0].EVs["attack"]; got != 10 {
+		t.Errorf("Expected a protein to add 10 attack EVs, got %d", got)
+	}
+	if s.profile.Inventory["protein"] != 1 {
+		t.Errorf("Expected the protein to be used up")
+	}
+	// 10 EVs are worth a point at level 50, on top of the base of 5.
+	if side, _ := playerSide(s); side.Team[0].Stats.Attack != 6 {
+		t.Errorf("Expected pikachu's attack to be 6, got %d", side.Team[0].Stats.Attack)
+	}
+}
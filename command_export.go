@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/qr"
+	"github.com/azs06/pokedexcli/internal/xlsx"
+)
+
+const exportUsage = "export <" + selectorUsage + " [--qr]|--livingdex <file>>"
+
+// livingDexColumns is how many pokemon a row of a box holds; boxes are
+// six wide and five tall, as in the games.
+const livingDexColumns = 6
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "export",
+		usage:       exportUsage,
+		description: "Make share codes for pokemon, for other players to import, or a living dex spreadsheet",
+		minArgs:     1,
+		maxArgs:     -1,
+		callback:    commandExport,
+		complete: func(s *session, args []string) []string {
+			return append(completeCaught(s, args), "--type", "--shiny", "--not-shiny", "--team", "--box", "--keep-best", "--qr", "--livingdex")
+		},
+	})
+}
+
+// commandExport prints a share code for each pokemon the selector picks,
+// and with --qr a QR code of it too, to scan on a phone. Exporting leaves
+// the pokemon where it is.
+func commandExport(s *session, args ...string) error {
+	if args[0] == "--livingdex" {
+		if len(args) != 2 {
+			return errors.New("usage: " + exportUsage)
+		}
+		return exportLivingDex(s, s.verbatim(args[1]))
+	}
+	picked, rest, err := pickOwned(s, args)
+	if err != nil {
+		return err
+	}
+	withQR := slices.Contains(rest, "--qr")
+	if rest = slices.DeleteFunc(rest, func(arg string) bool { return arg == "--qr" }); len(rest) > 0 {
+		return errors.New("usage: " + exportUsage)
+	}
+	key := shareKey(s)
+	for _, p := range picked {
+		code, err := encodeShare(key, *p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%s:\n%s\n", ownedText(s, p), code)
+		if !withQR {
+			continue
+		}
+		c, err := qr.Encode([]byte(code))
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Species, err)
+		}
+		fmt.Fprint(s.out, c.Terminal())
+	}
+	return nil
+}
+
+// exportLivingDex writes the national dex to path laid out in boxes, as
+// collectors keep a living dex: one of every species, in order. Species
+// the player owns now are marked ●, and ★ if one is shiny. Files ending in
+// .xlsx are Excel workbooks with those cells filled in; anything else is
+// CSV.
+func exportLivingDex(s *session, path string) error {
+	if s.readOnly {
+		return errors.New("files can't be written in read-only mode")
+	}
+	rows, owned, err := livingDexSheet(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".xlsx") {
+		err = xlsx.Write(f, "Living dex", rows)
+	} else {
+		w := csv.NewWriter(f)
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				record[i] = cell.Text
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		err = w.Error()
+	}
+	if err := errors.Join(err, f.Close()); err != nil {
+		return err
+	}
+	total := generations[len(generations)-1].last
+	fmt.Fprintf(s.out, "Wrote the living dex to %s: %d boxes, %d/%d species owned\n", path, (total+boxSize-1)/boxSize, owned, total)
+	return nil
+}
+
+// livingDexSheet lays the national dex out in boxes of boxSize. Species
+// are named where the player has met them or the data source lists them
+// without fetching; the rest are only numbered.
+func livingDexSheet(s *session) (rows [][]xlsx.Cell, owned int, err error) {
+	ids := map[string]int{}
+	idOf := func(name string) (int, error) {
+		if id, ok := ids[name]; ok {
+			return id, nil
+		}
+		species, err := s.source.Species(name)
+		if err != nil && !errors.Is(err, pokeapi.ErrNotFound) {
+			return 0, err
+		}
+		ids[name] = species.ID
+		return species.ID, nil
+	}
+
+	names, have, shiny := map[int]string{}, map[int]bool{}, map[int]bool{}
+	known := seenSpecies(s)
+	if lister, ok := s.source.(pokeapi.Lister); ok {
+		listed, err := lister.Names("pokemon-species")
+		if err != nil {
+			return nil, 0, err
+		}
+		known = append(known, listed...)
+	}
+	for _, name := range known {
+		id, err := idOf(name)
+		if err != nil {
+			return nil, 0, err
+		}
+		names[id] = name
+	}
+	for _, p := range s.profile.Pokemon {
+		name := p.Species
+		if entry, ok := s.profile.Pokedex[p.Species]; ok {
+			name = speciesName(entry)
+		}
+		id, err := idOf(name)
+		if err != nil {
+			return nil, 0, err
+		}
+		names[id], have[id] = name, true
+		shiny[id] = shiny[id] || p.Shiny
+	}
+	delete(have, 0)
+
+	total := generations[len(generations)-1].last
+	rows = append(rows, []xlsx.Cell{
+		{Text: fmt.Sprintf("Living dex: %d/%d owned", len(have), total), Style: xlsx.Bold},
+		{Text: "● owned", Style: xlsx.Green},
+		{Text: "★ shiny", Style: xlsx.Gold},
+	})
+	for first := 1; first <= total; first += boxSize {
+		last := min(first+boxSize-1, total)
+		rows = append(rows, nil, []xlsx.Cell{{Text: fmt.Sprintf("Box %d (%04d-%04d)", first/boxSize+1, first, last), Style: xlsx.Bold}})
+		for start := first; start <= last; start += livingDexColumns {
+			var row []xlsx.Cell
+			for n := start; n < start+livingDexColumns && n <= last; n++ {
+				cell := xlsx.Cell{Text: fmt.Sprintf("%04d", n)}
+				if names[n] != "" {
+					cell.Text += " " + names[n]
+				}
+				switch {
+				case shiny[n]:
+					cell.Text += " ●★"
+					cell.Style = xlsx.Gold
+				case have[n]:
+					cell.Text += " ●"
+					cell.Style = xlsx.Green
+				}
+				row = append(row, cell)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, len(have), nil
+}
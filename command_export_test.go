@@ -0,0 +1,130 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func TestExportImport(t *testing.T) {
+	red, blue := newTestSession(t), newTestSession(t)
+	red.profile.Name = "red"
+	caught := red.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 25)
+	p := red.profile.Get(caught.ID)
+	p.IVs = map[string]int{"hp": 31, "speed": 30}
+	p.Ribbons = []string{"cool-ribbon"}
+	p.Met = &profile.Met{Area: "viridian-forest-area", Ball: "poke-ball", Level: 5, Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	red.profile.Add(pokeapi.PokemonType{Name: "zubat"}, 3)
+
+	out := &bytes.Buffer{}
+	if err := red.run("export pikachu --qr", out); err != nil {
+		t.Fatalf("export returned error: %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if lines[0] != "#1 pikachu Lv. 25:" || !strings.Contains(out.String(), "█") {
+		t.Errorf("Expected pikachu's share code and a QR code, got %q", out.String())
+	}
+	code := lines[1]
+	if len(red.profile.Pokemon) != 2 {
+		t.Errorf("Expected exporting to leave the pokemon where it was")
+	}
+
+	blue.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 10)
+	out.Reset()
+	if err := blue.run("import "+code, out); err != nil {
+		t.Fatalf("import returned error: %v", err)
+	}
+	if want := "Imported #2 pikachu Lv. 25 (red (traded), met at Lv. 5 in viridian-forest-area on 2024-05-01 in a poke ball)\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	got := blue.profile.Get(2)
+	if got == nil || got.IVs["hp"] != 31 || len(got.Ribbons) != 1 || blue.profile.Pokedex["pikachu"].Name != "pikachu" {
+		t.Errorf("Expected pikachu to arrive as it was exported, got %+v", got)
+	}
+
+	// Change one character of the pokemon's data.
+	i := len(code) / 2
+	tampered := code[:i] + string("ab"[strings.IndexByte("ab", code[i])+1&1]) + code[i+1:]
+	if err := blue.run("import "+tampered, &bytes.Buffer{}); err == nil || len(blue.profile.Pokemon) != 2 {
+		t.Errorf("Expected a tampered code to be refused, got %v", err)
+	}
+	if err := blue.run("import pikachu", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a word that isn't a code to be refused")
+	}
+
+	blue.app.config = config.Config{ShareKey: "our league"}
+	if err := blue.run("import "+code, &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a code signed with another key to be refused")
+	}
+
+	// A code signed properly but for a pokemon that couldn't exist.
+	cheat, err := encodeShare(shareKey(blue), profile.Pokemon{Species: "mew", Level: 101})
+	if err != nil {
+		t.Fatalf("encodeShare returned error: %v", err)
+	}
+	if err := blue.run("import "+cheat, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "level 101") {
+		t.Errorf("Expected a level 101 mew to be refused, got %v", err)
+	}
+}
+
+func TestExportLivingDex(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "bulbasaur"}, 5)
+	caught := s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+	s.profile.Get(caught.ID).Shiny = true
+	s.profile.See("raichu")
+
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+	if err := s.run("export --livingdex "+filepath.Join(dir, "Dex.csv"), out); err != nil {
+		t.Fatalf("export --livingdex returned error: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "Dex.csv: 35 boxes, 2/1025 species owned\n") {
+		t.Errorf("Expected 35 boxes with two species owned, got %q", out.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "Dex.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Living dex: 2/1025 owned,● owned,★ shiny\n",
+		"Box 1 (0001-0030)\n0001 bulbasaur ●,0002,0003,0004,0005,0006\n",
+		"0025 pikachu ●★,0026 raichu,0027,",
+		"Box 35 (1021-1025)\n1021,1022,1023,1024,1025\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the sheet to have %q, got %q", want, data)
+		}
+	}
+
+	if err := s.run("export --livingdex "+filepath.Join(dir, "dex.xlsx"), &bytes.Buffer{}); err != nil {
+		t.Fatalf("export --livingdex returned error: %v", err)
+	}
+	z, err := zip.OpenReader(filepath.Join(dir, "dex.xlsx"))
+	if err != nil {
+		t.Fatalf("Expected an xlsx workbook, got %v", err)
+	}
+	defer z.Close()
+	sheet, err := z.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatalf("Expected the workbook to have a sheet, got %v", err)
+	}
+	data, _ = io.ReadAll(sheet)
+	if !strings.Contains(string(data), `s="3"><is><t xml:space="preserve">0025 pikachu ●★</t>`) {
+		t.Errorf("Expected pikachu's cell to be filled gold, got %s", data)
+	}
+
+	s.readOnly = true
+	if err := s.run("export --livingdex "+filepath.Join(dir, "guest.csv"), &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected read-only mode to refuse to write files")
+	}
+}
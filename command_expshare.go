@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// expShare is the key item that gives the pokemon that sat a battle out a
+// share of its experience.
+const expShare = "exp-share"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "expshare",
+		usage:       "expshare [on|off]",
+		description: "Switch the Exp. Share on or off",
+		maxArgs:     1,
+		callback:    commandExpShare,
+		complete: func(s *session, args []string) []string {
+			return []string{"on", "off"}
+		},
+	})
+}
+
+func commandExpShare(s *session, args ...string) error {
+	if s.profile.Inventory[expShare] == 0 {
+		return errors.New("you don't have an Exp. Share")
+	}
+	if len(args) > 0 {
+		switch args[0] {
+		case "on":
+			s.profile.ExpShare = true
+		case "off":
+			s.profile.ExpShare = false
+		default:
+			return fmt.Errorf("%s isn't on or off", args[0])
+		}
+	}
+	state := "off"
+	if s.profile.ExpShare {
+		state = "on"
+	}
+	fmt.Fprintf(s.out, "The Exp. Share is %s\n", state)
+	return nil
+}
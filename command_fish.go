@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// biteChance is how likely a cast is to hook something when there are
+// pokemon to hook.
+const biteChance = 0.7
+
+// rods are the fishing rods, by the PokeAPI encounter method each one is.
+var rods = map[string]string{
+	"old":   "old-rod",
+	"good":  "good-rod",
+	"super": "super-rod",
+}
+
+// waterMethods are the encounter methods of pokemon in the water.
+var waterMethods = []string{"old-rod", "good-rod", "super-rod", "surf"}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "fish",
+		usage:       "fish <old|good|super>",
+		description: "Cast a rod into the water where you are",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandFish,
+		complete: func(s *session, args []string) []string {
+			return []string{"old", "good", "super"}
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "surf",
+		description: "Surf the water where you are",
+		callback:    commandSurf,
+	})
+}
+
+// byMethod keeps the encounters that happen by method, with only the
+// details of that method, so their levels and odds are that method's.
+func byMethod(encounters []pokeapi.PokemonEncounter, method string) []pokeapi.PokemonEncounter {
+	kept := []pokeapi.PokemonEncounter{}
+	for _, enc := range encounters {
+		versions := []pokeapi.VersionEncounterDetail{}
+		for _, version := range enc.VersionDetails {
+			v := pokeapi.VersionEncounterDetail{Version: version.Version}
+			for _, detail := range version.EncounterDetails {
+				if detail.Method.Name == method {
+					v.EncounterDetails = append(v.EncounterDetails, detail)
+					v.MaxChance += detail.Chance
+				}
+			}
+			if len(v.EncounterDetails) > 0 {
+				versions = append(versions, v)
+			}
+		}
+		if len(versions) > 0 {
+			kept = append(kept, pokeapi.PokemonEncounter{Pokemon: enc.Pokemon, VersionDetails: versions})
+		}
+	}
+	return kept
+}
+
+// onLand drops the encounters that only happen on or in the water, which
+// fish and surf are for.
+func onLand(encounters []pokeapi.PokemonEncounter) []pokeapi.PokemonEncounter {
+	kept := []pokeapi.PokemonEncounter{}
+	for _, enc := range encounters {
+		water := len(enc.VersionDetails) > 0
+		for _, version := range enc.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				if !slices.Contains(waterMethods, detail.Method.Name) {
+					water = false
+				}
+			}
+		}
+		if !water {
+			kept = append(kept, enc)
+		}
+	}
+	return kept
+}
+
+// waterEncounters are the encounters by method in the area the player is
+// in.
+func waterEncounters(s *session, method string) ([]pokeapi.PokemonEncounter, error) {
+	if s.profile.Location == "" {
+		return nil, errors.New("travel somewhere first")
+	}
+	area, err := s.source.LocationArea(s.profile.Location)
+	if err != nil {
+		return nil, err
+	}
+	return byMethod(area.PokemonEncounters, method), nil
+}
+
+func commandFish(s *session, args ...string) error {
+	method, ok := rods[strings.TrimSuffix(args[0], "-rod")]
+	if !ok {
+		return fmt.Errorf("%s isn't a rod; try old, good or super", args[0])
+	}
+	encounters, err := waterEncounters(s, method)
+	if err != nil {
+		return err
+	}
+	if len(encounters) == 0 {
+		fmt.Fprintf(s.out, "There's nothing to fish for with the %s here\n", ballName(method))
+		return nil
+	}
+	fmt.Fprintf(s.out, "You cast the %s...\n", ballName(method))
+	enc, ok := pickEncounter(encounters)
+	if !ok || rand.Float64() >= biteChance {
+		fmt.Fprintln(s.out, "Not even a nibble...")
+		return nil
+	}
+	fmt.Fprintln(s.out, "Oh! A bite!")
+	return spawnEncounter(s, enc)
+}
+
+func commandSurf(s *session, args ...string) error {
+	encounters, err := waterEncounters(s, "surf")
+	if err != nil {
+		return err
+	}
+	if len(encounters) == 0 {
+		fmt.Fprintf(s.out, "There's no water to surf on in %s\n", s.profile.Location)
+		return nil
+	}
+	fmt.Fprintln(s.out, "You surf out across the water...")
+	enc, ok := pickEncounter(encounters)
+	if !ok || rand.Float64() >= encounterChance {
+		fmt.Fprintln(s.out, "Nothing turned up")
+		return nil
+	}
+	return spawnEncounter(s, enc)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFish(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Location = "rock-tunnel-1f"
+	out := &bytes.Buffer{}
+	if err := s.run("fish old", out); err != nil {
+		t.Fatalf("fish returned error: %v", err)
+	}
+	if out.String() != "There's nothing to fish for with the old rod here\n" || s.encounter != nil {
+		t.Errorf("Expected nothing to fish for in a tunnel, got %q", out.String())
+	}
+
+	s.profile.Location = "route-12-area"
+	if err := s.run("fish rusty", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected fishing with a rod that doesn't exist to fail")
+	}
+	for range 100 {
+		if err := s.run("fish good-rod", &bytes.Buffer{}); err != nil {
+			t.Fatalf("fish returned error: %v", err)
+		}
+		if s.encounter != nil {
+			break
+		}
+	}
+	if s.encounter == nil || s.encounter.species.Name != "poliwag" || s.encounter.level != 15 {
+		t.Fatalf("Expected to hook the good rod's level 15 poliwag, got %+v", s.encounter)
+	}
+}
+
+func TestSurf(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Location = "pallet-town-area"
+	out := &bytes.Buffer{}
+	if err := s.run("surf", out); err != nil {
+		t.Fatalf("surf returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "There's no water to surf on") {
+		t.Errorf("Expected no water in pallet town, got %q", out.String())
+	}
+
+	s.profile.Location = "route-12-area"
+	for range 100 {
+		if err := s.run("surf", &bytes.Buffer{}); err != nil {
+			t.Fatalf("surf returned error: %v", err)
+		}
+		if s.encounter != nil {
+			break
+		}
+	}
+	if s.encounter == nil || s.encounter.species.Name != "tentacool" {
+		t.Errorf("Expected to meet the tentacool on the water, got %+v", s.encounter)
+	}
+}
+
+func TestExploreStaysOnLand(t *testing.T) {
+	land := onLand(fakeEncounters["route-12-area"])
+	if len(land) != 1 || land[0].Pokemon.Name != "snorlax" {
+		t.Errorf("Expected only snorlax to be met on land, got %v", land)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "habitat",
+		usage:       "habitat <name>",
+		description: "List the pokemon living in a habitat, such as forest or cave",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandHabitat,
+		complete:    completeKnown("pokemon-habitat"),
+		paged:       true,
+	})
+	registerCommand(cliCommand{
+		name:        "shape",
+		usage:       "shape <name>",
+		description: "List the pokemon with a body shape, such as quadruped or wings",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandShape,
+		complete:    completeKnown("pokemon-shape"),
+		paged:       true,
+	})
+}
+
+func commandHabitat(s *session, args ...string) error {
+	habitat, err := s.source.Habitat(args[0])
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("there's no habitat called %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s habitat (%d pokemon):\n", habitat.Name, len(habitat.PokemonSpecies))
+	listSpecies(s, habitat.PokemonSpecies)
+	return nil
+}
+
+func commandShape(s *session, args ...string) error {
+	shape, err := s.source.Shape(args[0])
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("there's no shape called %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s shape (%d pokemon):\n", shape.Name, len(shape.PokemonSpecies))
+	listSpecies(s, shape.PokemonSpecies)
+	return nil
+}
+
+// listSpecies lists species a line each, marking those already caught.
+func listSpecies(s *session, species []pokeapi.Species) {
+	caught := caughtSpecies(s)
+	for _, sp := range species {
+		mark := ""
+		if slices.Contains(caught, sp.Name) {
+			mark = " (caught)"
+		}
+		fmt.Fprintf(s.out, "  - %s%s\n", sp.Name, mark)
+	}
+}
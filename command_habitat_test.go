@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestHabitat(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "zubat"}, 5)
+	out := &bytes.Buffer{}
+	if err := s.run("habitat cave", out); err != nil {
+		t.Fatalf("habitat returned error: %v", err)
+	}
+	want := "cave habitat (3 pokemon):\n  - zubat (caught)\n  - golbat\n  - diglett\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if err := s.run("habitat nonsense", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown habitat to fail")
+	}
+
+	out.Reset()
+	if err := s.run("inspect zubat", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Habitat: cave\n") {
+		t.Errorf("Expected inspect to show the habitat, got %q", out.String())
+	}
+}
+
+func TestShape(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("shape quadruped", out); err != nil {
+		t.Fatalf("shape returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "quadruped shape (2 pokemon):\n  - vulpix\n") {
+		t.Errorf("Expected the quadruped shape's members, got %q", out.String())
+	}
+	if err := s.run("shape nonsense", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown shape to fail")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// medicine is a healing item. It restores hp HP or share percent of the
+// pokemon's max HP, whichever is more; revives only work on fainted
+// pokemon and the rest only on ones still standing.
+type medicine struct {
+	hp     int
+	share  int
+	revive bool
+}
+
+func (m medicine) heal(maxHP int) int {
+	return max(m.hp, maxHP*m.share/100)
+}
+
+var medicines = map[string]medicine{
+	"potion":       {hp: 20},
+	"super-potion": {hp: 60},
+	"hyper-potion": {hp: 120},
+	"max-potion":   {share: 100},
+	"revive":       {share: 50, revive: true},
+	"max-revive":   {share: 100, revive: true},
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "heal",
+		description: "Restore your party to full health at the Pokémon Center",
+		callback:    commandHeal,
+	})
+}
+
+// inTown reports whether the player is in a town or city, which are where
+// the Pokémon Centers are.
+func inTown(s *session) (bool, error) {
+	if s.profile.Location == "" {
+		return false, nil
+	}
+	area, err := s.source.LocationArea(s.profile.Location)
+	if err != nil {
+		return false, err
+	}
+	name := area.Location.Name
+	return strings.HasSuffix(name, "-town") || strings.HasSuffix(name, "-city"), nil
+}
+
+// inBattle is p's battler if it's in the battle being fought.
+func inBattle(s *session, p *profile.Pokemon) (*battle.Pokemon, int, bool) {
+	if s.battle == nil {
+		return nil, 0, false
+	}
+	for i, member := range s.battle.mine().Team {
+		if member.ID == p.ID {
+			return member, i, true
+		}
+	}
+	return nil, 0, false
+}
+
+// hpOf is p's HP and max HP: in the battle being fought, if it's in it,
+// and otherwise what it had left after the last one.
+func hpOf(s *session, p *profile.Pokemon) (int, int, error) {
+	if member, _, ok := inBattle(s, p); ok {
+		return max(member.HP, 0), member.Stats.HP, nil
+	}
+	nature, err := natureOf(s, p.Nature)
+	if err != nil {
+		return 0, 0, err
+	}
+	full := ownStats(s.profile.Pokedex[p.Species], p, nature).HP
+	return max(full-p.Damage, 0), full, nil
+}
+
+// keepHP saves the HP the player's pokemon have left after a battle, so
+// they start the next one with it.
+func keepHP(s *session, b *battle.Battle) {
+	for _, member := range b.Sides[0].Team {
+		p := s.profile.Get(member.ID)
+		if p == nil {
+			continue
+		}
+		hp, full := max(member.HP, 0), member.Stats.HP
+		if member.Dynamaxed() {
+			hp, full = hp/2, full/2
+		}
+		p.Damage = full - hp
+	}
+}
+
+// useMedicine uses a healing item on p. In a battle it takes the turn of
+// the pokemon choosing an action.
+func useMedicine(s *session, item string, m medicine, p *profile.Pokemon) error {
+	hp, full, err := hpOf(s, p)
+	if err != nil {
+		return err
+	}
+	switch {
+	case m.revive && hp > 0:
+		return fmt.Errorf("%s hasn't fainted", p.Species)
+	case !m.revive && hp == 0:
+		return fmt.Errorf("%s has fainted; it needs a revive", p.Species)
+	case hp == full:
+		return fmt.Errorf("it won't have any effect on %s", p.Species)
+	}
+	if _, member, ok := inBattle(s, p); ok {
+		if s.tournament != nil || s.battle.link != nil {
+			return errors.New("items aren't allowed in tournament or link battles")
+		}
+		s.profile.Use(item)
+		return chooseAction(s, battle.Action{Kind: battle.UseItem, Item: ballName(item), Member: member, Heal: m.heal(full)})
+	}
+	s.profile.Use(item)
+	hp = min(hp+m.heal(full), full)
+	p.Damage = full - hp
+	fmt.Fprintf(s.out, "%s recovered to %d/%d HP\n", p.Species, hp, full)
+	return nil
+}
+
+func commandHeal(s *session, args ...string) error {
+	town, err := inTown(s)
+	if err != nil {
+		return err
+	}
+	if !town {
+		return errors.New("there's no Pokémon Center here; find a town or city")
+	}
+	if len(s.profile.Party) == 0 {
+		return errors.New("you don't have any pokemon to heal")
+	}
+	for _, p := range s.profile.PartyPokemon() {
+		p.Damage = 0
+	}
+	fmt.Fprintln(s.out, "Your pokemon have been restored to full health. We hope to see you again!")
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestMedicine(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	p := &s.profile.Pokemon[0]
+	p.Damage = 50
+	s.profile.Give("potion", 1)
+	s.profile.Give("revive", 1)
+	if err := s.run("use revive pikachu", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a revive not to work on a pokemon that hasn't fainted")
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("use potion pikachu", out); err != nil {
+		t.Fatalf("use returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pikachu recovered to 30/60 HP") || p.Damage != 30 {
+		t.Errorf("Expected the potion to restore 20 HP, got %q", out.String())
+	}
+
+	p.Damage = 60
+	out.Reset()
+	if err := s.run("party", out); err != nil {
+		t.Fatalf("party returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "0/60 HP, fainted") {
+		t.Errorf("Expected pikachu to show as fainted, got %q", out.String())
+	}
+	if _, err := playerSide(s); err == nil {
+		t.Errorf("Expected a party that has all fainted not to be able to battle")
+	}
+	if err := s.run("use revive pikachu", &bytes.Buffer{}); err != nil {
+		t.Fatalf("use returned error: %v", err)
+	}
+	if p.Damage != 30 {
+		t.Errorf("Expected the revive to restore half of pikachu's HP, got %d damage", p.Damage)
+	}
+}
+
+func TestHPLastsBetweenBattles(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Party = []int{s.profile.Pokemon[0].ID}
+	s.profile.Pokemon[0].Damage = 20
+	s.profile.Give("potion", 1)
+	if err := meetPokemon(s, pokeapi.PokemonType{Name: "magikarp"}, 5); err != nil {
+		t.Fatalf("meetPokemon returned error: %v", err)
+	}
+	if err := s.run("battle", &bytes.Buffer{}); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if hp := s.battle.Sides[0].Team[0].HP; hp != 40 {
+		t.Errorf("Expected pikachu to start the battle with 40/60 HP, got %d", hp)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("use potion 1", out); err != nil {
+		t.Fatalf("use returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "local used a potion on pikachu!") {
+		t.Errorf("Expected the potion to take pikachu's turn, got %q", out.String())
+	}
+	s.battle.Sides[0].Team[0].HP = 25
+	if err := s.run("forfeit", &bytes.Buffer{}); err != nil {
+		t.Fatalf("forfeit returned error: %v", err)
+	}
+	if got := s.profile.Pokemon[0].Damage; got != 35 {
+		t.Errorf("Expected pikachu to keep its HP after the battle, got %d damage", got)
+	}
+}
+
+func TestPokemonCenter(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Party = []int{s.profile.Pokemon[0].ID}
+	s.profile.Pokemon[0].Damage = 60
+	s.profile.Location = "rock-tunnel-1f"
+	if err := s.run("heal", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected there to be no Pokémon Center in Rock Tunnel")
+	}
+	s.profile.Location = "pallet-town-area"
+	if err := s.run("heal", &bytes.Buffer{}); err != nil {
+		t.Fatalf("heal returned error: %v", err)
+	}
+	if s.profile.Pokemon[0].Damage != 0 {
+		t.Errorf("Expected the party to be healed")
+	}
+}
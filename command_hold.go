@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "hold",
+		usage:       "hold <pokemon> <item>",
+		description: "Give a pokemon an item from your bag to hold",
+		minArgs:     2,
+		maxArgs:     2,
+		callback:    commandHold,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return completeCaught(s, args)
+			}
+			return slices.Sorted(maps.Keys(battle.HeldItems))
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "take",
+		usage:       "take <pokemon>",
+		description: "Put a pokemon's held item back in your bag",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandTake,
+		complete:    completeCaught,
+	})
+}
+
+// findPokemon looks a pokemon up by party slot, or by species, preferring
+// one in the party.
+func findPokemon(s *session, arg string) (*profile.Pokemon, error) {
+	party := s.profile.PartyPokemon()
+	if slot, err := strconv.Atoi(arg); err == nil {
+		if slot < 1 || slot > len(party) {
+			return nil, fmt.Errorf("there's no party slot %d", slot)
+		}
+		return party[slot-1], nil
+	}
+	for _, p := range party {
+		if p.Species == arg {
+			return p, nil
+		}
+	}
+	for i := range s.profile.Pokemon {
+		if p := &s.profile.Pokemon[i]; p.Species == arg {
+			return p, nil
+		}
+	}
+	owned := []string{}
+	for _, p := range s.profile.Pokemon {
+		owned = append(owned, p.Species)
+	}
+	species, err := match(s, "pokemon you have", arg, owned)
+	if errors.Is(err, errNoMatch) {
+		return nil, fmt.Errorf("you don't have a %s", arg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findPokemon(s, species)
+}
+
+func commandHold(s *session, args ...string) error {
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	item := args[1]
+	if _, ok := battle.HeldItems[item]; !ok {
+		return fmt.Errorf("%s can't be held", item)
+	}
+	if !s.profile.Use(item) {
+		return fmt.Errorf("you don't have a %s", ballName(item))
+	}
+	if p.Item != "" {
+		s.profile.Give(p.Item, 1)
+		fmt.Fprintf(s.out, "Took the %s from %s and put it in your bag.\n", ballName(p.Item), p.Species)
+	}
+	p.Item = item
+	fmt.Fprintf(s.out, "%s is now holding %s: it %s.\n", p.Species, ballName(item), battle.HeldItems[item])
+	return nil
+}
+
+func commandTake(s *session, args ...string) error {
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	if p.Item == "" {
+		return errors.New(p.Species + " isn't holding anything")
+	}
+	s.profile.Give(p.Item, 1)
+	fmt.Fprintf(s.out, "Took the %s from %s and put it in your bag.\n", ballName(p.Item), p.Species)
+	p.Item = ""
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestHoldAndTake(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "snorlax"}, 30)
+	if err := s.run("hold snorlax leftovers", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected holding an item that isn't in the bag to fail")
+	}
+	s.profile.Give(battle.Leftovers, 1)
+	if err := s.run("hold 1 leftovers", &bytes.Buffer{}); err != nil {
+		t.Fatalf("hold returned error: %v", err)
+	}
+	if s.profile.Pokemon[0].Item != battle.Leftovers || s.profile.Inventory[battle.Leftovers] != 0 {
+		t.Errorf("Expected the leftovers to move from the bag to snorlax")
+	}
+	if err := s.run("take snorlax", &bytes.Buffer{}); err != nil {
+		t.Fatalf("take returned error: %v", err)
+	}
+	if s.profile.Pokemon[0].Item != "" || s.profile.Inventory[battle.Leftovers] != 1 {
+		t.Errorf("Expected the leftovers to go back in the bag")
+	}
+}
+
+func TestLuckyEggBoostsExperience(t *testing.T) {
+	earned := func(item string) int {
+		s := newTestSession(t)
+		s.profile.Add(pokeapi.PokemonType{Name: "mew", BaseExperience: 100}, 5)
+		s.profile.Pokemon[0].Item = item
+		foe := &battle.Pokemon{Name: "rattata", Level: 14, BaseExperience: 50}
+		b := &battle.Battle{Sides: [2]*battle.Side{
+			{Team: []*battle.Pokemon{{ID: 1, HP: 10, Battled: true}}},
+			{Team: []*battle.Pokemon{foe}},
+		}}
+		awardExperience(s, b)
+		return s.profile.Pokemon[0].Exp - expForLevel("", 5)
+	}
+	plain, boosted := earned(""), earned(battle.LuckyEgg)
+	if plain != 100 || boosted != 150 {
+		t.Errorf("Expected 100 and 150 experience, got %d and %d", plain, boosted)
+	}
+}
+
+func TestExpShare(t *testing.T) {
+	s := newTestSession(t)
+	for _, name := range []string{"mew", "pikachu", "eevee"} {
+		s.profile.Add(pokeapi.PokemonType{Name: name, BaseExperience: 100}, 5)
+	}
+	s.profile.Pokemon[2].OT = "blue"
+	if err := s.run("expshare on", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected switching on an Exp. Share the player doesn't have to fail")
+	}
+	s.profile.Give(expShare, 1)
+	if err := s.run("expshare on", &bytes.Buffer{}); err != nil {
+		t.Fatalf("expshare returned error: %v", err)
+	}
+
+	foe := &battle.Pokemon{Name: "rattata", Level: 14, BaseExperience: 50}
+	b := &battle.Battle{Sides: [2]*battle.Side{
+		{Team: []*battle.Pokemon{{ID: 1, HP: 10, Battled: true}, {ID: 2, HP: 10}, {ID: 3, HP: 10}}},
+		{Team: []*battle.Pokemon{foe}},
+	}}
+	out := &bytes.Buffer{}
+	s.out = out
+	awardExperience(s, b)
+	for i, want := range []int{100, 50, 75} {
+		if got := s.profile.Pokemon[i].Exp - expForLevel("", 5); got != want {
+			t.Errorf("Expected %s to get %d experience, got %d", s.profile.Pokemon[i].Species, want, got)
+		}
+	}
+	if !strings.Contains(out.String(), "mew grew to level 6!") || !strings.Contains(out.String(), "eevee gained 75 experience") {
+		t.Errorf("Expected each pokemon to be told what it got, got %q", out.String())
+	}
+}
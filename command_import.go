@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const importUsage = "import <code...|--file <path> [--dry-run]>"
+
+// importColumns are the columns import --file reads; other trackers' extra
+// columns are left out.
+var importColumns = []string{"species", "level", "shiny", "nickname"}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "import",
+		usage:       importUsage,
+		description: "Add pokemon other players shared with export, or a collection from a CSV or JSON file",
+		minArgs:     1,
+		maxArgs:     -1,
+		callback:    commandImport,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return []string{"--file"}
+			}
+			if args[0] == "--file" && len(args) == 2 {
+				return []string{"--dry-run"}
+			}
+			return nil
+		},
+	})
+}
+
+// commandImport adds the pokemon in each share code as it was exported:
+// its trainer, where it was met, its ribbons and its held item. Every code
+// is checked before any pokemon is added.
+func commandImport(s *session, args ...string) error {
+	if args[0] == "--file" {
+		return importFile(s, args[1:])
+	}
+	key := shareKey(s)
+	mons := make([]profile.Pokemon, len(args))
+	species := make([]pokeapi.PokemonType, len(args))
+	for i, code := range args {
+		mon, err := decodeShare(key, code)
+		if err != nil {
+			return fmt.Errorf("code %d: %w", i+1, err)
+		}
+		entry, err := s.source.Pokemon(mon.Species)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("code %d: there's no pokemon called %s", i+1, mon.Species)
+		}
+		if err != nil {
+			return err
+		}
+		mons[i], species[i] = mon, entry
+	}
+	for i, mon := range mons {
+		mon = s.profile.Receive(mon, species[i])
+		line := "Imported " + ownedText(s, &mon)
+		if met := metText(s, &mon); met != "" {
+			line += " (" + met + ")"
+		}
+		fmt.Fprintln(s.out, line)
+	}
+	return nil
+}
+
+// importRecord is a row of a file to import: where it is in the file, for
+// the report, and its cells by column.
+type importRecord struct {
+	label string
+	cells map[string]string
+}
+
+// importFile adds the pokemon listed in a CSV or JSON file, as another
+// tracker exported them, to the player's own. Every row is checked and
+// reported on first; with --dry-run that's all, and otherwise nothing is
+// added unless every row is fine.
+func importFile(s *session, args []string) error {
+	dryRun := slices.Contains(args, "--dry-run")
+	args = slices.DeleteFunc(args, func(arg string) bool { return arg == "--dry-run" })
+	if len(args) != 1 {
+		return errors.New("usage: " + importUsage)
+	}
+	path := s.verbatim(args[0])
+	records, ignored, err := readImportFile(path)
+	if err != nil {
+		return err
+	}
+	if len(ignored) > 0 {
+		fmt.Fprintf(s.out, "Ignoring columns: %s\n", strings.Join(ignored, ", "))
+	}
+
+	type imported struct {
+		mon     profile.Pokemon
+		species pokeapi.PokemonType
+	}
+	var good []imported
+	problems := 0
+	for _, r := range records {
+		mon, species, err := importPokemon(s, r.cells)
+		if err != nil {
+			problems++
+			fmt.Fprintf(s.out, "%s: %v\n", r.label, err)
+			continue
+		}
+		good = append(good, imported{mon, species})
+		line := fmt.Sprintf("%s: %s%s Lv. %d", r.label, mon.Species, shinyMark(s, mon.Shiny), mon.Level)
+		if mon.Nickname != "" {
+			line += fmt.Sprintf(" %q", mon.Nickname)
+		}
+		fmt.Fprintln(s.out, line)
+	}
+
+	switch {
+	case dryRun:
+		fmt.Fprintf(s.out, "%d pokemon would be imported, %d rows have problems\n", len(good), problems)
+		return nil
+	case problems > 0:
+		return fmt.Errorf("%d rows have problems; fix or remove them and import again, nothing was imported", problems)
+	}
+	for _, im := range good {
+		added := s.profile.Add(im.species, im.mon.Level)
+		p := s.profile.Get(added.ID)
+		p.Shiny, p.Nickname = im.mon.Shiny, im.mon.Nickname
+	}
+	fmt.Fprintf(s.out, "Imported %d pokemon from %s\n", len(good), filepath.Base(path))
+	return nil
+}
+
+// readImportFile reads the records of a JSON file, an array of objects, or
+// otherwise of a CSV file with a header row. ignored are the columns
+// import doesn't read.
+func readImportFile(path string) (records []importRecord, ignored []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	columns := map[string]bool{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var entries []map[string]any
+		if err := json.NewDecoder(f).Decode(&entries); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for i, entry := range entries {
+			cells := map[string]string{}
+			for column, value := range entry {
+				column = strings.ToLower(column)
+				columns[column] = true
+				if value != nil {
+					cells[column] = fmt.Sprint(value)
+				}
+			}
+			records = append(records, importRecord{label: fmt.Sprintf("Entry %d", i+1), cells: cells})
+		}
+	} else {
+		r := csv.NewReader(f)
+		r.FieldsPerRecord = -1
+		rows, err := r.ReadAll()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(rows) == 0 {
+			return nil, nil, fmt.Errorf("%s is empty", path)
+		}
+		header := rows[0]
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+			columns[header[i]] = true
+		}
+		for i, row := range rows[1:] {
+			cells := map[string]string{}
+			for j, cell := range row {
+				if j < len(header) {
+					cells[header[j]] = cell
+				}
+			}
+			records = append(records, importRecord{label: fmt.Sprintf("Row %d", i+2), cells: cells})
+		}
+	}
+	if !columns["species"] {
+		return nil, nil, fmt.Errorf("%s has no species column", path)
+	}
+	for column := range columns {
+		if !slices.Contains(importColumns, column) {
+			ignored = append(ignored, column)
+		}
+	}
+	slices.Sort(ignored)
+	return records, ignored, nil
+}
+
+// importPokemon checks the cells of a row and makes the pokemon they
+// describe. A row with no level is imported at wildLevel.
+func importPokemon(s *session, cells map[string]string) (profile.Pokemon, pokeapi.PokemonType, error) {
+	var mon profile.Pokemon
+	name := speciesSlug(cells["species"])
+	if name == "" {
+		return mon, pokeapi.PokemonType{}, errors.New("missing species")
+	}
+	species, err := importSpecies(s, name)
+	if err != nil {
+		return mon, species, err
+	}
+	mon.Species = species.Name
+	mon.Level = wildLevel
+	if level := strings.TrimSpace(cells["level"]); level != "" {
+		if mon.Level, err = strconv.Atoi(level); err != nil {
+			return mon, species, fmt.Errorf("level %q isn't a number", level)
+		}
+	}
+	switch shiny := strings.ToLower(strings.TrimSpace(cells["shiny"])); shiny {
+	case "", "false", "no", "n", "0":
+	case "true", "yes", "y", "1", "x", "shiny":
+		mon.Shiny = true
+	default:
+		return mon, species, fmt.Errorf("shiny %q isn't yes or no", shiny)
+	}
+	mon.Nickname = strings.TrimSpace(cells["nickname"])
+	return mon, species, mon.Validate()
+}
+
+// speciesSlug turns a species as other trackers write it, like "Mr. Mime"
+// or "Nidoran♀", into its PokeAPI name.
+func speciesSlug(name string) string {
+	name = strings.NewReplacer("♀", "-f", "♂", "-m", ".", "", "'", "", "’", "", ":", "").Replace(name)
+	return formName(strings.Join(strings.Fields(strings.ToLower(name)), "-"))
+}
+
+// importSpecies looks a pokemon up by name, or by species for species with
+// several forms, without asking which was meant.
+func importSpecies(s *session, name string) (pokeapi.PokemonType, error) {
+	p, err := s.source.Pokemon(name)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return p, err
+	}
+	species, err := s.source.Species(name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return p, fmt.Errorf("there's no pokemon called %s", name)
+	}
+	if err != nil {
+		return p, err
+	}
+	for _, v := range species.Varieties {
+		if v.IsDefault {
+			return s.source.Pokemon(v.Pokemon.Name)
+		}
+	}
+	return p, fmt.Errorf("there's no pokemon called %s", name)
+}
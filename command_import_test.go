@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportFile(t *testing.T) {
+	s := newTestSession(t)
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "Dump.csv")
+	data := "Species,Level,Shiny,Nickname,Box\nPikachu,25,yes,Sparky,1\nMr. Mime,,,,1\nmissingno,5,,,2\nzubat,120,no,,2\n"
+	if err := os.WriteFile(csvPath, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("import --file "+csvPath+" --dry-run", out); err != nil {
+		t.Fatalf("import --dry-run returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Ignoring columns: box\n",
+		"Row 2: pikachu ★ Lv. 25 \"Sparky\"\n",
+		"Row 3: mr-mime Lv. 5\n",
+		"Row 4: there's no pokemon called missingno\n",
+		"Row 5: level 120 is out of range\n",
+		"2 pokemon would be imported, 2 rows have problems\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the report to have %q, got %q", want, out.String())
+		}
+	}
+	if len(s.profile.Pokemon) != 0 {
+		t.Errorf("Expected a dry run to import nothing, got %+v", s.profile.Pokemon)
+	}
+	if err := s.run("import --file "+csvPath, &bytes.Buffer{}); err == nil || len(s.profile.Pokemon) != 0 {
+		t.Errorf("Expected nothing to be imported while rows have problems, got %v", err)
+	}
+
+	jsonPath := filepath.Join(dir, "dump.json")
+	data = `[{"species": "Pikachu", "level": 25, "shiny": true, "nickname": "Sparky"}, {"species": "Nidoran♀"}]`
+	if err := os.WriteFile(jsonPath, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := s.run("import --file "+jsonPath, out); err != nil {
+		t.Fatalf("import returned error: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "Imported 2 pokemon from dump.json\n") {
+		t.Errorf("Expected two pokemon imported, got %q", out.String())
+	}
+	p := s.profile.Get(1)
+	if p == nil || p.Species != "pikachu" || p.Level != 25 || !p.Shiny || p.Nickname != "Sparky" || p.OT != "local" {
+		t.Errorf("Expected Sparky the shiny pikachu, got %+v", p)
+	}
+	if p := s.profile.Get(2); p == nil || p.Species != "nidoran-f" || p.Level != wildLevel {
+		t.Errorf("Expected a nidoran-f at level %d, got %+v", wildLevel, p)
+	}
+}
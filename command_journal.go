@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+const journalUsage = "journal <show|export <file>>"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "journal",
+		usage:       journalUsage,
+		description: "Read your journal of catches and Hall of Fame entries, or save it as Markdown",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandJournal,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return []string{"export", "show"}
+			}
+			return nil
+		},
+	})
+}
+
+// subscribeJournal writes the notable events of the game to the player's
+// journal as they happen.
+func (s *session) subscribeJournal() {
+	s.bus.SubscribeAll(func(e events.Event) {
+		if s.journals == nil || s.readOnly {
+			return
+		}
+		line := journalLine(e)
+		if line == "" {
+			return
+		}
+		if err := s.journals.Append(s.profile.Name, e.Time, line); err != nil {
+			fmt.Fprintln(s.out, "Journal error:", err)
+		}
+	})
+}
+
+// journalLine is e as a journal line, or "" if it isn't worth writing down.
+func journalLine(e events.Event) string {
+	switch e.Kind {
+	case events.Caught:
+		line := "Caught " + e.Pokemon
+		if e.Shiny {
+			line = "Caught a **shiny** " + e.Pokemon
+		}
+		line += fmt.Sprintf(" (Lv. %d)", e.Level)
+		if e.Area != "" {
+			line += " in " + e.Area
+		}
+		if e.Shiny {
+			return line + "!"
+		}
+		return line + "."
+	case events.Milestone:
+		return fmt.Sprintf("Caught %d kinds of pokemon!", e.Count)
+	case events.HallOfFame:
+		team := strings.Join(e.Team, ", ")
+		if e.Competition != "" {
+			return fmt.Sprintf("Won the %s with %s!", e.Competition, team)
+		}
+		return fmt.Sprintf("Entered the Hall of Fame with %s!", team)
+	}
+	return ""
+}
+
+func commandJournal(s *session, args ...string) error {
+	if s.journals == nil {
+		return errors.New("journals aren't kept here")
+	}
+	journal, err := s.journals.Read(s.profile.Name)
+	if err != nil {
+		return err
+	}
+	switch {
+	case args[0] == "show" && len(args) == 1:
+		if journal == "" {
+			fmt.Fprintln(s.out, "Your journal is empty. Catch some pokemon to fill it!")
+			return nil
+		}
+		fmt.Fprint(s.out, journal)
+		return nil
+	case args[0] == "export" && len(args) == 2:
+		if s.readOnly {
+			return errors.New("files can't be written in read-only mode")
+		}
+		if journal == "" {
+			return errors.New("your journal is empty")
+		}
+		path := s.verbatim(args[1])
+		if err := os.WriteFile(path, []byte(journal), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Wrote your journal to %s\n", path)
+		return nil
+	}
+	return errors.New("usage: " + journalUsage)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/journal"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func TestJournal(t *testing.T) {
+	s := newTestSession(t)
+	s.journals = journal.NewStore(t.TempDir())
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	out := &bytes.Buffer{}
+	if err := s.run("journal show", out); err != nil {
+		t.Fatalf("journal show returned error: %v", err)
+	}
+	if out.String() != "Your journal is empty. Catch some pokemon to fill it!\n" {
+		t.Errorf("Unexpected empty journal %q", out.String())
+	}
+
+	s.publish(events.Event{Kind: events.Caught, Pokemon: "pidgey", Area: "route-1", Level: 3})
+	s.publish(events.Event{Kind: events.Explored, Area: "route-1"})
+	now = now.Add(26 * time.Hour)
+	s.publish(events.Event{Kind: events.Caught, Pokemon: "pikachu", Area: "viridian-forest", Level: 5, Shiny: true})
+	s.publish(events.Event{Kind: events.Milestone, Count: 10})
+	for range profile.PartySize {
+		s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	}
+	enterHallOfFame(s, "")
+
+	expected := "# local's journal\n" +
+		"\n## 2024-03-01\n\n" +
+		"- 09:30 Caught pidgey (Lv. 3) in route-1.\n" +
+		"\n## 2024-03-02\n\n" +
+		"- 11:30 Caught a **shiny** pikachu (Lv. 5) in viridian-forest!\n" +
+		"- 11:30 Caught 10 kinds of pokemon!\n" +
+		"- 11:30 Entered the Hall of Fame with magikarp, magikarp, magikarp, magikarp, magikarp, magikarp!\n"
+	out.Reset()
+	if err := s.run("journal show", out); err != nil {
+		t.Fatalf("journal show returned error: %v", err)
+	}
+	if out.String() != expected {
+		t.Errorf("Expected journal %q, got %q", expected, out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "Journal.md")
+	if err := s.run("journal export "+path, &bytes.Buffer{}); err != nil {
+		t.Fatalf("journal export returned error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != expected {
+		t.Errorf("Expected the exported journal to match, got %q, %v", data, err)
+	}
+
+	s.readOnly = true
+	s.publish(events.Event{Kind: events.Caught, Pokemon: "rattata", Level: 2})
+	if err := s.run("journal export "+path, &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected export to be refused in read-only mode")
+	}
+	if got, _ := s.journals.Read("local"); got != expected {
+		t.Errorf("Expected nothing to be written in read-only mode, got %q", got)
+	}
+}
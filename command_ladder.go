@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/ladder"
+	"github.com/azs06/pokedexcli/internal/link"
+)
+
+const ladderUsage = "ladder <join [addr] [--double]|standings|challenge [player]>"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "ladder",
+		usage:       ladderUsage,
+		description: "Find link battles on the ladder server and climb its ratings",
+		minArgs:     1,
+		maxArgs:     3,
+		callback:    commandLadder,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return []string{"challenge", "join", "standings"}
+			}
+			if args[0] == "join" {
+				return []string{"--double"}
+			}
+			return nil
+		},
+	})
+}
+
+// commandLadder uses the ladder server to find link battles. Players are
+// known on the ladder by their profile name, which the ladder gives them a
+// secret for the first time they use it.
+func commandLadder(s *session, args ...string) error {
+	c := ladder.NewClient(s.ladder)
+	switch {
+	case args[0] == "join":
+		return ladderJoin(s, c, args[1:])
+	case args[0] == "standings" && len(args) == 1:
+		return ladderStandings(s, c)
+	case args[0] == "challenge" && len(args) <= 2:
+		opponent := ""
+		if len(args) == 2 {
+			opponent = args[1]
+		}
+		return ladderChallenge(s, c, opponent)
+	}
+	return errors.New("usage: " + ladderUsage)
+}
+
+// ladderJoin hosts a link battle and waits on the ladder for a challenger
+// to be sent to it.
+func ladderJoin(s *session, c *ladder.Client, args []string) error {
+	addr := link.DefaultAddr
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		addr, args = args[0], args[1:]
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	_, format, err := parseBattleArgs(args, "easy")
+	if err != nil {
+		return err
+	}
+	joined, key := false, ""
+	b, hello, err := hostBattle(s, addr, format, func(listening net.Addr) error {
+		// Register the port actually listened on, in case it was picked
+		// for us.
+		_, port, _ := net.SplitHostPort(listening.String())
+		t, err := c.Join(s.profile.Name, ladderSecret(s), net.JoinHostPort(host, port))
+		if err != nil {
+			return err
+		}
+		keepLadderSecret(s, t.Secret)
+		joined, key = true, t.Key
+		fmt.Fprintf(s.out, "You're on the ladder with a rating of %d.\n", t.Player.Rating)
+		return nil
+	})
+	if err != nil {
+		if joined {
+			c.Leave(s.profile.Name, ladderSecret(s))
+		}
+		return err
+	}
+	if hello.Match == "" {
+		// Someone connected without going through the ladder.
+		c.Leave(s.profile.Name, ladderSecret(s))
+		fmt.Fprintln(s.out, "This battle wasn't set up by the ladder, so it won't be rated.")
+	}
+	b.onEnd = ladderResult(c, hello.Match, key)
+	openBattle(s, b)
+	return nil
+}
+
+// ladderChallenge has the ladder pick an opponent, or check the one named
+// is waiting, and joins their battle.
+func ladderChallenge(s *session, c *ladder.Client, opponent string) error {
+	if _, err := linkSide(s); err != nil {
+		return err
+	}
+	ch, err := c.Challenge(s.profile.Name, ladderSecret(s), opponent)
+	if err != nil {
+		return err
+	}
+	keepLadderSecret(s, ch.Secret)
+	fmt.Fprintf(s.out, "Challenging %s (rating %d)...\n", ch.Opponent.Name, ch.Opponent.Rating)
+	b, err := joinBattle(s, ch.Opponent.Addr, ch.Match)
+	if err != nil {
+		return err
+	}
+	b.onEnd = ladderResult(c, ch.Match, ch.Key)
+	openBattle(s, b)
+	return nil
+}
+
+// ladderSecret is the secret the ladder server gave the player's name, if
+// it has yet.
+func ladderSecret(s *session) string {
+	return s.profile.LadderSecrets[s.ladder]
+}
+
+// keepLadderSecret saves the secret the ladder server gave the player's
+// name, to prove it's theirs next time.
+func keepLadderSecret(s *session, secret string) {
+	if s.profile.LadderSecrets == nil {
+		s.profile.LadderSecrets = map[string]string{}
+	}
+	s.profile.LadderSecrets[s.ladder] = secret
+}
+
+// ladderResult reports the winner of a ladder match with the player's key
+// for it. Both players report; the ladder rates the match once they agree.
+func ladderResult(c *ladder.Client, match, key string) func(s *session, b *battle.Battle) error {
+	return func(s *session, b *battle.Battle) error {
+		if match == "" {
+			return nil
+		}
+		if !b.Over() {
+			fmt.Fprintln(s.out, "The battle didn't finish, so it wasn't rated.")
+			return nil
+		}
+		r, err := c.Report(match, key, b.Sides[b.Winner].Name)
+		if err != nil {
+			return err
+		}
+		if r.Winner == "" {
+			fmt.Fprintln(s.out, "The battle will be rated once your opponent reports the result too.")
+			return nil
+		}
+		for _, p := range r.Players {
+			if p.Name == s.profile.Name {
+				fmt.Fprintf(s.out, "Your ladder rating is now %d (%d wins, %d losses)\n", p.Rating, p.Wins, p.Losses)
+			}
+		}
+		return nil
+	}
+}
+
+func ladderStandings(s *session, c *ladder.Client) error {
+	players, err := c.Standings()
+	if err != nil {
+		return err
+	}
+	if len(players) == 0 {
+		fmt.Fprintln(s.out, "Nobody is on the ladder yet. Join it with ladder join.")
+		return nil
+	}
+	for i, p := range players {
+		line := fmt.Sprintf("%d. %s: %d (%d wins, %d losses)", i+1, p.Name, p.Rating, p.Wins, p.Losses)
+		if p.Addr != "" {
+			line += ", waiting for a battle"
+		}
+		if p.Name == s.profile.Name {
+			line += " <- you"
+		}
+		fmt.Fprintln(s.out, line)
+	}
+	return nil
+}
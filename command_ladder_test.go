@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/ladder"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestLadder(t *testing.T) {
+	sv, err := ladder.NewServer("")
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+
+	host, guest := newTestSession(t), newTestSession(t)
+	host.profile.Name, guest.profile.Name = "red", "blue"
+	host.ladder, guest.ladder = ts.URL, ts.URL
+	host.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	guest.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 50)
+	if err := guest.run("ladder challenge", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a challenge with nobody on the ladder to fail")
+	}
+
+	joined := make(chan error, 1)
+	go func() { joined <- host.run("ladder join 127.0.0.1:0", &bytes.Buffer{}) }()
+	for try := 0; ; try++ {
+		err := guest.run("ladder challenge red", &bytes.Buffer{})
+		if err == nil {
+			break
+		}
+		if try == 50 {
+			t.Fatalf("ladder challenge returned error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := <-joined; err != nil {
+		t.Fatalf("ladder join returned error: %v", err)
+	}
+
+	for turn := 0; host.battle != nil || guest.battle != nil; turn++ {
+		if turn == 100 {
+			t.Fatalf("Expected the battle to end")
+		}
+		playBoth(t, host, guest, "fight tackle")
+	}
+	if host.profile.LadderSecrets[ts.URL] == "" || guest.profile.LadderSecrets[ts.URL] == "" {
+		t.Errorf("Expected both players to keep the secret for their name, got %v and %v", host.profile.LadderSecrets, guest.profile.LadderSecrets)
+	}
+	out := &bytes.Buffer{}
+	if err := guest.run("ladder standings", out); err != nil {
+		t.Fatalf("ladder standings returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), ": 1016 (1 wins, 0 losses)") || !strings.Contains(out.String(), ": 984 (0 wins, 1 losses)") {
+		t.Errorf("Expected the match to be rated once, got %q", out.String())
+	}
+}
+
+func TestServerModes(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("server --mode arena", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown server mode to fail")
+	}
+}
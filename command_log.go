@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/logfile"
+)
+
+const (
+	// defaultLogMB, defaultLogDays and defaultLogKeep are when the log is
+	// rotated and how many old ones are kept, when the config doesn't say.
+	defaultLogMB   = 10
+	defaultLogDays = 7
+	defaultLogKeep = 3
+	// defaultLogTail is how many entries log tail shows.
+	defaultLogTail = 20
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "log",
+		usage:       "log tail [n]",
+		description: "Show the last entries of the log: your commands, the requests they made and errors",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandLog,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return []string{"tail"}
+			}
+			return nil
+		},
+	})
+}
+
+func commandLog(s *session, args ...string) error {
+	if args[0] != "tail" {
+		return errors.New("usage: log tail [n]")
+	}
+	n := defaultLogTail
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return fmt.Errorf("%s isn't a number of entries", args[1])
+		}
+	}
+	if s.app.logPath == "" {
+		return errors.New("there's no log here")
+	}
+	lines, err := logfile.Lines(s.app.logPath)
+	if err != nil {
+		return err
+	}
+
+	// Other players' commands are left out, along with the requests they
+	// made, which are known by their commands' traces.
+	entries := make([]logEntry, 0, len(lines))
+	traces := map[string]bool{}
+	for _, line := range lines {
+		e, err := parseLogEntry(line)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, e)
+		if e.get("session") == s.id {
+			traces[e.get("trace")] = true
+		}
+	}
+	var mine []logEntry
+	for _, e := range entries {
+		if e.get("session") == s.id || (e.get("session") == "" && traces[e.get("trace")]) {
+			mine = append(mine, e)
+		}
+	}
+	if len(mine) == 0 {
+		fmt.Fprintln(s.out, "The log has nothing of yours yet.")
+		return nil
+	}
+	for _, e := range mine[max(len(mine)-n, 0):] {
+		fmt.Fprintln(s.out, e.format(s.now().Location()))
+	}
+	return nil
+}
+
+// secretFlags are the flags whose values logArgs leaves out.
+var secretFlags = map[string]bool{"--token": true}
+
+// logArgs is args as they're logged: without the values of secretFlags,
+// and URLs without their passwords.
+func logArgs(args []string) []string {
+	logged := slices.Clone(args)
+	for i, arg := range logged {
+		if i > 0 && secretFlags[logged[i-1]] {
+			logged[i] = "[redacted]"
+		} else if u, err := url.Parse(arg); err == nil && u.User != nil {
+			logged[i] = u.Redacted()
+		}
+	}
+	return logged
+}
+
+// logField is one field of a log entry, its value as it's written in JSON.
+type logField struct {
+	key   string
+	value json.RawMessage
+}
+
+// logEntry is a line of the log, its fields in the order they were written.
+type logEntry []logField
+
+func parseLogEntry(line string) (logEntry, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	var e logEntry
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := t.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		e = append(e, logField{key, value})
+	}
+	return e, nil
+}
+
+// get is the field's value if it's a string, or "".
+func (e logEntry) get(key string) string {
+	for _, f := range e {
+		if f.key == key {
+			var s string
+			json.Unmarshal(f.value, &s)
+			return s
+		}
+	}
+	return ""
+}
+
+// format is e as "time LEVEL message key=value...", the time in loc.
+// Sessions go without saying, as only the player's own are shown.
+func (e logEntry) format(loc *time.Location) string {
+	var b strings.Builder
+	if t, err := time.Parse(time.RFC3339Nano, e.get("time")); err == nil {
+		b.WriteString(t.In(loc).Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(&b, " %-5s %s", e.get("level"), e.get("msg"))
+	for _, f := range e {
+		switch f.key {
+		case "time", "level", "msg", "session":
+			continue
+		}
+		// Strings are quoted only when they need to be.
+		value := string(f.value)
+		var s string
+		if json.Unmarshal(f.value, &s) == nil && s != "" && !strings.ContainsAny(s, " \"=") {
+			value = s
+		}
+		fmt.Fprintf(&b, " %s=%s", f.key, value)
+	}
+	return b.String()
+}
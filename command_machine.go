@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "machine",
+		usage:       "machine <tm-number|move>",
+		description: "Show which TM teaches which move in each game",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandMachine,
+	})
+	registerCommand(cliCommand{
+		name:        "teach",
+		usage:       "teach <pokemon> <tm> [forget-move]",
+		description: "Use a TM or HM from your bag to teach a pokemon its move",
+		minArgs:     2,
+		maxArgs:     3,
+		callback:    commandTeach,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return completeCaught(s, args)
+			}
+			names := []string{}
+			for item := range s.profile.Inventory {
+				if isMachine(item) {
+					names = append(names, item)
+				}
+			}
+			slices.Sort(names)
+			return names
+		},
+	})
+}
+
+// isMachine reports whether item is named like a TM, TR or HM.
+func isMachine(item string) bool {
+	for _, prefix := range []string{"tm", "tr", "hm"} {
+		if n, ok := strings.CutPrefix(item, prefix); ok {
+			if _, err := strconv.Atoi(n); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// machineItem reads a TM number, either "24" or "tm24", as the TM's item
+// name.
+func machineItem(arg string) (string, bool) {
+	arg = strings.ToLower(arg)
+	if n, err := strconv.Atoi(arg); err == nil {
+		return fmt.Sprintf("tm%02d", n), true
+	}
+	return arg, isMachine(arg)
+}
+
+// machines looks up every machine in versions, in order.
+func machines(s *session, versions []pokeapi.MachineVersion) ([]pokeapi.MachineDetail, error) {
+	found := []pokeapi.MachineDetail{}
+	for _, v := range versions {
+		m, err := s.source.Machine(v.ID())
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, m)
+	}
+	return found, nil
+}
+
+func commandMachine(s *session, args ...string) error {
+	if item, ok := machineItem(args[0]); ok {
+		detail, err := s.source.Item(item)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("there's no %s", strings.ToUpper(item))
+		}
+		if err != nil {
+			return err
+		}
+		found, err := machines(s, detail.Machines)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%s:\n", strings.ToUpper(item))
+		for _, m := range found {
+			fmt.Fprintf(s.out, "  %s: %s\n", m.VersionGroup.Name, m.Move.Name)
+		}
+		return nil
+	}
+
+	move, err := s.source.Move(args[0])
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("there's no move called %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	if len(move.Machines) == 0 {
+		fmt.Fprintf(s.out, "%s isn't taught by any machine\n", move.Name)
+		return nil
+	}
+	found, err := machines(s, move.Machines)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s:\n", move.Name)
+	for _, m := range found {
+		fmt.Fprintf(s.out, "  %s: %s\n", m.VersionGroup.Name, strings.ToUpper(m.Item.Name))
+	}
+	return nil
+}
+
+// battleMove turns a PokeAPI move into one the battle engine can use.
+func battleMove(m pokeapi.MoveDetail) battle.Move {
+	if field, ok := battle.FieldMove(m.Name); ok {
+		return field
+	}
+	move := battle.Move{
+		Name:     m.Name,
+		Type:     m.Type.Name,
+		Power:    m.Power,
+		Accuracy: m.Accuracy,
+		Class:    battle.Class(m.DamageClass.Name),
+	}
+	switch target := battle.Target(m.Target.Name); target {
+	case battle.AllOpponents, battle.AllOther:
+		move.Target = target
+	}
+	return move
+}
+
+// learnsByMachine reports whether species can be taught move with a machine
+// in any game.
+func learnsByMachine(s *session, species, move string) (bool, error) {
+	moves, err := s.source.PokemonMoves(species)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range moves {
+		if m.Move.Name != move {
+			continue
+		}
+		for _, detail := range m.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "machine" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// knownMoves are the moves p fights with.
+func knownMoves(s *session, p *profile.Pokemon) []battle.Move {
+	if len(p.Moves) > 0 {
+		return p.Moves
+	}
+	return battle.DefaultMoves(typeNames(s.profile.Pokedex[p.Species]), p.Level)
+}
+
+func commandTeach(s *session, args ...string) error {
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	item := strings.ToLower(args[1])
+	if s.profile.Inventory[item] == 0 {
+		return fmt.Errorf("you don't have a %s", strings.ToUpper(item))
+	}
+	detail, err := s.source.Item(item)
+	if err != nil {
+		return err
+	}
+	if len(detail.Machines) == 0 {
+		return fmt.Errorf("%s isn't a TM or HM", ballName(item))
+	}
+
+	// Machines have been renumbered over the years; the newest game decides
+	// what this one teaches.
+	latest := slices.MaxFunc(detail.Machines, func(a, b pokeapi.MachineVersion) int {
+		return a.ID() - b.ID()
+	})
+	machine, err := s.source.Machine(latest.ID())
+	if err != nil {
+		return err
+	}
+	name := machine.Move.Name
+
+	ok, err := learnsByMachine(s, p.Species, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s can't learn %s", p.Species, name)
+	}
+	moves := slices.Clone(knownMoves(s, p))
+	if slices.ContainsFunc(moves, func(m battle.Move) bool { return m.Name == name }) {
+		return fmt.Errorf("%s already knows %s", p.Species, name)
+	}
+	details, err := s.source.Move(name)
+	if err != nil {
+		return err
+	}
+	move := battleMove(details)
+
+	if len(moves) < battle.MaxMoves {
+		moves = append(moves, move)
+	} else {
+		if len(args) < 3 {
+			return fmt.Errorf("%s already knows %d moves; name one to forget: teach %s %s <move>", p.Species, battle.MaxMoves, args[0], item)
+		}
+		i := slices.IndexFunc(moves, func(m battle.Move) bool { return m.Name == args[2] })
+		if n, err := strconv.Atoi(args[2]); err == nil && n >= 1 && n <= len(moves) {
+			i = n - 1
+		}
+		if i < 0 {
+			return fmt.Errorf("%s doesn't know %s", p.Species, args[2])
+		}
+		fmt.Fprintf(s.out, "%s forgot %s.\n", p.Species, moves[i].Name)
+		moves[i] = move
+	}
+	p.Moves = moves
+	// HMs can be used again and again; TMs and TRs are used up.
+	if !strings.HasPrefix(item, "hm") {
+		s.profile.Use(item)
+	}
+	fmt.Fprintf(s.out, "%s learned %s!\n", p.Species, name)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestMachine(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("machine 24", out); err != nil {
+		t.Fatalf("machine returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "TM24:\n  red-blue: thunderbolt\n  firered-leafgreen: thunderbolt\n") {
+		t.Errorf("Expected TM24 listed per version group, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := s.run("machine surf", out); err != nil {
+		t.Fatalf("machine returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "surf:\n  firered-leafgreen: HM03\n") {
+		t.Errorf("Expected surf's HM listed, got %q", out.String())
+	}
+}
+
+func TestTeach(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	if err := s.run("teach pikachu tm24", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected teaching a TM that isn't in the bag to fail")
+	}
+
+	s.profile.Give("tm24", 1)
+	s.profile.Give("hm03", 1)
+	if err := s.run("teach pikachu hm03", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected pikachu not to learn surf")
+	}
+	if err := s.run("teach pikachu tm24", &bytes.Buffer{}); err != nil {
+		t.Fatalf("teach returned error: %v", err)
+	}
+	moves := s.profile.Pokemon[0].Moves
+	if len(moves) != 2 || moves[1].Name != "thunderbolt" || moves[1].Class != battle.Special {
+		t.Errorf("Expected pikachu to know tackle and thunderbolt, got %v", moves)
+	}
+	if s.profile.Inventory["tm24"] != 0 || s.profile.Inventory["hm03"] != 1 {
+		t.Errorf("Expected the TM to be used up and the HM kept")
+	}
+	if side, _ := playerSide(s); len(side.Team[0].Moves) != 2 {
+		t.Errorf("Expected pikachu to fight with its taught moves, got %v", side.Team[0].Moves)
+	}
+}
+
+func TestTeachForgetsAMove(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	s.profile.Pokemon[0].Moves = []battle.Move{{Name: "tackle"}, {Name: "growl"}, {Name: "tail-whip"}, {Name: "thunder-shock"}}
+	s.profile.Give("tm24", 1)
+	if err := s.run("teach pikachu tm24", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected teaching a fifth move without forgetting one to fail")
+	}
+	if err := s.run("teach pikachu tm24 growl", &bytes.Buffer{}); err != nil {
+		t.Fatalf("teach returned error: %v", err)
+	}
+	if got := s.profile.Pokemon[0].Moves[1].Name; got != "thunderbolt" {
+		t.Errorf("Expected thunderbolt in growl's place, got %s", got)
+	}
+}
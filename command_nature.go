@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// natures are the 25 natures, in PokeAPI's order. A nature raises one stat by
+// 10% and lowers another by 10%; the five that would cancel out are neutral.
+var natures = []string{
+	"hardy", "bold", "modest", "calm", "timid",
+	"lonely", "docile", "mild", "gentle", "hasty",
+	"adamant", "impish", "bashful", "careful", "rash",
+	"jolly", "naughty", "lax", "quirky", "naive",
+	"brave", "relaxed", "quiet", "sassy", "serious",
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "nature",
+		usage:       "nature <list|name>",
+		description: "Show which stats a nature raises and lowers",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandNature,
+		complete: func(s *session, args []string) []string {
+			return append([]string{"list"}, natures...)
+		},
+	})
+}
+
+func rollNature() string {
+	return natures[rand.IntN(len(natures))]
+}
+
+// natureOf looks a nature up. Pokemon caught before natures existed have
+// none, which works like a neutral one.
+func natureOf(s *session, name string) (pokeapi.NatureDetail, error) {
+	if name == "" {
+		return pokeapi.NatureDetail{}, nil
+	}
+	return s.source.Nature(name)
+}
+
+// statField is the stat of stats PokeAPI calls name, or nil for HP, which no
+// nature changes.
+func statField(stats *battle.Stats, name string) *int {
+	switch name {
+	case "attack":
+		return &stats.Attack
+	case "defense":
+		return &stats.Defense
+	case "special-attack":
+		return &stats.SpAttack
+	case "special-defense":
+		return &stats.SpDefense
+	case "speed":
+		return &stats.Speed
+	}
+	return nil
+}
+
+// applyNature raises and lowers stats by the nature's 10%.
+func applyNature(stats *battle.Stats, n pokeapi.NatureDetail) {
+	if up := statField(stats, n.IncreasedStat.Name); up != nil {
+		*up = *up * 110 / 100
+	}
+	if down := statField(stats, n.DecreasedStat.Name); down != nil {
+		*down = *down * 90 / 100
+	}
+}
+
+// natureText describes what a nature does, e.g. "+attack -special-attack,
+// likes spicy, hates dry".
+func natureText(n pokeapi.NatureDetail) string {
+	if n.IncreasedStat.Name == "" {
+		return "neutral"
+	}
+	text := fmt.Sprintf("+%s -%s", n.IncreasedStat.Name, n.DecreasedStat.Name)
+	if n.LikesFlavor.Name != "" {
+		text += fmt.Sprintf(", likes %s, hates %s", n.LikesFlavor.Name, n.HatesFlavor.Name)
+	}
+	return text
+}
+
+// statsText lists stats, marking the ones the nature raises with + and the
+// ones it lowers with -.
+func statsText(stats battle.Stats, n pokeapi.NatureDetail) string {
+	parts := []string{fmt.Sprintf("hp %d", stats.HP)}
+	for _, name := range []string{"attack", "defense", "special-attack", "special-defense", "speed"} {
+		part := fmt.Sprintf("%s %d", name, *statField(&stats, name))
+		switch name {
+		case n.IncreasedStat.Name:
+			part += "+"
+		case n.DecreasedStat.Name:
+			part += "-"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func commandNature(s *session, args ...string) error {
+	names := []string{args[0]}
+	if args[0] == "list" {
+		names = natures
+	}
+	for _, name := range names {
+		n, err := s.source.Nature(name)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("there's no %s nature", name)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%s: %s\n", n.Name, natureText(n))
+	}
+	return nil
+}
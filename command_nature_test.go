@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestNature(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("nature adamant", out); err != nil {
+		t.Fatalf("nature returned error: %v", err)
+	}
+	if got := out.String(); got != "adamant: +attack -special-attack, likes spicy, hates dry\n" {
+		t.Errorf("Expected adamant's modifiers, got %q", got)
+	}
+
+	out.Reset()
+	if err := s.run("nature list", out); err != nil {
+		t.Fatalf("nature returned error: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 25 {
+		t.Errorf("Expected 25 natures, got %d", lines)
+	}
+	if !strings.Contains(out.String(), "hardy: neutral\n") {
+		t.Errorf("Expected hardy to be neutral, got %q", out.String())
+	}
+}
+
+func TestNatureModifiesStats(t *testing.T) {
+	s := newTestSession(t)
+	species := pokeapi.PokemonType{Name: "machop", Stats: []pokeapi.StatDetail{
+		{BaseStat: 100, Stat: pokeapi.Stat{Name: "attack"}},
+		{BaseStat: 100, Stat: pokeapi.Stat{Name: "special-attack"}},
+		{BaseStat: 100, Stat: pokeapi.Stat{Name: "speed"}},
+	}}
+	s.profile.Add(species, 50)
+	s.profile.Pokemon[0].Nature = "adamant"
+
+	side, err := playerSide(s)
+	if err != nil {
+		t.Fatalf("playerSide returned error: %v", err)
+	}
+	stats := side.Team[0].Stats
+	if stats.Attack != 115 || stats.SpAttack != 94 || stats.Speed != 105 {
+		t.Errorf("Expected attack 115, special-attack 94 and speed 105, got %+v", stats)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("inspect machop", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "adamant nature (+attack -special-attack") ||
+		!strings.Contains(out.String(), "attack 115+,") || !strings.Contains(out.String(), "special-attack 94-,") {
+		t.Errorf("Expected inspect to show the nature and its stats, got %q", out.String())
+	}
+}
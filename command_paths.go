@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+)
+
+// gamePath is a file or directory the game uses.
+type gamePath struct {
+	name string
+	path string
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "paths",
+		description: "Show where the game keeps its files",
+		callback:    commandPaths,
+	})
+}
+
+func commandPaths(s *session, args ...string) error {
+	width := 0
+	for _, p := range s.app.paths {
+		width = max(width, len(p.name))
+	}
+	for _, p := range s.app.paths {
+		fmt.Fprintf(s.out, "%-*s  %s\n", width+1, p.name+":", p.path)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPaths(t *testing.T) {
+	s := newTestSession(t)
+	s.app.paths = []gamePath{{"config", "/etc/pokedex.json"}, {"profiles", "/data/profiles"}}
+	out := &bytes.Buffer{}
+	if err := s.run("paths", out); err != nil {
+		t.Fatalf("paths returned error: %v", err)
+	}
+	want := "config:    /etc/pokedex.json\nprofiles:  /data/profiles\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// generations are the national pokedex numbers each generation adds, up to
+// and including last.
+var generations = []struct{ first, last int }{
+	{1, 151}, {152, 251}, {252, 386}, {387, 493}, {494, 649},
+	{650, 721}, {722, 809}, {810, 905}, {906, 1025},
+}
+
+// livingDexRow is how many pokedex numbers fit on a row of the living dex
+// grid.
+const livingDexRow = 25
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "pokedex",
+		usage:       "pokedex [--living] [--caught-in <area>]",
+		description: "View your pokedex",
+		maxArgs:     3,
+		callback:    commandPokedex,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			if len(args) > 0 && args[0] == "--caught-in" {
+				return slices.Sorted(maps.Keys(s.profile.Visited))
+			}
+			return []string{"--living", "--caught-in"}
+		},
+	})
+}
+
+// speciesName is the species a pokedex entry belongs to, which for forms
+// isn't the entry's own name.
+func speciesName(p pokeapi.PokemonType) string {
+	if p.Species.Name != "" {
+		return p.Species.Name
+	}
+	return p.Name
+}
+
+// ownedCounts counts the pokemon owned of each pokedex entry.
+func ownedCounts(s *session) map[string]int {
+	counts := map[string]int{}
+	for _, p := range s.profile.Pokemon {
+		counts[p.Species]++
+	}
+	return counts
+}
+
+// caughtSpecies are the species with an entry in the pokedex.
+func caughtSpecies(s *session) []string {
+	species := []string{}
+	for _, p := range s.profile.Pokedex {
+		if name := speciesName(p); !slices.Contains(species, name) {
+			species = append(species, name)
+		}
+	}
+	return species
+}
+
+// seenSpecies are the species met so far, caught or not. Forms count as
+// their species where the pokedex knows it.
+func seenSpecies(s *session) []string {
+	species := caughtSpecies(s)
+	for name := range s.profile.Seen {
+		if p, ok := s.profile.Pokedex[name]; ok {
+			name = speciesName(p)
+		}
+		if !slices.Contains(species, name) {
+			species = append(species, name)
+		}
+	}
+	return species
+}
+
+func commandPokedex(s *session, args ...string) error {
+	switch {
+	case len(args) == 1 && args[0] == "--living":
+		return livingDex(s)
+	case len(args) == 2 && args[0] == "--caught-in":
+		return caughtIn(s, args[1])
+	case len(args) > 0:
+		return errors.New("usage: pokedex [--living] [--caught-in <area>]")
+	}
+
+	counts := ownedCounts(s)
+	fmt.Fprintf(s.out, "Your Pokedex: %d seen, %d species caught, %d pokemon in all\n", len(seenSpecies(s)), len(caughtSpecies(s)), len(s.profile.Pokemon))
+	for _, name := range slices.Sorted(maps.Keys(s.profile.Pokedex)) {
+		fmt.Fprint(s.out, " - ", typeIcons(s, typeNames(s.profile.Pokedex[name])))
+		if f := form(s.profile.Pokedex[name]); f != "" {
+			fmt.Fprintf(s.out, "%s (%s form)", s.profile.Pokedex[name].Species.Name, f)
+		} else {
+			fmt.Fprint(s.out, name)
+		}
+		if n := counts[name]; n > 1 {
+			fmt.Fprintf(s.out, " x%d (%d duplicate%s)", n, n-1, plural(n-1))
+		}
+		fmt.Fprintln(s.out)
+	}
+	return nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// livingDex shows a grid per generation of the pokedex numbers caught (●),
+// seen (○) and missing (·).
+func livingDex(s *session) error {
+	caught, seen := map[int]bool{}, map[int]bool{}
+	caughtNames := caughtSpecies(s)
+	for _, name := range seenSpecies(s) {
+		species, err := s.source.Species(name)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		seen[species.ID] = true
+		if slices.Contains(caughtNames, name) {
+			caught[species.ID] = true
+		}
+	}
+
+	total := generations[len(generations)-1].last
+	fmt.Fprintf(s.out, "Living dex: %d/%d species caught, %d seen, %d pokemon in all\n", len(caught), total, len(seen), len(s.profile.Pokemon))
+	for i, gen := range generations {
+		count, seenCount := 0, 0
+		for n := gen.first; n <= gen.last; n++ {
+			if caught[n] {
+				count++
+			}
+			if seen[n] {
+				seenCount++
+			}
+		}
+		if s.a11y {
+			fmt.Fprintf(s.out, "Generation %d: %d of %d caught, %d seen.\n", i+1, count, gen.last-gen.first+1, seenCount)
+			continue
+		}
+		fmt.Fprintf(s.out, "Gen %d (%d/%d):\n", i+1, count, gen.last-gen.first+1)
+		for row := gen.first; row <= gen.last; row += livingDexRow {
+			var cells strings.Builder
+			for n := row; n < row+livingDexRow && n <= gen.last; n++ {
+				switch {
+				case caught[n]:
+					cells.WriteString("●")
+				case seen[n]:
+					cells.WriteString("○")
+				default:
+					cells.WriteString("·")
+				}
+			}
+			fmt.Fprintf(s.out, " %4d %s\n", row, cells.String())
+		}
+	}
+	return nil
+}
+
+// metIn reports whether met is in area, which can leave off the "-area"
+// every location area's name ends in.
+func metIn(met *profile.Met, area string) bool {
+	return met != nil && met.Area != "" && (met.Area == area || met.Area == area+"-area")
+}
+
+// metText is who caught p and where and when, for inspect.
+func metText(s *session, p *profile.Pokemon) string {
+	var parts []string
+	if p.OT != "" {
+		ot := p.OT
+		if s.profile.Traded(p) {
+			ot += " (traded)"
+		}
+		parts = append(parts, ot)
+	}
+	if p.Met != nil {
+		met := fmt.Sprintf("met at Lv. %d", p.Met.Level)
+		if p.Met.Area != "" {
+			met += " in " + p.Met.Area
+		}
+		met += " on " + p.Met.Time.Format("2006-01-02")
+		if p.Met.Ball != "" {
+			met += " in a " + ballName(p.Met.Ball)
+		}
+		parts = append(parts, met)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// caughtIn lists the pokemon caught in area, in the order they were
+// caught.
+func caughtIn(s *session, area string) error {
+	var found []profile.Pokemon
+	for _, p := range s.profile.Pokemon {
+		if metIn(p.Met, area) {
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		fmt.Fprintf(s.out, "You haven't caught anything in %s\n", area)
+		return nil
+	}
+	fmt.Fprintf(s.out, "Caught in %s: %d pokemon\n", found[0].Met.Area, len(found))
+	for _, p := range found {
+		fmt.Fprintf(s.out, " - #%d %s Lv. %d, %s\n", p.ID, p.Species, p.Met.Level, p.Met.Time.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestPokedexDuplicates(t *testing.T) {
+	s := newTestSession(t)
+	pikachu := pokeapi.PokemonType{Name: "pikachu", Species: pokeapi.Species{Name: "pikachu"}}
+	s.profile.Add(pikachu, 5)
+	s.profile.Add(pikachu, 7)
+	s.profile.Add(pikachu, 9)
+	s.profile.Add(pokeapi.PokemonType{Name: "raichu", Species: pokeapi.Species{Name: "raichu"}}, 20)
+	s.profile.Add(pokeapi.PokemonType{Name: "raichu-alola", Species: pokeapi.Species{Name: "raichu"}}, 20)
+
+	out := &bytes.Buffer{}
+	if err := s.run("pokedex", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	want := "Your Pokedex: 2 seen, 2 species caught, 5 pokemon in all\n" +
+		" - pikachu x3 (2 duplicates)\n" +
+		" - raichu\n" +
+		" - raichu (alola form)\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestLivingDex(t *testing.T) {
+	s := newTestSession(t)
+	for _, name := range []string{"bulbasaur", "pikachu", "chikorita"} {
+		s.profile.Add(pokeapi.PokemonType{Name: name}, 5)
+	}
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+
+	out := &bytes.Buffer{}
+	if err := s.run("pokedex --living", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Living dex: 3/1025 species caught, 3 seen, 4 pokemon in all\n",
+		"Gen 1 (2/151):\n    1 ●·······················●\n   26 ·",
+		"Gen 2 (1/100):\n  152 ●·",
+		"Gen 3 (0/135):\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the living dex, got %q", want, out.String())
+		}
+	}
+}
+
+func TestPokedexSeen(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "bulbasaur"}, 5)
+	s.profile.See("pikachu")
+	s.profile.See("bulbasaur")
+
+	out := &bytes.Buffer{}
+	if err := s.run("pokedex", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	if want := "Your Pokedex: 2 seen, 1 species caught, 1 pokemon in all\n"; !strings.HasPrefix(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	if err := s.run("pokedex --living", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Living dex: 1/1025 species caught, 2 seen, 1 pokemon in all\n",
+		"Gen 1 (1/151):\n    1 ●·······················○\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the living dex, got %q", want, out.String())
+		}
+	}
+}
+
+func TestExploreMarksSeen(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("explore rock-tunnel-1f", &bytes.Buffer{}); err != nil {
+		t.Fatalf("explore returned error: %v", err)
+	}
+	for _, name := range []string{"zubat", "machop", "geodude"} {
+		if !s.profile.Seen[name] {
+			t.Errorf("Expected %s to be seen after exploring", name)
+		}
+	}
+	if len(s.profile.Pokedex) != 0 {
+		t.Errorf("Expected nothing caught by exploring, got %d", len(s.profile.Pokedex))
+	}
+}
+
+func TestCaughtIn(t *testing.T) {
+	s := newTestSession(t)
+	s.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	s.profile.Location = "viridian-forest-area"
+	if err := meetPokemon(s, pokeapi.PokemonType{Name: "pikachu"}, 5); err != nil {
+		t.Fatalf("meetPokemon returned error: %v", err)
+	}
+	s.profile.Give("master-ball", 1)
+	if err := s.run("throw master-ball", &bytes.Buffer{}); err != nil {
+		t.Fatalf("throw returned error: %v", err)
+	}
+	s.profile.Add(pokeapi.PokemonType{Name: "rattata"}, 3)
+
+	out := &bytes.Buffer{}
+	if err := s.run("pokedex --caught-in viridian-forest", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	want := "Caught in viridian-forest-area: 1 pokemon\n - #1 pikachu Lv. 5, 2024-05-01\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	s.run("inspect pikachu", out)
+	if want := "  OT/met: local, met at Lv. 5 in viridian-forest-area on 2024-05-01 in a master ball\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q in inspect, got %q", want, out.String())
+	}
+	if err := s.run("pokedex --living --caught-in viridian-forest", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected --living and --caught-in together to fail")
+	}
+}
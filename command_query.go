@@ -0,0 +1,156 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/query"
+)
+
+// queryFields are what query can filter pokemon on.
+var queryFields = []string{
+	"name", "id", "gen", "type", "ability", "egg_group",
+	"base_hp", "base_attack", "base_defense", "base_special_attack", "base_special_defense", "base_speed", "base_total",
+	"base_exp", "height", "weight", "capture_rate", "legendary", "mythical",
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "query",
+		usage:       `query "<filter>"`,
+		description: "Find pokemon in the local data with a filter like type=water and base_attack>90",
+		minArgs:     1,
+		maxArgs:     -1,
+		callback:    commandQuery,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			return queryFields
+		},
+	})
+}
+
+// queryRow is a pokemon's fields as query filters them.
+type queryRow struct {
+	id   int
+	name string
+	row  query.Row
+}
+
+func commandQuery(s *session, args ...string) error {
+	src := strings.Join(args, " ")
+	if unquoted, ok := strings.CutPrefix(src, `"`); ok {
+		src, _ = strings.CutSuffix(unquoted, `"`)
+	}
+	q, err := query.Parse(src)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	for _, field := range q.Fields() {
+		if !slices.Contains(queryFields, field) {
+			return fmt.Errorf("query: there's no field %q; try %s", field, strings.Join(queryFields, ", "))
+		}
+	}
+
+	// Only what's on hand is searched: fetching every pokemon there is to
+	// filter them would take thousands of requests.
+	lister, ok := s.source.(pokeapi.Lister)
+	if !ok {
+		return errors.New("query searches local data, which this data source has none of")
+	}
+	names, err := lister.Names("pokemon")
+	if err != nil {
+		return err
+	}
+	var found []queryRow
+	for _, name := range names {
+		r, err := pokemonRow(s, name)
+		if err != nil {
+			return err
+		}
+		if q.Match(r.row) {
+			found = append(found, r)
+		}
+	}
+	slices.SortFunc(found, func(a, b queryRow) int {
+		return cmp.Or(cmp.Compare(a.id, b.id), cmp.Compare(a.name, b.name))
+	})
+	if len(found) == 0 {
+		fmt.Fprintf(s.out, "No pokemon match, of %d.\n", len(names))
+		return nil
+	}
+
+	// The table shows each pokemon's number, name and types, then the
+	// fields the filter named.
+	var columns []string
+	for _, field := range q.Fields() {
+		if field != "id" && field != "name" && field != "type" {
+			columns = append(columns, field)
+		}
+	}
+	fmt.Fprintf(s.out, "%5s  %-14s %-18s", "#", "name", "type")
+	for _, c := range columns {
+		fmt.Fprintf(s.out, " %*s", max(len(c), 6), c)
+	}
+	fmt.Fprintln(s.out)
+	for _, r := range found {
+		fmt.Fprintf(s.out, "%5d  %-14s %-18s", r.id, r.name, strings.Join(r.row["type"], "/"))
+		for _, c := range columns {
+			fmt.Fprintf(s.out, " %*s", max(len(c), 6), strings.Join(r.row[c], "/"))
+		}
+		fmt.Fprintln(s.out)
+	}
+	fmt.Fprintf(s.out, "%d of %d pokemon match.\n", len(found), len(names))
+	return nil
+}
+
+// pokemonRow gathers the fields of the pokemon named from the data source.
+// Snapshots without the pokemon's species leave the species' fields out.
+func pokemonRow(s *session, name string) (queryRow, error) {
+	p, err := s.source.Pokemon(name)
+	if err != nil {
+		return queryRow{}, fmt.Errorf("%s: %w", name, err)
+	}
+	row := query.Row{
+		"name":     {p.Name},
+		"type":     typeNames(p),
+		"base_exp": {strconv.Itoa(p.BaseExperience)},
+		"height":   {strconv.Itoa(p.Height)},
+		"weight":   {strconv.Itoa(p.Weight)},
+	}
+	total := 0
+	for _, st := range p.Stats {
+		field := "base_" + strings.ReplaceAll(st.Stat.Name, "-", "_")
+		row[field] = []string{strconv.Itoa(st.BaseStat)}
+		total += st.BaseStat
+	}
+	row["base_total"] = []string{strconv.Itoa(total)}
+	for _, a := range p.Abilities {
+		row["ability"] = append(row["ability"], a.Ability.Name)
+	}
+
+	species, err := s.source.Species(speciesName(p))
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return queryRow{name: p.Name, row: row}, nil
+	}
+	if err != nil {
+		return queryRow{}, fmt.Errorf("%s: %w", name, err)
+	}
+	row["id"] = []string{strconv.Itoa(species.ID)}
+	for i, gen := range generations {
+		if species.ID >= gen.first && species.ID <= gen.last {
+			row["gen"] = []string{strconv.Itoa(i + 1)}
+		}
+	}
+	row["capture_rate"] = []string{strconv.Itoa(species.CaptureRate)}
+	row["legendary"] = []string{strconv.FormatBool(species.IsLegendary)}
+	row["mythical"] = []string{strconv.FormatBool(species.IsMythical)}
+	for _, g := range species.EggGroups {
+		row["egg_group"] = append(row["egg_group"], g.Name)
+	}
+	return queryRow{id: species.ID, name: p.Name, row: row}, nil
+}
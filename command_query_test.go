@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestQuery(t *testing.T) {
+	s := newTestSession(t)
+	s.source = pokeapi.Bundled()
+	out := &bytes.Buffer{}
+	if err := s.run(`query "type=water and base_attack>=125 and gen in (1,2)"`, out); err != nil {
+		t.Fatalf("query returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], "kingler") || !strings.Contains(lines[2], "gyarados") || !strings.Contains(lines[2], "125") {
+		t.Errorf("Expected kingler and gyarados with their attack, got %q", out.String())
+	}
+	if want := "2 of 151 pokemon match."; lines[len(lines)-1] != want {
+		t.Errorf("Expected %q, got %q", want, lines[len(lines)-1])
+	}
+
+	for _, bad := range []string{"query colour=red", "query type=", "query type=water and"} {
+		if err := s.run(bad, out); err == nil {
+			t.Errorf("Expected %q to fail", bad)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/quests"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "quests",
+		description: "Show today's and this week's quests",
+		callback:    commandQuests,
+	})
+}
+
+// activeQuests returns the current quests, dropping progress on ones that
+// have expired.
+func (s *session) activeQuests() []quests.Quest {
+	active := quests.For(s.profile.Name, s.now())
+	maps.DeleteFunc(s.profile.Quests, func(id string, _ int) bool {
+		return !slices.ContainsFunc(active, func(q quests.Quest) bool { return q.ID == id })
+	})
+	return active
+}
+
+func (s *session) subscribeQuests() {
+	s.bus.SubscribeAll(func(e events.Event) {
+		for _, q := range s.activeQuests() {
+			if s.profile.Quests[q.ID] >= q.Goal || !q.Counts(e) {
+				continue
+			}
+			s.profile.Quests[q.ID]++
+			if s.profile.Quests[q.ID] == q.Goal {
+				s.payReward(q)
+			}
+		}
+	})
+}
+
+func (s *session) payReward(q quests.Quest) {
+	s.profile.Money += q.Reward.Money
+	for item, n := range q.Reward.Items {
+		s.profile.Give(item, n)
+	}
+	fmt.Fprintf(s.out, "Quest complete: %s! You got %s\n", q, rewardText(q.Reward))
+}
+
+func rewardText(r quests.Reward) string {
+	parts := []string{fmt.Sprintf("%d Pokédollars", r.Money)}
+	for _, item := range slices.Sorted(maps.Keys(r.Items)) {
+		name := ballName(item)
+		if r.Items[item] != 1 {
+			name += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", r.Items[item], name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func commandQuests(s *session, args ...string) error {
+	now := s.now()
+	var period quests.Period
+	for _, q := range s.activeQuests() {
+		if q.Period != period {
+			period = q.Period
+			fmt.Fprintf(s.out, "%s quests (reset in %s):\n", strings.ToUpper(string(period[:1]))+string(period[1:]), untilReset(period, now))
+		}
+		progress := s.profile.Quests[q.ID]
+		mark := " "
+		if progress >= q.Goal {
+			mark = "x"
+		}
+		fmt.Fprintf(s.out, "  [%s] %s (%d/%d) - %s\n", mark, q, progress, q.Goal, rewardText(q.Reward))
+	}
+	return nil
+}
+
+// untilReset says how long is left in the period, in days and hours.
+func untilReset(period quests.Period, now time.Time) string {
+	y, m, d := now.Date()
+	end := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	if period == quests.Weekly {
+		// ISO weeks end on Sunday night.
+		end = end.AddDate(0, 0, (7-int(now.Weekday()))%7)
+	}
+	hours := int(end.Sub(now).Round(time.Hour).Hours())
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
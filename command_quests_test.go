@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/quests"
+)
+
+func TestQuestRewards(t *testing.T) {
+	s := newTestSession(t)
+	day := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return day }
+	s.profile.Quests["daily-2026-10-14-0"] = 1
+
+	var explore quests.Quest
+	for _, q := range quests.For(s.profile.Name, day) {
+		if q.Period == quests.Daily && q.Kind == quests.ExploreNew {
+			explore = q
+		}
+	}
+	out := &bytes.Buffer{}
+	s.out = out
+	for range explore.Goal + 1 {
+		s.publish(events.Event{Kind: events.Explored, Area: "route-1-area", New: true})
+	}
+
+	if got := s.profile.Quests[explore.ID]; got != explore.Goal {
+		t.Errorf("Expected progress %d, got %d", explore.Goal, got)
+	}
+	if s.profile.Money != explore.Reward.Money {
+		t.Errorf("Expected the reward to be paid once, got %d Pokédollars", s.profile.Money)
+	}
+	if _, ok := s.profile.Quests["daily-2026-10-14-0"]; ok {
+		t.Errorf("Expected yesterday's progress to be dropped")
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Quest complete")) {
+		t.Errorf("Expected a completion message, got %q", out.String())
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const randomUsage = "usage: random [--type <type>] [--gen <n>] [--uncaught] [--encounter]"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "random",
+		usage:       "random [--type <type>] [--gen <n>] [--uncaught] [--encounter]",
+		description: "Show a random pokemon from the local data, or meet it in the wild",
+		maxArgs:     6,
+		callback:    commandRandom,
+		complete: func(s *session, args []string) []string {
+			return []string{"--type", "--gen", "--uncaught", "--encounter"}
+		},
+	})
+}
+
+// randomFilter is what random's flags ask of the pokemon it picks.
+type randomFilter struct {
+	typ       string
+	gen       string
+	uncaught  bool
+	encounter bool
+}
+
+func parseRandomFlags(args []string) (randomFilter, error) {
+	var f randomFilter
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--type", "--gen":
+			if i+1 == len(args) {
+				return f, errors.New(randomUsage)
+			}
+			if args[i] == "--type" {
+				f.typ = args[i+1]
+			} else if n, err := strconv.Atoi(args[i+1]); err != nil || n < 1 || n > len(generations) {
+				return f, fmt.Errorf("--gen is a generation from 1 to %d", len(generations))
+			} else {
+				f.gen = args[i+1]
+			}
+			i++
+		case "--uncaught":
+			f.uncaught = true
+		case "--encounter":
+			f.encounter = true
+		default:
+			return f, errors.New(randomUsage)
+		}
+	}
+	return f, nil
+}
+
+func commandRandom(s *session, args ...string) error {
+	f, err := parseRandomFlags(args)
+	if err != nil {
+		return err
+	}
+	// Like query, random only picks from what's on hand.
+	lister, ok := s.source.(pokeapi.Lister)
+	if !ok {
+		return errors.New("random picks from local data, which this data source has none of")
+	}
+	names, err := lister.Names("pokemon")
+	if err != nil {
+		return err
+	}
+	var matches []queryRow
+	for _, name := range names {
+		r, err := pokemonRow(s, name)
+		if err != nil {
+			return err
+		}
+		_, caught := s.profile.Pokedex[r.name]
+		if f.typ != "" && !slices.Contains(r.row["type"], f.typ) ||
+			f.gen != "" && !slices.Contains(r.row["gen"], f.gen) ||
+			f.uncaught && caught {
+			continue
+		}
+		matches = append(matches, r)
+	}
+	if len(matches) == 0 {
+		return errors.New("no pokemon match")
+	}
+
+	picked := matches[rand.IntN(len(matches))]
+	p, err := s.source.Pokemon(picked.name)
+	if err != nil {
+		return err
+	}
+	if f.encounter {
+		return meetPokemon(s, p, wildLevel)
+	}
+	fmt.Fprintf(s.out, "#%d %s%s, %s", picked.id, typeIcons(s, typeNames(p)), p.Name, strings.Join(typeNames(p), "/"))
+	if gen := picked.row["gen"]; len(gen) > 0 {
+		fmt.Fprintf(s.out, ", generation %s", gen[0])
+	}
+	fmt.Fprintf(s.out, " (1 of %d)\n", len(matches))
+	drawSprite(s, p, false)
+	var stats []string
+	for _, st := range p.Stats {
+		stats = append(stats, fmt.Sprintf("%s %d", st.Stat.Name, st.BaseStat))
+	}
+	if len(stats) > 0 {
+		fmt.Fprintf(s.out, "Base stats: %s\n", strings.Join(stats, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestRandom(t *testing.T) {
+	s := newTestSession(t)
+	s.source = pokeapi.Bundled()
+	out := &bytes.Buffer{}
+	if err := s.run("random --type ghost --gen 1", out); err != nil {
+		t.Fatalf("random returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "ghost/poison, generation 1 (1 of 3)") {
+		t.Errorf("Expected one of the three ghosts, got %q", out.String())
+	}
+
+	for _, bad := range []string{"random --gen 10", "random --type", "random --gen 2", "random --colour red"} {
+		if err := s.run(bad, out); err == nil {
+			t.Errorf("Expected %q to fail", bad)
+		}
+	}
+
+	gengar, _ := s.source.Pokemon("gengar")
+	haunter, _ := s.source.Pokemon("haunter")
+	s.profile.Pokedex["gengar"], s.profile.Pokedex["haunter"] = gengar, haunter
+	out.Reset()
+	if err := s.run("random --type ghost --uncaught --encounter", out); err != nil {
+		t.Fatalf("random returned error: %v", err)
+	}
+	if s.encounter == nil || s.encounter.species.Name != "gastly" {
+		t.Errorf("Expected to meet gastly, got %q", out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "release",
+		usage:       "release " + selectorUsage + " [--yes]",
+		description: "Release pokemon back into the wild, after showing which",
+		minArgs:     1,
+		maxArgs:     -1,
+		callback:    commandRelease,
+		complete: func(s *session, args []string) []string {
+			return append(completeCaught(s, args), "--type", "--shiny", "--not-shiny", "--team", "--box", "--keep-best", "--yes")
+		},
+	})
+}
+
+// commandRelease lets go of every pokemon the selector picks, once the
+// player has seen the list and agreed, or straight away with --yes. The
+// player keeps at least one pokemon, and the pokedex keeps its entries.
+func commandRelease(s *session, args ...string) error {
+	picked, rest, err := pickOwned(s, args)
+	if err != nil {
+		return err
+	}
+	yes := slices.Contains(rest, "--yes")
+	if rest = slices.DeleteFunc(rest, func(arg string) bool { return arg == "--yes" }); len(rest) > 0 {
+		return fmt.Errorf("usage: release %s [--yes]", selectorUsage)
+	}
+	if len(picked) == len(s.profile.Pokemon) {
+		return errors.New("you can't release every pokemon you have")
+	}
+
+	fmt.Fprintf(s.out, "Releasing %d pokemon:\n", len(picked))
+	for _, p := range picked {
+		fmt.Fprintf(s.out, " - %s\n", ownedText(s, p))
+	}
+	if !yes {
+		answer, ok := s.ask("Type 'yes' to release them: ")
+		if !ok {
+			return errors.New("can't confirm here, add --yes")
+		}
+		if answer != "yes" {
+			fmt.Fprintln(s.out, "Release cancelled")
+			return nil
+		}
+	}
+
+	// The picked pointers go stale as pokemon are taken out, so go by ID.
+	ids := make([]int, len(picked))
+	for i, p := range picked {
+		ids[i] = p.ID
+	}
+	for _, id := range ids {
+		s.profile.Release(id)
+	}
+	fmt.Fprintf(s.out, "Released %d pokemon. Bye-bye!\n", len(ids))
+	return nil
+}
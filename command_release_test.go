@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestRelease(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+	for _, iv := range []int{10, 30, 20} {
+		caught := s.profile.Add(pokeapi.PokemonType{Name: "rattata"}, 3)
+		s.profile.Get(caught.ID).IVs = map[string]int{"hp": iv}
+	}
+	s.profile.Get(3).Item = "oran-berry"
+	s.profile.Get(4).Shiny = true
+
+	if err := s.run("release", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected release with no selector to fail")
+	}
+	if err := s.run("release pikachu rattata --yes", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected release to refuse to let every pokemon go")
+	}
+
+	s.input = func() (string, bool) { return "no", true }
+	out := &bytes.Buffer{}
+	if err := s.run("release rattata --not-shiny --keep-best 1", out); err != nil {
+		t.Fatalf("release returned error: %v", err)
+	}
+	// The best rattata is kept and the shiny one isn't picked, which leaves
+	// the worst.
+	if want := "Releasing 1 pokemon:\n - #2 rattata Lv. 3\n"; !strings.HasPrefix(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if !strings.Contains(out.String(), "Release cancelled") || len(s.profile.Pokemon) != 4 {
+		t.Errorf("Expected nothing to be released without a yes, got %q", out.String())
+	}
+
+	s.input = func() (string, bool) { return "yes", true }
+	if err := s.run("release rattata --not-shiny", &bytes.Buffer{}); err != nil {
+		t.Fatalf("release returned error: %v", err)
+	}
+	if len(s.profile.Pokemon) != 2 || s.profile.Get(4) == nil || s.profile.Inventory["oran-berry"] != 1 {
+		t.Errorf("Expected the shiny rattata to stay and the held berry to come back, got %+v", s.profile.Pokemon)
+	}
+	if len(s.profile.Party) != 2 {
+		t.Errorf("Expected the released pokemon to leave the party, got %v", s.profile.Party)
+	}
+}
+
+func TestSelectorBoxes(t *testing.T) {
+	s := newTestSession(t)
+	for range 6 + boxSize + 1 {
+		s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	}
+	sel, _, err := parseSelector([]string{"--box", "2"})
+	if err != nil {
+		t.Fatalf("parseSelector returned error: %v", err)
+	}
+	if picked := sel.pick(s); len(picked) != 1 || picked[0].ID != 6+boxSize+1 {
+		t.Errorf("Expected the last pokemon caught alone in box 2, got %d pokemon", len(picked))
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("inspect --team", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "6 pokemon:\n- #1 magikarp") {
+		t.Errorf("Expected the party to be inspected, got %q", out.String())
+	}
+	if _, _, err := parseSelector([]string{"--box", "0"}); err == nil {
+		t.Errorf("Expected box 0 to be refused")
+	}
+}
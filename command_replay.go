@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battlelog"
+)
+
+// replayDelay is the pause between turns when replaying a battle.
+var replayDelay = 700 * time.Millisecond
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "battles",
+		usage:       "battles list",
+		description: "List your past battles",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandBattles,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			return []string{"list"}
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "replay",
+		usage:       "replay <id> [--fast]",
+		description: "Replay a past battle turn by turn",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandReplay,
+	})
+}
+
+func battleResult(rec battlelog.Record) string {
+	switch {
+	case rec.Forfeit:
+		return "forfeited"
+	case rec.Won:
+		return "won"
+	}
+	return "lost"
+}
+
+func commandBattles(s *session, args ...string) error {
+	if args[0] != "list" {
+		return fmt.Errorf("usage: battles list")
+	}
+	if s.battles == nil {
+		return errors.New("battles aren't recorded here")
+	}
+	records, err := s.battles.List(s.profile.Name)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(s.out, "You haven't fought any battles yet.")
+		return nil
+	}
+	for _, rec := range records {
+		fmt.Fprintf(s.out, "%d. %s vs %s: %s in %d turns\n", rec.ID, rec.Time.Format("2006-01-02 15:04"), rec.Opponent, battleResult(rec), rec.Turns)
+	}
+	return nil
+}
+
+func commandReplay(s *session, args ...string) error {
+	if s.battles == nil {
+		return errors.New("battles aren't recorded here")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%q isn't a battle ID; see battles list", args[0])
+	}
+	delay := replayDelay
+	if len(args) > 1 {
+		if args[1] != "--fast" {
+			return fmt.Errorf("unknown option %s", args[1])
+		}
+		delay = 0
+	}
+	rec, err := s.battles.Load(s.profile.Name, id)
+	if errors.Is(err, battlelog.ErrNotFound) {
+		return fmt.Errorf("there's no battle %d; see battles list", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Battle %d: %s vs %s, %s\n", rec.ID, rec.Player, rec.Opponent, rec.Time.Format("2006-01-02 15:04"))
+	turn := 0
+	for _, e := range rec.Log {
+		if e.Turn != turn {
+			turn = e.Turn
+			time.Sleep(delay)
+			fmt.Fprintf(s.out, "-- Turn %d --\n", turn)
+		}
+		fmt.Fprintln(s.out, e)
+	}
+	if rec.Forfeit {
+		fmt.Fprintf(s.out, "%s gave up the battle.\n", rec.Player)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "clear",
+		description: "Clear the screen",
+		callback:    commandClear,
+	})
+	registerCommand(cliCommand{
+		name:        "reset",
+		usage:       "reset [--yes]",
+		description: "Wipe your game progress and start over",
+		maxArgs:     1,
+		callback:    commandReset,
+	})
+}
+
+// plainClearLines is how many blank lines push everything off a screen
+// that can't be cleared.
+const plainClearLines = 50
+
+func commandClear(s *session, args ...string) error {
+	if s.app.plain {
+		fmt.Fprint(s.out, strings.Repeat("\n", plainClearLines))
+		return nil
+	}
+	fmt.Fprint(s.out, "\033[H\033[2J")
+	return nil
+}
+
+func commandReset(s *session, args ...string) error {
+	if len(args) == 0 {
+		answer, ok := s.ask("This deletes all your progress. Type 'yes' to continue: ")
+		if !ok {
+			return errors.New("can't confirm here, use reset --yes")
+		}
+		if answer != "yes" {
+			fmt.Fprintln(s.out, "Reset cancelled")
+			return nil
+		}
+	} else if args[0] != "--yes" {
+		return errors.New("usage: reset [--yes]")
+	}
+
+	s.resetState()
+	fmt.Fprintln(s.out, "Your progress has been reset")
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/fulltext"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "search",
+		usage:       `search "<words>"`,
+		description: "Find species by words in their names, genera and pokedex entries",
+		minArgs:     1,
+		maxArgs:     -1,
+		callback:    commandSearch,
+		paged:       true,
+	})
+}
+
+func commandSearch(s *session, args ...string) error {
+	q := strings.Trim(strings.Join(args, " "), `"`)
+	if len(fulltext.Words(s.language, q)) == 0 {
+		return errors.New(`usage: search "<words>"`)
+	}
+	index := searchIndex(s.app, s.language)
+	failed := indexSpecies(s, index)
+
+	results := index.Search(q)
+	if len(results) == 0 {
+		fmt.Fprintf(s.out, "Nothing matches %q in %d species.\n", q, index.Len())
+	}
+	for _, r := range results {
+		name := r.Species
+		if r.Name != "" && !strings.EqualFold(r.Name, r.Species) {
+			name += " (" + r.Name + ")"
+		}
+		if r.Genus != "" {
+			name += ", the " + r.Genus
+		}
+		fmt.Fprintln(s.out, name)
+		if r.Snippet != "" {
+			fmt.Fprintf(s.out, "  %s\n", r.Snippet)
+		}
+	}
+	if len(results) > 0 {
+		fmt.Fprintf(s.out, "%d of %d species match.\n", len(results), index.Len())
+	}
+	if failed > 0 {
+		fmt.Fprintf(s.out, "%d species couldn't be read, so weren't searched.\n", failed)
+	}
+	return nil
+}
+
+// searchIndex is the app's index of species text in lang.
+func searchIndex(a *app, lang string) *fulltext.Index {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.indexes == nil {
+		a.indexes = map[string]*fulltext.Index{}
+	}
+	if a.indexes[lang] == nil {
+		a.indexes[lang] = fulltext.New(lang)
+	}
+	return a.indexes[lang]
+}
+
+// indexSpecies adds to index the species on hand that it hasn't got: those
+// the data source has locally, and those in the pokedex, which have been
+// fetched already. It says how many couldn't be read.
+func indexSpecies(s *session, index *fulltext.Index) int {
+	names := caughtSpecies(s)
+	if lister, ok := s.source.(pokeapi.Lister); ok {
+		local, _ := lister.Names("pokemon-species")
+		names = append(names, local...)
+	}
+	slices.Sort(names)
+	failed := 0
+	for _, name := range slices.Compact(names) {
+		if index.Has(name) {
+			continue
+		}
+		species, err := s.source.Species(name)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			failed++
+			continue
+		}
+		index.Add(fulltext.EntryFor(species, s.language))
+	}
+	return failed
+}
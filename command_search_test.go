@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pokemon-species"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pokemon-species", "charmander.json"), []byte(`{
+		"name": "charmander",
+		"names": [{"name": "Charmander", "language": {"name": "en"}}, {"name": "ヒトカゲ", "language": {"name": "ja"}}],
+		"genera": [{"genus": "Lizard Pokémon", "language": {"name": "en"}}],
+		"flavor_text_entries": [
+			{"flavor_text": "The flame at the tip of its tail\nmakes a sound as it burns.", "language": {"name": "en"}},
+			{"flavor_text": "しっぽの　ほのおは　いのちの　ともしび", "language": {"name": "ja"}}
+		]
+	}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "pokemon-species", "squirtle.json"), []byte(`{"name": "squirtle"}`), 0o644)
+
+	s := newTestSession(t)
+	s.source = pokeapi.NewOffline(dir)
+	out := &bytes.Buffer{}
+	if err := s.run(`search "tail flame"`, out); err != nil {
+		t.Fatalf("search returned error: %v", err)
+	}
+	want := "charmander, the Lizard Pokémon\n  The flame at the tip of its tail makes a sound as it burns.\n1 of 2 species match.\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	s.language = "ja"
+	out.Reset()
+	s.run("search ほのお", out)
+	if !strings.Contains(out.String(), "charmander (ヒトカゲ)") {
+		t.Errorf("Expected charmander found in Japanese, got %q", out.String())
+	}
+}
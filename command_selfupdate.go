@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/azs06/pokedexcli/internal/release"
+)
+
+// releaseKey is the base64 ed25519 public key release checksums are signed
+// with. Official builds set it with -ldflags "-X main.releaseKey=...".
+var releaseKey = ""
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "self-update",
+		usage:       "self-update [--insecure]",
+		description: "Download and install the latest release",
+		maxArgs:     1,
+		callback:    commandSelfUpdate,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return []string{"--insecure"}
+			}
+			return nil
+		},
+	})
+}
+
+// commandSelfUpdate installs the latest release once the signature of its
+// checksums verifies. --insecure installs it on the checksums alone, which
+// anyone who can change the release can change too.
+func commandSelfUpdate(s *session, args ...string) error {
+	insecure := len(args) == 1 && args[0] == "--insecure"
+	if len(args) == 1 && !insecure {
+		return errors.New("usage: self-update [--insecure]")
+	}
+	if releaseKey == "" && !insecure {
+		return errors.New("this build has no release key to check a release's signature with; use self-update --insecure to install it on its checksums alone")
+	}
+	latest, err := release.Latest(release.DefaultRepo)
+	if err != nil {
+		return err
+	}
+	if !release.Newer(latest.Tag, version) {
+		fmt.Fprintf(s.out, "You're up to date (%s)\n", version)
+		return nil
+	}
+
+	name := release.AssetName(runtime.GOOS, runtime.GOARCH)
+	binary, ok := latest.Asset(name)
+	if !ok {
+		return fmt.Errorf("%s has no build for %s/%s", latest.Tag, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := latest.Asset(release.ChecksumsAsset)
+	if !ok {
+		return fmt.Errorf("%s has no %s, refusing to install", latest.Tag, release.ChecksumsAsset)
+	}
+
+	fmt.Fprintf(s.out, "Downloading %s %s...\n", name, latest.Tag)
+	checksums, err := release.Download(sums.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	if insecure {
+		fmt.Fprintln(s.out, "Warning: not checking the release's signature, only its checksums")
+	} else if err := verifyReleaseSignature(latest, checksums); err != nil {
+		return err
+	}
+	data, err := release.Download(binary.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	if err := release.VerifyChecksum(checksums, name, data); err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	if err := release.Replace(exe, data); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	fmt.Fprintf(s.out, "Updated to %s, restart pokedexcli to use it\n", latest.Tag)
+	return nil
+}
+
+func verifyReleaseSignature(latest release.Release, checksums []byte) error {
+	key, err := base64.StdEncoding.DecodeString(releaseKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("this build has an invalid release key")
+	}
+	sigAsset, ok := latest.Asset(release.SignatureAsset)
+	if !ok {
+		return fmt.Errorf("%s is not signed, refusing to install", latest.Tag)
+	}
+	sig, err := release.Download(sigAsset.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	return release.VerifySignature(ed25519.PublicKey(key), checksums, sig)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSelfUpdateNeedsReleaseKey(t *testing.T) {
+	s := newTestSession(t)
+	// The refusal comes before anything is downloaded.
+	err := s.run("self-update", &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "--insecure") {
+		t.Errorf("Expected a build without a release key to refuse to update, got %v", err)
+	}
+	if err := s.run("self-update --force", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("Expected a usage error, got %v", err)
+	}
+}
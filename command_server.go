@@ -0,0 +1,120 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/azs06/pokedexcli/internal/ladder"
+	"github.com/azs06/pokedexcli/internal/rpc"
+	"github.com/azs06/pokedexcli/internal/slack"
+	"github.com/azs06/pokedexcli/internal/wondertrade"
+)
+
+const serverUsage = "server --mode <ladder|wondertrade|slack|mcp|grpc> [--addr <addr>]"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "server",
+		usage:       serverUsage,
+		description: "Run a companion server: the ladder matches players for link battles, wondertrade swaps pokemon between players, slack answers Slack slash commands, mcp serves tools to AI assistants on stdin and stdout and grpc serves the Pokedex gRPC API",
+		minArgs:     2,
+		maxArgs:     4,
+		callback:    commandServer,
+		complete: func(s *session, args []string) []string {
+			if len(args) > 0 && args[len(args)-1] == "--mode" {
+				return []string{"ladder", "wondertrade", "slack", "mcp", "grpc"}
+			}
+			return []string{"--mode", "--addr"}
+		},
+	})
+}
+
+// commandServer runs a server until the program is stopped. It's meant to
+// be run on its own, as pokedexcli server --mode ladder.
+func commandServer(s *session, args ...string) error {
+	mode, addr := "", ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--mode" && i+1 < len(args):
+			i++
+			mode = args[i]
+		case args[i] == "--addr" && i+1 < len(args):
+			i++
+			addr = args[i]
+		default:
+			return errors.New("usage: " + serverUsage)
+		}
+	}
+	switch mode {
+	case "ladder":
+		return serveLadder(s, cmp.Or(addr, ladder.DefaultAddr))
+	case "wondertrade":
+		return serveWonderTrade(s, cmp.Or(addr, wondertrade.DefaultAddr))
+	case "slack":
+		return serveSlack(s, cmp.Or(addr, slack.DefaultAddr))
+	case "mcp":
+		return serveMCP(s, os.Stdin, os.Stdout)
+	case "grpc":
+		return serveGRPC(s, cmp.Or(addr, rpc.DefaultAddr))
+	}
+	return fmt.Errorf("unknown server mode %q; it's ladder, wondertrade, slack, mcp or grpc", mode)
+}
+
+func serveLadder(s *session, addr string) error {
+	if err := os.MkdirAll(s.app.dataDir, 0o755); err != nil {
+		return err
+	}
+	sv, err := ladder.NewServer(filepath.Join(s.app.dataDir, "ladder.json"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Ladder server listening on %s\n", addr)
+	return http.ListenAndServe(addr, sv.Handler())
+}
+
+func serveWonderTrade(s *session, addr string) error {
+	if err := os.MkdirAll(s.app.dataDir, 0o755); err != nil {
+		return err
+	}
+	sv, err := wondertrade.NewServer(filepath.Join(s.app.dataDir, "wondertrade.json"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Wonder trade server listening on %s\n", addr)
+	return http.ListenAndServe(addr, sv.Handler())
+}
+
+// profileRunner runs commands in the sessions of any profile, for servers
+// started from host. The server command keeps host locked for as long as it
+// runs, so host's own commands are run one at a time under mu instead.
+type profileRunner struct {
+	host *session
+	mu   sync.Mutex
+}
+
+// do calls fn with the session of profile locked and writing to out. An
+// empty profile is host's.
+func (r *profileRunner) do(profile string, out io.Writer, fn func(s *session) error) error {
+	s := r.host
+	if profile == "" || profile == r.host.id {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	} else {
+		var err error
+		if s, err = r.host.app.session(profile); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	old := s.out
+	s.out = out
+	defer func() { s.out = old }()
+	return fn(s)
+}
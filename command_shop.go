@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const (
+	// saleItems is how many items are on sale each day.
+	saleItems = 2
+	// saleDiscount is how much is taken off an item on sale, in percent.
+	saleDiscount = 25
+)
+
+// shopSection is a shelf of the shop, such as its balls.
+type shopSection struct {
+	name  string
+	items []string
+}
+
+// shopStock is what the shop sells, by PokeAPI item name. Prices are the
+// items' own costs; an item without one isn't for sale.
+var shopStock = []shopSection{
+	{"balls", []string{"poke-ball", "great-ball", "ultra-ball"}},
+	{"medicine", []string{"potion", "super-potion", "hyper-potion", "max-potion", "revive", "hp-up", "protein", "iron", "calcium", "zinc", "carbos"}},
+	{"berries", []string{"cheri-berry", "oran-berry", "razz-berry", "pomeg-berry", "kelpsy-berry", "qualot-berry", "hondew-berry", "grepa-berry", "tamato-berry"}},
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "shop",
+		usage:       "shop [balls|medicine|berries]",
+		description: "Show what the shop sells, or one of its sections",
+		maxArgs:     1,
+		callback:    commandShop,
+		complete: func(s *session, args []string) []string {
+			names := []string{}
+			for _, section := range shopStock {
+				names = append(names, section.name)
+			}
+			return names
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "buy",
+		usage:       "buy <item> [count]",
+		description: "Buy items from the shop",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandBuy,
+		complete: func(s *session, args []string) []string {
+			items := []string{}
+			for _, section := range shopStock {
+				items = append(items, section.items...)
+			}
+			slices.Sort(items)
+			return items
+		},
+	})
+}
+
+// onSale is the items on sale on the day of t. They're the same for every
+// player.
+func onSale(t time.Time) []string {
+	items := []string{}
+	for _, section := range shopStock {
+		items = append(items, section.items...)
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "sale/%s", t.Format(time.DateOnly))
+	rng := rand.New(rand.NewPCG(h.Sum64(), 0))
+	sale := []string{}
+	for _, i := range rng.Perm(len(items))[:saleItems] {
+		sale = append(sale, items[i])
+	}
+	return sale
+}
+
+// price is what item costs today, and what it costs when it isn't on sale.
+func price(s *session, item string) (int, int, error) {
+	sold := slices.ContainsFunc(shopStock, func(section shopSection) bool {
+		return slices.Contains(section.items, item)
+	})
+	if !sold {
+		return 0, 0, fmt.Errorf("the shop doesn't sell %s", item)
+	}
+	detail, err := s.source.Item(item)
+	if errors.Is(err, pokeapi.ErrNotFound) || err == nil && detail.Cost <= 0 {
+		return 0, 0, fmt.Errorf("%s is out of stock", item)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if slices.Contains(onSale(s.now()), item) {
+		return detail.Cost * (100 - saleDiscount) / 100, detail.Cost, nil
+	}
+	return detail.Cost, detail.Cost, nil
+}
+
+func commandShop(s *session, args ...string) error {
+	sections := shopStock
+	if len(args) > 0 {
+		i := slices.IndexFunc(shopStock, func(section shopSection) bool { return section.name == args[0] })
+		if i < 0 {
+			return fmt.Errorf("the shop has no %s section; try balls, medicine or berries", args[0])
+		}
+		sections = shopStock[i : i+1]
+	}
+
+	fmt.Fprintf(s.out, "You have %d Pokédollars. Today's sale: %s, %d%% off\n", s.profile.Money, strings.Join(onSale(s.now()), " and "), saleDiscount)
+	for _, section := range sections {
+		fmt.Fprintf(s.out, "%s%s:\n", strings.ToUpper(section.name[:1]), section.name[1:])
+		for _, item := range section.items {
+			cost, full, err := price(s, item)
+			if err != nil {
+				// Items the data source has no price for aren't sold.
+				continue
+			}
+			if cost < full {
+				fmt.Fprintf(s.out, " - %s: %d (sale, was %d)\n", item, cost, full)
+				continue
+			}
+			fmt.Fprintf(s.out, " - %s: %d\n", item, cost)
+		}
+	}
+	return nil
+}
+
+func commandBuy(s *session, args ...string) error {
+	item := args[0]
+	each, _, err := price(s, item)
+	if err != nil {
+		return err
+	}
+	count := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("%s isn't a number of items", args[1])
+		}
+		count = n
+	}
+	// Check against what the player can afford before multiplying, so a
+	// huge count can't overflow the cost.
+	if each > 0 && count > s.profile.Money/each {
+		return fmt.Errorf("%s cost %d Pokédollars each, so you can only afford %d", item, each, s.profile.Money/each)
+	}
+	cost := each * count
+	if cost > s.profile.Money {
+		return fmt.Errorf("%d %s cost %d Pokédollars, but you only have %d", count, item, cost, s.profile.Money)
+	}
+	s.profile.Money -= cost
+	s.profile.Give(item, count)
+	fmt.Fprintf(s.out, "You bought %d %s for %d Pokédollars\n", count, item, cost)
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// saleDay is the first day from 1 May 2024 on which the only priced items
+// on sale are those in sale.
+func saleDay(sale ...string) time.Time {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for {
+		priced := slices.DeleteFunc(onSale(day), func(item string) bool { return fakeItemCosts[item] == 0 })
+		if slices.Equal(priced, sale) {
+			return day
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+func TestShop(t *testing.T) {
+	s := newTestSession(t)
+	s.now = func() time.Time { return saleDay("great-ball") }
+	out := &bytes.Buffer{}
+	if err := s.run("shop", out); err != nil {
+		t.Fatalf("shop returned error: %v", err)
+	}
+	want := "Balls:\n - poke-ball: 200\n - great-ball: 450 (sale, was 600)\n - ultra-ball: 800\n" +
+		"Medicine:\n - potion: 200\n - revive: 2000\n - protein: 10000\n - carbos: 10000\n" +
+		"Berries:\n - cheri-berry: 80\n"
+	if !strings.Contains(out.String(), "Today's sale: ") || !strings.HasSuffix(out.String(), want) {
+		t.Errorf("Expected the stock priced by item cost, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := s.run("shop balls", out); err != nil {
+		t.Fatalf("shop returned error: %v", err)
+	}
+	if strings.Contains(out.String(), "protein") || !strings.Contains(out.String(), "ultra-ball") {
+		t.Errorf("Expected only the balls, got %q", out.String())
+	}
+	if err := s.run("shop toys", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown section to fail")
+	}
+}
+
+func TestBuy(t *testing.T) {
+	s := newTestSession(t)
+	day := saleDay("great-ball")
+	s.now = func() time.Time { return day }
+	s.profile.Money = 1000
+	had := s.profile.Inventory["great-ball"]
+	if err := s.run("buy great-ball 2", &bytes.Buffer{}); err != nil {
+		t.Fatalf("buy returned error: %v", err)
+	}
+	if s.profile.Money != 100 || s.profile.Inventory["great-ball"] != had+2 {
+		t.Errorf("Expected 2 great balls for 900 on sale, got %d and %d left", s.profile.Inventory["great-ball"]-had, s.profile.Money)
+	}
+	if err := s.run("buy oran-berry", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an item without a price not to be sold")
+	}
+	if err := s.run("buy rare-candy", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an item the shop doesn't stock not to be sold")
+	}
+
+	// 92233720368547759 poke balls cost 184 once the price overflows.
+	s.profile.Money = 1000
+	if err := s.run("buy poke-ball 92233720368547759", &bytes.Buffer{}); err == nil || s.profile.Money != 1000 {
+		t.Errorf("Expected a count too big to afford to be refused, got %v and %d left", err, s.profile.Money)
+	}
+
+	// The sale moves on.
+	s.now = func() time.Time { return saleDay() }
+	if each, full, err := price(s, "great-ball"); err != nil || each != 600 || full != 600 {
+		t.Errorf("Expected great balls to be back to 600, got %d (%v)", each, err)
+	}
+}
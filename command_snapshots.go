@@ -0,0 +1,236 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/profile"
+	"github.com/azs06/pokedexcli/internal/snapshot"
+)
+
+const snapshotsUsage = "snapshots <list|restore <timestamp> [--yes]>"
+
+// snapshotPeriods are how often the snapshots setting can archive a
+// profile; "off" never does.
+var snapshotPeriods = map[string]time.Duration{
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+	"off":    0,
+}
+
+// defaultSnapshotKeep is how many snapshots of each profile are kept when
+// the config doesn't say.
+const defaultSnapshotKeep = 7
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "snapshots",
+		usage:       snapshotsUsage,
+		description: "List the daily or weekly archives of your game, or go back to one",
+		minArgs:     1,
+		maxArgs:     3,
+		callback:    commandSnapshots,
+		paged:       true,
+		complete: func(s *session, args []string) []string {
+			switch len(args) {
+			case 0:
+				return []string{"list", "restore"}
+			case 1:
+				if args[0] != "restore" || s.snapshots == nil {
+					return nil
+				}
+				times, _ := s.snapshots.List(s.profile.Name)
+				ids := make([]string, len(times))
+				for i, t := range times {
+					ids[i] = t.Format(snapshot.Layout)
+				}
+				return ids
+			}
+			return nil
+		},
+	})
+}
+
+// autoSnapshot archives the player's game when the last snapshot is older
+// than the config's snapshots.every, then deletes all but the newest
+// snapshots.keep.
+func autoSnapshot(s *session) error {
+	if s.snapshots == nil {
+		return nil
+	}
+	s.app.mu.Lock()
+	policy := s.app.config.Snapshots
+	s.app.mu.Unlock()
+	period, ok := snapshotPeriods[cmp.Or(policy.Every, "daily")]
+	if !ok {
+		period = snapshotPeriods["daily"]
+	}
+	if period == 0 {
+		return nil
+	}
+	if s.snapshotted.IsZero() {
+		times, err := s.snapshots.List(s.profile.Name)
+		if err != nil {
+			return err
+		}
+		if len(times) > 0 {
+			s.snapshotted = times[len(times)-1]
+		}
+	}
+	if s.now().Sub(s.snapshotted) < period {
+		return nil
+	}
+	return takeSnapshot(s, cmp.Or(policy.Keep, defaultSnapshotKeep))
+}
+
+// takeSnapshot archives the player's profile and journal as they are now,
+// keeping the newest keep snapshots.
+func takeSnapshot(s *session, keep int) error {
+	data, err := json.MarshalIndent(s.profile, "", "  ")
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{"profile.json": data}
+	if s.journals != nil {
+		journal, err := s.journals.Read(s.profile.Name)
+		if err != nil {
+			return err
+		}
+		if journal != "" {
+			files["journal.md"] = []byte(journal)
+		}
+	}
+	now := s.now()
+	if err := s.snapshots.Take(s.profile.Name, now, files); err != nil {
+		return err
+	}
+	s.snapshotted = now
+	return s.snapshots.Prune(s.profile.Name, keep)
+}
+
+func commandSnapshots(s *session, args ...string) error {
+	if s.snapshots == nil {
+		return errors.New("snapshots aren't kept here")
+	}
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		return listSnapshots(s)
+	case args[0] == "restore" && len(args) == 2:
+		return restoreSnapshot(s, args[1], false)
+	case args[0] == "restore" && len(args) == 3 && args[2] == "--yes":
+		return restoreSnapshot(s, args[1], true)
+	}
+	return errors.New("usage: " + snapshotsUsage)
+}
+
+func listSnapshots(s *session) error {
+	times, err := s.snapshots.List(s.profile.Name)
+	if err != nil {
+		return err
+	}
+	if len(times) == 0 {
+		fmt.Fprintln(s.out, "No snapshots yet. Your game is archived once a day as you play.")
+		return nil
+	}
+	for _, t := range slices.Backward(times) {
+		fmt.Fprintf(s.out, "%s  %s\n", t.Format(snapshot.Layout), t.In(s.now().Location()).Format("Mon 2 Jan 2006 15:04"))
+	}
+	return nil
+}
+
+// restoreSnapshot puts the player's game back as it was at the snapshot
+// named by timestamp. The game as it is now is archived first, so the
+// restore can be undone.
+func restoreSnapshot(s *session, timestamp string, confirmed bool) error {
+	if s.readOnly {
+		return errors.New("snapshots can't be restored in read-only mode")
+	}
+	t, err := time.Parse(snapshot.Layout, timestamp)
+	if err != nil {
+		return fmt.Errorf("%s isn't a snapshot timestamp; snapshots list shows them", timestamp)
+	}
+	files, err := s.snapshots.Open(s.profile.Name, t)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return fmt.Errorf("there's no snapshot from %s", timestamp)
+	}
+	if err != nil {
+		return err
+	}
+	p, err := profile.Decode(s.profile.Name, files["profile.json"])
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %w", timestamp, err)
+	}
+
+	if !confirmed {
+		answer, ok := s.ask(fmt.Sprintf("This replaces your game with the one from %s. Type 'yes' to continue: ", timestamp))
+		if !ok {
+			return errors.New("can't confirm here, use snapshots restore " + timestamp + " --yes")
+		}
+		if answer != "yes" {
+			fmt.Fprintln(s.out, "Restore cancelled")
+			return nil
+		}
+	}
+
+	s.app.mu.Lock()
+	keep := cmp.Or(s.app.config.Snapshots.Keep, defaultSnapshotKeep)
+	s.app.mu.Unlock()
+	// One more than kept, so archiving the game as it is doesn't push out
+	// the oldest snapshot.
+	if err := takeSnapshot(s, keep+1); err != nil {
+		return fmt.Errorf("failed to snapshot your game first: %w", err)
+	}
+	if s.journals != nil {
+		if err := s.journals.Write(s.profile.Name, string(files["journal.md"])); err != nil {
+			return err
+		}
+	}
+	s.resetState()
+	s.profile = p
+	fmt.Fprintf(s.out, "Your game is back to how it was at %s. Your game from before is snapshot %s.\n", timestamp, s.snapshotted.UTC().Format(snapshot.Layout))
+	return nil
+}
+
+// recoverSave offers to replace the player's damaged save with their newest
+// snapshot that's intact, asking through input. The damaged save is set
+// aside rather than deleted. restored is false if the player said no.
+func recoverSave(a *app, name string, out io.Writer, input func() (string, bool)) (restored bool, err error) {
+	if a.snapshots == nil || a.store == nil {
+		return false, errors.New("there are no snapshots to restore")
+	}
+	times, err := a.snapshots.List(name)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range slices.Backward(times) {
+		files, err := a.snapshots.Open(name, t)
+		if err != nil {
+			continue
+		}
+		p, err := profile.Decode(name, files["profile.json"])
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(out, "Your newest intact snapshot is from %s. Type 'yes' to restore it: ", t.Local().Format("Mon 2 Jan 2006 15:04"))
+		answer, ok := input()
+		if !ok || strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			return false, nil
+		}
+		damaged, err := a.store.SetAside(name)
+		if err != nil {
+			return false, err
+		}
+		if err := a.store.Save(p); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(out, "Restored your game from %s. The damaged save was moved to %s.\n", t.Format(snapshot.Layout), damaged)
+		return true, nil
+	}
+	return false, errors.New("there's no intact snapshot to restore")
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/journal"
+	"github.com/azs06/pokedexcli/internal/profile"
+	"github.com/azs06/pokedexcli/internal/snapshot"
+)
+
+func TestSnapshots(t *testing.T) {
+	s := newTestSession(t)
+	s.store = profile.NewStore(t.TempDir())
+	s.snapshots = snapshot.NewStore(t.TempDir())
+	s.journals = journal.NewStore(t.TempDir())
+	s.app.config.Snapshots.Keep = 2
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	now := start
+	s.now = func() time.Time { return now }
+
+	// A snapshot is taken on the first save and then once a day; the
+	// oldest are pruned.
+	for day := range 4 {
+		now = start.AddDate(0, 0, day)
+		s.profile.Money = 1000 * (day + 1)
+		s.journals.Write("local", strings.Repeat("- entry\n", day+1))
+		if err := s.run("snapshots list", &bytes.Buffer{}); err != nil {
+			t.Fatalf("snapshots list returned error: %v", err)
+		}
+		s.profile.Steps++
+		s.run("snapshots list", &bytes.Buffer{})
+	}
+	out := &bytes.Buffer{}
+	s.run("snapshots list", out)
+	expected := "20240304-093000  Mon 4 Mar 2024 09:30\n20240303-093000  Sun 3 Mar 2024 09:30\n"
+	if out.String() != expected {
+		t.Errorf("Expected snapshots %q, got %q", expected, out.String())
+	}
+
+	s.input = func() (string, bool) { return "no", true }
+	out.Reset()
+	s.run("snapshots restore 20240303-093000", out)
+	if !strings.Contains(out.String(), "Restore cancelled") || s.profile.Money != 4000 {
+		t.Errorf("Expected the restore to be cancelled, got %q", out.String())
+	}
+
+	now = now.Add(time.Hour)
+	out.Reset()
+	if err := s.run("snapshots restore 20240303-093000 --yes", out); err != nil {
+		t.Fatalf("snapshots restore returned error: %v", err)
+	}
+	if s.profile.Money != 3000 || s.profile.Steps != 2 {
+		t.Errorf("Expected the game from the third day, got %d money and %d steps", s.profile.Money, s.profile.Steps)
+	}
+	if got, _ := s.journals.Read("local"); got != strings.Repeat("- entry\n", 3) {
+		t.Errorf("Expected the journal from the third day, got %q", got)
+	}
+	if saved, _, _ := s.store.Load("local"); saved.Money != 3000 {
+		t.Errorf("Expected the restored game to be saved, got %d money", saved.Money)
+	}
+	if !strings.Contains(out.String(), "Your game from before is snapshot 20240304-103000") {
+		t.Errorf("Expected the game before the restore to be kept, got %q", out.String())
+	}
+	if times, _ := s.snapshots.List("local"); len(times) != 3 {
+		t.Errorf("Expected the restored snapshot to be kept, got %v", times)
+	}
+
+	if err := s.run("snapshots restore 20200101-000000 --yes", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "no snapshot from") {
+		t.Errorf("Expected a missing snapshot to be an error, got %v", err)
+	}
+	if err := s.run("snapshots restore yesterday --yes", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a bad timestamp to be an error")
+	}
+}
+
+func TestSnapshotsOff(t *testing.T) {
+	s := newTestSession(t)
+	s.store = profile.NewStore(t.TempDir())
+	s.snapshots = snapshot.NewStore(t.TempDir())
+	s.app.config.Snapshots.Every = "off"
+	s.profile.Money++
+	s.run("snapshots list", &bytes.Buffer{})
+	if times, _ := s.snapshots.List("local"); len(times) != 0 {
+		t.Errorf("Expected no snapshots, got %v", times)
+	}
+}
+
+func TestRecoverSave(t *testing.T) {
+	dir := t.TempDir()
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	a.store = profile.NewStore(dir)
+	a.snapshots = snapshot.NewStore(t.TempDir())
+	p := profile.New("ash")
+	p.Money = 500
+	a.store.Save(p)
+	s, _ := a.session("ash")
+	if err := takeSnapshot(s, defaultSnapshotKeep); err != nil {
+		t.Fatalf("takeSnapshot returned error: %v", err)
+	}
+	delete(a.sessions, "ash")
+	os.WriteFile(filepath.Join(dir, "ash.json"), []byte(`{"money": 5`), 0o644)
+
+	if _, err := a.session("ash"); !errors.Is(err, profile.ErrCorrupt) {
+		t.Fatalf("Expected the damaged save to be refused, got %v", err)
+	}
+	answer := "no"
+	input := func() (string, bool) { return answer, true }
+	if restored, err := recoverSave(a, "ash", &bytes.Buffer{}, input); restored || err != nil {
+		t.Errorf("Expected nothing restored when the player says no, got %v, %v", restored, err)
+	}
+	answer = "yes"
+	out := &bytes.Buffer{}
+	if restored, err := recoverSave(a, "ash", out, input); !restored || err != nil {
+		t.Fatalf("recoverSave() = %v, %v", restored, err)
+	}
+	s, err := a.session("ash")
+	if err != nil || s.profile.Money != 500 {
+		t.Errorf("Expected the snapshot's game, got %v", err)
+	}
+	if damaged, err := os.ReadFile(filepath.Join(dir, "ash.json.damaged")); err != nil || string(damaged) != `{"money": 5` {
+		t.Errorf("Expected the damaged save to be kept, got %q, %v", damaged, err)
+	}
+
+	if _, err := recoverSave(a, "misty", out, input); err == nil {
+		t.Errorf("Expected an error without snapshots to restore")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const starterLevel = 5
+
+// starters are the classic grass, fire and water partners of each
+// generation.
+var starters = [][3]string{
+	{"bulbasaur", "charmander", "squirtle"},
+	{"chikorita", "cyndaquil", "totodile"},
+	{"treecko", "torchic", "mudkip"},
+	{"turtwig", "chimchar", "piplup"},
+	{"snivy", "tepig", "oshawott"},
+	{"chespin", "fennekin", "froakie"},
+	{"rowlet", "litten", "popplio"},
+	{"grookey", "scorbunny", "sobble"},
+	{"sprigatito", "fuecoco", "quaxly"},
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "starter",
+		usage:       "starter [pokemon]",
+		description: "List the starter pokemon, or pick your first partner",
+		maxArgs:     1,
+		callback:    commandStarter,
+		complete: func(s *session, args []string) []string {
+			names := []string{}
+			for _, gen := range starters {
+				names = append(names, gen[:]...)
+			}
+			return names
+		},
+	})
+	registerCommand(cliCommand{
+		name:        "party",
+		description: "Show the pokemon travelling with you",
+		callback:    commandParty,
+	})
+}
+
+func isStarter(name string) bool {
+	for _, gen := range starters {
+		if slices.Contains(gen[:], name) {
+			return true
+		}
+	}
+	return false
+}
+
+func commandStarter(s *session, args ...string) error {
+	if s.profile.Starter != "" {
+		return fmt.Errorf("you already chose %s as your starter", s.profile.Starter)
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(s.out, "Choose your starter:")
+		for i, gen := range starters {
+			fmt.Fprintf(s.out, "Gen %d: %s\n", i+1, strings.Join(gen[:], ", "))
+		}
+		fmt.Fprintln(s.out, "Then run: starter <pokemon>")
+		return nil
+	}
+
+	name := args[0]
+	if !isStarter(name) {
+		return errors.New(name + " isn't a starter pokemon")
+	}
+	species, err := s.source.Pokemon(name)
+	if err != nil {
+		return err
+	}
+
+	// Starters are male seven times out of eight.
+	genderRate := 1
+	if details, err := s.source.Species(name); err == nil {
+		genderRate = details.GenderRate
+	}
+	s.profile.Starter = name
+	starter := s.profile.Add(species, starterLevel)
+	mon := s.profile.Get(starter.ID)
+	mon.Gender, mon.Nature, mon.IVs = rollGender(genderRate), rollNature(), rollIVs(0)
+	mon.Met = &profile.Met{Area: s.profile.Location, Level: starterLevel, Time: s.now()}
+	fmt.Fprintf(s.out, "You chose %s! It joins your party at level %d.\n", name, starterLevel)
+	if s.profile.Inventory[expShare] == 0 {
+		s.profile.Give(expShare, 1)
+		s.profile.ExpShare = true
+		fmt.Fprintln(s.out, "You received an Exp. Share! It's on; switch it off with expshare off.")
+	}
+	s.publish(events.Event{Kind: events.Caught, Pokemon: name, Types: typeNames(species), Level: starterLevel})
+	return nil
+}
+
+func commandParty(s *session, args ...string) error {
+	party := s.profile.PartyPokemon()
+	if len(party) == 0 {
+		fmt.Fprintln(s.out, "Your party is empty. Pick a starter with the starter command.")
+		return nil
+	}
+	fmt.Fprintln(s.out, "Your party:")
+	for i, p := range party {
+		hp, full, err := hpOf(s, p)
+		if err != nil {
+			return err
+		}
+		if s.a11y {
+			fmt.Fprintf(s.out, "%d. %s%s%s. Level %d. HP %d of %d.", i+1, p.Species, genderSymbol(s, p.Gender), shinyMark(s, p.Shiny), p.Level, hp, full)
+		} else {
+			fmt.Fprintf(s.out, "%d. %s%s%s (Lv. %d) %d/%d HP", i+1, p.Species, genderSymbol(s, p.Gender), shinyMark(s, p.Shiny), p.Level, hp, full)
+		}
+		switch {
+		case hp == 0 && s.a11y:
+			fmt.Fprint(s.out, " Fainted.")
+		case hp == 0:
+			fmt.Fprint(s.out, ", fainted")
+		}
+		fmt.Fprintln(s.out)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStarterIsGuaranteed(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("starter pikachu", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected pikachu to be refused as a starter")
+	}
+	if err := s.run("starter bulbasaur", &bytes.Buffer{}); err != nil {
+		t.Fatalf("starter returned error: %v", err)
+	}
+	if err := s.run("starter charmander", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a second starter to be refused")
+	}
+
+	party := s.profile.PartyPokemon()
+	if len(party) != 1 || party[0].Species != "bulbasaur" || party[0].Level != starterLevel {
+		t.Errorf("Unexpected party %+v", party)
+	}
+}
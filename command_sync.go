@@ -0,0 +1,52 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "sync",
+		usage:       "sync refresh",
+		description: "Download what changed on PokeAPI into the offline snapshot",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandSync,
+		complete: func(s *session, args []string) []string {
+			return []string{"refresh"}
+		},
+	})
+}
+
+func commandSync(s *session, args ...string) error {
+	if args[0] != "refresh" {
+		return errors.New("usage: sync refresh")
+	}
+	if s.app.snapshotDir == "" {
+		return errors.New("there's no snapshot to refresh; start the game with -source offline")
+	}
+	s.app.mu.Lock()
+	url := cmp.Or(s.app.config.API, pokeapi.DefaultUrl)
+	s.app.mu.Unlock()
+	fmt.Fprintf(s.out, "Refreshing %s from %s...\n", s.app.snapshotDir, url)
+	changes, err := pokeapi.RefreshSnapshot(url, s.app.snapshotDir)
+	changed := false
+	for _, resource := range changes.Resources {
+		updated, added := changes.Updated[resource], changes.Added[resource]
+		if updated > 0 || added > 0 {
+			fmt.Fprintf(s.out, "%s: %d updated, %d new, of %d\n", resource, updated, added, changes.Checked[resource])
+			changed = true
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Fprintln(s.out, "Everything was up to date.")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSyncRefresh(t *testing.T) {
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/pokemon":
+			w.Write([]byte(`{"count": 2, "results": [{"name": "pikachu"}, {"name": "mew"}]}`))
+		case "/api/v2/pokemon/pikachu/", "/api/v2/pokemon/mew/":
+			etag := `"` + r.URL.Path + `"`
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			downloads.Add(1)
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(`{"name": "` + filepath.Base(r.URL.Path) + `", "base_experience": 112}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := newTestSession(t)
+	s.app.config.API = server.URL + "/api/v2/"
+	out := &bytes.Buffer{}
+	if err := s.run("sync refresh", out); err == nil {
+		t.Error("Expected an error without a snapshot")
+	}
+
+	s.app.snapshotDir = t.TempDir()
+	os.MkdirAll(filepath.Join(s.app.snapshotDir, "pokemon"), 0o755)
+	os.WriteFile(filepath.Join(s.app.snapshotDir, "pokemon", "pikachu.json"), []byte(`{"name": "pikachu"}`), 0o644)
+	out.Reset()
+	if err := s.run("sync refresh", out); err != nil {
+		t.Fatalf("sync refresh returned error: %v", err)
+	}
+	if want := "pokemon: 1 updated, 1 new, of 2"; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(s.app.snapshotDir, "pokemon", "mew.json")); !strings.Contains(string(data), "mew") {
+		t.Errorf("Expected mew saved, got %q", data)
+	}
+
+	out.Reset()
+	if err := s.run("sync refresh", out); err != nil {
+		t.Fatalf("sync refresh returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Everything was up to date.") {
+		t.Errorf("Expected nothing to change, got %q", out.String())
+	}
+	if downloads.Load() != 2 {
+		t.Errorf("Expected unchanged pokemon not downloaded again, got %d downloads", downloads.Load())
+	}
+}
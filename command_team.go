@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "team",
+		usage:       "team <coverage|suggest>",
+		description: "Check your party's type coverage, or get a balanced six suggested",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandTeam,
+		complete: func(s *session, args []string) []string {
+			return []string{"coverage", "suggest"}
+		},
+	})
+}
+
+func commandTeam(s *session, args ...string) error {
+	sub, err := match(s, "team command", args[0], []string{"coverage", "suggest"})
+	if err != nil {
+		return errors.New("usage: team <coverage|suggest>")
+	}
+	switch sub {
+	case "coverage":
+		return teamCoverage(s)
+	case "suggest":
+		return teamSuggest(s)
+	}
+	return errors.New("usage: team <coverage|suggest>")
+}
+
+// typeTitle capitalises a type name, as the games write it.
+func typeTitle(t string) string {
+	return strings.ToUpper(t[:1]) + t[1:]
+}
+
+// moveTypes are the types of the damaging moves team knows.
+func moveTypes(s *session, team []*profile.Pokemon) []string {
+	types := []string{}
+	for _, p := range team {
+		for _, m := range knownMoves(s, p) {
+			if m.Class != battle.Status && !slices.Contains(types, m.Type) {
+				types = append(types, m.Type)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// bestEffectiveness is how well the best of moves does against a pokemon of
+// type defender.
+func bestEffectiveness(moves []string, defender string) float64 {
+	best := 0.0
+	for _, m := range moves {
+		best = max(best, battle.Effectiveness(m, []string{defender}))
+	}
+	return best
+}
+
+func teamCoverage(s *session) error {
+	party := s.profile.PartyPokemon()
+	if len(party) == 0 {
+		return errors.New("your party is empty")
+	}
+	moves := moveTypes(s, party)
+	var hit, gaps, resisted []string
+	for _, t := range battle.Types {
+		switch best := bestEffectiveness(moves, t); {
+		case best > 1:
+			hit = append(hit, t)
+		case best == 1:
+			gaps = append(gaps, t)
+		default:
+			resisted = append(resisted, t)
+		}
+	}
+	fmt.Fprintf(s.out, "Move types: %s\n", strings.Join(moves, ", "))
+	fmt.Fprintf(s.out, "Super effective against: %s\n", listOrNone(hit))
+	fmt.Fprintf(s.out, "Nothing super effective against: %s\n", listOrNone(gaps))
+	if len(resisted) > 0 {
+		fmt.Fprintf(s.out, "Every move resisted by: %s\n", strings.Join(resisted, ", "))
+	}
+
+	type weakness struct {
+		attack string
+		count  int
+	}
+	var shared []weakness
+	for _, t := range battle.Types {
+		count := 0
+		for _, p := range party {
+			if battle.Effectiveness(t, typeNames(s.profile.Pokedex[p.Species])) > 1 {
+				count++
+			}
+		}
+		if count > 1 {
+			shared = append(shared, weakness{t, count})
+		}
+	}
+	sort.SliceStable(shared, func(i, j int) bool { return shared[i].count > shared[j].count })
+	if len(shared) == 0 {
+		fmt.Fprintln(s.out, "No weakness is shared by more than one party member")
+		return nil
+	}
+	fmt.Fprintln(s.out, "Shared weaknesses:")
+	for _, w := range shared {
+		fmt.Fprintf(s.out, "  %d/%d party members weak to %s\n", w.count, len(party), s.paint(s.theme.Types[w.attack], typeTitle(w.attack)))
+	}
+	return nil
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// role is the job a pokemon's best stat suits it for.
+func role(p pokeapi.PokemonType) string {
+	best, bestStat := "", -1
+	for _, stat := range statNames {
+		if v := baseStat(p, stat); v > bestStat {
+			best, bestStat = stat, v
+		}
+	}
+	switch best {
+	case "attack":
+		return "physical attacker"
+	case "special-attack":
+		return "special attacker"
+	case "speed":
+		return "fast sweeper"
+	}
+	return "tank"
+}
+
+func baseStatTotal(p pokeapi.PokemonType) int {
+	total := 0
+	for _, stat := range p.Stats {
+		total += stat.BaseStat
+	}
+	return total
+}
+
+// pick is one pokemon teamSuggest chose, and why.
+type pick struct {
+	pokemon *profile.Pokemon
+	score   float64
+	reasons []string
+}
+
+// scorePick rates adding p to team: base stat total counts for most, then
+// covering types nobody on the team hits super effectively yet, filling a
+// role nobody has, and not piling onto a weakness the team already has.
+func scorePick(s *session, team []*profile.Pokemon, p *profile.Pokemon) pick {
+	species := s.profile.Pokedex[p.Species]
+	bst := baseStatTotal(species)
+	r := pick{pokemon: p, score: float64(bst) / 100}
+	r.reasons = append(r.reasons, fmt.Sprintf("BST %d", bst))
+
+	before, after := moveTypes(s, team), moveTypes(s, append(slices.Clone(team), p))
+	var covers []string
+	for _, t := range battle.Types {
+		if bestEffectiveness(after, t) > 1 && bestEffectiveness(before, t) <= 1 {
+			covers = append(covers, t)
+		}
+	}
+	if len(covers) > 0 {
+		r.score += float64(len(covers))
+		r.reasons = append(r.reasons, "hits "+strings.Join(covers, ", ")+" super effectively")
+	}
+
+	job := role(species)
+	roles := []string{}
+	for _, member := range team {
+		roles = append(roles, role(s.profile.Pokedex[member.Species]))
+	}
+	if !slices.Contains(roles, job) {
+		r.score += 3
+		r.reasons = append(r.reasons, "the team's "+job)
+	}
+
+	var stacks []string
+	for _, t := range battle.Types {
+		if battle.Effectiveness(t, typeNames(species)) <= 1 {
+			continue
+		}
+		for _, member := range team {
+			if battle.Effectiveness(t, typeNames(s.profile.Pokedex[member.Species])) > 1 {
+				stacks = append(stacks, typeTitle(t))
+				break
+			}
+		}
+	}
+	if len(stacks) > 0 {
+		r.score -= float64(len(stacks))
+		r.reasons = append(r.reasons, "but shares the weakness to "+strings.Join(stacks, ", "))
+	}
+	return r
+}
+
+// teamSuggest picks a party of six from every pokemon the player owns, one
+// at a time, each time taking the one that adds the most to those picked so
+// far. Only one of each species is picked.
+func teamSuggest(s *session) error {
+	if len(s.profile.Pokemon) == 0 {
+		return errors.New("you haven't caught any pokemon yet")
+	}
+	var team []*profile.Pokemon
+	var picks []pick
+	for len(team) < profile.PartySize {
+		var best *pick
+		for i := range s.profile.Pokemon {
+			p := &s.profile.Pokemon[i]
+			if slices.ContainsFunc(team, func(m *profile.Pokemon) bool { return m.Species == p.Species }) {
+				continue
+			}
+			// Of two equally good picks, the stronger one wins.
+			r := scorePick(s, team, p)
+			if best == nil || r.score > best.score || r.score == best.score && p.Level > best.pokemon.Level {
+				best = &r
+			}
+		}
+		if best == nil {
+			break
+		}
+		team = append(team, best.pokemon)
+		picks = append(picks, *best)
+	}
+
+	fmt.Fprintln(s.out, "Suggested team:")
+	for i, r := range picks {
+		fmt.Fprintf(s.out, "%d. %s (#%d, Lv. %d): %s\n", i+1, r.pokemon.Species, r.pokemon.ID, r.pokemon.Level, strings.Join(r.reasons, "; "))
+	}
+	moves := moveTypes(s, team)
+	covered := 0
+	for _, t := range battle.Types {
+		if bestEffectiveness(moves, t) > 1 {
+			covered++
+		}
+	}
+	fmt.Fprintf(s.out, "Together they hit %d/%d types super effectively.\n", covered, len(battle.Types))
+	return nil
+}
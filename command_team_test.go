@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func typed(name string, types ...string) pokeapi.PokemonType {
+	p := pokeapi.PokemonType{Name: name}
+	for i, t := range types {
+		p.Types = append(p.Types, pokeapi.TypeDetails{Slot: i + 1, Type: pokeapi.Type{Name: t}})
+	}
+	return p
+}
+
+func TestTeamCoverage(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(typed("charmander", "fire"), 10)
+	s.profile.Add(typed("vulpix", "fire"), 10)
+	s.profile.Add(typed("onix", "rock", "ground"), 10)
+	s.profile.Pokemon[0].Moves = []battle.Move{{Name: "ember", Type: "fire", Class: battle.Special}}
+	s.profile.Pokemon[1].Moves = []battle.Move{{Name: "ember", Type: "fire", Class: battle.Special}, {Name: "growl", Type: "normal", Class: battle.Status}}
+	s.profile.Pokemon[2].Moves = []battle.Move{{Name: "rock-throw", Type: "rock", Class: battle.Physical}}
+
+	out := &bytes.Buffer{}
+	if err := s.run("team coverage", out); err != nil {
+		t.Fatalf("team coverage returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Move types: fire, rock\n",
+		"Super effective against: flying, bug, steel, fire, grass, ice\n",
+		"Nothing super effective against: normal, fighting, poison, ground, rock, ghost, water, electric, psychic, dragon, dark, fairy\n",
+		"  3/3 party members weak to Water\n",
+		"  2/3 party members weak to Rock\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the coverage, got %q", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "weak to Grass") {
+		t.Errorf("Expected a weakness of one member not to be listed, got %q", out.String())
+	}
+}
+
+// withStats gives p base stats, in the order of statNames.
+func withStats(p pokeapi.PokemonType, stats ...int) pokeapi.PokemonType {
+	for i, v := range stats {
+		p.Stats = append(p.Stats, pokeapi.StatDetail{BaseStat: v, Stat: pokeapi.Stat{Name: statNames[i]}})
+	}
+	return p
+}
+
+func TestTeamSuggest(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(withStats(typed("rattata", "normal"), 30, 56, 35, 25, 35, 72), 5)
+	s.profile.Add(withStats(typed("dragonite", "dragon", "flying"), 91, 134, 95, 100, 100, 80), 55)
+	s.profile.Add(withStats(typed("dragonite", "dragon", "flying"), 91, 134, 95, 100, 100, 80), 60)
+	s.profile.Add(withStats(typed("snorlax", "normal"), 160, 110, 65, 65, 110, 30), 50)
+	s.profile.Add(withStats(typed("alakazam", "psychic"), 55, 50, 45, 135, 95, 120), 50)
+	s.profile.Add(withStats(typed("lapras", "water", "ice"), 130, 85, 80, 85, 95, 60), 50)
+	s.profile.Add(withStats(typed("jolteon", "electric"), 65, 65, 60, 110, 95, 130), 50)
+	s.profile.Add(withStats(typed("arcanine", "fire"), 90, 110, 80, 100, 80, 95), 50)
+
+	out := &bytes.Buffer{}
+	if err := s.run("team suggest", out); err != nil {
+		t.Fatalf("team suggest returned error: %v", err)
+	}
+	got := out.String()
+	if strings.Count(got, "dragonite") != 1 || !strings.Contains(got, "dragonite (#3, Lv. 60)") {
+		t.Errorf("Expected only the stronger dragonite, got %q", got)
+	}
+	if strings.Contains(got, "rattata") {
+		t.Errorf("Expected rattata to be left out, got %q", got)
+	}
+	if !strings.Contains(got, "alakazam") || !strings.Contains(got, "the team's special attacker") {
+		t.Errorf("Expected alakazam to be picked as the special attacker, got %q", got)
+	}
+	if strings.Count(got, "\n") != 8 {
+		t.Errorf("Expected six picks and a summary, got %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/telegram"
+)
+
+const telegramUsage = "telegram [--token <token>]"
+
+// telegramPoll is how long each request for updates waits for one.
+const telegramPoll = 50 * time.Second
+
+// telegramURL is the Bot API the telegram command talks to.
+var telegramURL = telegram.DefaultURL
+
+// telegramCommands are the commands chats can run through the bot.
+var telegramCommands = map[string]bool{
+	"explore": true,
+	"catch":   true,
+	"throw":   true,
+	"bait":    true,
+	"run":     true,
+	"inspect": true,
+}
+
+const telegramHelp = `/explore <area> - look for wild pokemon in an area
+/catch <pokemon> [ball] - go after a pokemon
+/inspect <pokemon> - see one you've caught
+When a wild pokemon appears, pick a ball, bait or run from the buttons under it.
+`
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "telegram",
+		usage:       telegramUsage,
+		description: "Run a Telegram bot that lets every chat catch pokemon with its own profile",
+		maxArgs:     2,
+		callback:    commandTelegram,
+		complete: func(s *session, args []string) []string {
+			return []string{"--token"}
+		},
+	})
+}
+
+// commandTelegram runs the bot until the program is stopped. It's meant
+// to be run on its own, as pokedexcli telegram --token <token>.
+func commandTelegram(s *session, args ...string) error {
+	token := os.Getenv("TELEGRAM_TOKEN")
+	switch {
+	case len(args) == 2 && args[0] == "--token":
+		// Tokens are case-sensitive, and the line was lowercased.
+		token = s.verbatim(args[1])
+	case len(args) > 0:
+		return errors.New("usage: " + telegramUsage)
+	}
+	if token == "" {
+		return errors.New("the bot needs its token from BotFather: " + telegramUsage + ", or set TELEGRAM_TOKEN")
+	}
+	return telegramBot(s.app, telegram.NewClient(telegramURL, token), s.out)
+}
+
+// telegramBot answers updates as they come in, reporting errors to log.
+// Only failing to reach Telegram at all stops it.
+func telegramBot(a *app, client *telegram.Client, log io.Writer) error {
+	offset, polled := 0, false
+	for {
+		updates, err := client.Updates(offset, telegramPoll)
+		if err != nil {
+			if !polled {
+				return err
+			}
+			fmt.Fprintln(log, "Telegram error:", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		if !polled {
+			fmt.Fprintln(log, "Telegram bot is running.")
+			polled = true
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if err := telegramUpdate(a, client, u); err != nil {
+				fmt.Fprintln(log, "Telegram error:", err)
+			}
+		}
+	}
+}
+
+// telegramUpdate answers a command or a button press in the session of the
+// chat it came from.
+func telegramUpdate(a *app, client *telegram.Client, u telegram.Update) error {
+	var chat int64
+	var line string
+	switch {
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		if err := client.Answer(u.CallbackQuery.ID); err != nil {
+			return err
+		}
+		chat, line = u.CallbackQuery.Message.Chat.ID, u.CallbackQuery.Data
+	case u.Message != nil && strings.HasPrefix(u.Message.Text, "/"):
+		// In groups, commands can be addressed to a bot: /catch@pokedexbot.
+		name, rest, _ := strings.Cut(u.Message.Text[1:], " ")
+		name, _, _ = strings.Cut(name, "@")
+		chat, line = u.Message.Chat.ID, name+" "+rest
+	default:
+		return nil
+	}
+	s, err := a.session("telegram-" + strconv.FormatInt(chat, 10))
+	if err != nil {
+		return err
+	}
+	text, keyboard := telegramRun(s, line)
+	return client.Send(chat, text, keyboard)
+}
+
+// telegramRun runs line in s, returning the reply and the buttons to show
+// under it.
+func telegramRun(s *session, line string) (string, *telegram.Keyboard) {
+	out := &bytes.Buffer{}
+	switch words := cleanInput(line); {
+	case len(words) == 0:
+	case words[0] == "start":
+		s.start(out)
+		fmt.Fprint(out, telegramHelp)
+	case words[0] == "help":
+		fmt.Fprint(out, telegramHelp)
+	case !telegramCommands[words[0]]:
+		fmt.Fprintf(out, "There's no /%s here.\n%s", words[0], telegramHelp)
+	default:
+		if err := s.run(line, out); err != nil {
+			fmt.Fprintln(out, "Error:", err)
+		}
+	}
+
+	text := cmp.Or(strings.TrimSpace(out.String()), "Done.")
+	if len(text) > telegram.MaxText {
+		text = text[:strings.LastIndex(text[:telegram.MaxText-4], "\n")+1] + "..."
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return text, encounterKeyboard(s)
+}
+
+// encounterKeyboard offers the balls in the bag, bait and running away
+// while a wild pokemon is in front of the player.
+func encounterKeyboard(s *session) *telegram.Keyboard {
+	if s.encounter == nil {
+		return nil
+	}
+	var balls []telegram.Button
+	for _, ball := range append(slices.Clone(ballOrder), "master-ball") {
+		if n := s.profile.Inventory[ball]; n > 0 {
+			balls = append(balls, telegram.Button{Text: fmt.Sprintf("%s ×%d", ballName(ball), n), Data: "throw " + ball})
+		}
+	}
+	rows := [][]telegram.Button{{{Text: "Bait", Data: "bait"}, {Text: "Run", Data: "run"}}}
+	if len(balls) > 0 {
+		rows = slices.Insert(rows, 0, balls)
+	}
+	return &telegram.Keyboard{Rows: rows}
+}
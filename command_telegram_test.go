@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/telegram"
+)
+
+func TestTelegramRun(t *testing.T) {
+	s := newTestSession(t)
+	if _, keyboard := telegramRun(s, "help"); keyboard != nil {
+		t.Errorf("Expected no buttons without a wild pokemon, got %+v", keyboard)
+	}
+	if text, _ := telegramRun(s, "reset"); !strings.Contains(text, "There's no /reset here.") {
+		t.Errorf("Expected reset to be refused, got %q", text)
+	}
+
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 3}
+	_, keyboard := telegramRun(s, "help")
+	if keyboard == nil || len(keyboard.Rows) != 2 || keyboard.Rows[0][0].Data != "throw poke-ball" || keyboard.Rows[0][1].Text != "great ball ×3" {
+		t.Fatalf("Expected buttons for the balls in the bag, got %+v", keyboard)
+	}
+	if text, keyboard := telegramRun(s, keyboard.Rows[1][1].Data); s.encounter != nil || keyboard != nil {
+		t.Errorf("Expected the run button to get away, got %q", text)
+	}
+}
+
+func TestTelegramUpdate(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	client := telegram.NewClient(server.URL, "secret")
+
+	u := telegram.Update{Message: &telegram.Message{Chat: telegram.Chat{ID: -42}, Text: "/inspect@pokedexbot pikachu"}}
+	if err := telegramUpdate(a, client, u); err != nil {
+		t.Fatalf("telegramUpdate returned error: %v", err)
+	}
+	if sent["chat_id"] != float64(-42) || sent["text"] != "You haven't caught pikachu" {
+		t.Errorf("Expected an answer in chat -42, got %v", sent)
+	}
+	if _, ok := a.sessions["telegram--42"]; !ok {
+		t.Errorf("Expected the chat to get a session of its own, got %v", a.sessions)
+	}
+}
+
+func TestTelegramToken(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"description":"Unauthorized"}`))
+	}))
+	defer server.Close()
+	defer func(url string) { telegramURL = url }(telegramURL)
+	telegramURL = server.URL
+
+	s := newTestSession(t)
+	if err := s.run("telegram --token 123:AbCdEf", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected the bot to stop when Telegram refuses it")
+	}
+	if !strings.HasPrefix(path, "/bot123:AbCdEf/") {
+		t.Errorf("Expected the token as it was typed, got %q", path)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/azs06/pokedexcli/internal/theme"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "theme",
+		usage:       "theme <list|set <name> [--profile]>",
+		description: "List the color themes, or pick one",
+		minArgs:     1,
+		maxArgs:     3,
+		callback:    commandTheme,
+		complete: func(s *session, args []string) []string {
+			switch len(args) {
+			case 0:
+				return []string{"list", "set"}
+			case 1:
+				return theme.List(s.app.themesDir)
+			}
+			return []string{"--profile"}
+		},
+	})
+}
+
+func commandTheme(s *session, args ...string) error {
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		for _, name := range theme.List(s.app.themesDir) {
+			if name == s.theme.Name {
+				fmt.Fprintf(s.out, "%s (current)\n", name)
+			} else {
+				fmt.Fprintln(s.out, name)
+			}
+		}
+		return nil
+	case args[0] == "set" && len(args) == 2:
+		return commandConfig(s, "theme", args[1])
+	case args[0] == "set" && len(args) == 3 && args[2] == "--profile":
+		return commandConfig(s, "--profile", "theme", args[1])
+	}
+	return errors.New("usage: theme <list|set <name> [--profile]>")
+}
+
+// paint colors text for the terminal, unless the output goes elsewhere or
+// accessibility mode is on.
+func (s *session) paint(color, text string) string {
+	if s.tty == nil || s.out != s.tty || s.a11y {
+		return text
+	}
+	return theme.Paint(color, text)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThemeCommand(t *testing.T) {
+	s := newTestSession(t)
+	s.app.configPath = filepath.Join(t.TempDir(), "config.json")
+	out := &bytes.Buffer{}
+	if err := s.run("theme set gameboy-green", out); err != nil {
+		t.Fatalf("theme set returned error: %v", err)
+	}
+	if s.theme.Name != "gameboy-green" || s.theme.Prompt == "" {
+		t.Errorf("Expected the session to take the theme, got %+v", s.theme)
+	}
+	if err := s.run("theme set neon", out); err == nil || s.theme.Name != "gameboy-green" {
+		t.Errorf("Expected a missing theme to be refused and the old one kept, got %v", err)
+	}
+
+	out.Reset()
+	s.run("theme list", out)
+	if !strings.Contains(out.String(), "gameboy-green (current)\n") {
+		t.Errorf("Expected the current theme to be marked, got %q", out.String())
+	}
+
+	if got := s.paint(s.theme.Highlight, "pikachu"); got != "pikachu" {
+		t.Errorf("Expected output that isn't the terminal's to stay plain, got %q", got)
+	}
+	s.tty, s.out = out, out
+	if got := s.paint(s.theme.Highlight, "pikachu"); !strings.HasPrefix(got, "\033[") {
+		t.Errorf("Expected the terminal's output to be colored, got %q", got)
+	}
+	s.a11y = true
+	if got := s.paint(s.theme.Highlight, "pikachu"); got != "pikachu" {
+		t.Errorf("Expected accessibility mode to leave colors out, got %q", got)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+)
+
+// bracketSize is how many trainers enter a tournament, the player among
+// them.
+const bracketSize = 8
+
+// roundNames name the rounds of a tournament, last first.
+var roundNames = []string{"Final", "Semifinals", "Quarterfinals"}
+
+// tournamentTrainers are the trainers a tournament draws the player's
+// opponents from. Each sticks to one type; their levels match the player's
+// strongest pokemon.
+var tournamentTrainers = []trainer{
+	{"Marina", "Swimmer", []trainerPokemon{{"starmie", 0}, {"tentacruel", 0}, {"seaking", 0}}},
+	{"Clint", "Hiker", []trainerPokemon{{"golem", 0}, {"onix", 0}, {"rhydon", 0}}},
+	{"Skye", "Bird Keeper", []trainerPokemon{{"pidgeot", 0}, {"fearow", 0}, {"dodrio", 0}}},
+	{"Dmitri", "Psychic", []trainerPokemon{{"alakazam", 0}, {"hypno", 0}, {"mr-mime", 0}}},
+	{"Rick", "Bug Catcher", []trainerPokemon{{"butterfree", 0}, {"beedrill", 0}, {"scyther", 0}}},
+	{"Koji", "Black Belt", []trainerPokemon{{"machamp", 0}, {"hitmonlee", 0}, {"primeape", 0}}},
+	{"Hope", "Channeler", []trainerPokemon{{"gengar", 0}, {"haunter", 0}, {"misdreavus", 0}}},
+	{"Darien", "Dragon Tamer", []trainerPokemon{{"dragonite", 0}, {"kingdra", 0}, {"gyarados", 0}}},
+	{"Otis", "Firebreather", []trainerPokemon{{"arcanine", 0}, {"ninetales", 0}, {"magmar", 0}}},
+	{"Flora", "Gardener", []trainerPokemon{{"venusaur", 0}, {"vileplume", 0}, {"victreebel", 0}}},
+	{"Braxton", "Engineer", []trainerPokemon{{"raichu", 0}, {"magneton", 0}, {"electrode", 0}}},
+}
+
+// tournament is a single-elimination bracket the player is fighting
+// through. The player's party is healed before each round, and items
+// can't be used in its battles.
+type tournament struct {
+	// entrants are the trainers in the bracket, in bracket order; the
+	// player's entry has no team.
+	entrants []trainer
+	round    int
+	level    int
+	format   battle.Format
+	ai       battle.AI
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "tournament",
+		usage:       "tournament [--difficulty <level>] [--double]",
+		description: "Enter a knockout tournament against trainers with themed teams",
+		maxArgs:     3,
+		callback:    commandTournament,
+		complete:    completeBattleArgs,
+	})
+}
+
+func (t *tournament) roundName() string {
+	if i := bits.Len(uint(len(t.entrants))) - 2; i < len(roundNames) {
+		return roundNames[i]
+	}
+	return fmt.Sprintf("Round %d", t.round)
+}
+
+// opponent is who the player faces this round.
+func (t *tournament) opponent() trainer {
+	for i, entrant := range t.entrants {
+		if entrant.team == nil {
+			return t.entrants[i^1]
+		}
+	}
+	return trainer{}
+}
+
+func entrantName(t trainer) string {
+	if t.title == "" {
+		return t.name
+	}
+	return t.title + " " + t.name
+}
+
+func commandTournament(s *session, args ...string) error {
+	party := s.profile.PartyPokemon()
+	if len(party) == 0 {
+		return errors.New("you need a party to enter a tournament")
+	}
+	ai, format, err := parseBattleArgs(args, "normal")
+	if err != nil {
+		return err
+	}
+	level := 0
+	for _, p := range party {
+		level = max(level, p.Level)
+	}
+
+	entrants := []trainer{{name: s.profile.Name}}
+	for _, i := range rand.Perm(len(tournamentTrainers))[:bracketSize-1] {
+		t := tournamentTrainers[i]
+		team := make([]trainerPokemon, len(t.team))
+		for j, member := range t.team {
+			team[j] = trainerPokemon{member.species, level}
+		}
+		entrants = append(entrants, trainer{t.name, t.title, team})
+	}
+	rand.Shuffle(len(entrants), func(i, j int) { entrants[i], entrants[j] = entrants[j], entrants[i] })
+	s.tournament = &tournament{entrants: entrants, round: 1, level: level, format: format, ai: ai}
+	fmt.Fprintf(s.out, "Welcome to the tournament! %d trainers, one winner. Your party is healed before every round, and no items are allowed.\n", bracketSize)
+	return nextRound(s)
+}
+
+// nextRound shows the round's matches, heals the player's party and starts
+// the player's battle.
+func nextRound(s *session) error {
+	t := s.tournament
+	fmt.Fprintf(s.out, "%s:\n", t.roundName())
+	for i := 0; i < len(t.entrants); i += 2 {
+		fmt.Fprintf(s.out, " - %s vs %s\n", entrantName(t.entrants[i]), entrantName(t.entrants[i+1]))
+	}
+	for _, p := range s.profile.PartyPokemon() {
+		p.Damage = 0
+	}
+	player, err := playerSide(s)
+	if err != nil {
+		s.tournament = nil
+		return err
+	}
+	foe := t.opponent()
+	opponent, err := trainerSide(s, foe)
+	if err != nil {
+		s.tournament = nil
+		return err
+	}
+	fmt.Fprintf(s.out, "%s wants to battle!\n", entrantName(foe))
+	startBattle(s, player, opponent, t.format, t.ai, tournamentResult)
+	return nil
+}
+
+// playMatch fights a match between two computer trainers out, returning the
+// winner. A match that drags on is settled by a coin toss.
+func playMatch(s *session, a, b trainer) (trainer, error) {
+	t := s.tournament
+	sides := [2]*battle.Side{}
+	for i, entrant := range []trainer{a, b} {
+		side, err := trainerSide(s, entrant)
+		if err != nil {
+			return trainer{}, err
+		}
+		sides[i] = side
+	}
+	match := battle.New(sides[0], sides[1], t.format, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+	match.Rules = s.rules[t.format]
+	for !match.Over() && match.Turn < maxAutoTurns {
+		var actions [2][]battle.Action
+		for side := range match.Sides {
+			for slot := range match.Sides[side].Active {
+				actions[side] = append(actions[side], battle.Gimmicks(match, side, slot, t.ai(match, side, slot)))
+			}
+		}
+		match.Play(actions)
+	}
+	if match.Winner == 1 || !match.Over() && rand.IntN(2) == 1 {
+		return b, nil
+	}
+	return a, nil
+}
+
+// tournamentResult knocks the player out of the tournament or, once the
+// other matches of the round are played, sends them on to the next round.
+// Winning the final puts the party in the Hall of Fame.
+func tournamentResult(s *session, b *battle.Battle) error {
+	t := s.tournament
+	foe := t.opponent()
+	if b.Winner != 0 {
+		s.tournament = nil
+		fmt.Fprintf(s.out, "You were knocked out of the tournament in the %s by %s.\n", t.roundName(), entrantName(foe))
+		return nil
+	}
+	fmt.Fprintf(s.out, "You beat %s!\n", entrantName(foe))
+
+	winners := []trainer{}
+	for i := 0; i < len(t.entrants); i += 2 {
+		first, second := t.entrants[i], t.entrants[i+1]
+		if first.team == nil {
+			winners = append(winners, first)
+			continue
+		}
+		if second.team == nil {
+			winners = append(winners, second)
+			continue
+		}
+		winner, err := playMatch(s, first, second)
+		if err != nil {
+			s.tournament = nil
+			return err
+		}
+		loser := first
+		if winner.name == first.name {
+			loser = second
+		}
+		fmt.Fprintf(s.out, "%s beat %s\n", entrantName(winner), entrantName(loser))
+		winners = append(winners, winner)
+	}
+
+	if len(winners) > 1 {
+		t.entrants = winners
+		t.round++
+		return nextRound(s)
+	}
+	s.tournament = nil
+	prize := 300 * t.level
+	s.profile.Money += prize
+	enterHallOfFame(s, "tournament")
+	fmt.Fprintf(s.out, "You won the tournament and %d Pokédollars! Your team has been entered into the Hall of Fame.\n", prize)
+	awardTeamRibbon(s, tournamentRibbon)
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestTournament(t *testing.T) {
+	s := newTestSession(t)
+	mew := pokeapi.PokemonType{Name: "mew"}
+	for _, stat := range []string{"hp", "attack", "defense", "speed"} {
+		mew.Stats = append(mew.Stats, pokeapi.StatDetail{BaseStat: 100, Stat: pokeapi.Stat{Name: stat}})
+	}
+	s.profile.Add(mew, 50)
+	s.profile.Give("potion", 1)
+	out := &bytes.Buffer{}
+	if err := s.run("tournament", out); err != nil {
+		t.Fatalf("tournament returned error: %v", err)
+	}
+	if s.state() != stateBattle || !strings.Contains(out.String(), "Quarterfinals:") {
+		t.Fatalf("Expected the quarterfinals to start, got %q", out.String())
+	}
+	if err := s.run("use potion mew", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected items to be banned in tournament battles")
+	}
+
+	out.Reset()
+	for turn := 0; s.battle != nil; turn++ {
+		if turn == 500 {
+			t.Fatalf("Expected the tournament to end")
+		}
+		// Damage from earlier rounds is healed before the next.
+		if s.battle.Turn == 0 && s.battle.Sides[0].Team[0].HP != s.battle.Sides[0].Team[0].Stats.HP {
+			t.Fatalf("Expected mew to be healed before the round")
+		}
+		if err := s.run("fight tackle", out); err != nil {
+			t.Fatalf("fight returned error: %v", err)
+		}
+	}
+	for _, round := range []string{"Semifinals:", "Final:", "You won the tournament"} {
+		if !strings.Contains(out.String(), round) {
+			t.Errorf("Expected %q, got %q", round, out.String())
+		}
+	}
+	if len(s.profile.HallOfFame) != 1 || s.profile.HallOfFame[0].Event != "tournament" {
+		t.Fatalf("Expected the win in the Hall of Fame, got %+v", s.profile.HallOfFame)
+	}
+	if ribbons := s.profile.Pokemon[0].Ribbons; !slices.Equal(ribbons, []string{tournamentRibbon}) {
+		t.Errorf("Expected the tournament ribbon, got %v", ribbons)
+	}
+	out.Reset()
+	s.run("halloffame", out)
+	if !strings.Contains(out.String(), "(tournament): mew (Lv. 50)") {
+		t.Errorf("Expected the win to be listed, got %q", out.String())
+	}
+}
+
+func TestTournamentKnockout(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	if err := s.run("tournament", &bytes.Buffer{}); err != nil {
+		t.Fatalf("tournament returned error: %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("forfeit", out); err != nil {
+		t.Fatalf("forfeit returned error: %v", err)
+	}
+	if s.tournament != nil || !strings.Contains(out.String(), "knocked out of the tournament in the Quarterfinals") {
+		t.Errorf("Expected forfeiting to end the tournament, got %q", out.String())
+	}
+	if len(s.profile.HallOfFame) != 0 {
+		t.Errorf("Expected no Hall of Fame entry, got %+v", s.profile.HallOfFame)
+	}
+}
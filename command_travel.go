@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/azs06/pokedexcli/internal/world"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "travel",
+		usage:       "travel [area]",
+		description: "Walk to a neighbouring area, or list the ones you can reach",
+		maxArgs:     1,
+		callback:    commandTravel,
+	})
+	registerCommand(cliCommand{
+		name:        "goto",
+		usage:       "goto <area>",
+		description: "Walk to a neighbouring area",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandTravel,
+	})
+	registerCommand(cliCommand{
+		name:        "whereami",
+		description: "Show where you are",
+		callback:    commandWhereAmI,
+	})
+}
+
+func commandWhereAmI(s *session, args ...string) error {
+	if s.profile.Location == "" {
+		fmt.Fprintln(s.out, "You haven't set off yet. Pick any area from map and travel there.")
+		return nil
+	}
+	fmt.Fprintf(s.out, "You are in %s", s.profile.Location)
+	if area, err := s.source.LocationArea(s.profile.Location); err == nil && area.Location.Name != "" {
+		fmt.Fprintf(s.out, " (%s", area.Location.Name)
+		if location, err := s.source.Location(area.Location.Name); err == nil && location.Region.Name != "" {
+			fmt.Fprintf(s.out, ", %s", location.Region.Name)
+		}
+		fmt.Fprint(s.out, ")")
+	}
+	fmt.Fprintln(s.out)
+	fmt.Fprintf(s.out, "You've walked %d steps\n", s.profile.Steps)
+	return nil
+}
+
+func commandTravel(s *session, args ...string) error {
+	if len(args) == 0 {
+		return listNeighbours(s)
+	}
+	return travelTo(s, args[0])
+}
+
+func listNeighbours(s *session) error {
+	if s.profile.Location == "" {
+		fmt.Fprintln(s.out, "You haven't set off yet. Pick any area from map and travel there.")
+		return nil
+	}
+	neighbours, err := world.Neighbours(s.source, s.profile.Location)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "You are in %s. From here you can reach:\n", s.profile.Location)
+	if len(neighbours) == 0 {
+		fmt.Fprintln(s.out, " nowhere, it seems")
+	}
+	for _, n := range neighbours {
+		fmt.Fprintf(s.out, " - %s (%d steps)\n", n.Area, n.Steps)
+	}
+	return nil
+}
+
+// travelTo moves the player to area. The first trip can go anywhere; after
+// that only neighbouring areas are in reach.
+func travelTo(s *session, area string) error {
+	if area == s.profile.Location {
+		fmt.Fprintf(s.out, "You're already in %s\n", area)
+		return nil
+	}
+	if s.profile.Location == "" {
+		if _, err := s.source.LocationArea(area); err != nil {
+			return err
+		}
+		s.profile.Location = area
+		fmt.Fprintf(s.out, "You set off from %s\n", area)
+		return nil
+	}
+
+	neighbours, err := world.Neighbours(s.source, s.profile.Location)
+	if err != nil {
+		return err
+	}
+	n, ok := world.Find(neighbours, area)
+	if !ok {
+		return fmt.Errorf("%s is too far away from %s, run travel to see where you can go", area, s.profile.Location)
+	}
+	s.profile.Location = n.Area
+	s.profile.Steps += n.Steps
+	fmt.Fprintf(s.out, "You walked %d steps to %s\n", n.Steps, n.Area)
+	return nil
+}
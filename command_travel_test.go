@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/world"
+)
+
+func TestTravelOnlyToNeighbours(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("travel pallet-town-area", &bytes.Buffer{}); err != nil {
+		t.Fatalf("first travel returned error: %v", err)
+	}
+	if err := s.run("travel viridian-city-area", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected viridian-city to be out of reach from pallet-town")
+	}
+	if err := s.run("travel route-1-area", &bytes.Buffer{}); err != nil {
+		t.Fatalf("travel to a neighbour returned error: %v", err)
+	}
+	if s.profile.Location != "route-1-area" || s.profile.Steps != world.NextLocationSteps {
+		t.Errorf("Expected to be on route-1 after %d steps, got %s after %d", world.NextLocationSteps, s.profile.Location, s.profile.Steps)
+	}
+}
+
+func TestExploreDefaultsToCurrentArea(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("explore", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected bare explore to fail before setting off")
+	}
+
+	s.run("goto pallet-town-area", &bytes.Buffer{})
+	if err := s.run("explore", &bytes.Buffer{}); err != nil {
+		t.Fatalf("explore returned error: %v", err)
+	}
+	if !s.profile.Visited["pallet-town-area"] {
+		t.Errorf("Expected bare explore to explore the current area")
+	}
+
+	s.run("explore route-1-area", &bytes.Buffer{})
+	if s.profile.Visited["route-1-area"] {
+		t.Errorf("Expected a remote area to be looked up, not visited")
+	}
+	if got := s.prompt(); got != "Pokedex [pallet-town-area] > " {
+		t.Errorf("Unexpected prompt %q", got)
+	}
+}
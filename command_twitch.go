@@ -0,0 +1,185 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/twitch"
+)
+
+const twitchUsage = "twitch [channel] [--window <seconds>]"
+
+// defaultVoteWindow is how long chat has to vote each round.
+const defaultVoteWindow = 20 * time.Second
+
+// voteCooldown is how often a viewer's messages count, so spamming a vote
+// doesn't help.
+const voteCooldown = 2 * time.Second
+
+// chatCommands are the commands chat can vote for; the game's state narrows
+// them down further.
+var chatCommands = map[string]bool{
+	"catch":   true,
+	"throw":   true,
+	"bait":    true,
+	"run":     true,
+	"battle":  true,
+	"fight":   true,
+	"switch":  true,
+	"explore": true,
+	"travel":  true,
+	"map":     true,
+	"mapb":    true,
+	"heal":    true,
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "twitch",
+		usage:       twitchUsage,
+		description: "Let a Twitch channel's chat play by voting on commands",
+		maxArgs:     3,
+		callback:    commandTwitch,
+		complete: func(s *session, args []string) []string {
+			return []string{"--window"}
+		},
+	})
+}
+
+// commandTwitch joins the channel's chat and plays whatever it votes for
+// until a moderator stops it.
+func commandTwitch(s *session, args ...string) error {
+	channel, window := s.twitch.Channel, time.Duration(s.twitch.Window)*time.Second
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--window" && i+1 < len(args):
+			i++
+			seconds, err := strconv.Atoi(args[i])
+			if err != nil || seconds < 1 {
+				return fmt.Errorf("the window must be a number of seconds, not %q", args[i])
+			}
+			window = time.Duration(seconds) * time.Second
+		case !strings.HasPrefix(args[i], "--"):
+			channel = args[i]
+		default:
+			return errors.New("usage: " + twitchUsage)
+		}
+	}
+	if channel == "" {
+		return errors.New("usage: " + twitchUsage + ", or set twitch.channel in the config")
+	}
+	channel = strings.ToLower(strings.TrimPrefix(channel, "#"))
+
+	fmt.Fprintf(s.out, "Joining #%s...\n", channel)
+	chat, err := twitch.Dial(twitch.DefaultAddr, cmp.Or(s.twitch.Nick, channel), s.twitch.Token, channel)
+	if err != nil {
+		return err
+	}
+	defer chat.Close()
+	if s.twitch.Token == "" {
+		fmt.Fprintln(s.out, "No Twitch token is set, so the results won't be posted in chat.")
+	}
+	mods := map[string]bool{channel: true}
+	for _, name := range s.twitch.Moderators {
+		mods[strings.ToLower(name)] = true
+	}
+	return twitchPlays(s, chat, cmp.Or(window, defaultVoteWindow), mods)
+}
+
+// twitchPlays runs a round of voting every window, playing the command
+// with the most votes. Votes are chat messages like !catch or !fight
+// tackle. Moderators can also !do any command right away, and !stop the
+// game.
+func twitchPlays(s *session, chat *twitch.Client, window time.Duration, mods map[string]bool) error {
+	messages, failed, done := make(chan twitch.Message), make(chan error, 1), make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			m, err := chat.Read()
+			if err != nil {
+				failed <- err
+				return
+			}
+			select {
+			case messages <- m:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	announce := func(text string) {
+		fmt.Fprintln(s.out, text)
+		if err := chat.Say(text); err != nil {
+			fmt.Fprintln(s.out, "Twitch error:", err)
+		}
+	}
+	play := func(line string) error {
+		err := s.exec(cleanInput(line))
+		if err != nil && !errors.Is(err, errExit) {
+			fmt.Fprintln(s.out, "Error:", err)
+			return nil
+		}
+		return err
+	}
+
+	announce(fmt.Sprintf("Twitch plays Pokedex! Vote with a command such as !catch, !run or !fight tackle; every %s the most popular one is played.", window))
+	poll, limiter := twitch.NewPoll(), twitch.NewLimiter(voteCooldown)
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-failed:
+			return fmt.Errorf("lost the connection to chat: %w", err)
+		case m := <-messages:
+			words := cleanInput(strings.TrimPrefix(m.Text, "!"))
+			if !strings.HasPrefix(m.Text, "!") || len(words) == 0 {
+				continue
+			}
+			if mods[m.User] {
+				switch {
+				case len(words) == 1 && words[0] == "stop":
+					announce(m.User + " stopped the game.")
+					return nil
+				case words[0] == "do" && len(words) > 1 && words[1] != "twitch":
+					announce(m.User + " played " + strings.Join(words[1:], " ") + ".")
+					if err := play(strings.Join(words[1:], " ")); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if line, ok := chatVote(s, words); ok && limiter.Allow(m.User, time.Now()) {
+				poll.Vote(m.User, line)
+			}
+		case <-ticker.C:
+			line, votes, total := poll.Result()
+			poll.Reset()
+			if total == 0 {
+				continue
+			}
+			announce(fmt.Sprintf("Chat voted for %s (%d of %d votes).", line, votes, total))
+			if err := play(line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// chatVote is the command line chat voted for with words, if it's one chat
+// can play right now.
+func chatVote(s *session, words []string) (string, bool) {
+	cmd, ok := commands[words[0]]
+	if !ok || !chatCommands[cmd.name] || !s.state().allows(cmd.name) || cmd.checkArgs(words[1:]) != nil {
+		return "", false
+	}
+	// Chat only gets to battle what's in front of it, not other players.
+	if cmd.name == "battle" && len(words) > 1 {
+		return "", false
+	}
+	return strings.Join(words, " "), true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/twitch"
+)
+
+func TestTwitchPlays(t *testing.T) {
+	s := newTestSession(t)
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 3}
+	out := &bytes.Buffer{}
+	s.out = out
+
+	server, conn := net.Pipe()
+	defer server.Close()
+	go func() {
+		lines := bufio.NewScanner(server)
+		say := func(user, text string) {
+			server.Write([]byte(":" + user + "!" + user + "@" + user + ".tmi.twitch.tv PRIVMSG #stream :" + text + "\r\n"))
+		}
+		for lines.Scan() {
+			switch {
+			case strings.Contains(lines.Text(), "Twitch plays Pokedex!"):
+				say("ash", "!run")
+				// Voting again straight away is ignored.
+				say("ash", "!catch")
+				say("misty", "!catch")
+				say("brock", "!map")
+				say("brock", "!run")
+			case strings.Contains(lines.Text(), "Chat voted for"):
+				say("oak", "!stop")
+			}
+		}
+	}()
+	chat, err := twitch.New(conn, "bot", "token", "stream")
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if err := twitchPlays(s, chat, 50*time.Millisecond, map[string]bool{"oak": true}); err != nil {
+		t.Fatalf("twitchPlays returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Chat voted for run (2 of 3 votes).") || !strings.Contains(out.String(), "oak stopped the game.") {
+		t.Errorf("Expected chat to vote to run until oak stopped the game, got %q", out.String())
+	}
+	if s.encounter != nil {
+		t.Errorf("Expected chat's vote to run from rattata")
+	}
+}
+
+func TestChatVote(t *testing.T) {
+	s := newTestSession(t)
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 3}
+	for _, words := range [][]string{{"map"}, {"battle", "host"}, {"reset"}, {"run", "away"}, {"nonsense"}} {
+		if _, ok := chatVote(s, words); ok {
+			t.Errorf("Expected chat not to be able to vote for %v", words)
+		}
+	}
+	if line, ok := chatVote(s, []string{"catch", "great"}); !ok || line != "catch great" {
+		t.Errorf("Expected chat to be able to vote for catch great, got %q", line)
+	}
+}
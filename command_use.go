@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "use",
+		usage:       "use <item> <pokemon>",
+		description: "Use a vitamin or healing item from your bag on a pokemon",
+		minArgs:     2,
+		maxArgs:     2,
+		callback:    commandUse,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return slices.Sorted(maps.Keys(usable()))
+			}
+			return completeCaught(s, args)
+		},
+	})
+}
+
+// usable are the items that can be used on a pokemon.
+func usable() map[string]bool {
+	items := map[string]bool{}
+	for item := range vitamins {
+		items[item] = true
+	}
+	for item := range medicines {
+		items[item] = true
+	}
+	return items
+}
+
+func commandUse(s *session, args ...string) error {
+	item := args[0]
+	if !usable()[item] {
+		return fmt.Errorf("%s can't be used on a pokemon", item)
+	}
+	if s.profile.Inventory[item] == 0 {
+		return fmt.Errorf("you don't have any %s", item)
+	}
+	p, err := findPokemon(s, args[1])
+	if err != nil {
+		return err
+	}
+	if m, ok := medicines[item]; ok {
+		return useMedicine(s, item, m, p)
+	}
+	if s.battle != nil {
+		return fmt.Errorf("%s can't be used in a battle", item)
+	}
+	stat := vitamins[item]
+	n := addEVs(p, stat, vitaminEVs)
+	if n == 0 {
+		return fmt.Errorf("it won't have any effect on %s", p.Species)
+	}
+	s.profile.Use(item)
+	fmt.Fprintf(s.out, "%s gained %d %s EVs (%d/%d)\n", p.Species, n, stat, p.EVs[stat], maxStatEVs)
+	return nil
+}
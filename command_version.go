@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/azs06/pokedexcli/internal/release"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse --short HEAD) -X main.date=$(date -u +%Y-%m-%d)"
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "version",
+		description: "Show version and build information",
+		callback:    commandVersion,
+	})
+	registerCommand(cliCommand{
+		name:        "update",
+		usage:       "update check",
+		description: "Check GitHub for a newer release",
+		minArgs:     1,
+		maxArgs:     1,
+		callback:    commandUpdate,
+		complete: func(s *session, args []string) []string {
+			return []string{"check"}
+		},
+	})
+}
+
+// buildInfo fills in commit and date from the Go toolchain's VCS stamp when
+// they weren't set with ldflags.
+func buildInfo() (rev, built string) {
+	rev, built = commit, date
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return rev, built
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && rev == "":
+			rev = setting.Value
+			if len(rev) > 12 {
+				rev = rev[:12]
+			}
+		case setting.Key == "vcs.time" && built == "":
+			built = setting.Value
+		}
+	}
+	return rev, built
+}
+
+func commandVersion(s *session, args ...string) error {
+	rev, built := buildInfo()
+	if rev == "" {
+		rev = "unknown"
+	}
+	if built == "" {
+		built = "unknown"
+	}
+	fmt.Fprintf(s.out, "pokedexcli %s\n", version)
+	fmt.Fprintf(s.out, "Commit: %s\n", rev)
+	fmt.Fprintf(s.out, "Built: %s\n", built)
+	fmt.Fprintf(s.out, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return nil
+}
+
+func commandUpdate(s *session, args ...string) error {
+	if args[0] != "check" {
+		return fmt.Errorf("usage: update check")
+	}
+	latest, err := release.Latest(release.DefaultRepo)
+	if err != nil {
+		return err
+	}
+	if !release.Newer(latest.Tag, version) {
+		fmt.Fprintf(s.out, "You're up to date (%s)\n", version)
+		return nil
+	}
+	fmt.Fprintf(s.out, "A newer version is available: %s (you have %s)\n", latest.Tag, version)
+	fmt.Fprintln(s.out, latest.Url)
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/wondertrade"
+)
+
+const wonderTradeUsage = "wondertrade <pokemon|collect> [--yes]"
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "wondertrade",
+		usage:       wonderTradeUsage,
+		description: "Trade a pokemon on the wonder trade server for whatever another player sent",
+		minArgs:     1,
+		maxArgs:     2,
+		callback:    commandWonderTrade,
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return append(completeCaught(s, args), "collect")
+			}
+			return []string{"--yes"}
+		},
+	})
+}
+
+// commandWonderTrade sends a pokemon to the wonder trade server, once the
+// player has agreed to part with it, and takes whatever comes back. If
+// nobody else's pokemon is waiting, the player's waits instead and what it
+// gets is picked up later with collect. Players are known on the server by
+// their profile name.
+func commandWonderTrade(s *session, args ...string) error {
+	yes := slices.Contains(args, "--yes")
+	args = slices.DeleteFunc(args, func(arg string) bool { return arg == "--yes" })
+	if len(args) != 1 {
+		return errors.New("usage: " + wonderTradeUsage)
+	}
+	c := wondertrade.NewClient(s.wonderTrade)
+	if args[0] == "collect" {
+		mail, err := c.Collect(s.profile.Name)
+		if err != nil {
+			return err
+		}
+		if len(mail) == 0 {
+			fmt.Fprintln(s.out, "Nothing has come back from wonder trade yet")
+		}
+		for _, d := range mail {
+			receiveTraded(s, d)
+		}
+		return nil
+	}
+
+	p, err := findPokemon(s, args[0])
+	if err != nil {
+		return err
+	}
+	if len(s.profile.Pokemon) == 1 {
+		return errors.New("you can't trade away your only pokemon")
+	}
+	d := wondertrade.Deposit{Trainer: s.profile.Name, Pokemon: *p, Species: s.profile.Pokedex[p.Species]}
+	if err := wondertrade.Validate(d); err != nil {
+		return fmt.Errorf("%s can't be traded: %w", p.Species, err)
+	}
+	fmt.Fprintf(s.out, "Sending %s to wonder trade\n", ownedText(s, p))
+	if !yes {
+		answer, ok := s.ask("Type 'yes' to trade it away: ")
+		if !ok {
+			return errors.New("can't confirm here, add --yes")
+		}
+		if answer != "yes" {
+			fmt.Fprintln(s.out, "Trade cancelled")
+			return nil
+		}
+	}
+
+	t, err := c.Trade(d)
+	if err != nil {
+		return err
+	}
+	s.profile.Remove(p.ID)
+	if t.Received == nil {
+		fmt.Fprintf(s.out, "%s is waiting for a partner. Use 'wondertrade collect' later to see what it was traded for.\n", d.Pokemon.Species)
+		return nil
+	}
+	fmt.Fprintf(s.out, "You sent %s away.\n", d.Pokemon.Species)
+	receiveTraded(s, *t.Received)
+	return nil
+}
+
+// receiveTraded adds a pokemon that came back from wonder trade.
+func receiveTraded(s *session, d wondertrade.Deposit) {
+	mon := s.profile.Receive(d.Pokemon, d.Species)
+	fmt.Fprintf(s.out, "You received %s (Lv. %d) from %s!\n", mon.Species, mon.Level, d.Trainer)
+}
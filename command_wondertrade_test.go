@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/wondertrade"
+)
+
+func TestWonderTrade(t *testing.T) {
+	sv, err := wondertrade.NewServer("")
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+
+	red, blue := newTestSession(t), newTestSession(t)
+	red.profile.Name, blue.profile.Name = "red", "blue"
+	red.wonderTrade, blue.wonderTrade = ts.URL, ts.URL
+	red.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+	if err := red.run("wondertrade pikachu --yes", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected trading away the only pokemon to fail")
+	}
+	sent := red.profile.Add(pokeapi.PokemonType{Name: "zubat"}, 3)
+	red.profile.Get(sent.ID).Item = "oran-berry"
+	blue.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 10)
+	blue.profile.Add(pokeapi.PokemonType{Name: "vulpix"}, 12)
+
+	red.input = func() (string, bool) { return "no", true }
+	out := &bytes.Buffer{}
+	if err := red.run("wondertrade zubat", out); err != nil {
+		t.Fatalf("wondertrade returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Trade cancelled") || len(red.profile.Pokemon) != 2 {
+		t.Errorf("Expected nothing to be traded without a yes, got %q", out.String())
+	}
+	red.input = func() (string, bool) { return "yes", true }
+	out.Reset()
+	if err := red.run("wondertrade zubat", out); err != nil {
+		t.Fatalf("wondertrade returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "zubat is waiting for a partner") || len(red.profile.Pokemon) != 1 {
+		t.Errorf("Expected zubat to wait on the server, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := blue.run("wondertrade vulpix --yes", out); err != nil {
+		t.Fatalf("wondertrade returned error: %v", err)
+	}
+	if want := "You received zubat (Lv. 3) from red!"; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	zubat := blue.profile.Pokemon[len(blue.profile.Pokemon)-1]
+	if zubat.Species != "zubat" || zubat.OT != "red" || zubat.Item != "oran-berry" || !blue.profile.Seen["zubat"] {
+		t.Errorf("Expected blue to get red's zubat as it was, got %+v", zubat)
+	}
+	if _, ok := blue.profile.Pokedex["vulpix"]; !ok {
+		t.Errorf("Expected the pokedex to keep the vulpix traded away")
+	}
+
+	out.Reset()
+	if err := red.run("wondertrade collect", out); err != nil {
+		t.Fatalf("wondertrade collect returned error: %v", err)
+	}
+	if want := "You received vulpix (Lv. 12) from blue!"; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if _, ok := red.profile.Pokedex["vulpix"]; !ok || len(red.profile.Pokemon) != 2 {
+		t.Errorf("Expected vulpix to join red's pokemon and pokedex, got %+v", red.profile.Pokemon)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"cmp"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// playCry plays p's cry on the REPL's computer when the sound setting is
+// on. It's fetched and played in the background, and skipped without a
+// word when there's no player or no cry to be had.
+func playCry(s *session, p pokeapi.PokemonType) {
+	a := s.app
+	url := cmp.Or(p.Cries.Latest, p.Cries.Legacy)
+	if !s.sound || s.tty == nil || s.out != s.tty || a.player == nil || a.cries == nil || url == "" {
+		return
+	}
+	go func() {
+		if path, err := a.cries.File(url); err == nil {
+			a.player.Play(path)
+		}
+	}()
+}
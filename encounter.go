@@ -0,0 +1,399 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// encounterChance is how likely exploring an area is to run into one of its
+// pokemon.
+const encounterChance = 0.5
+
+// maxBait is as much as bait adds up to.
+const maxBait = 2
+
+// defaultCaptureRate is used when a species' capture rate is unknown.
+const defaultCaptureRate = 45
+
+// replState is where the player is in the game. The dispatcher only accepts
+// the commands the current state allows.
+type replState int
+
+const (
+	stateRoaming replState = iota
+	stateEncounter
+	stateBattle
+)
+
+// encounterCommands are the only commands accepted while a wild pokemon is
+// in front of the player.
+var encounterCommands = map[string]bool{
+	"catch":  true,
+	"throw":  true,
+	"bait":   true,
+	"run":    true,
+	"battle": true,
+	"help":   true,
+	"exit":   true,
+}
+
+func (st replState) allows(command string) bool {
+	switch st {
+	case stateEncounter:
+		return encounterCommands[command]
+	case stateBattle:
+		return battleCommands[command]
+	}
+	return true
+}
+
+// encounter is a wild pokemon the player has run into.
+type encounter struct {
+	species     pokeapi.PokemonType
+	level       int
+	captureRate int
+	legendary   bool
+	// featured is set for the day's featured pokemon, easier to catch.
+	featured bool
+	gender   string
+	nature   string
+	shiny    bool
+	maxHP    int
+	hp       int
+	// roamer is set for roaming legendaries, which flee after one turn.
+	roamer bool
+	// baited counts bait thrown since the last ball; it makes the next throw
+	// more likely to work and the pokemon less likely to flee.
+	baited int
+	// failedThrows makes the pokemon more restless after every ball it
+	// breaks out of.
+	failedThrows int
+	// runAttempts feeds the escape formula, which gets easier every try.
+	runAttempts int
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "throw",
+		usage:       "throw [ball]",
+		description: "Throw a ball at the wild pokemon",
+		maxArgs:     1,
+		callback:    commandThrow,
+		complete:    completeBalls,
+	})
+	registerCommand(cliCommand{
+		name:        "bait",
+		usage:       "bait [berry]",
+		description: "Throw bait, or a berry, to make the wild pokemon easier to catch",
+		maxArgs:     1,
+		callback:    commandBait,
+		complete:    completeBerries,
+	})
+	registerCommand(cliCommand{
+		name:        "run",
+		description: "Try to get away from the wild pokemon",
+		callback:    commandRun,
+	})
+}
+
+func (s *session) state() replState {
+	if s.battle != nil {
+		return stateBattle
+	}
+	if s.encounter != nil {
+		return stateEncounter
+	}
+	return stateRoaming
+}
+
+// statAt is a stat's value at a level with iv IVs and ev EVs in it, ignoring
+// natures.
+func statAt(base, iv, ev, level int) int {
+	return (2*base+iv+ev/4)*level/100 + 5
+}
+
+// hpAt is the HP stat at a level, which grows faster than the others.
+func hpAt(base, iv, ev, level int) int {
+	return (2*base+iv+ev/4)*level/100 + level + 10
+}
+
+func baseStat(p pokeapi.PokemonType, name string) int {
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == name {
+			return stat.BaseStat
+		}
+	}
+	return 0
+}
+
+// encounterLevel picks a level within the ranges the area lists for a
+// pokemon, falling back to wildLevel.
+func encounterLevel(enc pokeapi.PokemonEncounter) int {
+	low, high := 0, 0
+	for _, version := range enc.VersionDetails {
+		for _, detail := range version.EncounterDetails {
+			if low == 0 || detail.MinLevel < low {
+				low = detail.MinLevel
+			}
+			high = max(high, detail.MaxLevel)
+		}
+	}
+	if low <= 0 || high < low {
+		return wildLevel
+	}
+	return low + rand.IntN(high-low+1)
+}
+
+// encounterWeight is how often a pokemon shows up relative to the others in
+// its area.
+func encounterWeight(enc pokeapi.PokemonEncounter) int {
+	weight := 0
+	for _, version := range enc.VersionDetails {
+		weight = max(weight, version.MaxChance)
+	}
+	return max(weight, 1)
+}
+
+// pickEncounter chooses one of encounters at random, weighted by how common
+// each is.
+func pickEncounter(encounters []pokeapi.PokemonEncounter) (pokeapi.PokemonEncounter, bool) {
+	total := 0
+	for _, enc := range encounters {
+		total += encounterWeight(enc)
+	}
+	if total == 0 {
+		return pokeapi.PokemonEncounter{}, false
+	}
+	roll := rand.IntN(total)
+	for _, enc := range encounters {
+		roll -= encounterWeight(enc)
+		if roll < 0 {
+			return enc, true
+		}
+	}
+	return encounters[len(encounters)-1], true
+}
+
+// spawnEncounter puts a wild pokemon from an area's table in front of the
+// player.
+func spawnEncounter(s *session, enc pokeapi.PokemonEncounter) error {
+	species, err := s.source.Pokemon(enc.Pokemon.Name)
+	if err != nil {
+		return err
+	}
+	return meetPokemon(s, species, encounterLevel(enc))
+}
+
+// meetPokemon starts an encounter with p at level.
+func meetPokemon(s *session, p pokeapi.PokemonType, level int) error {
+	speciesName := p.Species.Name
+	if speciesName == "" {
+		speciesName = p.Name
+	}
+	captureRate, legendary, gender, featured := defaultCaptureRate, false, "", false
+	species, err := s.source.Species(speciesName)
+	if err == nil {
+		captureRate, legendary = species.CaptureRate, species.IsLegendary || species.IsMythical
+		featured = species.ID == featuredNumber(s.now())
+		gender = rollGender(species.GenderRate)
+	} else if !errors.Is(err, pokeapi.ErrNotFound) {
+		return err
+	}
+
+	s.profile.See(p.Name)
+	maxHP := hpAt(baseStat(p, "hp"), 0, 0, level)
+	s.encounter = &encounter{
+		species:     p,
+		level:       level,
+		captureRate: captureRate,
+		legendary:   legendary,
+		featured:    featured,
+		gender:      gender,
+		nature:      rollNature(),
+		shiny:       rand.IntN(shinyOdds(s.shinyOdds, comboFor(s, p.Name))) == 0,
+		maxHP:       maxHP,
+		hp:          maxHP,
+	}
+	wild := "A wild "
+	if s.encounter.shiny {
+		wild = "A wild shiny "
+	}
+	fmt.Fprintf(s.out, "%s%s%s%s (Lv. %d) appeared!\n", typeIcons(s, typeNames(p)), wild, p.Name, genderSymbol(s, gender), level)
+	drawSprite(s, p, s.encounter.shiny)
+	playCry(s, p)
+	if featured {
+		fmt.Fprintln(s.out, "It's today's featured pokemon!")
+	}
+	fmt.Fprintln(s.out, "What will you do? catch, battle, bait or run")
+	s.publish(events.Event{Kind: events.Encountered, Pokemon: p.Name, Types: typeNames(p), Level: level, Shiny: s.encounter.shiny})
+	return nil
+}
+
+// throwAtEncounter throws a ball from the bag at the wild pokemon. The ball
+// is used up whatever happens. If the pokemon breaks free it may flee,
+// ending the encounter.
+func throwAtEncounter(s *session, args ...string) error {
+	enc := s.encounter
+	ball, err := chooseBall(s, args...)
+	if err != nil {
+		return err
+	}
+	s.profile.Use(ball)
+	s.animate(throwFrames(fmt.Sprintf("Throwing a %s at %s...", ballName(ball), enc.species.Name))...)
+
+	shakes := catchShakes(enc, ballBonuses[ball])
+	enc.baited = 0
+	for range min(shakes, 3) {
+		s.animate(shakeFrames("...the ball shakes...")...)
+	}
+	p := enc.species
+	if shakes == 4 {
+		s.encounter = nil
+		s.animate(outcomeFrames(true, s.paint(s.theme.Highlight, p.Name+" was caught"))...)
+		playCry(s, p)
+		_, seen := s.profile.Pokedex[p.Name]
+		caught := s.profile.Add(p, enc.level)
+		mon := s.profile.Get(caught.ID)
+		mon.Gender, mon.Nature, mon.Shiny = enc.gender, enc.nature, enc.shiny
+		mon.IVs = rollIVs(perfectIVs(comboFor(s, p.Name)))
+		mon.Met = &profile.Met{Area: s.profile.Location, Ball: ball, Level: enc.level, Time: s.now()}
+		if mon.Mark = rollMark(s.now(), rand.IntN); mon.Mark != "" {
+			fmt.Fprintf(s.out, "%s has the %s!\n", p.Name, ribbonName(mon.Mark))
+		}
+		continueCombo(s, p.Name)
+		if enc.roamer {
+			s.profile.RoamersCaught = append(s.profile.RoamersCaught, p.Name)
+		}
+		s.publish(events.Event{Kind: events.Caught, Pokemon: p.Name, Types: typeNames(p), Area: s.profile.Location, Level: enc.level, Shiny: enc.shiny})
+		if !seen && slices.Contains(events.Milestones, len(s.profile.Pokedex)) {
+			s.publish(events.Event{Kind: events.Milestone, Count: len(s.profile.Pokedex)})
+		}
+		return nil
+	}
+
+	s.animate(outcomeFrames(false, p.Name+" escaped")...)
+	s.publish(events.Event{Kind: events.Escaped, Pokemon: p.Name, Types: typeNames(p), Level: enc.level})
+	enc.failedThrows++
+	if enc.roamer || rand.Float64() < fleeChance(enc) {
+		flee(s)
+	}
+	return nil
+}
+
+// flee ends the encounter with the wild pokemon running off.
+func flee(s *session) {
+	enc := s.encounter
+	s.encounter = nil
+	fmt.Fprintf(s.out, "%s fled!\n", enc.species.Name)
+	breakCombo(s)
+	s.publish(events.Event{Kind: events.Fled, Pokemon: enc.species.Name, Types: typeNames(enc.species), Level: enc.level})
+}
+
+// catchShakes runs the Gen III/IV capture check and returns how many times
+// the ball shook; 4 means the pokemon was caught.
+func catchShakes(enc *encounter, ballBonus float64) int {
+	if ballBonus >= masterBallBonus {
+		return 4
+	}
+	a := float64(3*enc.maxHP-2*enc.hp) * float64(enc.captureRate) * ballBonus / float64(3*enc.maxHP)
+	a *= 1 + 0.5*float64(enc.baited)
+	if enc.featured {
+		a *= featuredBonus
+	}
+	if a >= 255 {
+		return 4
+	}
+	if a <= 0 {
+		return 0
+	}
+	b := 1048560 / math.Sqrt(math.Sqrt(16711680/a))
+	shakes := 0
+	for shakes < 4 && float64(rand.IntN(65536)) < b {
+		shakes++
+	}
+	return shakes
+}
+
+// fleeChance is how likely the pokemon is to run off after breaking free.
+func fleeChance(enc *encounter) float64 {
+	chance := 0.1 + 0.05*float64(enc.failedThrows)
+	if enc.legendary {
+		chance += 0.15
+	}
+	if enc.baited > 0 {
+		chance /= 2
+	}
+	return min(chance, 0.9)
+}
+
+func commandThrow(s *session, args ...string) error {
+	if s.encounter == nil {
+		return errors.New("there's nothing to throw a ball at")
+	}
+	return throwAtEncounter(s, args...)
+}
+
+func commandBait(s *session, args ...string) error {
+	if s.encounter == nil {
+		return errors.New("there's nothing to bait")
+	}
+	if len(args) > 0 {
+		// A berry works as well as two lots of bait.
+		berry, err := parseBerry(s, args[0])
+		if err != nil {
+			return err
+		}
+		s.profile.Use(berry)
+		s.encounter.baited = maxBait
+		fmt.Fprintf(s.out, "%s is busy eating the %s!\n", s.encounter.species.Name, ballName(berry))
+	} else {
+		s.encounter.baited = min(s.encounter.baited+1, maxBait)
+		fmt.Fprintf(s.out, "%s is busy eating the bait!\n", s.encounter.species.Name)
+	}
+	if s.encounter.roamer {
+		flee(s)
+	}
+	return nil
+}
+
+// escapes is the Gen III escape formula: a faster lead always gets away,
+// otherwise the odds out of 256 grow with every attempt.
+func escapes(playerSpeed, wildSpeed, attempts int) bool {
+	if playerSpeed >= wildSpeed || wildSpeed == 0 {
+		return true
+	}
+	odds := playerSpeed*128/wildSpeed + 30*attempts
+	return odds > 255 || rand.IntN(256) < odds
+}
+
+func commandRun(s *session, args ...string) error {
+	enc := s.encounter
+	if enc == nil {
+		return errors.New("there's nothing to run from")
+	}
+	enc.runAttempts++
+
+	playerSpeed := 0
+	if party := s.profile.PartyPokemon(); len(party) > 0 {
+		lead := party[0]
+		playerSpeed = statAt(baseStat(s.profile.Pokedex[lead.Species], "speed"), lead.IVs["speed"], lead.EVs["speed"], lead.Level)
+	} else {
+		playerSpeed = statAt(baseStat(enc.species, "speed"), 0, 0, enc.level)
+	}
+	wildSpeed := statAt(baseStat(enc.species, "speed"), 0, 0, enc.level)
+
+	if !escapes(playerSpeed, wildSpeed, enc.runAttempts) {
+		fmt.Fprintln(s.out, "Can't escape!")
+		return nil
+	}
+	fmt.Fprintln(s.out, "Got away safely!")
+	s.encounter = nil
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestEncounterRestrictsCommands(t *testing.T) {
+	s := newTestSession(t)
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 3}
+
+	out := &bytes.Buffer{}
+	s.run("map", out)
+	if !strings.Contains(out.String(), "A wild rattata is in your way") {
+		t.Errorf("Expected map to be refused during an encounter, got %q", out.String())
+	}
+
+	if err := s.run("run", &bytes.Buffer{}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if s.encounter != nil {
+		t.Errorf("Expected an equally fast player to get away")
+	}
+	if err := s.run("run", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected run without an encounter to fail")
+	}
+}
+
+func TestEscapes(t *testing.T) {
+	if !escapes(50, 40, 1) {
+		t.Errorf("Expected a faster pokemon to always escape")
+	}
+	if !escapes(1, 255, 9) {
+		t.Errorf("Expected repeated attempts to guarantee an escape")
+	}
+}
+
+func TestThrowUsesBalls(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Inventory = map[string]int{"master-ball": 1}
+
+	if err := s.run("catch mew master", &bytes.Buffer{}); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	if _, ok := s.profile.Pokedex["mew"]; !ok {
+		t.Errorf("Expected a master ball to always catch")
+	}
+	if len(s.profile.Inventory) != 0 {
+		t.Errorf("Expected the master ball to be used up, got %v", s.profile.Inventory)
+	}
+	if err := s.run("catch mew", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an error with an empty bag")
+	}
+	if s.encounter != nil {
+		t.Errorf("Expected no encounter without a ball to throw")
+	}
+}
+
+func TestCatchNamingEncounter(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Inventory = map[string]int{"poke-ball": 1, "master-ball": 1}
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 3}
+
+	if err := s.run("catch rattata master", &bytes.Buffer{}); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	if s.profile.Inventory["master-ball"] != 0 || s.profile.Inventory["poke-ball"] != 1 {
+		t.Errorf("Expected the master ball to be thrown at rattata, got %v", s.profile.Inventory)
+	}
+
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "pidgey"}, level: 3}
+	s.run("catch pidgey", &bytes.Buffer{})
+	if s.profile.Inventory["poke-ball"] != 0 {
+		t.Errorf("Expected catch pidgey to throw the default ball at pidgey, got %v", s.profile.Inventory)
+	}
+}
+
+func TestParseBall(t *testing.T) {
+	for _, arg := range []string{"great", "greatball", "great-ball"} {
+		if ball, ok := parseBall(arg); !ok || ball != "great-ball" {
+			t.Errorf("parseBall(%q) = %q, %v", arg, ball, ok)
+		}
+	}
+	if _, ok := parseBall("potion"); ok {
+		t.Errorf("Expected potion not to be a ball")
+	}
+}
+
+func TestBattleWeakensWildPokemon(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 50, maxHP: 60, hp: 60}
+
+	if err := s.run("battle --difficulty impossible", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an unknown difficulty to be refused")
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("battle --difficulty hard", out); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Your pikachu: 60/60 HP | Foe rattata: 60/60 HP") {
+		t.Errorf("Expected the battle HUD, got %q", out.String())
+	}
+	s.run("fight tackle", &bytes.Buffer{})
+	if s.battle != nil {
+		s.run("forfeit", &bytes.Buffer{})
+	}
+	if s.encounter != nil && s.encounter.hp >= 60 {
+		t.Errorf("Expected the wild pokemon to be weakened, got %d HP", s.encounter.hp)
+	}
+	if s.state() == stateBattle {
+		t.Errorf("Expected the battle to be over")
+	}
+}
+
+func TestDoubleWildBattle(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 50)
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 50, maxHP: 60, hp: 60}
+
+	out := &bytes.Buffer{}
+	if err := s.run("battle --double", out); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if len(s.battle.Sides[1].Active) != 2 || !strings.Contains(out.String(), "What will pikachu do?") {
+		t.Fatalf("Expected a double battle, got %q", out.String())
+	}
+	if err := s.run("fight tackle 3", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected a missing target to be refused")
+	}
+	out.Reset()
+	s.run("fight tackle 2", out)
+	if s.battle.Turn != 0 || !strings.Contains(out.String(), "What will eevee do?") {
+		t.Errorf("Expected the turn to wait for eevee, got %q", out.String())
+	}
+	s.run("fight tackle ally", &bytes.Buffer{})
+	if s.battle != nil && s.battle.Turn != 1 {
+		t.Errorf("Expected the turn to be played, got turn %d", s.battle.Turn)
+	}
+}
+
+func TestFightMegaAndDynamax(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "charizard"}, 50)
+	s.profile.Pokemon[0].Item = "charizardite-x"
+	s.encounter = &encounter{species: pokeapi.PokemonType{Name: "rattata"}, level: 50, maxHP: 60, hp: 60}
+	s.run("battle", &bytes.Buffer{})
+
+	if err := s.run("fight tackle --mega", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected mega evolution without a mega ring to be refused")
+	}
+	if err := s.run("fight tackle --dynamax", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected dynamaxing while holding a mega stone to be refused")
+	}
+	s.run("forfeit", &bytes.Buffer{})
+
+	s.profile.Give(megaRing, 1)
+	s.run("battle", &bytes.Buffer{})
+	out := &bytes.Buffer{}
+	if err := s.run("fight tackle --mega", out); err != nil {
+		t.Fatalf("fight returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "charizard has mega evolved into charizard-mega-x!") {
+		t.Errorf("Expected charizard to mega evolve, got %q", out.String())
+	}
+}
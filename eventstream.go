@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/webhooks"
+	"github.com/azs06/pokedexcli/internal/websocket"
+)
+
+// eventBacklog is how far a slow client can fall behind before it misses
+// events, rather than hold up the game.
+const eventBacklog = 64
+
+// eventPing is how often clients are pinged, to notice ones that are gone.
+const eventPing = 30 * time.Second
+
+// eventMessage is what /events sends for each event: the event, and a
+// line describing it as webhooks do.
+type eventMessage struct {
+	events.Event
+	Text string `json:"text"`
+}
+
+// eventsHandler streams bus's events to WebSocket clients as JSON, one
+// message per event. ?kinds=caught,leveled_up picks the kinds sent.
+func eventsHandler(bus *events.Bus) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var kinds []string
+		if k := r.URL.Query().Get("kinds"); k != "" {
+			kinds = strings.Split(k, ",")
+		}
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		queue := make(chan events.Event, eventBacklog)
+		unsubscribe := bus.SubscribeAll(func(e events.Event) {
+			if len(kinds) > 0 && !slices.Contains(kinds, string(e.Kind)) {
+				return
+			}
+			select {
+			case queue <- e:
+			default:
+			}
+		})
+		defer unsubscribe()
+		// Clients have nothing to say, so reading only finds out when they
+		// leave.
+		left := make(chan struct{})
+		go func() {
+			defer close(left)
+			for {
+				if _, _, err := conn.Read(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ping := time.NewTicker(eventPing)
+		defer ping.Stop()
+		for {
+			select {
+			case <-left:
+				return
+			case <-ping.C:
+				if conn.Ping() != nil {
+					return
+				}
+			case e := <-queue:
+				data, err := json.Marshal(eventMessage{Event: e, Text: webhooks.Summary(e)})
+				if err != nil || conn.WriteText(data) != nil {
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/websocket"
+)
+
+func TestEventsHandler(t *testing.T) {
+	bus := events.NewBus()
+	server := httptest.NewServer(eventsHandler(bus))
+	defer server.Close()
+
+	c, err := websocket.Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/events?kinds=caught")
+	if err != nil {
+		t.Fatalf("Dial() returned error: %v", err)
+	}
+	defer c.Close()
+	// The handler subscribes once the handshake is done, so keep publishing
+	// until it's listening.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			bus.Publish(events.Event{Kind: events.Escaped, Session: "ash", Pokemon: "mew"})
+			bus.Publish(events.Event{Kind: events.Caught, Session: "ash", Pokemon: "pikachu"})
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	_, payload, err := c.Read()
+	if err != nil {
+		t.Fatalf("Read() returned error: %v", err)
+	}
+	var got eventMessage
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("Expected a JSON event, got %s", payload)
+	}
+	if got.Kind != events.Caught || got.Pokemon != "pikachu" || got.Text != "ash caught pikachu!" {
+		t.Errorf("Expected only catches to be sent, got %+v", got)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const maxLevel = 100
+
+// expBarWidth is how many blocks wide inspect's experience bar is.
+const expBarWidth = 20
+
+// expForLevel is the total experience needed to reach level on the growth
+// rate named rate, as PokeAPI names them. Unknown rates are medium-fast,
+// which PokeAPI calls "medium".
+func expForLevel(rate string, level int) int {
+	n := level
+	if n <= 1 {
+		return 0
+	}
+	cube := n * n * n
+	switch rate {
+	case "slow":
+		return 5 * cube / 4
+	case "fast":
+		return 4 * cube / 5
+	case "medium-slow":
+		return 6*cube/5 - 15*n*n + 100*n - 140
+	case "slow-then-very-fast": // Erratic
+		switch {
+		case n < 50:
+			return cube * (100 - n) / 50
+		case n < 68:
+			return cube * (150 - n) / 100
+		case n < 98:
+			return cube * ((1911 - 10*n) / 3) / 500
+		}
+		return cube * (160 - n) / 100
+	case "fast-then-very-slow": // Fluctuating
+		switch {
+		case n < 15:
+			return cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			return cube * (n + 14) / 50
+		}
+		return cube * (n/2 + 32) / 50
+	}
+	return cube
+}
+
+// growthRate is the name of p's species' growth rate, or empty if it can't
+// be looked up.
+func growthRate(s *session, p *profile.Pokemon) string {
+	species, err := s.source.Species(speciesName(s.profile.Pokedex[p.Species]))
+	if err != nil {
+		return ""
+	}
+	return species.GrowthRate.Name
+}
+
+// expBar shows how far p is from its level to the next.
+func expBar(s *session, p *profile.Pokemon) string {
+	if p.Level >= maxLevel {
+		return "max level"
+	}
+	rate := growthRate(s, p)
+	floor, next := expForLevel(rate, p.Level), expForLevel(rate, p.Level+1)
+	done := min(max(p.Exp, floor)-floor, next-floor)
+	if s.a11y {
+		return fmt.Sprintf("%d of %d to level %d", done, next-floor, p.Level+1)
+	}
+	filled := done * expBarWidth / (next - floor)
+	return fmt.Sprintf("[%s%s] %d/%d to Lv. %d", strings.Repeat("█", filled), strings.Repeat("░", expBarWidth-filled), done, next-floor, p.Level+1)
+}
+
+// defeatExp is the Gen I experience for knocking out foe, before it's
+// shared out; trainers' pokemon are worth half again as much.
+func defeatExp(foe *battle.Pokemon, trainer bool) int {
+	exp := max(foe.BaseExperience, 1) * foe.Level / 7
+	if trainer {
+		exp = exp * 3 / 2
+	}
+	return max(exp, 1)
+}
+
+// awardExperience shares the experience for every fainted foe between the
+// player's pokemon that took part and are still standing. With the Exp.
+// Share on, each of them gets all of it instead, and the rest of the party
+// that's still standing half.
+func awardExperience(s *session, b *battle.Battle) {
+	share := s.profile.ExpShare && s.profile.Inventory[expShare] > 0
+	var earners, benched []*battle.Pokemon
+	for _, p := range b.Sides[0].Team {
+		switch {
+		case p.Fainted():
+		case p.Battled:
+			earners = append(earners, p)
+		case share:
+			benched = append(benched, p)
+		}
+	}
+	if len(earners) == 0 {
+		return
+	}
+	total := 0
+	for _, foe := range b.Sides[1].Team {
+		if foe.Fainted() {
+			total += defeatExp(foe, b.Sides[1].Name != "")
+		}
+	}
+	if total == 0 {
+		return
+	}
+	each := max(total/len(earners), 1)
+	if share {
+		each = total
+	}
+	for _, earner := range append(earners, benched...) {
+		p := s.profile.Get(earner.ID)
+		if p == nil {
+			continue
+		}
+		exp := each
+		if !earner.Battled {
+			exp = max(total/2, 1)
+		}
+		// Traded pokemon and Lucky Egg holders each get half as much again.
+		if s.profile.Traded(p) {
+			exp = exp * 3 / 2
+		}
+		if p.Item == battle.LuckyEgg {
+			exp = exp * 3 / 2
+		}
+		gainExp(s, p, exp)
+		// Unlike experience, every earner gets each foe's full EV yield.
+		// So do those of the party the Exp. Share gave experience to.
+		for _, foe := range b.Sides[1].Team {
+			if foe.Fainted() {
+				gainEVs(p, foe.EffortYield)
+			}
+		}
+	}
+}
+
+// gainExp adds exp to p, levelling it up as far as it goes.
+func gainExp(s *session, p *profile.Pokemon, exp int) {
+	// Pokemon caught before experience existed start at their level's floor.
+	rate := growthRate(s, p)
+	p.Exp = max(p.Exp, expForLevel(rate, p.Level)) + exp
+	fmt.Fprintf(s.out, "%s gained %d experience\n", p.Species, exp)
+	for p.Level < maxLevel && p.Exp >= expForLevel(rate, p.Level+1) {
+		p.Level++
+		fmt.Fprintf(s.out, "%s grew to level %d!\n", p.Species, p.Level)
+		s.publish(events.Event{Kind: events.LeveledUp, Pokemon: p.Species, Types: typeNames(s.profile.Pokedex[p.Species]), Level: p.Level})
+		learnLevelUpMoves(s, p)
+	}
+}
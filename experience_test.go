@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestGrowthRates(t *testing.T) {
+	cases := []struct {
+		rate     string
+		level    int
+		expected int
+	}{
+		{"medium", 100, 1000000},
+		{"", 10, 1000},
+		{"slow", 100, 1250000},
+		{"fast", 100, 800000},
+		{"medium-slow", 50, 117360},
+		{"medium-slow", 1, 0},
+		{"slow-then-very-fast", 100, 600000},
+		{"slow-then-very-fast", 60, 194400},
+		{"fast-then-very-slow", 100, 1640000},
+		{"fast-then-very-slow", 10, 540},
+	}
+	for _, c := range cases {
+		if got := expForLevel(c.rate, c.level); got != c.expected {
+			t.Errorf("Expected level %d on %q to need %d experience, got %d", c.level, c.rate, c.expected, got)
+		}
+	}
+}
+
+func TestLevelUpFollowsGrowthRate(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "magikarp"}, 10)
+	p := &s.profile.Pokemon[0]
+	// Magikarp grows slowly, from 1250 experience at level 10; a
+	// medium-fast pokemon would be at level 13 with what it ends up with.
+	gainExp(s, p, 1000)
+	if p.Level != 12 || p.Exp != 2250 {
+		t.Errorf("Expected magikarp to reach level 12 with 2250 experience, got %d and %d", p.Level, p.Exp)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("inspect magikarp", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "  Exp: [███░░░░░░░░░░░░░░░░░] 90/586 to Lv. 13\n") {
+		t.Errorf("Expected an experience bar, got %q", out.String())
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFirstVisitBonus(t *testing.T) {
+	s := newTestSession(t)
+	s.run("explore pallet-town-area", &bytes.Buffer{})
+	s.run("explore pallet-town-area", &bytes.Buffer{})
+	if s.profile.Money != firstVisitBonus {
+		t.Errorf("Expected one bonus of %d, got %d", firstVisitBonus, s.profile.Money)
+	}
+
+	out := &bytes.Buffer{}
+	s.run("map", out)
+	if !strings.Contains(out.String(), "pallet-town-area (visited)") {
+		t.Errorf("Expected a visited marker, got %q", out.String())
+	}
+
+	s.next = ""
+	out.Reset()
+	s.run("map --unvisited", out)
+	if strings.Contains(out.String(), "pallet-town-area") {
+		t.Errorf("Expected visited areas to be hidden, got %q", out.String())
+	}
+}
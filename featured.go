@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// featuredBonus multiplies the catch rate of the day's featured pokemon.
+const featuredBonus = 2
+
+// streakWeek is how many days in a row earn a bonus item, and streakItem
+// that item.
+const (
+	streakWeek = 7
+	streakItem = "ultra-ball"
+)
+
+// featuredNumber is the national pokedex number of the pokemon featured on
+// the day of t. It's the same for every player.
+func featuredNumber(t time.Time) int {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "featured/%s", t.Format(time.DateOnly))
+	return 1 + int(h.Sum64()%uint64(generations[len(generations)-1].last))
+}
+
+// streakMoney is what playing on the streak's nth day in a row pays; it
+// grows for a week.
+func streakMoney(n int) int {
+	return 50 * min(n, streakWeek)
+}
+
+// checkIn shows the day's featured pokemon and, the first time the player
+// plays each day, pays for their streak of days in a row.
+func checkIn(s *session) {
+	now := s.now()
+	featured := "#" + strconv.Itoa(featuredNumber(now))
+	if species, err := s.source.Species(strconv.Itoa(featuredNumber(now))); err == nil {
+		featured = species.Name
+	}
+	fmt.Fprintf(s.out, "Today's featured pokemon is %s: it's %dx as easy to catch until midnight.\n", featured, featuredBonus)
+
+	if s.readOnly {
+		return
+	}
+	streak, first := s.profile.CheckIn(now)
+	if !first {
+		return
+	}
+	money := streakMoney(streak)
+	s.profile.Money += money
+	reward := fmt.Sprintf("%d Pokédollars", money)
+	if streak%streakWeek == 0 {
+		s.profile.Give(streakItem, 1)
+		reward += " and an " + ballName(streakItem)
+	}
+	if streak == 1 {
+		fmt.Fprintf(s.out, "Thanks for playing today! You got %s. Come back tomorrow to start a streak.\n", reward)
+		return
+	}
+	fmt.Fprintf(s.out, "Day %d of your streak! You got %s.\n", streak, reward)
+}
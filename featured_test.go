@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeaturedNumber(t *testing.T) {
+	day := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	n := featuredNumber(day)
+	if n < 1 || n > generations[len(generations)-1].last {
+		t.Errorf("Expected a national pokedex number, got %d", n)
+	}
+	if featuredNumber(day.Add(12*time.Hour)) != n {
+		t.Error("Expected the same pokemon all day")
+	}
+}
+
+func TestCheckInStreak(t *testing.T) {
+	s := newTestSession(t)
+	day := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return day }
+	out := &bytes.Buffer{}
+	s.start(out)
+	if !strings.Contains(out.String(), "Today's featured pokemon is ") || !strings.Contains(out.String(), "You got 50 Pokédollars.") {
+		t.Errorf("Expected the featured pokemon and a first day's pay, got %q", out.String())
+	}
+	money := s.profile.Money
+
+	out.Reset()
+	s.start(out)
+	if s.profile.Money != money || strings.Contains(out.String(), "You got") {
+		t.Errorf("Expected no more pay the same day, got %q", out.String())
+	}
+
+	for i := 2; i <= streakWeek; i++ {
+		day = day.AddDate(0, 0, 1)
+		out.Reset()
+		s.start(out)
+	}
+	if want := "Day 7 of your streak! You got 350 Pokédollars and an ultra ball."; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if s.profile.Inventory[streakItem] != 1 {
+		t.Errorf("Expected an %s for a week in a row, got %d", streakItem, s.profile.Inventory[streakItem])
+	}
+
+	day = day.AddDate(0, 0, 2)
+	s.start(&bytes.Buffer{})
+	if s.profile.Streak != 1 {
+		t.Errorf("Expected a missed day to end the streak, got %d", s.profile.Streak)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// formAliases are the ways players write a form, by the suffix PokeAPI
+// gives it: "alolan-raichu" and "raichu-alolan" are both raichu-alola.
+var formAliases = map[string]string{
+	"alolan":     "alola",
+	"alola":      "alola",
+	"galarian":   "galar",
+	"galar":      "galar",
+	"hisuian":    "hisui",
+	"hisui":      "hisui",
+	"paldean":    "paldea",
+	"paldea":     "paldea",
+	"mega":       "mega",
+	"gigantamax": "gmax",
+	"gmax":       "gmax",
+}
+
+// formName rewrites name the way PokeAPI names forms: the species first,
+// then the form, then a mega's X or Y.
+func formName(name string) string {
+	words := strings.Split(name, "-")
+	form, ok := formAliases[words[0]]
+	if !ok || len(words) == 1 {
+		for i, w := range words[1:] {
+			if alias, ok := formAliases[w]; ok {
+				words[i+1] = alias
+			}
+		}
+		return strings.Join(words, "-")
+	}
+	base, suffix := words[1:], []string{}
+	if last := base[len(base)-1]; form == "mega" && len(base) > 1 && (last == "x" || last == "y") {
+		base, suffix = base[:len(base)-1], []string{last}
+	}
+	return strings.Join(append(append(base, form), suffix...), "-")
+}
+
+// resolvePokemon looks a pokemon up by name, accepting the usual ways of
+// writing a form, and a species name for species, like deoxys, whose default
+// form has a name of its own.
+func resolvePokemon(s *session, name string) (pokeapi.PokemonType, error) {
+	name = formName(name)
+	p, err := s.source.Pokemon(name)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return p, err
+	}
+
+	species, speciesName := pokeapi.PokemonSpecies{}, name
+	for {
+		species, err = s.source.Species(speciesName)
+		if err == nil || !errors.Is(err, pokeapi.ErrNotFound) {
+			break
+		}
+		i := strings.LastIndex(speciesName, "-")
+		if i < 0 {
+			return pokemonStarting(s, name)
+		}
+		speciesName = speciesName[:i]
+	}
+	if err != nil {
+		return pokeapi.PokemonType{}, err
+	}
+	forms := []string{}
+	for _, v := range species.Varieties {
+		if v.IsDefault && speciesName == name {
+			return s.source.Pokemon(v.Pokemon.Name)
+		}
+		forms = append(forms, v.Pokemon.Name)
+	}
+	if len(forms) == 0 {
+		return pokeapi.PokemonType{}, fmt.Errorf("there's no pokemon called %s", name)
+	}
+	if choice, ok := s.choose(fmt.Sprintf("%s has no form called %s. Did you mean:", species.Name, name), forms); ok {
+		return s.source.Pokemon(choice)
+	}
+	return pokeapi.PokemonType{}, fmt.Errorf("%s has no form called %s; try %s", species.Name, name, strings.Join(forms, ", "))
+}
+
+// pokemonStarting looks up the pokemon whose name starts with prefix, as
+// far as the data source can list names without the network.
+func pokemonStarting(s *session, prefix string) (pokeapi.PokemonType, error) {
+	lister, ok := s.source.(pokeapi.Lister)
+	if !ok {
+		return pokeapi.PokemonType{}, fmt.Errorf("there's no pokemon called %s", prefix)
+	}
+	names, err := lister.Names("pokemon")
+	if err != nil {
+		return pokeapi.PokemonType{}, err
+	}
+	name, err := match(s, "pokemon", prefix, names)
+	if errors.Is(err, errNoMatch) {
+		return pokeapi.PokemonType{}, fmt.Errorf("there's no pokemon called %s", prefix)
+	}
+	if err != nil {
+		return pokeapi.PokemonType{}, err
+	}
+	return s.source.Pokemon(name)
+}
+
+// form is the form a pokemon is in, such as "alola", or "" for pokemon named
+// after their species.
+func form(p pokeapi.PokemonType) string {
+	f, _ := strings.CutPrefix(p.Name, p.Species.Name+"-")
+	if p.Species.Name == "" || f == p.Name {
+		return ""
+	}
+	return f
+}
+
+// rollGender picks a gender for a pokemon of a species with genderRate, the
+// chance of being female in eighths; genderless pokemon get "".
+func rollGender(genderRate int) string {
+	switch {
+	case genderRate < 0:
+		return ""
+	case rand.IntN(8) < genderRate:
+		return "female"
+	}
+	return "male"
+}
+
+// genderSymbol is shown after a pokemon's name.
+func genderSymbol(s *session, gender string) string {
+	if s.a11y && gender != "" {
+		return " (" + gender + ")"
+	}
+	switch gender {
+	case "male":
+		return " ♂"
+	case "female":
+		return " ♀"
+	}
+	return ""
+}
+
+// spokenSummary sums a pokemon up in a sentence or three for screen
+// readers, like "pikachu. Electric type. HP 35."
+func spokenSummary(p pokeapi.PokemonType) string {
+	summary := p.Name + "."
+	if types := typeNames(p); len(types) > 0 {
+		types[0] = strings.ToUpper(types[0][:1]) + types[0][1:]
+		summary += fmt.Sprintf(" %s type.", strings.Join(types, " and "))
+	}
+	if hp := baseStat(p, "hp"); hp > 0 {
+		summary += fmt.Sprintf(" HP %d.", hp)
+	}
+	return summary
+}
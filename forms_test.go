@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestFormName(t *testing.T) {
+	cases := map[string]string{
+		"raichu":           "raichu",
+		"raichu-alola":     "raichu-alola",
+		"alolan-raichu":    "raichu-alola",
+		"raichu-alolan":    "raichu-alola",
+		"galarian-mr-mime": "mr-mime-galar",
+		"mega-charizard-x": "charizard-mega-x",
+		"mega-venusaur":    "venusaur-mega",
+		"gigantamax-eevee": "eevee-gmax",
+		"pikachu-gmax":     "pikachu-gmax",
+	}
+	for input, want := range cases {
+		if got := formName(input); got != want {
+			t.Errorf("Expected formName(%q) to be %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestCatchFormWithGender(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	if err := s.run("catch alolan-raichu master", &bytes.Buffer{}); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	caught := s.profile.Pokemon[0]
+	if caught.Species != "raichu-alola" {
+		t.Errorf("Expected raichu-alola, got %s", caught.Species)
+	}
+	if caught.Gender != "male" && caught.Gender != "female" {
+		t.Errorf("Expected a gender, got %q", caught.Gender)
+	}
+
+	out := &bytes.Buffer{}
+	if err := s.run("inspect raichu-alola", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Form: alola of raichu\n") || !strings.Contains(out.String(), "#1"+genderSymbol(s, caught.Gender)+" Lv.") {
+		t.Errorf("Expected inspect to show the form and gender, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := s.run("pokedex", out); err != nil {
+		t.Fatalf("pokedex returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), " - raichu (alola form)") {
+		t.Errorf("Expected the pokedex to show the form, got %q", out.String())
+	}
+}
+
+func TestGenderlessPokemon(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	if err := s.run("catch magnemite master", &bytes.Buffer{}); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	if g := s.profile.Pokemon[0].Gender; g != "" {
+		t.Errorf("Expected magnemite to be genderless, got %q", g)
+	}
+}
+
+func TestSpokenSummary(t *testing.T) {
+	var p pokeapi.PokemonType
+	json.Unmarshal([]byte(`{"name": "charizard", "types": [{"slot": 1, "type": {"name": "fire"}}, {"slot": 2, "type": {"name": "flying"}}], "stats": [{"base_stat": 78, "stat": {"name": "hp"}}]}`), &p)
+	if got := spokenSummary(p); got != "charizard. Fire and flying type. HP 78." {
+		t.Errorf("Expected a spoken summary, got %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/rpc"
+)
+
+// encounterBacklog is how many encounters a stream holds for a slow
+// client; after that they're dropped rather than hold up the game.
+const encounterBacklog = 16
+
+// serveGRPC serves the Pokedex gRPC API of internal/rpc/pokedex.proto.
+func serveGRPC(s *session, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(rpc.Path, rpc.Handler(&grpcService{runner: &profileRunner{host: s}}))
+	mux.Handle("/events", eventsHandler(s.app.bus))
+	fmt.Fprintf(s.out, "gRPC server listening on %s\n", addr)
+	return rpc.NewServer(addr, mux).ListenAndServe()
+}
+
+// grpcService plays the profile each request names.
+type grpcService struct {
+	runner *profileRunner
+}
+
+func (g *grpcService) Catch(ctx context.Context, req *rpc.CatchRequest) (*rpc.CommandReply, error) {
+	words := []string{"catch"}
+	for _, arg := range []string{req.Pokemon, req.Ball} {
+		if arg != "" {
+			words = append(words, arg)
+		}
+	}
+	return g.command(req.Profile, words)
+}
+
+func (g *grpcService) Explore(ctx context.Context, req *rpc.ExploreRequest) (*rpc.CommandReply, error) {
+	words := []string{"explore"}
+	if req.Area != "" {
+		words = append(words, req.Area)
+	}
+	return g.command(req.Profile, words)
+}
+
+// command runs words for profile, replying with its output and whatever
+// wild pokemon is in the way afterwards.
+func (g *grpcService) command(profile string, words []string) (*rpc.CommandReply, error) {
+	out, res := &bytes.Buffer{}, &rpc.CommandReply{}
+	err := g.runner.do(profile, out, func(s *session) error {
+		err := s.exec(words)
+		if enc := s.encounter; enc != nil {
+			res.Encounter = &rpc.Encounter{Profile: s.id, Pokemon: enc.species.Name, Level: int32(enc.level), Types: typeNames(enc.species), Shiny: enc.shiny}
+		}
+		return err
+	})
+	if errors.Is(err, errExit) {
+		err = nil
+	}
+	if err != nil {
+		return nil, rpc.Errorf(rpc.FailedPrecondition, "%v", err)
+	}
+	res.Output = out.String()
+	return res, nil
+}
+
+func (g *grpcService) ListPokedex(ctx context.Context, req *rpc.ListPokedexRequest) (*rpc.ListPokedexReply, error) {
+	res := &rpc.ListPokedexReply{}
+	err := g.runner.do(req.Profile, &bytes.Buffer{}, func(s *session) error {
+		owned := ownedCounts(s)
+		for name, p := range s.profile.Pokedex {
+			// Species that can't be looked up are listed without a number.
+			id := 0
+			if species, err := s.source.Species(speciesName(p)); err == nil {
+				id = species.ID
+			}
+			res.Entries = append(res.Entries, &rpc.PokedexEntry{ID: int32(id), Name: name, Types: typeNames(p), Owned: int32(owned[name])})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, rpc.Errorf(rpc.Internal, "%v", err)
+	}
+	slices.SortFunc(res.Entries, func(a, b *rpc.PokedexEntry) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), cmp.Compare(a.Name, b.Name))
+	})
+	return res, nil
+}
+
+func (g *grpcService) StreamEncounters(ctx context.Context, req *rpc.StreamEncountersRequest, send func(*rpc.Encounter) error) error {
+	found := make(chan events.Event, encounterBacklog)
+	unsubscribe := g.runner.host.app.bus.Subscribe(events.Encountered, func(e events.Event) {
+		if req.Profile != "" && e.Session != req.Profile {
+			return
+		}
+		select {
+		case found <- e:
+		default:
+		}
+	})
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-found:
+			if err := send(&rpc.Encounter{Profile: e.Session, Pokemon: e.Pokemon, Level: int32(e.Level), Types: e.Types, Shiny: e.Shiny}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/rpc"
+)
+
+func grpcTestClient(t *testing.T, s *session) *rpc.Client {
+	t.Helper()
+	server := httptest.NewUnstartedServer(rpc.Handler(&grpcService{runner: &profileRunner{host: s}}))
+	server.Config.Protocols = &http.Protocols{}
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return rpc.NewClient(server.URL)
+}
+
+func TestGRPCCatch(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	c := grpcTestClient(t, s)
+	ctx := context.Background()
+
+	res, err := c.Catch(ctx, &rpc.CatchRequest{Pokemon: "pikachu", Ball: "master"})
+	if err != nil {
+		t.Fatalf("Catch() returned error: %v", err)
+	}
+	if !strings.Contains(res.Output, "pikachu") || res.Encounter != nil {
+		t.Errorf("Expected pikachu to be caught, got %+v", res)
+	}
+	list, err := c.ListPokedex(ctx, &rpc.ListPokedexRequest{})
+	if err != nil {
+		t.Fatalf("ListPokedex() returned error: %v", err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].Name != "pikachu" || list.Entries[0].ID != 25 || list.Entries[0].Owned != 1 {
+		t.Errorf("Expected pikachu in the server's own pokedex, got %+v", list.Entries)
+	}
+
+	_, err = c.Catch(ctx, &rpc.CatchRequest{Pokemon: "pikachu", Ball: "rock"})
+	var status *rpc.Error
+	if !errors.As(err, &status) || status.Code != rpc.FailedPrecondition {
+		t.Errorf("Expected a failed command to be refused, got %v", err)
+	}
+}
+
+func TestGRPCStreamEncounters(t *testing.T) {
+	s := newTestSession(t)
+	c := grpcTestClient(t, s)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	found := make(chan *rpc.Encounter, 1)
+	go c.StreamEncounters(ctx, &rpc.StreamEncountersRequest{Profile: "ash"}, func(e *rpc.Encounter) error {
+		found <- e
+		return nil
+	})
+	// misty's encounters aren't ash's.
+	if _, err := c.Catch(ctx, &rpc.CatchRequest{Profile: "misty", Pokemon: "psyduck"}); err != nil {
+		t.Fatalf("Catch() returned error: %v", err)
+	}
+	// Keep meeting pokemon until the stream is listening.
+	for try := 0; ; try++ {
+		if _, err := c.Catch(ctx, &rpc.CatchRequest{Profile: "ash", Pokemon: "pikachu"}); err != nil {
+			t.Fatalf("Catch() returned error: %v", err)
+		}
+		select {
+		case e := <-found:
+			if e.Profile != "ash" || e.Pokemon != "pikachu" || e.Level != wildLevel {
+				t.Errorf("Expected ash to meet pikachu, got %+v", e)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+		if try == 10 {
+			t.Fatalf("Expected the encounter to be streamed")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// iconSet is the icons one style of the icons setting shows.
+type iconSet struct {
+	// types has an icon for each type, by PokeAPI name.
+	types map[string]string
+	shiny string
+	ball  string
+	// here and visited mark areas on the map.
+	here    string
+	visited string
+}
+
+// iconSets are the styles of the icons setting. "nerd" needs a Nerd Font
+// (https://www.nerdfonts.com) in the terminal.
+var iconSets = map[string]iconSet{
+	"none": {},
+	"emoji": {
+		types: map[string]string{
+			"normal": "⚪", "fire": "🔥", "water": "💧", "grass": "🌿",
+			"electric": "⚡", "ice": "❄️", "fighting": "🥊", "poison": "☠️",
+			"ground": "⛰️", "flying": "🪶", "psychic": "🔮", "bug": "🐛",
+			"rock": "🪨", "ghost": "👻", "dragon": "🐉", "dark": "🌑",
+			"steel": "⚙️", "fairy": "🧚",
+		},
+		shiny:   "✨",
+		ball:    "🔴",
+		here:    "📍",
+		visited: "✅",
+	},
+	// Font Awesome and Material Design icons, where Nerd Fonts put them.
+	"nerd": {
+		types: map[string]string{
+			"normal": "\uf10c", "fire": "\uf06d", "water": "\uf043", "grass": "\uf06c",
+			"electric": "\uf0e7", "ice": "\uf2dc", "fighting": "\uf255", "poison": "\uf0c3",
+			"ground": "\uf0ac", "flying": "\uf1d8", "psychic": "\uf06e", "bug": "\uf188",
+			"rock": "\uf219", "ghost": "\U000f02a0", "dragon": "\uf132", "dark": "\uf186",
+			"steel": "\uf013", "fairy": "\uf0d0",
+		},
+		shiny:   "\uf005",
+		ball:    "\U000f0438",
+		here:    "\uf041",
+		visited: "\uf00c",
+	},
+}
+
+// iconStyles are the names of iconSets, for the config command.
+func iconStyles() []string {
+	return slices.Sorted(maps.Keys(iconSets))
+}
+
+// icons is the icon set s shows. Accessibility mode shows none, since
+// screen readers read icons out by name.
+func (s *session) icons() iconSet {
+	if s.a11y {
+		return iconSet{}
+	}
+	return iconSets[s.iconStyle]
+}
+
+// typeIcons are the icons of types, followed by a space, or nothing
+// without icons.
+func typeIcons(s *session, types []string) string {
+	var icons strings.Builder
+	for _, t := range types {
+		icons.WriteString(s.icons().types[t])
+	}
+	if icons.Len() == 0 {
+		return ""
+	}
+	return icons.String() + " "
+}
+
+// iconPrefix puts icon and a space in front of text, if there's an icon.
+func iconPrefix(icon, text string) string {
+	if icon == "" {
+		return text
+	}
+	return fmt.Sprintf("%s %s", icon, text)
+}
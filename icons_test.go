@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIcons(t *testing.T) {
+	s := newTestSession(t)
+	s.app.configPath = filepath.Join(t.TempDir(), "config.json")
+	s.profile.Give("potion", 1)
+
+	out := &bytes.Buffer{}
+	if err := s.run("config icons emoji", out); err != nil {
+		t.Fatalf("config returned error: %v", err)
+	}
+	out.Reset()
+	s.run("bag", out)
+	if !strings.Contains(out.String(), " - 🔴 poke-ball x") || !strings.Contains(out.String(), " - potion x1\n") {
+		t.Errorf("Expected balls to get an icon, got %q", out.String())
+	}
+	if got := typeIcons(s, []string{"fire", "flying"}); got != "🔥🪶 " {
+		t.Errorf("Expected the icons of both types, got %q", got)
+	}
+	if got := shinyMark(s, true); got != " ✨" {
+		t.Errorf("Expected the emoji shiny mark, got %q", got)
+	}
+
+	s.a11y = true
+	if got := typeIcons(s, []string{"fire"}); got != "" {
+		t.Errorf("Expected accessibility mode to leave icons out, got %q", got)
+	}
+	if err := s.run("config icons sparkly", out); err == nil {
+		t.Errorf("Expected an unknown icon style to be refused")
+	}
+}
@@ -0,0 +1,56 @@
+// Package audio plays sounds through whichever command-line player the
+// system has, since Go has no portable audio output of its own. Without
+// one, sounds are silently skipped.
+package audio
+
+import (
+	"os/exec"
+)
+
+// players are tried in order; each can play Ogg Vorbis, the format of
+// PokeAPI's cries. afplay, the macOS player, is last since only recent
+// versions of macOS read Ogg.
+var players = [][]string{
+	{"pw-play"},
+	{"paplay"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"},
+	{"mpv", "--no-video", "--really-quiet"},
+	{"ogg123", "-q"},
+	{"afplay"},
+}
+
+// Player plays sound files with a command.
+type Player struct {
+	command []string
+}
+
+// Find returns a player for the first command of players on the PATH, nil
+// if there's none.
+func Find() *Player {
+	return find(exec.LookPath)
+}
+
+func find(lookPath func(string) (string, error)) *Player {
+	for _, command := range players {
+		if path, err := lookPath(command[0]); err == nil {
+			return &Player{command: append([]string{path}, command[1:]...)}
+		}
+	}
+	return nil
+}
+
+// Name is the command the player uses.
+func (p *Player) Name() string {
+	return p.command[0]
+}
+
+// Play starts playing the file at path and returns without waiting for it
+// to finish.
+func (p *Player) Play(path string) error {
+	cmd := exec.Command(p.command[0], append(p.command[1:], path)...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
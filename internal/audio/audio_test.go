@@ -0,0 +1,26 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	installed := map[string]bool{"mpv": true, "afplay": true}
+	lookPath := func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	p := find(lookPath)
+	if p == nil || p.Name() != "/usr/bin/mpv" {
+		t.Fatalf("Expected the first player installed, got %v", p)
+	}
+	if len(p.command) != 3 {
+		t.Errorf("Expected mpv's options kept, got %v", p.command)
+	}
+	if p := find(func(string) (string, error) { return "", errors.New("not found") }); p != nil {
+		t.Errorf("Expected no player, got %v", p)
+	}
+}
@@ -0,0 +1,124 @@
+package battle
+
+import "slices"
+
+// AI picks the action of the pokemon in one slot of a computer-controlled
+// side.
+type AI func(b *Battle, side, slot int) Action
+
+type Difficulty struct {
+	Name string
+	AI   AI
+}
+
+// Difficulties are the difficulty levels, easiest first.
+var Difficulties = []Difficulty{
+	{"easy", Random},
+	{"normal", Greedy},
+	{"hard", TypeAware},
+}
+
+// ParseDifficulty returns the AI for a difficulty level.
+func ParseDifficulty(name string) (AI, bool) {
+	i := slices.IndexFunc(Difficulties, func(d Difficulty) bool { return d.Name == name })
+	if i < 0 {
+		return nil, false
+	}
+	return Difficulties[i].AI, true
+}
+
+// Random uses any of its moves on any opponent.
+func Random(b *Battle, side, slot int) Action {
+	return Action{
+		Kind:   Fight,
+		Move:   b.rng.IntN(len(b.Sides[side].InSlot(slot).Moves)),
+		Target: b.rng.IntN(len(b.Sides[1-side].Active)),
+	}
+}
+
+// Greedy always uses the move and target that do the most damage. Damage
+// to its own partner counts against a move.
+func Greedy(b *Battle, side, slot int) Action {
+	own, foes := b.Sides[side], b.Sides[1-side]
+	attacker := own.InSlot(slot)
+	best, bestDamage := Action{Kind: Fight}, -1.0
+	for i, m := range attacker.Moves {
+		for target := range foes.Active {
+			a := Action{Kind: Fight, Move: i, Target: target}
+			hits := b.targets(turnAction{side, slot, a}, m)
+			damage := 0.0
+			for _, p := range hits {
+				d := expectedDamage(attacker, p, m)
+				if len(hits) > 1 {
+					d *= spreadModifier
+				}
+				if slices.Contains(foes.Team, p) {
+					damage += d
+				} else {
+					damage -= d
+				}
+			}
+			if damage > bestDamage {
+				best, bestDamage = a, damage
+			}
+		}
+	}
+	return best
+}
+
+// switchMargin is how much better a teammate's matchup must be before
+// TypeAware gives up a turn to switch to it.
+const switchMargin = 0.5
+
+// TypeAware fights like Greedy, but switches to a benched teammate that
+// matches up much better against the opposing pokemon.
+func TypeAware(b *Battle, side, slot int) Action {
+	own := b.Sides[side]
+	best, bestScore := own.Active[slot], b.matchup(own.InSlot(slot), side)
+	for i, p := range own.Team {
+		if p.Fainted() || slices.Contains(own.Active, i) {
+			continue
+		}
+		if score := b.matchup(p, side); score > bestScore+switchMargin {
+			best, bestScore = i, score
+		}
+	}
+	if best != own.Active[slot] {
+		return Action{Kind: Switch, Switch: best}
+	}
+	return Greedy(b, side, slot)
+}
+
+// matchup is how well p would do against the opposing pokemon: the share
+// of their HP it takes per turn, less the share of its own HP it loses,
+// averaged over them.
+func (b *Battle) matchup(p *Pokemon, side int) float64 {
+	total, foes := 0.0, 0
+	for slot := range b.Sides[1-side].Active {
+		foe := b.Sides[1-side].InSlot(slot)
+		if foe.Fainted() {
+			continue
+		}
+		dealt := expectedDamage(p, foe, p.Moves[BestMove(p, foe)])
+		taken := expectedDamage(foe, p, foe.Moves[BestMove(foe, p)])
+		total += dealt/float64(max(foe.HP, 1)) - taken/float64(max(p.HP, 1))
+		foes++
+	}
+	return total / float64(max(foes, 1))
+}
+
+// Gimmicks adds mega evolution or dynamaxing to a computer-controlled
+// side's fight: it mega evolves as soon as it can and saves dynamaxing for
+// its last pokemon.
+func Gimmicks(b *Battle, side, slot int, a Action) Action {
+	if a.Kind != Fight {
+		return a
+	}
+	switch {
+	case b.CanMega(side, slot):
+		a.Mega = true
+	case b.CanDynamax(side, slot) && b.Sides[side].bench() < 0:
+		a.Dynamax = true
+	}
+	return a
+}
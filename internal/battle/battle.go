@@ -0,0 +1,551 @@
+// Package battle is a turn-based engine for single and double battles. It knows nothing
+// about PokeAPI or the REPL: callers build the teams, pick both sides'
+// actions and render the log.
+package battle
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+type Stats struct {
+	HP        int `json:"hp"`
+	Attack    int `json:"attack"`
+	Defense   int `json:"defense"`
+	SpAttack  int `json:"special_attack"`
+	SpDefense int `json:"special_defense"`
+	Speed     int `json:"speed"`
+}
+
+// Pokemon is a battler. Stats are the real values at its level; HP is what
+// it has left.
+type Pokemon struct {
+	// ID is the caller's, to match battlers back to its own pokemon.
+	ID      int
+	Name    string
+	Level   int
+	Types   []string
+	Ability string
+	Item    string
+	Stats   Stats
+	HP      int
+	Moves   []Move
+	// BaseExperience and EffortYield aren't used in battle; they're there
+	// for the caller's experience and EV formulas.
+	BaseExperience int
+	EffortYield    map[string]int
+	// Battled is set once the pokemon has been out on the field.
+	Battled bool
+	// Mega is the form its held mega stone lets it take, if any.
+	Mega *Form
+	// locked is the move a choice item holds it to until it switches out.
+	locked string
+	// base is the form it had before it mega evolved.
+	base         *Form
+	dynamaxTurns int
+	protected    bool
+}
+
+func (p *Pokemon) Fainted() bool {
+	return p.HP <= 0
+}
+
+// Format is how many pokemon each side has out at once.
+type Format int
+
+const (
+	Single Format = 1
+	Double Format = 2
+)
+
+// Side is a trainer, or wild pokemon when Name is empty.
+type Side struct {
+	Name string
+	Team []*Pokemon
+	// Active has the team index of the pokemon in each slot on the field.
+	Active []int
+	// MegaRing and DynamaxBand are the key items that let one of the side's
+	// pokemon mega evolve or dynamax, once a battle.
+	MegaRing    bool
+	DynamaxBand bool
+	megaUsed    bool
+	dynamaxUsed bool
+}
+
+// Lead is the pokemon in the first slot.
+func (s *Side) Lead() *Pokemon {
+	return s.Team[s.Active[0]]
+}
+
+func (s *Side) InSlot(slot int) *Pokemon {
+	return s.Team[s.Active[slot]]
+}
+
+func (s *Side) Defeated() bool {
+	return !slices.ContainsFunc(s.Team, func(p *Pokemon) bool { return !p.Fainted() })
+}
+
+// bench returns the first healthy pokemon that isn't on the field, or -1.
+func (s *Side) bench() int {
+	for i, p := range s.Team {
+		if !p.Fainted() && !slices.Contains(s.Active, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+type ActionKind int
+
+const (
+	Fight ActionKind = iota
+	Switch
+	UseItem
+)
+
+// Action is what the pokemon in one slot does in a turn: use Move, switch
+// to Team[Switch], or have its trainer use Item on Team[Member], restoring
+// Heal HP and reviving it if it fainted. A single-target move aims at the opposing slot Target, or
+// at the user's partner with Ally set; if its target has fainted it goes to
+// another opponent instead. Mega and Dynamax transform the pokemon first,
+// if the battle's rules and the side allow it.
+type Action struct {
+	Kind    ActionKind
+	Move    int
+	Switch  int
+	Target  int
+	Ally    bool
+	Mega    bool
+	Dynamax bool
+	Item    string
+	Member  int
+	Heal    int
+}
+
+type EntryKind string
+
+const (
+	Used    EntryKind = "used"
+	Missed  EntryKind = "missed"
+	Hit     EntryKind = "hit"
+	Fainted EntryKind = "fainted"
+	SentOut EntryKind = "sent_out"
+	Won     EntryKind = "won"
+
+	WeatherStarted EntryKind = "weather_started"
+	WeatherEnded   EntryKind = "weather_ended"
+	TerrainStarted EntryKind = "terrain_started"
+	TerrainEnded   EntryKind = "terrain_ended"
+	// Buffeted is residual damage from the weather.
+	Buffeted EntryKind = "buffeted"
+	Healed   EntryKind = "healed"
+
+	MegaEvolved  EntryKind = "mega_evolved"
+	Dynamaxed    EntryKind = "dynamaxed"
+	DynamaxEnded EntryKind = "dynamax_ended"
+	Protected    EntryKind = "protected"
+
+	UsedItem EntryKind = "used_item"
+)
+
+// Entry is one thing that happened in a battle. Only the fields that make
+// sense for the kind are set.
+type Entry struct {
+	Turn    int       `json:"turn"`
+	Kind    EntryKind `json:"kind"`
+	Trainer string    `json:"trainer,omitempty"`
+	Pokemon string    `json:"pokemon,omitempty"`
+	Move    string    `json:"move,omitempty"`
+	Damage  int       `json:"damage,omitempty"`
+	HP      int       `json:"hp,omitempty"`
+	MaxHP   int       `json:"max_hp,omitempty"`
+	// Effectiveness is the type multiplier of a hit.
+	Effectiveness float64 `json:"effectiveness,omitempty"`
+	Critical      bool    `json:"critical,omitempty"`
+	Weather       Weather `json:"weather,omitempty"`
+	Terrain       Terrain `json:"terrain,omitempty"`
+	// Ability and Item are set when an ability or held item caused the
+	// entry.
+	Ability string `json:"ability,omitempty"`
+	Item    string `json:"item,omitempty"`
+	// Form is what a pokemon mega evolved into.
+	Form string `json:"form,omitempty"`
+}
+
+func (e Entry) String() string {
+	switch e.Kind {
+	case Used:
+		return fmt.Sprintf("%s used %s!", e.Pokemon, e.Move)
+	case Missed:
+		return fmt.Sprintf("%s avoided the attack!", e.Pokemon)
+	case Hit:
+		if e.Effectiveness == 0 {
+			return fmt.Sprintf("It doesn't affect %s...", e.Pokemon)
+		}
+		text := ""
+		if e.Critical {
+			text += "A critical hit! "
+		}
+		if e.Effectiveness > 1 {
+			text += "It's super effective! "
+		} else if e.Effectiveness < 1 {
+			text += "It's not very effective... "
+		}
+		return text + fmt.Sprintf("%s took %d damage (%d/%d HP)", e.Pokemon, e.Damage, max(e.HP, 0), e.MaxHP)
+	case Fainted:
+		return fmt.Sprintf("%s fainted!", e.Pokemon)
+	case SentOut:
+		if e.Trainer == "" {
+			return fmt.Sprintf("The wild %s is ready to battle!", e.Pokemon)
+		}
+		return fmt.Sprintf("%s sent out %s!", e.Trainer, e.Pokemon)
+	case Won:
+		if e.Trainer == "" {
+			return "The wild pokemon won the battle!"
+		}
+		return fmt.Sprintf("%s won the battle!", e.Trainer)
+	case UsedItem:
+		return fmt.Sprintf("%s used a %s on %s! (%d/%d HP)", e.Trainer, e.Item, e.Pokemon, e.HP, e.MaxHP)
+	}
+	return e.gimmickString()
+}
+
+// Battle is a battle between Sides[0], the player, and Sides[1].
+type Battle struct {
+	Sides  [2]*Side
+	Format Format
+	Turn   int
+	Log    []Entry
+	// Weather and Terrain are what's on the field, each with the turns it
+	// has left.
+	Weather      Weather
+	WeatherTurns int
+	Terrain      Terrain
+	TerrainTurns int
+	Rules        Rules
+	// Winner is the index of the winning side, or -1 while the battle is on.
+	Winner int
+	rng    *rand.Rand
+}
+
+// New starts a battle, sending out the first healthy pokemon of each side.
+func New(player, opponent *Side, format Format, rng *rand.Rand) *Battle {
+	b := &Battle{Sides: [2]*Side{player, opponent}, Format: format, Winner: -1, rng: rng}
+	for _, side := range b.Sides {
+		side.Active = nil
+		side.megaUsed, side.dynamaxUsed = false, false
+		for range format {
+			next := side.bench()
+			if next < 0 {
+				break
+			}
+			side.Active = append(side.Active, next)
+			side.Team[next].Battled = true
+			side.Team[next].locked = ""
+			b.log(Entry{Kind: SentOut, Trainer: side.Name, Pokemon: side.Team[next].Name})
+		}
+	}
+	// Abilities kick in once everyone is out, faster pokemon first.
+	var field []*Pokemon
+	for _, side := range b.Sides {
+		for slot := range side.Active {
+			field = append(field, side.InSlot(slot))
+		}
+	}
+	slices.SortStableFunc(field, func(x, y *Pokemon) int { return y.Stats.Speed - x.Stats.Speed })
+	for _, p := range field {
+		b.enter(p)
+	}
+	return b
+}
+
+func (b *Battle) Over() bool {
+	return b.Winner >= 0
+}
+
+// turnAction is an action with the slot it was picked for.
+type turnAction struct {
+	side, slot int
+	Action
+}
+
+// Play runs one turn, given an action for each slot of each side, and
+// returns what happened in it. Switches and items go first, then mega
+// evolution and dynamaxing, then moves in speed order, protecting moves
+// before the rest.
+func (b *Battle) Play(actions [2][]Action) []Entry {
+	start := len(b.Log)
+	b.Turn++
+
+	var fights []turnAction
+	for side, slots := range actions {
+		for slot, a := range slots[:min(len(slots), len(b.Sides[side].Active))] {
+			switch a.Kind {
+			case Switch:
+				b.switchTo(side, slot, a.Switch)
+			case UseItem:
+				b.useItem(side, a)
+			default:
+				fights = append(fights, turnAction{side, slot, a})
+			}
+		}
+	}
+	for _, f := range fights {
+		if f.Mega {
+			b.megaEvolve(f.side, f.slot)
+		} else if f.Dynamax {
+			b.dynamax(f.side, f.slot)
+		}
+	}
+	// Shuffle first so speed ties go either way.
+	b.rng.Shuffle(len(fights), func(i, j int) { fights[i], fights[j] = fights[j], fights[i] })
+	slices.SortStableFunc(fights, func(x, y turnAction) int {
+		px, py := b.Sides[x.side].InSlot(x.slot), b.Sides[y.side].InSlot(y.slot)
+		if protectX, protectY := b.moveFor(px, x.Move).Protect, b.moveFor(py, y.Move).Protect; protectX != protectY {
+			if protectX {
+				return -1
+			}
+			return 1
+		}
+		return b.speed(py) - b.speed(px)
+	})
+	for _, f := range fights {
+		attacker := b.Sides[f.side].InSlot(f.slot)
+		if attacker.Fainted() || f.Move < 0 || f.Move >= len(attacker.Moves) {
+			continue
+		}
+		move := b.moveFor(attacker, f.Move)
+		if attacker.choiceLocked() && !attacker.Dynamaxed() {
+			attacker.locked = move.Name
+		}
+		if move.Class == Status {
+			b.useStatus(attacker, move)
+		} else if targets := b.targets(f, move); len(targets) > 0 {
+			b.attack(attacker, targets, move)
+		}
+	}
+	b.endTurn()
+	b.endGimmickTurn()
+	b.replaceFainted()
+	return b.Log[start:]
+}
+
+// moveFor is the move p uses when told to use its i-th: the one a choice
+// item locks it into, and a max move while it's dynamaxed.
+func (b *Battle) moveFor(p *Pokemon, i int) Move {
+	if locked := p.lockedMove(); locked >= 0 && !p.Dynamaxed() {
+		i = locked
+	}
+	if i < 0 || i >= len(p.Moves) {
+		return Move{}
+	}
+	if p.Dynamaxed() {
+		return MaxMove(p.Moves[i])
+	}
+	return p.Moves[i]
+}
+
+// targets are the healthy pokemon an action's move hits.
+func (b *Battle) targets(a turnAction, move Move) []*Pokemon {
+	own, foes := b.Sides[a.side], b.Sides[1-a.side]
+	var targets []*Pokemon
+	add := func(side *Side, slot int) {
+		if p := side.InSlot(slot); !p.Fainted() {
+			targets = append(targets, p)
+		}
+	}
+	switch move.Target {
+	case AllOpponents, AllOther:
+		for slot := range foes.Active {
+			add(foes, slot)
+		}
+		if move.Target == AllOther {
+			for slot := range own.Active {
+				if slot != a.slot {
+					add(own, slot)
+				}
+			}
+		}
+		return targets
+	}
+
+	if a.Ally && len(own.Active) > 1 {
+		add(own, 1-a.slot)
+		return targets
+	}
+	if a.Target >= 0 && a.Target < len(foes.Active) {
+		add(foes, a.Target)
+	}
+	for slot := range foes.Active {
+		if len(targets) == 0 {
+			add(foes, slot)
+		}
+	}
+	return targets
+}
+
+func (b *Battle) switchTo(side, slot, to int) {
+	s := b.Sides[side]
+	if to < 0 || to >= len(s.Team) || slices.Contains(s.Active, to) || s.Team[to].Fainted() {
+		return
+	}
+	leaving := s.InSlot(slot)
+	leaving.locked = ""
+	if leaving.Dynamaxed() {
+		b.undoDynamax(leaving)
+	}
+	s.Active[slot] = to
+	s.Team[to].Battled = true
+	b.log(Entry{Kind: SentOut, Trainer: s.Name, Pokemon: s.Team[to].Name})
+	b.enter(s.Team[to])
+}
+
+// useItem heals, or revives, the pokemon an item is used on.
+func (b *Battle) useItem(side int, a Action) {
+	s := b.Sides[side]
+	if a.Member < 0 || a.Member >= len(s.Team) {
+		return
+	}
+	p := s.Team[a.Member]
+	p.HP = min(max(p.HP, 0)+a.Heal, p.Stats.HP)
+	b.log(Entry{Kind: UsedItem, Trainer: s.Name, Pokemon: p.Name, Item: a.Item, HP: p.HP, MaxHP: p.Stats.HP})
+}
+
+// useStatus uses a move that changes the field instead of doing damage.
+func (b *Battle) useStatus(user *Pokemon, move Move) {
+	b.log(Entry{Kind: Used, Pokemon: user.Name, Move: move.Name})
+	if move.Protect {
+		user.protected = true
+	}
+	if move.Weather != Clear {
+		b.setWeather(move.Weather, user, "")
+	}
+	if move.Terrain != NoTerrain {
+		b.setTerrain(move.Terrain, user, "")
+	}
+}
+
+// spreadModifier cuts the damage of a move that hits several pokemon at
+// once.
+const spreadModifier = 0.75
+
+func (b *Battle) attack(attacker *Pokemon, targets []*Pokemon, move Move) {
+	b.log(Entry{Kind: Used, Pokemon: attacker.Name, Move: move.Name})
+	for _, defender := range targets {
+		if defender.protected {
+			b.log(Entry{Kind: Protected, Pokemon: defender.Name})
+			continue
+		}
+		if move.Accuracy > 0 && b.rng.IntN(100) >= move.Accuracy {
+			b.log(Entry{Kind: Missed, Pokemon: defender.Name})
+			continue
+		}
+		critical := b.rng.IntN(24) == 0
+		damage, effectiveness := Damage(attacker, defender, move, critical, 85+b.rng.IntN(16))
+		if damage > 0 {
+			modifier := b.fieldModifier(attacker, defender, move)
+			if len(targets) > 1 {
+				modifier *= spreadModifier
+			}
+			damage = max(int(float64(damage)*modifier), 1)
+		}
+		defender.HP -= damage
+		b.log(Entry{
+			Kind:          Hit,
+			Pokemon:       defender.Name,
+			Damage:        damage,
+			HP:            defender.HP,
+			MaxHP:         defender.Stats.HP,
+			Effectiveness: effectiveness,
+			Critical:      critical && effectiveness > 0,
+		})
+		if defender.Fainted() {
+			defender.HP = 0
+			b.log(Entry{Kind: Fainted, Pokemon: defender.Name})
+		}
+	}
+	// Max moves change the field once they've hit.
+	if move.Class != Status && !attacker.Fainted() {
+		if move.Weather != Clear {
+			b.setWeather(move.Weather, attacker, "")
+		}
+		if move.Terrain != NoTerrain {
+			b.setTerrain(move.Terrain, attacker, "")
+		}
+	}
+}
+
+// replaceFainted fills the slots of fainted pokemon from the bench, and ends
+// the battle once a side has no healthy pokemon left.
+func (b *Battle) replaceFainted() {
+	for i, side := range b.Sides {
+		if side.Defeated() {
+			b.Winner = 1 - i
+			b.endGimmicks()
+			b.log(Entry{Kind: Won, Trainer: b.Sides[b.Winner].Name})
+			return
+		}
+	}
+	for i, side := range b.Sides {
+		for slot := range side.Active {
+			if side.InSlot(slot).Fainted() {
+				b.switchTo(i, slot, side.bench())
+			}
+		}
+	}
+}
+
+func (b *Battle) log(e Entry) {
+	e.Turn = b.Turn
+	b.Log = append(b.Log, e)
+}
+
+// Damage is the Gen V damage formula. roll is the random factor, from 85 to
+// 100. It returns the damage and the type effectiveness.
+func Damage(attacker, defender *Pokemon, move Move, critical bool, roll int) (int, float64) {
+	effectiveness := Effectiveness(move.Type, defender.Types)
+	if effectiveness == 0 || move.Power == 0 {
+		return 0, effectiveness
+	}
+	a, d := attacker.Stats.Attack, defender.Stats.Defense
+	if move.Class == Special {
+		a, d = attacker.Stats.SpAttack, defender.Stats.SpDefense
+	}
+	if (move.Class == Physical && attacker.Item == ChoiceBand) || (move.Class == Special && attacker.Item == ChoiceSpecs) {
+		a = a * 3 / 2
+	}
+	base := (2*attacker.Level/5+2)*move.Power*max(a, 1)/max(d, 1)/50 + 2
+
+	modifier := effectiveness * float64(roll) / 100
+	if slices.Contains(attacker.Types, move.Type) {
+		modifier *= 1.5
+	}
+	if critical {
+		modifier *= 1.5
+	}
+	return max(int(float64(base)*modifier), 1), effectiveness
+}
+
+// expectedDamage is the average damage of move, allowing for misses but not
+// for the weather or terrain.
+func expectedDamage(attacker, defender *Pokemon, m Move) float64 {
+	damage, _ := Damage(attacker, defender, m, false, 93)
+	accuracy := m.Accuracy
+	if accuracy == 0 {
+		accuracy = 100
+	}
+	return float64(damage*accuracy) / 100
+}
+
+// BestMove is the index of the move that does the most damage to defender on
+// average.
+func BestMove(attacker, defender *Pokemon) int {
+	best := 0
+	for i, m := range attacker.Moves {
+		if expectedDamage(attacker, defender, m) > expectedDamage(attacker, defender, attacker.Moves[best]) {
+			best = i
+		}
+	}
+	return best
+}
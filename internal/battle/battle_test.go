@@ -0,0 +1,280 @@
+package battle
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestEffectiveness(t *testing.T) {
+	cases := []struct {
+		attack   string
+		defender []string
+		expected float64
+	}{
+		{"water", []string{"fire"}, 2},
+		{"electric", []string{"water", "flying"}, 4},
+		{"ground", []string{"flying"}, 0},
+		{"fire", []string{"water", "rock"}, 0.25},
+		{"normal", []string{"psychic"}, 1},
+	}
+	for _, c := range cases {
+		if got := Effectiveness(c.attack, c.defender); got != c.expected {
+			t.Errorf("Expected %s vs %v to be %v, got %v", c.attack, c.defender, c.expected, got)
+		}
+	}
+}
+
+func newPokemon(name string, types []string, level int) *Pokemon {
+	stats := Stats{HP: 100, Attack: 50, Defense: 50, SpAttack: 50, SpDefense: 50, Speed: 50}
+	return &Pokemon{Name: name, Level: level, Types: types, Stats: stats, HP: stats.HP, Moves: DefaultMoves(types, level)}
+}
+
+func TestBattleRunsToTheEnd(t *testing.T) {
+	player := &Side{Name: "Red", Team: []*Pokemon{newPokemon("squirtle", []string{"water"}, 50)}}
+	opponent := &Side{Name: "Blue", Team: []*Pokemon{
+		newPokemon("charmander", []string{"fire"}, 10),
+		newPokemon("vulpix", []string{"fire"}, 10),
+	}}
+	b := New(player, opponent, Single, rand.New(rand.NewPCG(1, 2)))
+
+	for turn := 0; !b.Over(); turn++ {
+		if turn == 100 {
+			t.Fatalf("Expected the battle to end, log: %v", b.Log)
+		}
+		b.Play([2][]Action{{Greedy(b, 0, 0)}, {Greedy(b, 1, 0)}})
+	}
+	if b.Winner != 0 {
+		t.Errorf("Expected the level 50 squirtle to win, log: %v", b.Log)
+	}
+	if !opponent.Defeated() {
+		t.Errorf("Expected the whole opposing team to faint")
+	}
+	if m := player.Lead().Moves[BestMove(player.Lead(), opponent.Team[0])]; m.Type != "water" {
+		t.Errorf("Expected a water move against fire, got %s", m.Name)
+	}
+}
+
+func TestTypeAwareSwitches(t *testing.T) {
+	ai := &Side{Name: "Misty", Team: []*Pokemon{
+		newPokemon("vulpix", []string{"fire"}, 30),
+		newPokemon("staryu", []string{"water"}, 30),
+	}}
+	player := &Side{Name: "Red", Team: []*Pokemon{newPokemon("squirtle", []string{"water"}, 30)}}
+	b := New(player, ai, Single, rand.New(rand.NewPCG(1, 2)))
+
+	if a := TypeAware(b, 1, 0); a.Kind != Switch || a.Switch != 1 {
+		t.Errorf("Expected fire to switch out against water, got %+v", a)
+	}
+	if a := Greedy(b, 1, 0); a.Kind != Fight {
+		t.Errorf("Expected Greedy to fight, got %+v", a)
+	}
+	ai.Active[0] = 1
+	if a := TypeAware(b, 1, 0); a.Kind != Fight {
+		t.Errorf("Expected water to stay in, got %+v", a)
+	}
+	if _, ok := ParseDifficulty("impossible"); ok {
+		t.Errorf("Expected an unknown difficulty to be rejected")
+	}
+}
+
+func TestDoubleBattle(t *testing.T) {
+	player := &Side{Name: "Red", Team: []*Pokemon{
+		newPokemon("sandslash", []string{"ground"}, 40),
+		newPokemon("pidgeot", []string{"normal", "flying"}, 40),
+	}}
+	opponent := &Side{Name: "Blue", Team: []*Pokemon{
+		newPokemon("magnemite", []string{"electric", "steel"}, 40),
+		newPokemon("voltorb", []string{"electric"}, 40),
+		newPokemon("pikachu", []string{"electric"}, 40),
+	}}
+	b := New(player, opponent, Double, rand.New(rand.NewPCG(3, 4)))
+	if len(player.Active) != 2 || len(opponent.Active) != 2 {
+		t.Fatalf("Expected two pokemon out on each side, got %v and %v", player.Active, opponent.Active)
+	}
+
+	quake := Action{Kind: Fight, Move: 1}
+	if m := player.Lead().Moves[quake.Move]; m.Name != "earthquake" {
+		t.Fatalf("Expected sandslash to know earthquake, got %s", m.Name)
+	}
+	entries := b.Play([2][]Action{{quake, {Kind: Fight, Move: 0, Target: 1}}, {{Kind: Fight}, {Kind: Fight}}})
+	hits := map[string]Entry{}
+	for _, e := range entries {
+		if e.Kind == Hit && e.Damage > 0 {
+			if _, ok := hits[e.Pokemon]; !ok {
+				hits[e.Pokemon] = e
+			}
+		}
+	}
+	if _, ok := hits["pidgeot"]; ok {
+		t.Errorf("Expected earthquake to miss the flying partner, log: %v", entries)
+	}
+	e, ok := hits["voltorb"]
+	single, _ := Damage(player.Lead(), opponent.Team[1], player.Lead().Moves[1], e.Critical, 100)
+	if !ok || e.Damage > single*3/4 {
+		t.Errorf("Expected spread damage of at most %d on voltorb, got %+v", single*3/4, e)
+	}
+
+	if a := Greedy(b, 0, 1); a.Kind != Fight {
+		t.Errorf("Expected Greedy to fight, got %+v", a)
+	}
+}
+
+func TestWeather(t *testing.T) {
+	kyogre := newPokemon("kyogre", []string{"water"}, 50)
+	kyogre.Ability = "drizzle"
+	golem := newPokemon("golem", []string{"rock", "ground"}, 50)
+	b := New(&Side{Name: "Red", Team: []*Pokemon{kyogre}}, &Side{Name: "Blue", Team: []*Pokemon{golem}}, Single, rand.New(rand.NewPCG(5, 6)))
+	if b.Weather != Rain || b.WeatherTurns != fieldTurns {
+		t.Fatalf("Expected drizzle to start the rain, got %q for %d turns", b.Weather, b.WeatherTurns)
+	}
+	if m := b.fieldModifier(kyogre, golem, strongMoves["water"]); m != 1.5 {
+		t.Errorf("Expected rain to boost water moves, got %v", m)
+	}
+	if m := b.fieldModifier(golem, kyogre, strongMoves["fire"]); m != 0.5 {
+		t.Errorf("Expected rain to weaken fire moves, got %v", m)
+	}
+
+	sand := Action{Kind: Fight, Move: slices.IndexFunc(golem.Moves, func(m Move) bool { return m.Name == "sandstorm" })}
+	b.Play([2][]Action{{{Kind: Switch}}, {sand}})
+	if b.Weather != Sandstorm {
+		t.Fatalf("Expected the sandstorm to replace the rain, got %q", b.Weather)
+	}
+	if kyogre.HP != kyogre.Stats.HP-kyogre.Stats.HP/16 || golem.HP != golem.Stats.HP {
+		t.Errorf("Expected only kyogre to be buffeted, got %d and %d HP", kyogre.HP, golem.HP)
+	}
+	for range fieldTurns {
+		b.Play([2][]Action{{{Kind: Switch}}, {{Kind: Switch}}})
+	}
+	if b.Weather != Clear {
+		t.Errorf("Expected the sandstorm to die down, got %q", b.Weather)
+	}
+}
+
+func TestHeldItems(t *testing.T) {
+	snorlax := newPokemon("snorlax", []string{"normal"}, 50)
+	snorlax.Stats.HP, snorlax.HP = 1000, 500
+	snorlax.Item = Leftovers
+	scizor := newPokemon("scizor", []string{"bug", "steel"}, 50)
+	scizor.Item = ChoiceBand
+	b := New(&Side{Name: "Red", Team: []*Pokemon{snorlax}}, &Side{Name: "Blue", Team: []*Pokemon{scizor}}, Single, rand.New(rand.NewPCG(7, 8)))
+
+	plain := *scizor
+	plain.Item = ""
+	banded, _ := Damage(scizor, snorlax, tackle, false, 100)
+	normal, _ := Damage(&plain, snorlax, tackle, false, 100)
+	if banded <= normal {
+		t.Errorf("Expected the choice band to boost damage, got %d vs %d", banded, normal)
+	}
+
+	idle := Action{Kind: Switch}
+	b.Play([2][]Action{{idle}, {{Kind: Fight, Move: 0}}})
+	entries := b.Play([2][]Action{{idle}, {{Kind: Fight, Move: 1}}})
+	if entries[0].Kind != Used || entries[0].Move != "tackle" {
+		t.Errorf("Expected scizor to be locked into tackle, got %v", entries)
+	}
+	healed := slices.ContainsFunc(entries, func(e Entry) bool { return e.Kind == Healed && e.Item == Leftovers })
+	if !healed {
+		t.Errorf("Expected leftovers to heal snorlax, got %v", entries)
+	}
+	if !snorlax.Battled || !scizor.Battled {
+		t.Errorf("Expected both leads to be marked as having battled")
+	}
+}
+
+func TestMegaEvolution(t *testing.T) {
+	charizard := newPokemon("charizard", []string{"fire", "flying"}, 50)
+	charizard.Item = "charizardite-x"
+	charizard.Mega = &Form{Name: "charizard-mega-x", Types: []string{"fire", "dragon"}, Ability: "tough-claws", Stats: Stats{HP: 999, Attack: 90, Defense: 90, SpAttack: 90, SpDefense: 90, Speed: 90}}
+	player := &Side{Name: "Red", Team: []*Pokemon{charizard}}
+	foe := newPokemon("blissey", []string{"normal"}, 50)
+	foe.Stats.HP, foe.HP = 5000, 5000
+	opponent := &Side{Name: "Blue", Team: []*Pokemon{foe}}
+	b := New(player, opponent, Single, rand.New(rand.NewPCG(1, 2)))
+	if b.CanMega(0, 0) {
+		t.Errorf("Expected mega evolution to need the rules and a mega ring")
+	}
+	b.Rules.Mega, player.MegaRing = true, true
+
+	b.Play([2][]Action{{{Kind: Fight, Mega: true}}, {{Kind: Fight}}})
+	if charizard.Name != "charizard-mega-x" || !slices.Equal(charizard.Types, []string{"fire", "dragon"}) {
+		t.Errorf("Expected charizard to mega evolve, got %s %v", charizard.Name, charizard.Types)
+	}
+	if charizard.Stats.HP != 100 || charizard.Stats.Attack != 90 {
+		t.Errorf("Expected the mega form's stats with the old HP, got %+v", charizard.Stats)
+	}
+	if b.CanMega(0, 0) {
+		t.Errorf("Expected mega evolution to work once a battle")
+	}
+	foe.HP = 1
+	for !b.Over() {
+		b.Play([2][]Action{{{Kind: Fight, Move: 1}}, {{Kind: Fight}}})
+	}
+	if charizard.Name != "charizard" {
+		t.Errorf("Expected charizard to turn back after the battle, got %s", charizard.Name)
+	}
+}
+
+func TestDynamax(t *testing.T) {
+	snorlax := newPokemon("snorlax", []string{"normal"}, 50)
+	snorlax.Moves = []Move{tackle, {Name: "rest", Type: "psychic", Class: Status}}
+	player := &Side{Name: "Red", Team: []*Pokemon{snorlax}, DynamaxBand: true}
+	foe := newPokemon("blissey", []string{"normal"}, 50)
+	foe.Stats.HP, foe.HP = 5000, 5000
+	opponent := &Side{Name: "Blue", Team: []*Pokemon{foe}}
+	b := New(player, opponent, Single, rand.New(rand.NewPCG(1, 2)))
+	b.Rules.Dynamax = true
+
+	entries := b.Play([2][]Action{{{Kind: Fight, Dynamax: true}}, {{Kind: Fight}}})
+	if !snorlax.Dynamaxed() || snorlax.Stats.HP != 200 {
+		t.Fatalf("Expected snorlax to dynamax with double HP, got %+v", snorlax.Stats)
+	}
+	if !slices.ContainsFunc(entries, func(e Entry) bool { return e.Kind == Used && e.Move == "max-strike" }) {
+		t.Errorf("Expected tackle to become max-strike, log: %v", entries)
+	}
+
+	entries = b.Play([2][]Action{{{Kind: Fight, Move: 1}}, {{Kind: Fight}}})
+	if !slices.ContainsFunc(entries, func(e Entry) bool { return e.Kind == Protected && e.Pokemon == "snorlax" }) {
+		t.Errorf("Expected max-guard to protect snorlax, log: %v", entries)
+	}
+
+	b.Play([2][]Action{{{Kind: Fight}}, {{Kind: Fight}}})
+	if snorlax.Dynamaxed() || snorlax.Stats.HP != 100 {
+		t.Errorf("Expected snorlax back to normal after three turns, got %+v", snorlax.Stats)
+	}
+	if b.CanDynamax(0, 0) {
+		t.Errorf("Expected dynamaxing to work once a battle")
+	}
+}
+
+func TestMaxMove(t *testing.T) {
+	m := MaxMove(basicMoves["fire"])
+	if m.Name != "max-flare" || m.Power != 90 || m.Weather != Sun || m.Accuracy != 0 {
+		t.Errorf("Expected ember to become a 90 power max-flare that sets the sun, got %+v", m)
+	}
+	if m := MaxMove(strongMoves["fighting"]); m.Power != 90 {
+		t.Errorf("Expected brick-break to become a 90 power max-knuckle, got %+v", m)
+	}
+}
+
+func TestUseItem(t *testing.T) {
+	squirtle := newPokemon("squirtle", []string{"water"}, 50)
+	squirtle.HP = 30
+	pikachu := newPokemon("pikachu", []string{"electric"}, 50)
+	pikachu.HP = 0
+	b := New(&Side{Name: "Red", Team: []*Pokemon{squirtle, pikachu}}, &Side{Name: "Blue", Team: []*Pokemon{newPokemon("onix", []string{"rock"}, 50)}}, Single, rand.New(rand.NewPCG(1, 2)))
+
+	idle := Action{Kind: Switch}
+	entries := b.Play([2][]Action{{{Kind: UseItem, Item: "potion", Member: 0, Heal: 20}}, {idle}})
+	if entries[0].Kind != UsedItem || squirtle.HP != 50 {
+		t.Errorf("Expected the potion to heal squirtle to 50 HP first, got %d HP and %v", squirtle.HP, entries)
+	}
+	b.Play([2][]Action{{{Kind: UseItem, Item: "revive", Member: 1, Heal: 50}}, {idle}})
+	if pikachu.Fainted() || pikachu.HP != 50 {
+		t.Errorf("Expected the revive to bring pikachu back with 50 HP, got %d", pikachu.HP)
+	}
+	b.Play([2][]Action{{{Kind: UseItem, Item: "max-potion", Member: 1, Heal: 1000}}, {idle}})
+	if pikachu.HP != pikachu.Stats.HP {
+		t.Errorf("Expected healing to stop at max HP, got %d", pikachu.HP)
+	}
+}
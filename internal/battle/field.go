@@ -0,0 +1,205 @@
+package battle
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Weather string
+
+const (
+	Clear     Weather = ""
+	Rain      Weather = "rain"
+	Sun       Weather = "sun"
+	Sandstorm Weather = "sandstorm"
+	Hail      Weather = "hail"
+)
+
+type Terrain string
+
+const (
+	NoTerrain       Terrain = ""
+	ElectricTerrain Terrain = "electric"
+	GrassyTerrain   Terrain = "grassy"
+)
+
+// fieldTurns is how long weather and terrain last once set.
+const fieldTurns = 5
+
+// weatherAbilities and terrainAbilities set the field when their holder is
+// sent out.
+var weatherAbilities = map[string]Weather{
+	"drizzle":      Rain,
+	"drought":      Sun,
+	"sand-stream":  Sandstorm,
+	"snow-warning": Hail,
+}
+
+var terrainAbilities = map[string]Terrain{
+	"electric-surge": ElectricTerrain,
+	"grassy-surge":   GrassyTerrain,
+}
+
+// speedAbilities double their holder's speed in a weather.
+var speedAbilities = map[string]Weather{
+	"swift-swim":  Rain,
+	"chlorophyll": Sun,
+	"sand-rush":   Sandstorm,
+	"slush-rush":  Hail,
+}
+
+// weatherImmune lists the types a weather doesn't hurt.
+var weatherImmune = map[Weather][]string{
+	Sandstorm: {"rock", "ground", "steel"},
+	Hail:      {"ice"},
+}
+
+var weatherText = map[Weather][2]string{
+	Rain:      {"It started to rain!", "The rain stopped."},
+	Sun:       {"The sunlight turned harsh!", "The harsh sunlight faded."},
+	Sandstorm: {"A sandstorm kicked up!", "The sandstorm subsided."},
+	Hail:      {"It started to hail!", "The hail stopped."},
+}
+
+var terrainText = map[Terrain][2]string{
+	ElectricTerrain: {"An electric current ran across the battlefield!", "The electricity disappeared from the battlefield."},
+	GrassyTerrain:   {"Grass grew to cover the battlefield!", "The grass disappeared from the battlefield."},
+}
+
+func (p *Pokemon) grounded() bool {
+	return p.Ability != "levitate" && !slices.Contains(p.Types, "flying")
+}
+
+// setWeather starts w, unless it's already on. ability is set when by's
+// ability did it rather than a move.
+func (b *Battle) setWeather(w Weather, by *Pokemon, ability string) {
+	if w == b.Weather {
+		return
+	}
+	b.Weather, b.WeatherTurns = w, fieldTurns
+	b.log(Entry{Kind: WeatherStarted, Weather: w, Pokemon: by.Name, Ability: ability})
+}
+
+func (b *Battle) setTerrain(t Terrain, by *Pokemon, ability string) {
+	if t == b.Terrain {
+		return
+	}
+	b.Terrain, b.TerrainTurns = t, fieldTurns
+	b.log(Entry{Kind: TerrainStarted, Terrain: t, Pokemon: by.Name, Ability: ability})
+}
+
+// enter runs a pokemon's abilities as it's sent out.
+func (b *Battle) enter(p *Pokemon) {
+	if w, ok := weatherAbilities[p.Ability]; ok {
+		b.setWeather(w, p, p.Ability)
+	}
+	if t, ok := terrainAbilities[p.Ability]; ok {
+		b.setTerrain(t, p, p.Ability)
+	}
+}
+
+// speed is p's speed with its weather ability and item applied.
+func (b *Battle) speed(p *Pokemon) int {
+	speed := p.Stats.Speed
+	if w, ok := speedAbilities[p.Ability]; ok && w == b.Weather {
+		speed *= 2
+	}
+	if p.Item == ChoiceScarf {
+		speed = speed * 3 / 2
+	}
+	return speed
+}
+
+// fieldModifier is what weather and terrain do to the damage of a move.
+func (b *Battle) fieldModifier(attacker, defender *Pokemon, move Move) float64 {
+	m := 1.0
+	switch {
+	case b.Weather == Rain && move.Type == "water", b.Weather == Sun && move.Type == "fire":
+		m *= 1.5
+	case b.Weather == Rain && move.Type == "fire", b.Weather == Sun && move.Type == "water":
+		m *= 0.5
+	}
+	if b.Weather == Sandstorm && move.Class == Special && slices.Contains(defender.Types, "rock") {
+		// Rock types get half again their special defense in a sandstorm.
+		m /= 1.5
+	}
+	if attacker.grounded() {
+		switch {
+		case b.Terrain == ElectricTerrain && move.Type == "electric", b.Terrain == GrassyTerrain && move.Type == "grass":
+			m *= 1.3
+		}
+	}
+	if b.Terrain == GrassyTerrain && move.Name == "earthquake" && defender.grounded() {
+		m *= 0.5
+	}
+	return m
+}
+
+// endTurn hurts or heals the pokemon on the field and winds the weather and
+// terrain down.
+func (b *Battle) endTurn() {
+	for _, side := range b.Sides {
+		for slot := range side.Active {
+			p := side.InSlot(slot)
+			if p.Fainted() {
+				continue
+			}
+			if immune, ok := weatherImmune[b.Weather]; ok && !slices.ContainsFunc(p.Types, func(t string) bool { return slices.Contains(immune, t) }) {
+				damage := max(p.Stats.HP/16, 1)
+				p.HP = max(p.HP-damage, 0)
+				b.log(Entry{Kind: Buffeted, Pokemon: p.Name, Weather: b.Weather, Damage: damage, HP: p.HP, MaxHP: p.Stats.HP})
+				if p.Fainted() {
+					b.log(Entry{Kind: Fainted, Pokemon: p.Name})
+				}
+			}
+			if b.Terrain == GrassyTerrain && p.grounded() && !p.Fainted() && p.HP < p.Stats.HP {
+				heal := min(max(p.Stats.HP/16, 1), p.Stats.HP-p.HP)
+				p.HP += heal
+				b.log(Entry{Kind: Healed, Pokemon: p.Name, Terrain: b.Terrain, Damage: heal, HP: p.HP, MaxHP: p.Stats.HP})
+			}
+			if p.Item == Leftovers && !p.Fainted() && p.HP < p.Stats.HP {
+				heal := min(max(p.Stats.HP/16, 1), p.Stats.HP-p.HP)
+				p.HP += heal
+				b.log(Entry{Kind: Healed, Pokemon: p.Name, Item: p.Item, Damage: heal, HP: p.HP, MaxHP: p.Stats.HP})
+			}
+		}
+	}
+
+	if b.Weather != Clear {
+		if b.WeatherTurns--; b.WeatherTurns == 0 {
+			b.log(Entry{Kind: WeatherEnded, Weather: b.Weather})
+			b.Weather = Clear
+		}
+	}
+	if b.Terrain != NoTerrain {
+		if b.TerrainTurns--; b.TerrainTurns == 0 {
+			b.log(Entry{Kind: TerrainEnded, Terrain: b.Terrain})
+			b.Terrain = NoTerrain
+		}
+	}
+}
+
+func (e Entry) fieldString() string {
+	prefix := ""
+	if e.Ability != "" {
+		prefix = fmt.Sprintf("[%s's %s] ", e.Pokemon, e.Ability)
+	}
+	switch e.Kind {
+	case WeatherStarted:
+		return prefix + weatherText[e.Weather][0]
+	case WeatherEnded:
+		return weatherText[e.Weather][1]
+	case TerrainStarted:
+		return prefix + terrainText[e.Terrain][0]
+	case TerrainEnded:
+		return terrainText[e.Terrain][1]
+	case Buffeted:
+		return fmt.Sprintf("%s is buffeted by the %s (%d/%d HP)", e.Pokemon, e.Weather, e.HP, e.MaxHP)
+	case Healed:
+		if e.Item != "" {
+			return fmt.Sprintf("%s restored a little HP using its %s (%d/%d HP)", e.Pokemon, e.Item, e.HP, e.MaxHP)
+		}
+		return fmt.Sprintf("%s is healed by the grassy terrain (%d/%d HP)", e.Pokemon, e.HP, e.MaxHP)
+	}
+	return string(e.Kind)
+}
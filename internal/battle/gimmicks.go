@@ -0,0 +1,236 @@
+package battle
+
+import "fmt"
+
+// Rules are the once-per-battle gimmicks a battle allows. Callers pick them
+// per format.
+type Rules struct {
+	Mega    bool `json:"mega"`
+	Dynamax bool `json:"dynamax"`
+}
+
+// Form is what a pokemon holding its mega stone turns into when it mega
+// evolves. It keeps its HP.
+type Form struct {
+	Name    string
+	Types   []string
+	Ability string
+	Stats   Stats
+}
+
+// MegaStones maps each mega stone to the form it unlocks.
+var MegaStones = map[string]string{
+	"venusaurite":    "venusaur-mega",
+	"charizardite-x": "charizard-mega-x",
+	"charizardite-y": "charizard-mega-y",
+	"blastoisinite":  "blastoise-mega",
+	"alakazite":      "alakazam-mega",
+	"gengarite":      "gengar-mega",
+	"kangaskhanite":  "kangaskhan-mega",
+	"pinsirite":      "pinsir-mega",
+	"gyaradosite":    "gyarados-mega",
+	"aerodactylite":  "aerodactyl-mega",
+	"mewtwonite-x":   "mewtwo-mega-x",
+	"mewtwonite-y":   "mewtwo-mega-y",
+	"ampharosite":    "ampharos-mega",
+	"scizorite":      "scizor-mega",
+	"heracronite":    "heracross-mega",
+	"houndoominite":  "houndoom-mega",
+	"tyranitarite":   "tyranitar-mega",
+	"blazikenite":    "blaziken-mega",
+	"gardevoirite":   "gardevoir-mega",
+	"aggronite":      "aggron-mega",
+	"manectite":      "manectric-mega",
+	"garchompite":    "garchomp-mega",
+	"lucarionite":    "lucario-mega",
+	"abomasite":      "abomasnow-mega",
+}
+
+func init() {
+	for stone, form := range MegaStones {
+		HeldItems[stone] = "lets its pokemon mega evolve into " + form
+	}
+}
+
+// dynamaxTurns is how long a pokemon stays dynamaxed.
+const dynamaxTurns = 3
+
+// maxMoveNames are the max moves damaging moves of each type become.
+var maxMoveNames = map[string]string{
+	"normal":   "max-strike",
+	"fire":     "max-flare",
+	"water":    "max-geyser",
+	"electric": "max-lightning",
+	"grass":    "max-overgrowth",
+	"ice":      "max-hailstorm",
+	"fighting": "max-knuckle",
+	"poison":   "max-ooze",
+	"ground":   "max-quake",
+	"flying":   "max-airstream",
+	"psychic":  "max-mindstorm",
+	"bug":      "max-flutterby",
+	"rock":     "max-rockfall",
+	"ghost":    "max-phantasm",
+	"dragon":   "max-wyrmwind",
+	"dark":     "max-darkness",
+	"steel":    "max-steelspike",
+	"fairy":    "max-starfall",
+}
+
+// maxGuard is what status moves become: it protects the user for the turn.
+var maxGuard = Move{Name: "max-guard", Type: "normal", Class: Status, Protect: true}
+
+// MaxMove is the max move m becomes when its user is dynamaxed. Max moves
+// never miss, and those of the weather and terrain types set the field
+// after they hit.
+func MaxMove(m Move) Move {
+	if m.Class == Status {
+		return maxGuard
+	}
+	maxMove := Move{Name: maxMoveNames[m.Type], Type: m.Type, Power: maxPower(m), Class: m.Class}
+	if field, ok := fieldMoves[m.Type]; ok {
+		maxMove.Weather, maxMove.Terrain = field.Weather, field.Terrain
+	}
+	return maxMove
+}
+
+// maxPower is the power of the max move m becomes, from its own power.
+// Fighting and Poison max moves are weaker, as they raise the user's stats in
+// the games.
+func maxPower(m Move) int {
+	steps := []struct{ upTo, power, weak int }{
+		{40, 90, 70}, {50, 100, 75}, {60, 110, 80}, {70, 120, 85}, {100, 130, 90}, {140, 140, 95},
+	}
+	weak := m.Type == "fighting" || m.Type == "poison"
+	for _, s := range steps {
+		if m.Power <= s.upTo {
+			if weak {
+				return s.weak
+			}
+			return s.power
+		}
+	}
+	if weak {
+		return 100
+	}
+	return 150
+}
+
+func (p *Pokemon) Dynamaxed() bool {
+	return p.dynamaxTurns > 0
+}
+
+// DynamaxTurns is how many more turns p stays dynamaxed.
+func (p *Pokemon) DynamaxTurns() int {
+	return p.dynamaxTurns
+}
+
+// CanMega reports whether the pokemon in a side's slot could mega evolve
+// this turn.
+func (b *Battle) CanMega(side, slot int) bool {
+	s := b.Sides[side]
+	p := s.InSlot(slot)
+	return b.Rules.Mega && s.MegaRing && !s.megaUsed && p.Mega != nil && p.base == nil
+}
+
+// CanDynamax reports whether the pokemon in a side's slot could dynamax this
+// turn. Pokemon holding a mega stone can't.
+func (b *Battle) CanDynamax(side, slot int) bool {
+	s := b.Sides[side]
+	p := s.InSlot(slot)
+	return b.Rules.Dynamax && s.DynamaxBand && !s.dynamaxUsed && p.Mega == nil
+}
+
+func (b *Battle) megaEvolve(side, slot int) {
+	if !b.CanMega(side, slot) {
+		return
+	}
+	s := b.Sides[side]
+	p := s.InSlot(slot)
+	s.megaUsed = true
+	b.log(Entry{Kind: MegaEvolved, Trainer: s.Name, Pokemon: p.Name, Form: p.Mega.Name, Item: p.Item})
+	p.base = &Form{Name: p.Name, Types: p.Types, Ability: p.Ability, Stats: p.Stats}
+	p.takeForm(*p.Mega)
+	b.enter(p)
+}
+
+// takeForm changes p into f, keeping its HP.
+func (p *Pokemon) takeForm(f Form) {
+	hp := p.Stats.HP
+	p.Name, p.Types, p.Ability, p.Stats = f.Name, f.Types, f.Ability, f.Stats
+	p.Stats.HP = hp
+}
+
+func (b *Battle) dynamax(side, slot int) {
+	if !b.CanDynamax(side, slot) {
+		return
+	}
+	s := b.Sides[side]
+	p := s.InSlot(slot)
+	s.dynamaxUsed = true
+	p.dynamaxTurns, p.locked = dynamaxTurns, ""
+	p.Stats.HP *= 2
+	p.HP *= 2
+	b.log(Entry{Kind: Dynamaxed, Trainer: s.Name, Pokemon: p.Name, HP: p.HP, MaxHP: p.Stats.HP})
+}
+
+// shrink brings a dynamaxed p back to its normal size, halving its HP.
+func (p *Pokemon) shrink() {
+	p.dynamaxTurns = 0
+	p.Stats.HP /= 2
+	p.HP = (p.HP + 1) / 2
+}
+
+// undoDynamax shrinks p, saying so unless it fainted.
+func (b *Battle) undoDynamax(p *Pokemon) {
+	p.shrink()
+	if !p.Fainted() {
+		b.log(Entry{Kind: DynamaxEnded, Pokemon: p.Name, HP: p.HP, MaxHP: p.Stats.HP})
+	}
+}
+
+// endGimmickTurn drops every protection and counts a turn off every
+// dynamaxed pokemon on the field.
+func (b *Battle) endGimmickTurn() {
+	for _, side := range b.Sides {
+		for slot := range side.Active {
+			p := side.InSlot(slot)
+			p.protected = false
+			if p.Dynamaxed() && !p.Fainted() {
+				if p.dynamaxTurns--; p.dynamaxTurns == 0 {
+					b.undoDynamax(p)
+				}
+			}
+		}
+	}
+}
+
+// endGimmicks turns every pokemon back to how it started the battle, with
+// its real HP, so callers and later battles see it as it is.
+func (b *Battle) endGimmicks() {
+	for _, side := range b.Sides {
+		for _, p := range side.Team {
+			if p.Dynamaxed() {
+				p.shrink()
+			}
+			if p.base != nil {
+				p.takeForm(*p.base)
+				p.base = nil
+			}
+		}
+	}
+}
+
+func (e Entry) gimmickString() string {
+	switch e.Kind {
+	case MegaEvolved:
+		return fmt.Sprintf("%s's %s is reacting! %s has mega evolved into %s!", e.Pokemon, e.Item, e.Pokemon, e.Form)
+	case Dynamaxed:
+		return fmt.Sprintf("%s dynamaxed! (%d/%d HP)", e.Pokemon, e.HP, e.MaxHP)
+	case DynamaxEnded:
+		return fmt.Sprintf("%s returned to its normal size (%d/%d HP)", e.Pokemon, e.HP, e.MaxHP)
+	case Protected:
+		return fmt.Sprintf("%s protected itself!", e.Pokemon)
+	}
+	return e.fieldString()
+}
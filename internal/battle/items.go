@@ -0,0 +1,37 @@
+package battle
+
+const (
+	Leftovers   = "leftovers"
+	ChoiceBand  = "choice-band"
+	ChoiceSpecs = "choice-specs"
+	ChoiceScarf = "choice-scarf"
+	// LuckyEgg does nothing in battle; it boosts the experience callers give
+	// out afterwards.
+	LuckyEgg = "lucky-egg"
+)
+
+// HeldItems describes every item a pokemon can hold.
+var HeldItems = map[string]string{
+	Leftovers:   "restores 1/16 of max HP every turn",
+	ChoiceBand:  "boosts Attack by 50% but locks the holder into one move",
+	ChoiceSpecs: "boosts Sp. Atk by 50% but locks the holder into one move",
+	ChoiceScarf: "boosts Speed by 50% but locks the holder into one move",
+	LuckyEgg:    "earns 50% more experience",
+}
+
+func (p *Pokemon) choiceLocked() bool {
+	return p.Item == ChoiceBand || p.Item == ChoiceSpecs || p.Item == ChoiceScarf
+}
+
+// lockedMove is the move a choice item holds p to, or -1.
+func (p *Pokemon) lockedMove() int {
+	if !p.choiceLocked() || p.locked == "" {
+		return -1
+	}
+	for i, m := range p.Moves {
+		if m.Name == p.locked {
+			return i
+		}
+	}
+	return -1
+}
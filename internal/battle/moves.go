@@ -0,0 +1,132 @@
+package battle
+
+type Class string
+
+const (
+	Physical Class = "physical"
+	Special  Class = "special"
+	// Status moves do no damage; here they only set the weather or terrain,
+	// or protect the user.
+	Status Class = "status"
+)
+
+// Target is who a move hits, named as in PokeAPI. Moves that hit several
+// pokemon only matter in double battles.
+type Target string
+
+const (
+	SelectedPokemon Target = ""
+	AllOpponents    Target = "all-opponents"
+	// AllOther hits the user's partner too.
+	AllOther Target = "all-other-pokemon"
+)
+
+type Move struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Power    int     `json:"power"`
+	Accuracy int     `json:"accuracy"`
+	Class    Class   `json:"class"`
+	Target   Target  `json:"target,omitempty"`
+	Weather  Weather `json:"weather,omitempty"`
+	Terrain  Terrain `json:"terrain,omitempty"`
+	// Protect moves go first and shield the user from attacks for the turn.
+	Protect bool `json:"protect,omitempty"`
+}
+
+// MaxMoves is how many moves a pokemon can know at once.
+const MaxMoves = 4
+
+// strongMoveLevel is the level from which pokemon get the stronger move of
+// their types.
+const strongMoveLevel = 30
+
+var tackle = Move{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, Class: Physical}
+
+// basicMoves and strongMoves are one typical move per type.
+var basicMoves = map[string]Move{
+	"normal":   {Name: "quick-attack", Type: "normal", Power: 40, Accuracy: 100, Class: Physical},
+	"fire":     {Name: "ember", Type: "fire", Power: 40, Accuracy: 100, Class: Special},
+	"water":    {Name: "water-gun", Type: "water", Power: 40, Accuracy: 100, Class: Special},
+	"electric": {Name: "thunder-shock", Type: "electric", Power: 40, Accuracy: 100, Class: Special},
+	"grass":    {Name: "vine-whip", Type: "grass", Power: 45, Accuracy: 100, Class: Physical},
+	"ice":      {Name: "powder-snow", Type: "ice", Power: 40, Accuracy: 100, Class: Special, Target: AllOpponents},
+	"fighting": {Name: "karate-chop", Type: "fighting", Power: 50, Accuracy: 100, Class: Physical},
+	"poison":   {Name: "acid", Type: "poison", Power: 40, Accuracy: 100, Class: Special},
+	"ground":   {Name: "mud-shot", Type: "ground", Power: 55, Accuracy: 95, Class: Special},
+	"flying":   {Name: "gust", Type: "flying", Power: 40, Accuracy: 100, Class: Special},
+	"psychic":  {Name: "confusion", Type: "psychic", Power: 50, Accuracy: 100, Class: Special},
+	"bug":      {Name: "bug-bite", Type: "bug", Power: 60, Accuracy: 100, Class: Physical},
+	"rock":     {Name: "rock-throw", Type: "rock", Power: 50, Accuracy: 90, Class: Physical},
+	"ghost":    {Name: "astonish", Type: "ghost", Power: 30, Accuracy: 100, Class: Physical},
+	"dragon":   {Name: "dragon-breath", Type: "dragon", Power: 60, Accuracy: 100, Class: Special},
+	"dark":     {Name: "bite", Type: "dark", Power: 60, Accuracy: 100, Class: Physical},
+	"steel":    {Name: "metal-claw", Type: "steel", Power: 50, Accuracy: 95, Class: Physical},
+	"fairy":    {Name: "fairy-wind", Type: "fairy", Power: 40, Accuracy: 100, Class: Special},
+}
+
+var strongMoves = map[string]Move{
+	"normal":   {Name: "body-slam", Type: "normal", Power: 85, Accuracy: 100, Class: Physical},
+	"fire":     {Name: "flamethrower", Type: "fire", Power: 90, Accuracy: 100, Class: Special},
+	"water":    {Name: "surf", Type: "water", Power: 90, Accuracy: 100, Class: Special, Target: AllOther},
+	"electric": {Name: "thunderbolt", Type: "electric", Power: 90, Accuracy: 100, Class: Special},
+	"grass":    {Name: "energy-ball", Type: "grass", Power: 90, Accuracy: 100, Class: Special},
+	"ice":      {Name: "ice-beam", Type: "ice", Power: 90, Accuracy: 100, Class: Special},
+	"fighting": {Name: "brick-break", Type: "fighting", Power: 75, Accuracy: 100, Class: Physical},
+	"poison":   {Name: "sludge-bomb", Type: "poison", Power: 90, Accuracy: 100, Class: Special},
+	"ground":   {Name: "earthquake", Type: "ground", Power: 100, Accuracy: 100, Class: Physical, Target: AllOther},
+	"flying":   {Name: "fly", Type: "flying", Power: 90, Accuracy: 95, Class: Physical},
+	"psychic":  {Name: "psychic", Type: "psychic", Power: 90, Accuracy: 100, Class: Special},
+	"bug":      {Name: "x-scissor", Type: "bug", Power: 80, Accuracy: 100, Class: Physical},
+	"rock":     {Name: "rock-slide", Type: "rock", Power: 75, Accuracy: 90, Class: Physical, Target: AllOpponents},
+	"ghost":    {Name: "shadow-ball", Type: "ghost", Power: 80, Accuracy: 100, Class: Special},
+	"dragon":   {Name: "dragon-claw", Type: "dragon", Power: 80, Accuracy: 100, Class: Physical},
+	"dark":     {Name: "crunch", Type: "dark", Power: 80, Accuracy: 100, Class: Physical},
+	"steel":    {Name: "iron-head", Type: "steel", Power: 80, Accuracy: 100, Class: Physical},
+	"fairy":    {Name: "moonblast", Type: "fairy", Power: 95, Accuracy: 100, Class: Special},
+}
+
+// fieldMoves set the weather or terrain that suits a type.
+var fieldMoves = map[string]Move{
+	"water":    {Name: "rain-dance", Type: "water", Class: Status, Weather: Rain},
+	"fire":     {Name: "sunny-day", Type: "fire", Class: Status, Weather: Sun},
+	"rock":     {Name: "sandstorm", Type: "rock", Class: Status, Weather: Sandstorm},
+	"ice":      {Name: "hail", Type: "ice", Class: Status, Weather: Hail},
+	"electric": {Name: "electric-terrain", Type: "electric", Class: Status, Terrain: ElectricTerrain},
+	"grass":    {Name: "grassy-terrain", Type: "grass", Class: Status, Terrain: GrassyTerrain},
+}
+
+// DefaultMoves gives a pokemon tackle plus a move of each of its types,
+// stronger ones from strongMoveLevel on, when it also learns to set the
+// field. It stands in for real learnsets.
+func DefaultMoves(types []string, level int) []Move {
+	moves := []Move{tackle}
+	table := basicMoves
+	if level >= strongMoveLevel {
+		table = strongMoves
+	}
+	for _, t := range types {
+		if m, ok := table[t]; ok && m.Name != moves[len(moves)-1].Name {
+			moves = append(moves, m)
+		}
+	}
+	if level >= strongMoveLevel {
+		for _, t := range types {
+			if m, ok := fieldMoves[t]; ok {
+				moves = append(moves, m)
+				break
+			}
+		}
+	}
+	return moves
+}
+
+// FieldMove returns the move called name that sets the weather or terrain.
+func FieldMove(name string) (Move, bool) {
+	for _, m := range fieldMoves {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Move{}, false
+}
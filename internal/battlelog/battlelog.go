@@ -0,0 +1,147 @@
+// Package battlelog keeps a record of every battle a player fought, one JSON
+// file each, so they can be listed and replayed.
+package battlelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+)
+
+// Keep is how many battles are kept per player; older ones are deleted as
+// new ones are saved.
+const Keep = 50
+
+// ErrNotFound is returned by Load for an ID that doesn't exist.
+var ErrNotFound = errors.New("battle not found")
+
+// Record is one finished battle.
+type Record struct {
+	ID       int            `json:"id"`
+	Time     time.Time      `json:"time"`
+	Player   string         `json:"player"`
+	Opponent string         `json:"opponent"`
+	Won      bool           `json:"won"`
+	Forfeit  bool           `json:"forfeit,omitempty"`
+	Turns    int            `json:"turns"`
+	Log      []battle.Entry `json:"log"`
+}
+
+// Store keeps each player's battles in a directory of their own.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (st *Store) playerDir(player string) (string, error) {
+	if player == "" || strings.ContainsAny(player, `/\.`) {
+		return "", fmt.Errorf("invalid profile name %q", player)
+	}
+	return filepath.Join(st.dir, player), nil
+}
+
+// ids lists the player's saved battle IDs in order.
+func (st *Store) ids(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := []int{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if id, err := strconv.Atoi(name); ok && err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// Save gives rec the next ID, writes it and deletes all but the newest Keep
+// battles.
+func (st *Store) Save(rec *Record) error {
+	dir, err := st.playerDir(rec.Player)
+	if err != nil {
+		return err
+	}
+	ids, err := st.ids(dir)
+	if err != nil {
+		return err
+	}
+	rec.ID = 1
+	if len(ids) > 0 {
+		rec.ID = ids[len(ids)-1] + 1
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(rec.ID)+".json"), data, 0o644); err != nil {
+		return err
+	}
+
+	ids = append(ids, rec.ID)
+	var errs []error
+	for _, id := range ids[:max(len(ids)-Keep, 0)] {
+		errs = append(errs, os.Remove(filepath.Join(dir, strconv.Itoa(id)+".json")))
+	}
+	return errors.Join(errs...)
+}
+
+// Load reads one battle of player.
+func (st *Store) Load(player string, id int) (Record, error) {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return Record{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(id)+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return Record{}, fmt.Errorf("battle %d: %w", id, err)
+	}
+	return rec, nil
+}
+
+// List returns all of player's saved battles, oldest first.
+func (st *Store) List(player string) ([]Record, error) {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := st.ids(dir)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(ids))
+	for _, id := range ids {
+		rec, err := st.Load(player, id)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
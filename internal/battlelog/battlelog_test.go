@@ -0,0 +1,49 @@
+package battlelog
+
+import (
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+)
+
+func TestSaveRotates(t *testing.T) {
+	st := NewStore(t.TempDir())
+	for i := range Keep + 3 {
+		rec := &Record{Player: "ash", Opponent: "gary", Turns: i}
+		if err := st.Save(rec); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+		if rec.ID != i+1 {
+			t.Errorf("Expected ID %d, got %d", i+1, rec.ID)
+		}
+	}
+
+	records, err := st.List("ash")
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(records) != Keep || records[0].ID != 4 {
+		t.Errorf("Expected the newest %d battles from ID 4, got %d from ID %d", Keep, len(records), records[0].ID)
+	}
+	if _, err := st.Load("ash", 1); err != ErrNotFound {
+		t.Errorf("Expected the oldest battle to be gone, got %v", err)
+	}
+}
+
+func TestLoadRoundTrip(t *testing.T) {
+	st := NewStore(t.TempDir())
+	rec := &Record{Player: "ash", Opponent: "gary", Won: true, Log: []battle.Entry{{Kind: battle.Hit, Pokemon: "eevee", Damage: 12}}}
+	if err := st.Save(rec); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	got, err := st.Load("ash", rec.ID)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !got.Won || len(got.Log) != 1 || got.Log[0].Damage != 12 {
+		t.Errorf("Unexpected record %+v", got)
+	}
+	if records, _ := st.List("misty"); len(records) != 0 {
+		t.Errorf("Expected no battles for another player, got %d", len(records))
+	}
+}
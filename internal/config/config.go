@@ -0,0 +1,220 @@
+// Package config loads the user's settings file.
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type Webhook struct {
+	URL string `json:"url"`
+	// Events lists the event kinds to send; empty means all of them.
+	Events []string `json:"events"`
+}
+
+type Config struct {
+	// API is the base URL of the REST API, for a PokeAPI of one's own, and
+	// GraphQL the endpoint of the GraphQL one. Empty uses pokeapi.co's.
+	API      string    `json:"api,omitempty"`
+	GraphQL  string    `json:"graphql,omitempty"`
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Settings are the defaults for every profile; their fields sit at the
+	// top level of the file.
+	Settings
+	// Profiles overrides Settings for the profiles named.
+	Profiles map[string]Settings `json:"profiles,omitempty"`
+	// Battles turns mega evolution and dynamaxing on or off per battle
+	// format, "single" or "double". Formats left out allow both.
+	Battles map[string]BattleRules `json:"battles,omitempty"`
+	// Ladder is the URL of the ladder server to find link battles on.
+	// Empty uses a server on this machine.
+	Ladder string `json:"ladder,omitempty"`
+	// WonderTrade is the URL of the wonder trade server. Empty uses a
+	// server on this machine.
+	WonderTrade string `json:"wonder_trade,omitempty"`
+	// ShareKey signs the codes export makes and import checks. Players who
+	// set the same key can share pokemon only with each other; empty uses
+	// the key every copy of the game has.
+	ShareKey string `json:"share_key,omitempty"`
+	// Twitch is the chat the twitch command lets vote on the game.
+	Twitch Twitch `json:"twitch,omitzero"`
+	// Slack is the app the slack server answers slash commands for.
+	Slack Slack `json:"slack,omitzero"`
+	// Snapshots is how often each profile is archived, for going back to.
+	Snapshots Snapshots `json:"snapshots,omitzero"`
+	// Log is when the log file is rotated and how many old ones are kept.
+	Log Log `json:"log,omitzero"`
+	// Cache bounds the memory API responses are kept in.
+	Cache Cache `json:"cache,omitzero"`
+	// Network is how requests reach the internet.
+	Network Network `json:"network,omitzero"`
+}
+
+// Network is for networks that only let traffic out through a proxy, or
+// inspect it with their own certificates.
+type Network struct {
+	// Proxy is the URL requests go through. Empty uses HTTPS_PROXY,
+	// HTTP_PROXY and NO_PROXY from the environment.
+	Proxy string `json:"proxy,omitempty"`
+	// CABundle is a PEM file of certificates to trust as well as the
+	// system's.
+	CABundle string `json:"ca_bundle,omitempty"`
+	// Insecure trusts any certificate. Anyone on the network can then read
+	// and change what's fetched, so it's only for trying things out.
+	Insecure bool `json:"insecure_skip_verify,omitempty"`
+}
+
+type Snapshots struct {
+	// Every is how often a profile is archived when it's saved: "daily",
+	// the default, "weekly" or "off".
+	Every string `json:"every,omitempty"`
+	// Keep is how many archives of each profile are kept before the oldest
+	// are deleted; 0 means 7.
+	Keep int `json:"keep,omitempty"`
+}
+
+type Log struct {
+	// MaxMB is how many megabytes the log grows to before it's rotated;
+	// 0 means 10.
+	MaxMB int `json:"max_mb,omitempty"`
+	// MaxDays is how many days a log is written to before it's rotated;
+	// 0 means 7.
+	MaxDays int `json:"max_days,omitempty"`
+	// Keep is how many rotated logs are kept; 0 means 3.
+	Keep int `json:"keep,omitempty"`
+}
+
+type Cache struct {
+	// MaxMB is the most megabytes responses take up before the least
+	// recently used are dropped; 0 means 64.
+	MaxMB int `json:"max_mb,omitempty"`
+	// Compress gzips large responses in memory, trading a little time for
+	// several times the room.
+	Compress bool `json:"compress,omitempty"`
+	// TTL is how many seconds entries of each namespace, "api", "sprites",
+	// "audio" or "snapshots", are kept; those left out keep five minutes.
+	TTL map[string]int `json:"ttl,omitempty"`
+}
+
+// Settings are what each profile can set for itself. Empty fields take the
+// global setting, or else the default.
+type Settings struct {
+	// Prompt is a text/template for the REPL prompt; see package prompt.
+	Prompt string `json:"prompt,omitempty"`
+	// VersionGroup is the game whose level-up learnsets are used, such as
+	// "scarlet-violet". Empty uses the newest game each pokemon has one in.
+	VersionGroup string `json:"version_group,omitempty"`
+	// ShinyOdds is the one-in-n chance of a wild pokemon being shiny before
+	// catch combos improve it; 0 means 4096.
+	ShinyOdds int `json:"shiny_odds,omitempty"`
+	// A11y writes for screen readers: words instead of symbols, bars and
+	// grids, and no colors. It's a pointer so a profile can turn it off
+	// when it's on for everyone.
+	A11y *bool `json:"a11y,omitempty"`
+	// Theme is the color theme, built in or from the themes directory; see
+	// package theme.
+	Theme string `json:"theme,omitempty"`
+	// Icons shows icons for types, shiny pokemon, balls and places on the
+	// map: "emoji", "nerd" for a Nerd Font, or "none", the default.
+	Icons string `json:"icons,omitempty"`
+	// Sprites draws pokemon in inspect and encounters: "kitty", "iterm" or
+	// "sixel" images, "ascii" art, "off", or "auto", the default, for images
+	// if the terminal is known to show them.
+	Sprites string `json:"sprites,omitempty"`
+	// Fast skips animations, such as the ball shaking when catching.
+	Fast *bool `json:"fast,omitempty"`
+	// Sound plays pokemon's cries when they appear and are caught.
+	Sound *bool `json:"sound,omitempty"`
+	// Timing ends each command's output with what it fetched and how long
+	// it took.
+	Timing *bool `json:"timing,omitempty"`
+	// Language is the language species' names, genera and flavor text are
+	// searched in, as PokeAPI names them: "en", the default, "ja", "fr",
+	// "de" and so on.
+	Language string `json:"language,omitempty"`
+	// Pager shows output taller than the terminal a screen at a time:
+	// "auto", the default, for $PAGER or less, "internal" for the game's
+	// own, "off", or a pager command.
+	Pager string `json:"pager,omitempty"`
+}
+
+// For is the settings profile plays with: its own, over the global ones.
+func (c Config) For(profile string) Settings {
+	s, own := c.Settings, c.Profiles[profile]
+	s.Prompt = cmp.Or(own.Prompt, s.Prompt)
+	s.VersionGroup = cmp.Or(own.VersionGroup, s.VersionGroup)
+	s.ShinyOdds = cmp.Or(own.ShinyOdds, s.ShinyOdds)
+	s.Theme = cmp.Or(own.Theme, s.Theme)
+	s.Icons = cmp.Or(own.Icons, s.Icons)
+	s.Sprites = cmp.Or(own.Sprites, s.Sprites)
+	s.Pager = cmp.Or(own.Pager, s.Pager)
+	s.Language = cmp.Or(own.Language, s.Language)
+	if own.A11y != nil {
+		s.A11y = own.A11y
+	}
+	if own.Fast != nil {
+		s.Fast = own.Fast
+	}
+	if own.Sound != nil {
+		s.Sound = own.Sound
+	}
+	if own.Timing != nil {
+		s.Timing = own.Timing
+	}
+	return s
+}
+
+type Slack struct {
+	// SigningSecret checks that requests come from Slack.
+	SigningSecret string `json:"signing_secret"`
+}
+
+type Twitch struct {
+	Channel string `json:"channel"`
+	// Nick and Token log in to chat; without a token, chat is only read.
+	Nick  string `json:"nick"`
+	Token string `json:"token"`
+	// Moderators can run any command without a vote, and stop the game.
+	// The channel's owner always can.
+	Moderators []string `json:"moderators"`
+	// Window is how many seconds chat has to vote each round; 0 means 20.
+	Window int `json:"window"`
+}
+
+type BattleRules struct {
+	Mega    bool `json:"mega"`
+	Dynamax bool `json:"dynamax"`
+}
+
+// Load reads the JSON config at path. A missing file gives the defaults.
+func Load(path string) (Config, error) {
+	cfg := Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to path as JSON. The file can hold secrets, so only its
+// owner can read it.
+func Save(path string, cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,47 @@
+// Package console finds out what the terminal the game runs in can show.
+// Windows consoles only understand ANSI escape codes once asked to, so that
+// part lives behind build tags.
+package console
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// Enable gets f ready for ANSI colors and cursor movement, and reports
+// whether they can be used: f is a terminal that understands them, TERM
+// isn't dumb and NO_COLOR isn't set.
+func Enable(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	return enableVT(f)
+}
+
+var escapes = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// Strip removes ANSI escape codes from s, for terminals that would show
+// them as they are.
+func Strip(s string) string {
+	return escapes.ReplaceAllString(s, "")
+}
+
+// Height is how many lines the terminal f shows, 0 if that can't be told.
+// LINES, when set, is taken over asking the terminal.
+func Height(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	return height(f)
+}
+
+// Raw puts the terminal f reads from in raw mode, so every key press can be
+// read as it comes, without echo. restore puts it back.
+func Raw(f *os.File) (restore func(), err error) {
+	return raw(f)
+}
@@ -0,0 +1,50 @@
+//go:build !windows
+
+package console
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// enableVT has nothing to do: other terminals take ANSI escape codes as
+// they are.
+func enableVT(f *os.File) bool {
+	return true
+}
+
+// height asks stty, which every Unix has, rather than making the ioctl
+// each system numbers differently.
+func height(f *os.File) int {
+	out, err := stty(f, "size")
+	if err != nil {
+		return 0
+	}
+	var rows, cols int
+	if _, err := fmt.Sscan(out, &rows, &cols); err != nil {
+		return 0
+	}
+	return rows
+}
+
+// raw turns off line editing and echo with stty, which prints the settings
+// to restore when asked with -g.
+func raw(f *os.File) (restore func(), err error) {
+	saved, err := stty(f, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(f, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(f, strings.TrimSpace(saved)) }, nil
+}
+
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,28 @@
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	if got := Strip("\x1b[36mPokedex\x1b[0m > \x1b[H\x1b[2J"); got != "Pokedex > " {
+		t.Errorf("Expected the escape codes to be gone, got %q", got)
+	}
+}
+
+func TestEnable(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if Enable(f) {
+		t.Errorf("Expected a file not to get colors")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if Enable(os.Stdout) {
+		t.Errorf("Expected NO_COLOR to turn colors off")
+	}
+}
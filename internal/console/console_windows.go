@@ -0,0 +1,69 @@
+package console
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// enableVirtualTerminalProcessing makes a Windows console interpret ANSI
+// escape codes; it's there from Windows 10 on.
+const enableVirtualTerminalProcessing = 0x0004
+
+// Input modes raw turns off, and the one it turns on so arrow keys come as
+// escape codes.
+const (
+	enableProcessedInput       = 0x0001
+	enableLineInput            = 0x0002
+	enableEchoInput            = 0x0004
+	enableVirtualTerminalInput = 0x0200
+)
+
+var (
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	setConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	getConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+func enableVT(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	ok, _, _ := setConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return ok != 0
+}
+
+// consoleScreenBufferInfo is CONSOLE_SCREEN_BUFFER_INFO.
+type consoleScreenBufferInfo struct {
+	size, cursor                    [2]int16
+	attributes                      uint16
+	left, top, right, bottom        int16
+	maxWindowWidth, maxWindowHeight int16
+}
+
+func height(f *os.File) int {
+	var info consoleScreenBufferInfo
+	ok, _, _ := getConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info)))
+	if ok == 0 {
+		return 0
+	}
+	return int(info.bottom-info.top) + 1
+}
+
+func raw(f *os.File) (restore func(), err error) {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return nil, err
+	}
+	rawMode := mode&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if ok, _, err := setConsoleMode.Call(uintptr(h), uintptr(rawMode)); ok == 0 {
+		return nil, err
+	}
+	return func() { setConsoleMode.Call(uintptr(h), uintptr(mode)) }, nil
+}
@@ -0,0 +1,120 @@
+// Package events is a small synchronous pub/sub bus for game events.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	Started Kind = "started"
+	Caught  Kind = "caught"
+	Escaped Kind = "escaped"
+	// Fled fires when a wild pokemon runs away, ending the encounter.
+	Fled     Kind = "fled"
+	Explored Kind = "explored"
+	// Encountered fires when a wild pokemon appears in front of the player.
+	Encountered Kind = "encountered"
+	LeveledUp   Kind = "leveled_up"
+	ShinyFound  Kind = "shiny_found"
+	// Milestone fires when the number of species caught reaches one of
+	// Milestones.
+	Milestone Kind = "milestone"
+	// HallOfFame fires when the player's party enters the Hall of Fame,
+	// for beating the Elite Four or winning a tournament.
+	HallOfFame Kind = "hall_of_fame"
+)
+
+// Event describes something that happened to a player. Only the fields that
+// make sense for the kind are set.
+type Event struct {
+	Kind    Kind      `json:"kind"`
+	Session string    `json:"session"`
+	Time    time.Time `json:"time"`
+	// Trace is the ID of the command the event happened in, as sent to the
+	// API with its requests.
+	Trace string `json:"trace,omitempty"`
+
+	Pokemon    string   `json:"pokemon,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Area       string   `json:"area,omitempty"`
+	Encounters []string `json:"encounters,omitempty"`
+	Level      int      `json:"level,omitempty"`
+	Shiny      bool     `json:"shiny,omitempty"`
+	Count      int      `json:"count,omitempty"`
+	// New is set when the player explored Area for the first time.
+	New bool `json:"new,omitempty"`
+	// Competition is what a HallOfFame team won, empty for the Elite Four,
+	// and Team the species in it.
+	Competition string   `json:"competition,omitempty"`
+	Team        []string `json:"team,omitempty"`
+}
+
+var Milestones = []int{10, 25, 50, 100, 151, 250, 500, 1000}
+
+type Handler func(Event)
+
+type subscription struct {
+	id      int
+	kind    Kind
+	handler Handler
+}
+
+// Bus delivers each published event to its subscribers, in subscription
+// order, on the publisher's goroutine. Handlers must not publish to the bus
+// that called them.
+type Bus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls h for every event of the given kind. The returned function
+// removes the subscription.
+func (b *Bus) Subscribe(kind Kind, h Handler) func() {
+	return b.add(kind, h)
+}
+
+// SubscribeAll calls h for every event.
+func (b *Bus) SubscribeAll(h Handler) func() {
+	return b.add("", h)
+}
+
+func (b *Bus) add(kind Kind, h Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscription{id: id, kind: kind, handler: h})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subs {
+			if sub.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.kind == "" || sub.kind == e.Kind {
+			sub.handler(e)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package events
+
+import "testing"
+
+func TestBusDelivery(t *testing.T) {
+	bus := NewBus()
+	caught, all := 0, 0
+	unsubscribe := bus.Subscribe(Caught, func(e Event) { caught++ })
+	bus.SubscribeAll(func(e Event) { all++ })
+
+	bus.Publish(Event{Kind: Caught, Pokemon: "pikachu"})
+	bus.Publish(Event{Kind: Escaped, Pokemon: "mew"})
+	unsubscribe()
+	bus.Publish(Event{Kind: Caught, Pokemon: "eevee"})
+
+	if caught != 1 {
+		t.Errorf("Expected 1 caught event, got %d", caught)
+	}
+	if all != 3 {
+		t.Errorf("Expected 3 events in total, got %d", all)
+	}
+}
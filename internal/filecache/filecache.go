@@ -0,0 +1,137 @@
+// Package filecache keeps downloaded files, such as sprites and cries, in a
+// directory so each is fetched once.
+package filecache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxFile is the largest file downloaded.
+const maxFile = 4 << 20
+
+// Cache keeps downloaded files in a directory.
+type Cache struct {
+	dir        string
+	httpClient *http.Client
+
+	mu sync.Mutex
+	// failed are URLs that couldn't be fetched, not tried again until the
+	// game restarts so an offline game isn't slowed down by every encounter.
+	failed map[string]bool
+}
+
+func New(dir string) *Cache {
+	return &Cache{dir: dir, httpClient: &http.Client{Timeout: 5 * time.Second}, failed: map[string]bool{}}
+}
+
+// path is where the file at rawURL is kept: its path on its host, such as
+// raw.githubusercontent.com/.../pokemon/shiny/25.png.
+func (c *Cache) path(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.FromSlash(strings.TrimPrefix(u.Path, "/"))
+	if name == "" || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("bad URL %s", rawURL)
+	}
+	return filepath.Join(c.dir, u.Host, name), nil
+}
+
+// Get returns the file at rawURL, from the directory if it was fetched
+// before.
+func (c *Cache) Get(rawURL string) ([]byte, error) {
+	path, err := c.File(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// File returns the path of the file at rawURL, downloading it into the
+// directory if it isn't there yet.
+func (c *Cache) File(rawURL string) (string, error) {
+	path, err := c.path(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	c.mu.Lock()
+	failed := c.failed[rawURL]
+	c.mu.Unlock()
+	if failed {
+		return "", fmt.Errorf("%s is unavailable", rawURL)
+	}
+	data, err := c.fetch(rawURL)
+	if err != nil {
+		c.mu.Lock()
+		c.failed[rawURL] = true
+		c.mu.Unlock()
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	// Written whole under another name first, so a file is never seen half
+	// downloaded.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp, path)
+}
+
+func (c *Cache) fetch(rawURL string) ([]byte, error) {
+	res, err := c.httpClient.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", rawURL, res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxFile))
+}
+
+// Usage is how many files the directory holds, and their total size.
+func (c *Cache) Usage() (files int, size int64, err error) {
+	err = filepath.WalkDir(c.dir, func(path string, d os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) && path == c.dir {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	return files, size, err
+}
+
+// Clear deletes every file kept, and says how many there were.
+func (c *Cache) Clear() (int, error) {
+	files, _, err := c.Usage()
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	clear(c.failed)
+	c.mu.Unlock()
+	return files, os.RemoveAll(c.dir)
+}
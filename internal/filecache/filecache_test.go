@@ -0,0 +1,47 @@
+package filecache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("sprite"))
+	}))
+	defer server.Close()
+
+	c := New(t.TempDir())
+	for range 2 {
+		got, err := c.Get(server.URL + "/pokemon/25.png")
+		if err != nil || string(got) != "sprite" {
+			t.Fatalf("Expected the file, got %q, %v", got, err)
+		}
+	}
+	path, err := c.File(server.URL + "/pokemon/25.png")
+	if err != nil {
+		t.Fatalf("File() returned error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the file kept on disk, got %v", err)
+	}
+	for range 2 {
+		if _, err := c.Get(server.URL + "/missing.png"); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("Expected each file fetched once, got %d fetches", fetches)
+	}
+	if _, err := c.Get(server.URL + "/../../etc/passwd"); err == nil {
+		t.Error("Expected a path outside the directory to be refused")
+	}
+}
@@ -0,0 +1,251 @@
+// Package fulltext is a full-text index of pokemon species: their names,
+// genera and pokedex flavor text, in one language.
+//
+// Text is split into words on anything that isn't a letter or digit, and
+// lowercased. Chinese and Japanese, written without spaces, are indexed by
+// pairs of characters instead, and English words lose a plural s. A query
+// finds the species that have every one of its words, or words they begin,
+// so "tail flame" finds the Charmander line.
+package fulltext
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// DefaultLanguage is English, which every species has text in.
+const DefaultLanguage = "en"
+
+// Words in names and genera count for more than words in flavor text.
+const (
+	nameWeight  = 5
+	genusWeight = 3
+	textWeight  = 1
+)
+
+// Entry is a species' text in one language.
+type Entry struct {
+	// Species is the species' name in the API, such as "mr-mime".
+	Species string
+	// Name is what the language calls it, such as "Mr. Mime".
+	Name  string
+	Genus string
+	// FlavorText are its pokedex entries, without repeats.
+	FlavorText []string
+}
+
+// EntryFor is species' text in lang, with English for any it hasn't.
+func EntryFor(species pokeapi.PokemonSpecies, lang string) Entry {
+	e := Entry{Species: species.Name}
+	for _, l := range []string{lang, DefaultLanguage} {
+		hadText := len(e.FlavorText) > 0
+		for _, n := range species.Names {
+			if n.Language.Name == l && e.Name == "" {
+				e.Name = n.Name
+			}
+		}
+		for _, g := range species.Genera {
+			if g.Language.Name == l && e.Genus == "" {
+				e.Genus = g.Genus
+			}
+		}
+		for _, f := range species.FlavorTextEntries {
+			// PokeAPI keeps the games' line and page breaks.
+			text := strings.Join(strings.Fields(f.FlavorText), " ")
+			if f.Language.Name == l && !hadText && !slices.Contains(e.FlavorText, text) {
+				e.FlavorText = append(e.FlavorText, text)
+			}
+		}
+	}
+	return e
+}
+
+// Index finds species by the words of their text.
+type Index struct {
+	mu   sync.Mutex
+	lang string
+	// entries are the species indexed, and postings the weight each word
+	// has in each of them.
+	entries  map[string]Entry
+	postings map[string]map[string]int
+}
+
+// New is an empty index of text in lang.
+func New(lang string) *Index {
+	return &Index{lang: lang, entries: map[string]Entry{}, postings: map[string]map[string]int{}}
+}
+
+// Has reports whether the species is indexed.
+func (x *Index) Has(species string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	_, ok := x.entries[species]
+	return ok
+}
+
+// Len is how many species are indexed.
+func (x *Index) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.entries)
+}
+
+// Add indexes e, replacing what was indexed for its species.
+func (x *Index) Add(e Entry) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.entries[e.Species]; ok {
+		for _, docs := range x.postings {
+			delete(docs, e.Species)
+		}
+	}
+	x.entries[e.Species] = e
+	add := func(text string, weight int) {
+		for _, word := range Words(x.lang, text) {
+			if x.postings[word] == nil {
+				x.postings[word] = map[string]int{}
+			}
+			x.postings[word][e.Species] += weight
+		}
+	}
+	add(e.Species, nameWeight)
+	if !strings.EqualFold(e.Name, e.Species) {
+		add(e.Name, nameWeight)
+	}
+	add(e.Genus, genusWeight)
+	for _, text := range e.FlavorText {
+		add(text, textWeight)
+	}
+}
+
+// Result is a species found, with the flavor text that best matched.
+type Result struct {
+	Entry
+	Snippet string
+	Score   int
+}
+
+// Search finds the species with every word of q, best matches first.
+func (x *Index) Search(q string) []Result {
+	words := Words(x.lang, q)
+	if len(words) == 0 {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var scores map[string]int
+	for _, w := range words {
+		found := map[string]int{}
+		for word, docs := range x.postings {
+			if strings.HasPrefix(word, w) {
+				for species, weight := range docs {
+					found[species] += weight
+				}
+			}
+		}
+		if scores == nil {
+			scores = found
+			continue
+		}
+		for species, score := range scores {
+			if weight, ok := found[species]; ok {
+				scores[species] = score + weight
+			} else {
+				delete(scores, species)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for species, score := range scores {
+		e := x.entries[species]
+		results = append(results, Result{Entry: e, Snippet: x.snippet(e, words), Score: score})
+	}
+	slices.SortFunc(results, func(a, b Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Species, b.Species))
+	})
+	return results
+}
+
+// snippet is the flavor text of e with the most of words.
+func (x *Index) snippet(e Entry, words []string) string {
+	best, most := "", 0
+	for _, text := range e.FlavorText {
+		n := 0
+		for _, w := range words {
+			if slices.ContainsFunc(Words(x.lang, text), func(word string) bool { return strings.HasPrefix(word, w) }) {
+				n++
+			}
+		}
+		if n > most {
+			best, most = text, n
+		}
+	}
+	return best
+}
+
+// Words splits text into the words lang indexes it by.
+func Words(lang, text string) []string {
+	var words []string
+	for _, run := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	}) {
+		for _, part := range splitScripts(run) {
+			switch {
+			case isCJK([]rune(part)[0]):
+				words = append(words, bigrams(part)...)
+			case lang == "en":
+				words = append(words, singular(part))
+			default:
+				words = append(words, part)
+			}
+		}
+	}
+	return words
+}
+
+// splitScripts splits run where it goes in or out of Chinese or Japanese
+// characters, as in "メガシンカ2".
+func splitScripts(run string) []string {
+	var parts []string
+	start, cjk := 0, false
+	for i, r := range run {
+		if i > 0 && isCJK(r) != cjk {
+			parts = append(parts, run[start:i])
+			start = i
+		}
+		cjk = isCJK(r)
+	}
+	return append(parts, run[start:])
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// bigrams are the overlapping pairs of characters in s, or s itself if it
+// has only one.
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) == 1 {
+		return []string{s}
+	}
+	pairs := make([]string, 0, len(runes)-1)
+	for i := range len(runes) - 1 {
+		pairs = append(pairs, string(runes[i:i+2]))
+	}
+	return pairs
+}
+
+// singular drops an English plural s, so "flames" finds "flame".
+func singular(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,61 @@
+package fulltext
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func species(name, genus string, text ...string) pokeapi.PokemonSpecies {
+	en := pokeapi.Language{Name: "en"}
+	s := pokeapi.PokemonSpecies{Name: name, Genera: []pokeapi.Genus{{Genus: genus, Language: en}}}
+	for _, t := range text {
+		s.FlavorTextEntries = append(s.FlavorTextEntries, pokeapi.FlavorText{FlavorText: t, Language: en})
+	}
+	return s
+}
+
+func TestSearch(t *testing.T) {
+	x := New("en")
+	x.Add(EntryFor(species("charmander", "Lizard Pokémon", "The flame at the tip of its tail\nmakes a sound as it burns.", "Obviously prefers\fhot places."), "en"))
+	x.Add(EntryFor(species("charmeleon", "Flame Pokémon", "It has a barbaric nature. In battle, it whips its fiery tail around and slashes away with sharp claws.", "Tail flames burn hotter when it's excited."), "en"))
+	x.Add(EntryFor(species("squirtle", "Tiny Turtle Pokémon", "Shoots water at prey while in the water."), "en"))
+
+	results := x.Search("tail flame")
+	names := []string{}
+	for _, r := range results {
+		names = append(names, r.Species)
+	}
+	if !slices.Equal(names, []string{"charmeleon", "charmander"}) {
+		t.Errorf("Expected charmeleon, then charmander, got %v", names)
+	}
+	if want := "Tail flames burn hotter when it's excited."; results[0].Snippet != want {
+		t.Errorf("Expected snippet %q, got %q", want, results[0].Snippet)
+	}
+	if results := x.Search("SQUIRT"); len(results) != 1 || results[0].Species != "squirtle" {
+		t.Errorf("Expected squirtle by its name, got %v", results)
+	}
+	if results := x.Search("tail water"); len(results) != 0 {
+		t.Errorf("Expected nothing with both words, got %v", results)
+	}
+}
+
+func TestEntryForLanguage(t *testing.T) {
+	s := species("charmander", "Lizard Pokémon", "The flame at the tip of its tail makes a sound as it burns.")
+	ja := pokeapi.Language{Name: "ja"}
+	s.Names = []pokeapi.LocalizedName{{Name: "ヒトカゲ", Language: ja}}
+	s.FlavorTextEntries = append(s.FlavorTextEntries, pokeapi.FlavorText{FlavorText: "しっぽの　ほのおは\nいのちの　ともしび", Language: ja})
+
+	e := EntryFor(s, "ja")
+	if e.Name != "ヒトカゲ" || e.Genus != "Lizard Pokémon" || len(e.FlavorText) != 1 || e.FlavorText[0] != "しっぽの ほのおは いのちの ともしび" {
+		t.Errorf("Expected Japanese text with the English genus, got %+v", e)
+	}
+	x := New("ja")
+	x.Add(e)
+	for _, q := range []string{"ほのお", "ヒトカゲ", "charmander"} {
+		if len(x.Search(q)) != 1 {
+			t.Errorf("Expected %q to find charmander", q)
+		}
+	}
+}
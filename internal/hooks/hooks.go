@@ -0,0 +1,207 @@
+// Package hooks runs user Starlark scripts when game events happen.
+//
+// Every *.star file in the hooks directory is loaded once. A script reacts to
+// an event by defining a function with the event's name:
+//
+//	def on_start():
+//	    log("welcome back, %d caught so far" % len(pokedex()))
+//
+//	def on_catch(pokemon):
+//	    log("caught " + pokemon.name)
+//
+//	def on_explore(area, pokemon):
+//	    log("%s has %d pokemon" % (area, len(pokemon)))
+//
+// Scripts are sandboxed: there is no load(), no file or network access, and
+// each call is capped at maxSteps. The only way to see game state is through
+// the builtins below, which return frozen copies.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+const maxSteps = 1_000_000
+
+const (
+	OnStart   = "on_start"
+	OnCatch   = "on_catch"
+	OnExplore = "on_explore"
+)
+
+// Context is what a hook can see and where its output goes.
+type Context struct {
+	Out     io.Writer
+	Pokedex map[string]pokeapi.PokemonType
+}
+
+type script struct {
+	name    string
+	globals starlark.StringDict
+}
+
+// Runner holds the loaded scripts. Loaded globals are frozen, so a Runner is
+// safe to fire from several sessions at once.
+type Runner struct {
+	scripts []script
+}
+
+var builtins = starlark.StringDict{
+	"log":     starlark.NewBuiltin("log", builtinLog),
+	"pokedex": starlark.NewBuiltin("pokedex", builtinPokedex),
+	"pokemon": starlark.NewBuiltin("pokemon", builtinPokemon),
+}
+
+// Load reads every script in dir. A missing directory just means no hooks.
+func Load(dir string) (*Runner, error) {
+	r := &Runner{}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return r, err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := filepath.Base(path)
+		thread := newThread(name, Context{Out: io.Discard})
+		globals, err := starlark.ExecFile(thread, name, src, builtins)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		globals.Freeze()
+		r.scripts = append(r.scripts, script{name: name, globals: globals})
+	}
+	return r, errors.Join(errs...)
+}
+
+// Len reports how many scripts were loaded.
+func (r *Runner) Len() int {
+	return len(r.scripts)
+}
+
+func (r *Runner) Start(ctx Context) error {
+	return r.fire(ctx, OnStart)
+}
+
+func (r *Runner) Catch(ctx Context, p pokeapi.PokemonType) error {
+	return r.fire(ctx, OnCatch, pokemonValue(p))
+}
+
+func (r *Runner) Explore(ctx Context, area string, pokemon []string) error {
+	names := make([]starlark.Value, len(pokemon))
+	for i, name := range pokemon {
+		names[i] = starlark.String(name)
+	}
+	return r.fire(ctx, OnExplore, starlark.String(area), starlark.NewList(names))
+}
+
+func (r *Runner) fire(ctx Context, event string, args ...starlark.Value) error {
+	var errs []error
+	for _, s := range r.scripts {
+		fn, ok := s.globals[event].(starlark.Callable)
+		if !ok {
+			continue
+		}
+		thread := newThread(s.name, ctx)
+		if _, err := starlark.Call(thread, fn, args, nil); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", s.name, event, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func newThread(name string, ctx Context) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			fmt.Fprintln(ctx.Out, msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+	thread.SetLocal("ctx", ctx)
+	return thread
+}
+
+func contextOf(thread *starlark.Thread) Context {
+	ctx, _ := thread.Local("ctx").(Context)
+	return ctx
+}
+
+func builtinLog(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var msg string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &msg); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(contextOf(thread).Out, "[%s] %s\n", thread.Name, msg)
+	return starlark.None, nil
+}
+
+func builtinPokedex(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	dex := contextOf(thread).Pokedex
+	names := make([]string, 0, len(dex))
+	for name := range dex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]starlark.Value, len(names))
+	for i, name := range names {
+		values[i] = starlark.String(name)
+	}
+	list := starlark.NewList(values)
+	list.Freeze()
+	return list, nil
+}
+
+func builtinPokemon(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	p, ok := contextOf(thread).Pokedex[name]
+	if !ok {
+		return starlark.None, nil
+	}
+	return pokemonValue(p), nil
+}
+
+func pokemonValue(p pokeapi.PokemonType) starlark.Value {
+	types := make([]starlark.Value, len(p.Types))
+	for i, t := range p.Types {
+		types[i] = starlark.String(t.Type.Name)
+	}
+	stats := starlark.NewDict(len(p.Stats))
+	for _, s := range p.Stats {
+		stats.SetKey(starlark.String(s.Stat.Name), starlark.MakeInt(s.BaseStat))
+	}
+
+	v := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"name":            starlark.String(p.Name),
+		"height":          starlark.MakeInt(p.Height),
+		"weight":          starlark.MakeInt(p.Weight),
+		"base_experience": starlark.MakeInt(p.BaseExperience),
+		"types":           starlark.NewList(types),
+		"stats":           stats,
+	})
+	v.Freeze()
+	return v
+}
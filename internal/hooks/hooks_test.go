@@ -0,0 +1,53 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestCatchHook(t *testing.T) {
+	dir := t.TempDir()
+	script := `
+def on_catch(pokemon):
+    log("caught %s, %d in dex" % (pokemon.name, len(pokedex())))
+`
+	os.WriteFile(filepath.Join(dir, "log.star"), []byte(script), 0o644)
+
+	runner, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	pikachu := pokeapi.PokemonType{Name: "pikachu"}
+	ctx := Context{Out: out, Pokedex: map[string]pokeapi.PokemonType{"pikachu": pikachu}}
+	if err := runner.Catch(ctx, pikachu); err != nil {
+		t.Fatalf("Catch() returned error: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "caught pikachu, 1 in dex") {
+		t.Errorf("Unexpected hook output %q", got)
+	}
+}
+
+func TestHooksAreSandboxed(t *testing.T) {
+	dir := t.TempDir()
+	script := `
+def on_start():
+    for i in range(100000000):
+        pass
+`
+	os.WriteFile(filepath.Join(dir, "loop.star"), []byte(script), 0o644)
+
+	runner, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if err := runner.Start(Context{Out: &bytes.Buffer{}}); err == nil {
+		t.Errorf("Expected runaway script to be stopped")
+	}
+}
@@ -0,0 +1,96 @@
+// Package journal keeps a Markdown journal for each player of the notable
+// things that happened in their game: a line for each, under a heading for
+// the day.
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store keeps each player's journal in a file of their own.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Path is where player's journal is kept.
+func (st *Store) Path(player string) (string, error) {
+	if player == "" || strings.ContainsAny(player, `/\.`) {
+		return "", fmt.Errorf("invalid profile name %q", player)
+	}
+	return filepath.Join(st.dir, player+".md"), nil
+}
+
+// Append adds line to player's journal at t, starting the journal and the
+// day's heading as needed.
+func (st *Store) Append(player string, t time.Time, line string) error {
+	path, err := st.Path(player)
+	if err != nil {
+		return err
+	}
+	journal, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var add strings.Builder
+	if len(journal) == 0 {
+		fmt.Fprintf(&add, "# %s's journal\n", player)
+	}
+	if day := "## " + t.Format("2006-01-02"); lastHeading(string(journal)) != day {
+		fmt.Fprintf(&add, "\n%s\n\n", day)
+	}
+	fmt.Fprintf(&add, "- %s %s\n", t.Format("15:04"), line)
+
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(add.String())
+	return errors.Join(err, f.Close())
+}
+
+// lastHeading is the last day's heading in journal, or "" if it has none.
+func lastHeading(journal string) string {
+	i := strings.LastIndex(journal, "\n## ")
+	if i < 0 {
+		return ""
+	}
+	heading, _, _ := strings.Cut(journal[i+1:], "\n")
+	return heading
+}
+
+// Write replaces player's journal with journal.
+func (st *Store) Write(player, journal string) error {
+	path, err := st.Path(player)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(journal), 0o644)
+}
+
+// Read returns player's journal, or "" if nothing has happened yet.
+func (st *Store) Read(player string) (string, error) {
+	path, err := st.Path(player)
+	if err != nil {
+		return "", err
+	}
+	journal, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return string(journal), err
+}
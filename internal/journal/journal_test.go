@@ -0,0 +1,38 @@
+package journal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppend(t *testing.T) {
+	st := NewStore(t.TempDir())
+	if journal, err := st.Read("red"); err != nil || journal != "" {
+		t.Errorf("Expected an empty journal before anything happened, got %q, %v", journal, err)
+	}
+	day := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	for _, e := range []struct {
+		t    time.Time
+		line string
+	}{
+		{day, "Caught pikachu (Lv. 5)."},
+		{day.Add(time.Hour), "Caught a **shiny** zubat (Lv. 3)!"},
+		{day.Add(24 * time.Hour), "Entered the Hall of Fame."},
+	} {
+		if err := st.Append("red", e.t, e.line); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+	want := "# red's journal\n" +
+		"\n## 2024-05-01\n\n" +
+		"- 09:30 Caught pikachu (Lv. 5).\n" +
+		"- 10:30 Caught a **shiny** zubat (Lv. 3)!\n" +
+		"\n## 2024-05-02\n\n" +
+		"- 09:30 Entered the Hall of Fame.\n"
+	if journal, err := st.Read("red"); err != nil || journal != want {
+		t.Errorf("Expected %q, got %q, %v", want, journal, err)
+	}
+	if err := st.Append("../red", day, "Cheated."); err == nil {
+		t.Errorf("Expected a profile name with a path in it to be refused")
+	}
+}
@@ -0,0 +1,86 @@
+package ladder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to a ladder server.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Join puts name on the ladder, waiting for a challenger at addr. An addr
+// without a host is reached at the address the request came from. secret
+// is the one the ladder gave name, or empty the first time it's used.
+func (c *Client) Join(name, secret, addr string) (Ticket, error) {
+	var t Ticket
+	err := c.post("/join", request{Name: name, Secret: secret, Addr: addr}, &t)
+	return t, err
+}
+
+// Leave stops name waiting for a challenger.
+func (c *Client) Leave(name, secret string) error {
+	return c.post("/leave", request{Name: name, Secret: secret}, nil)
+}
+
+// Challenge matches name with opponent, or with whoever waiting is closest
+// in rating if opponent is empty.
+func (c *Client) Challenge(name, secret, opponent string) (Challenge, error) {
+	var ch Challenge
+	err := c.post("/challenge", request{Name: name, Secret: secret, Opponent: opponent}, &ch)
+	return ch, err
+}
+
+// Report says who won match, with the key the player was given for it.
+func (c *Client) Report(match, key, winner string) (Result, error) {
+	var r Result
+	err := c.post("/results", request{Match: match, Key: key, Winner: winner}, &r)
+	return r, err
+}
+
+// Standings is everyone on the ladder, highest rated first.
+func (c *Client) Standings() ([]Player, error) {
+	resp, err := c.http.Get(c.url + "/standings")
+	if err != nil {
+		return nil, err
+	}
+	var players []Player
+	return players, decode(resp, &players)
+}
+
+func (c *Client) post(path string, req request, into any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(c.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return decode(resp, into)
+}
+
+// decode reads a response into into, turning error responses into errors
+// with the server's message.
+func decode(resp *http.Response, into any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ladder server: %s", strings.TrimSpace(string(msg)))
+	}
+	if into == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
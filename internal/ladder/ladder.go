@@ -0,0 +1,375 @@
+// Package ladder is a matchmaking server for link battles. Players waiting
+// for a battle register the address their game hosts on, challengers are
+// matched with the closest-rated of them, and both report the result back
+// to keep an Elo ladder. A name belongs to whoever first uses it, who is
+// given a secret to prove it with from then on. Each player is also given a
+// secret key for every match, and a result only counts once both players
+// have reported it the same.
+package ladder
+
+import (
+	"cmp"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// DefaultAddr is where the server listens unless told otherwise, and
+// DefaultURL where clients look for it.
+const (
+	DefaultAddr = ":7778"
+	DefaultURL  = "http://localhost:7778"
+)
+
+const (
+	// StartRating is every player's rating on joining the ladder.
+	StartRating = 1000
+	// kFactor is the most a rating moves in one battle.
+	kFactor = 32
+)
+
+// Player is someone on the ladder.
+type Player struct {
+	Name   string `json:"name"`
+	Rating int    `json:"rating"`
+	Wins   int    `json:"wins"`
+	Losses int    `json:"losses"`
+	// Addr is where the player's game is hosting a battle while they wait
+	// for a challenger; it's empty otherwise.
+	Addr string `json:"addr,omitempty"`
+}
+
+// Match is a battle the server paired two players for. Keys are the
+// players' secrets for reporting the result, and Reports who each of them
+// said won. Winner is empty until both reports agree.
+type Match struct {
+	ID      string    `json:"id"`
+	Players [2]string `json:"players"`
+	Keys    [2]string `json:"keys"`
+	Reports [2]string `json:"reports,omitzero"`
+	Winner  string    `json:"winner,omitempty"`
+}
+
+// Ticket is the server's answer to a join: the player, the key to report
+// the result of the match they're challenged to with, and the secret that
+// owns their name.
+type Ticket struct {
+	Player Player `json:"player"`
+	Key    string `json:"key"`
+	Secret string `json:"secret"`
+}
+
+// Challenge is the server's answer to a challenge: who to battle, and the
+// match to report the result of, with the challenger's key for it and the
+// secret that owns their name.
+type Challenge struct {
+	Match    string `json:"match"`
+	Key      string `json:"key"`
+	Secret   string `json:"secret"`
+	Opponent Player `json:"opponent"`
+}
+
+// Result is a reported winner, and the players' standing afterwards. Winner
+// is empty while the other player's report is still to come.
+type Result struct {
+	Match   string   `json:"match"`
+	Winner  string   `json:"winner"`
+	Players []Player `json:"players,omitempty"`
+}
+
+// Elo is the winner's and the loser's ratings after a battle between them.
+func Elo(winner, loser int) (int, int) {
+	expected := 1 / (1 + math.Pow(10, float64(loser-winner)/400))
+	change := int(math.Round(kFactor * (1 - expected)))
+	return winner + change, loser - change
+}
+
+// Server keeps the ladder, saving it to a JSON file after every change so
+// standings survive a restart.
+type Server struct {
+	mu      sync.Mutex
+	path    string
+	Players map[string]*Player `json:"players"`
+	Matches map[string]*Match  `json:"matches"`
+	NextID  int                `json:"next_id"`
+	// Keys has the key of each player waiting for a challenger, for the
+	// match they're put in.
+	Keys map[string]string `json:"keys"`
+	// Secrets has the secret of each name, given to whoever used it first.
+	Secrets map[string]string `json:"secrets"`
+}
+
+// NewServer loads the ladder saved at path. An empty path keeps it in
+// memory only.
+func NewServer(path string) (*Server, error) {
+	sv := &Server{path: path, Players: map[string]*Player{}, Matches: map[string]*Match{}, NextID: 1, Keys: map[string]string{}, Secrets: map[string]string{}}
+	if path == "" {
+		return sv, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sv, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, sv); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if sv.Keys == nil {
+		sv.Keys = map[string]string{}
+	}
+	if sv.Secrets == nil {
+		sv.Secrets = map[string]string{}
+	}
+	return sv, nil
+}
+
+func (sv *Server) save() error {
+	if sv.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(sv, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write a new file and rename it over the old one, so a crash can't
+	// leave the ladder half written.
+	tmp, err := os.CreateTemp(filepath.Dir(sv.path), filepath.Base(sv.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), sv.path)
+}
+
+// claim checks that a request comes from whoever owns its name, returning
+// the name's secret: a new one for a name nobody has used yet, which is
+// only kept if the request succeeds.
+func (sv *Server) claim(req request) (string, int, error) {
+	secret, ok := sv.Secrets[req.Name]
+	if !ok {
+		return rand.Text(), 0, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(req.Secret)) != 1 {
+		return "", http.StatusForbidden, fmt.Errorf("%s is another player's name on this ladder", req.Name)
+	}
+	return secret, 0, nil
+}
+
+func (sv *Server) player(name string) *Player {
+	p, ok := sv.Players[name]
+	if !ok {
+		p = &Player{Name: name, Rating: StartRating}
+		sv.Players[name] = p
+	}
+	return p
+}
+
+// Handler serves the ladder's API:
+//
+//	POST /join       {"name", "secret", "addr"}: wait for a challenger
+//	POST /leave      {"name", "secret"}: stop waiting
+//	POST /challenge  {"name", "secret", "opponent"}: get matched, with
+//	                 anyone if opponent is empty
+//	POST /results    {"match", "key", "winner"}: report who won
+//	GET  /standings: everyone, highest rated first
+func (sv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /join", sv.join)
+	mux.HandleFunc("POST /leave", sv.leave)
+	mux.HandleFunc("POST /challenge", sv.challenge)
+	mux.HandleFunc("POST /results", sv.results)
+	mux.HandleFunc("GET /standings", sv.standings)
+	return mux
+}
+
+type request struct {
+	Name     string `json:"name"`
+	Secret   string `json:"secret"`
+	Addr     string `json:"addr"`
+	Opponent string `json:"opponent"`
+	Match    string `json:"match"`
+	Key      string `json:"key"`
+	Winner   string `json:"winner"`
+}
+
+// handle decodes a request and, holding the lock, answers it with respond
+// and saves the ladder.
+func (sv *Server) handle(w http.ResponseWriter, r *http.Request, respond func(req request) (any, int, error)) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" && req.Match == "" {
+		http.Error(w, "missing name", http.StatusBadRequest)
+		return
+	}
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	body, status, err := respond(req)
+	if err == nil {
+		if err = sv.save(); err != nil {
+			status = http.StatusInternalServerError
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func (sv *Server) join(w http.ResponseWriter, r *http.Request) {
+	sv.handle(w, r, func(req request) (any, int, error) {
+		secret, status, err := sv.claim(req)
+		if err != nil {
+			return nil, status, err
+		}
+		host, port, err := net.SplitHostPort(req.Addr)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if host == "" {
+			// Games hosting on every interface are reached at the address
+			// they called from.
+			host, _, _ = net.SplitHostPort(r.RemoteAddr)
+		}
+		p := sv.player(req.Name)
+		p.Addr = net.JoinHostPort(host, port)
+		sv.Keys[p.Name] = rand.Text()
+		sv.Secrets[p.Name] = secret
+		return Ticket{Player: *p, Key: sv.Keys[p.Name], Secret: secret}, 0, nil
+	})
+}
+
+func (sv *Server) leave(w http.ResponseWriter, r *http.Request) {
+	sv.handle(w, r, func(req request) (any, int, error) {
+		if _, status, err := sv.claim(req); err != nil {
+			return nil, status, err
+		}
+		p := sv.player(req.Name)
+		p.Addr = ""
+		delete(sv.Keys, p.Name)
+		return p, 0, nil
+	})
+}
+
+func (sv *Server) challenge(w http.ResponseWriter, r *http.Request) {
+	sv.handle(w, r, func(req request) (any, int, error) {
+		secret, status, err := sv.claim(req)
+		if err != nil {
+			return nil, status, err
+		}
+		me := sv.player(req.Name)
+		var opponent *Player
+		for _, p := range sv.Players {
+			if p.Addr == "" || p.Name == me.Name || req.Opponent != "" && p.Name != req.Opponent {
+				continue
+			}
+			if opponent == nil || gap(p, me) < gap(opponent, me) || gap(p, me) == gap(opponent, me) && p.Name < opponent.Name {
+				opponent = p
+			}
+		}
+		if opponent == nil {
+			if req.Opponent != "" {
+				return nil, http.StatusNotFound, fmt.Errorf("%s isn't waiting for a battle", req.Opponent)
+			}
+			return nil, http.StatusNotFound, errors.New("nobody is waiting for a battle")
+		}
+		m := &Match{
+			ID:      strconv.Itoa(sv.NextID),
+			Players: [2]string{opponent.Name, me.Name},
+			Keys:    [2]string{sv.Keys[opponent.Name], rand.Text()},
+		}
+		sv.NextID++
+		sv.Matches[m.ID] = m
+		c := Challenge{Match: m.ID, Key: m.Keys[1], Secret: secret, Opponent: *opponent}
+		opponent.Addr = ""
+		delete(sv.Keys, opponent.Name)
+		sv.Secrets[me.Name] = secret
+		return c, 0, nil
+	})
+}
+
+func gap(p, q *Player) int {
+	return max(p.Rating-q.Rating, q.Rating-p.Rating)
+}
+
+// results records a player's report of a match, which their key says
+// they played in, and rates the match once both reports agree.
+func (sv *Server) results(w http.ResponseWriter, r *http.Request) {
+	sv.handle(w, r, func(req request) (any, int, error) {
+		m, ok := sv.Matches[req.Match]
+		if !ok {
+			return nil, http.StatusNotFound, fmt.Errorf("there's no match %q", req.Match)
+		}
+		seat := slices.IndexFunc(m.Keys[:], func(key string) bool {
+			return key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(req.Key)) == 1
+		})
+		if seat < 0 {
+			return nil, http.StatusForbidden, fmt.Errorf("that isn't a key for match %s", m.ID)
+		}
+		i := slices.Index(m.Players[:], req.Winner)
+		if i < 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("%s didn't play in match %s", req.Winner, m.ID)
+		}
+		switch m.Reports[seat] {
+		case "":
+			m.Reports[seat] = req.Winner
+		case req.Winner:
+		default:
+			return nil, http.StatusConflict, fmt.Errorf("you already reported match %s as won by %s", m.ID, m.Reports[seat])
+		}
+		if other := m.Reports[1-seat]; other != "" && other != req.Winner {
+			return nil, http.StatusConflict, fmt.Errorf("%s reported match %s as won by %s, so it isn't rated", m.Players[1-seat], m.ID, other)
+		}
+		winner, loser := sv.player(m.Players[i]), sv.player(m.Players[1-i])
+		if m.Winner == "" && m.Reports[0] == m.Reports[1] {
+			m.Winner = winner.Name
+			winner.Rating, loser.Rating = Elo(winner.Rating, loser.Rating)
+			winner.Wins++
+			loser.Losses++
+		}
+		if m.Winner == "" {
+			return Result{Match: m.ID}, 0, nil
+		}
+		return Result{Match: m.ID, Winner: m.Winner, Players: []Player{*winner, *loser}}, 0, nil
+	})
+}
+
+func (sv *Server) standings(w http.ResponseWriter, r *http.Request) {
+	sv.mu.Lock()
+	players := []Player{}
+	for _, p := range sv.Players {
+		players = append(players, *p)
+	}
+	sv.mu.Unlock()
+	slices.SortFunc(players, func(p, q Player) int {
+		if p.Rating != q.Rating {
+			return q.Rating - p.Rating
+		}
+		return cmp.Compare(p.Name, q.Name)
+	})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(players)
+}
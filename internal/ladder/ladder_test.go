@@ -0,0 +1,175 @@
+package ladder
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestElo(t *testing.T) {
+	if w, l := Elo(1000, 1000); w != 1016 || l != 984 {
+		t.Errorf("Elo(1000, 1000) = %d, %d", w, l)
+	}
+	if w, l := Elo(1400, 1000); w != 1403 || l != 997 {
+		t.Errorf("Elo(1400, 1000) = %d, %d", w, l)
+	}
+}
+
+func TestLadder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ladder.json")
+	sv, err := NewServer(path)
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+	c := NewClient(ts.URL)
+
+	if _, err := c.Challenge("blue", "", ""); err == nil {
+		t.Errorf("Expected a challenge with nobody waiting to fail")
+	}
+	red, err := c.Join("red", "", ":7777")
+	if err != nil {
+		t.Fatalf("Join() returned error: %v", err)
+	}
+	if p := red.Player; p.Addr != "127.0.0.1:7777" || p.Rating != StartRating {
+		t.Errorf("Expected red to wait at the address they called from, got %+v", p)
+	}
+	ch, err := c.Challenge("blue", "", "")
+	if err != nil {
+		t.Fatalf("Challenge() returned error: %v", err)
+	}
+	if ch.Opponent.Name != "red" || ch.Opponent.Addr != "127.0.0.1:7777" {
+		t.Errorf("Expected blue to be matched with red, got %+v", ch)
+	}
+	if _, err := c.Challenge("green", "", "red"); err == nil {
+		t.Errorf("Expected red to stop waiting once matched")
+	}
+
+	if _, err := c.Report(ch.Match, "", "blue"); err == nil {
+		t.Errorf("Expected a report without a key to be refused")
+	}
+	if _, err := c.Report(ch.Match, red.Key+"x", "blue"); err == nil {
+		t.Errorf("Expected a report with the wrong key to be refused")
+	}
+	r, err := c.Report(ch.Match, ch.Key, "blue")
+	if err != nil {
+		t.Fatalf("Report() returned error: %v", err)
+	}
+	if r.Winner != "" {
+		t.Errorf("Expected the match not to be rated on one player's word, got %+v", r)
+	}
+	if _, err := c.Report(ch.Match, ch.Key, "red"); err == nil {
+		t.Errorf("Expected a player to be refused changing their report")
+	}
+	r, err = c.Report(ch.Match, red.Key, "blue")
+	if err != nil {
+		t.Fatalf("Report() returned error: %v", err)
+	}
+	if r.Winner != "blue" || r.Players[0].Name != "blue" || r.Players[0].Rating != 1016 || r.Players[1].Rating != 984 {
+		t.Errorf("Expected blue to gain 16 from red, got %+v", r.Players)
+	}
+	if _, err := c.Report(ch.Match, red.Key, "blue"); err != nil {
+		t.Errorf("Expected the same result reported twice to be accepted, got %v", err)
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*")); len(files) != 1 {
+		t.Errorf("Expected only the ladder to be saved, got %v", files)
+	}
+
+	// The standings survive a restart, and only count the result once.
+	sv, err = NewServer(path)
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts2 := httptest.NewServer(sv.Handler())
+	defer ts2.Close()
+	standings, err := NewClient(ts2.URL).Standings()
+	if err != nil {
+		t.Fatalf("Standings() returned error: %v", err)
+	}
+	if len(standings) != 3 || standings[0].Name != "blue" || standings[0].Wins != 1 || standings[2].Name != "red" || standings[2].Losses != 1 {
+		t.Errorf("Expected blue first and red last, got %+v", standings)
+	}
+}
+
+func TestDisputedResult(t *testing.T) {
+	sv, err := NewServer("")
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+	c := NewClient(ts.URL)
+
+	red, err := c.Join("red", "", ":7777")
+	if err != nil {
+		t.Fatalf("Join() returned error: %v", err)
+	}
+	ch, err := c.Challenge("blue", "", "red")
+	if err != nil {
+		t.Fatalf("Challenge() returned error: %v", err)
+	}
+	if _, err := c.Report(ch.Match, red.Key, "red"); err != nil {
+		t.Fatalf("Report() returned error: %v", err)
+	}
+	if _, err := c.Report(ch.Match, ch.Key, "blue"); err == nil {
+		t.Errorf("Expected reports that disagree to be refused")
+	}
+	standings, err := c.Standings()
+	if err != nil {
+		t.Fatalf("Standings() returned error: %v", err)
+	}
+	for _, p := range standings {
+		if p.Rating != StartRating || p.Wins != 0 || p.Losses != 0 {
+			t.Errorf("Expected a disputed match not to be rated, got %+v", p)
+		}
+	}
+}
+
+func TestNameOwnership(t *testing.T) {
+	sv, err := NewServer("")
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+	c := NewClient(ts.URL)
+
+	red, err := c.Join("red", "", ":7777")
+	if err != nil {
+		t.Fatalf("Join() returned error: %v", err)
+	}
+	if red.Secret == "" {
+		t.Fatalf("Expected a secret for the name the first time it's used")
+	}
+	for _, secret := range []string{"", red.Secret + "x"} {
+		if _, err := c.Join("red", secret, ":7778"); err == nil {
+			t.Errorf("Expected a join as red with secret %q to be refused", secret)
+		}
+		if err := c.Leave("red", secret); err == nil {
+			t.Errorf("Expected a leave as red with secret %q to be refused", secret)
+		}
+		if _, err := c.Challenge("red", secret, ""); err == nil {
+			t.Errorf("Expected a challenge as red with secret %q to be refused", secret)
+		}
+	}
+	again, err := c.Join("red", red.Secret, ":7779")
+	if err != nil {
+		t.Fatalf("Join() with red's secret returned error: %v", err)
+	}
+	if again.Secret != red.Secret || again.Player.Addr != "127.0.0.1:7779" {
+		t.Errorf("Expected red to rejoin with the same secret, got %+v", again)
+	}
+
+	ch, err := c.Challenge("blue", "", "red")
+	if err != nil {
+		t.Fatalf("Challenge() returned error: %v", err)
+	}
+	if ch.Secret == "" || ch.Key != sv.Matches[ch.Match].Keys[1] || sv.Matches[ch.Match].Keys[0] != again.Key {
+		t.Errorf("Expected the match keys of red's latest join and blue's challenge, got %+v", ch)
+	}
+	if _, err := c.Join("blue", "", ":7777"); err == nil {
+		t.Errorf("Expected blue's name to be taken once they challenged")
+	}
+}
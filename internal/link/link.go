@@ -0,0 +1,280 @@
+// Package link connects two players' games over TCP so they can battle. Both
+// games run the same battle from a shared seed and only swap the actions
+// they pick each turn, one JSON message per line. Spectators can connect to
+// the host to be sent the battle's log as it's played.
+package link
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+)
+
+// DefaultAddr is where games host battles unless told otherwise.
+const DefaultAddr = ":7777"
+
+// Kind is what a message is for.
+type Kind string
+
+const (
+	// Hello is the challenger's first message, with its name and team, and
+	// the ladder match the battle is for, if any.
+	Hello Kind = "hello"
+	// Start is the host's answer: its name and team, and the seed, format
+	// and rules of the battle.
+	Start Kind = "start"
+	// Turn has the actions a side picked for a turn.
+	Turn Kind = "turn"
+	// Forfeit says a side gave up.
+	Forfeit Kind = "forfeit"
+	// Watch is a spectator's first message.
+	Watch Kind = "watch"
+	// Log has entries of the battle, named by Name, for spectators.
+	Log Kind = "log"
+)
+
+// Message is one line of the protocol. Only the fields that make sense for
+// the kind are set.
+type Message struct {
+	Kind    Kind            `json:"kind"`
+	Name    string          `json:"name,omitempty"`
+	Team    *battle.Side    `json:"team,omitempty"`
+	Seed    [2]uint64       `json:"seed,omitzero"`
+	Format  battle.Format   `json:"format,omitempty"`
+	Rules   battle.Rules    `json:"rules,omitzero"`
+	Actions []battle.Action `json:"actions,omitempty"`
+	Match   string          `json:"match,omitempty"`
+	Log     []battle.Entry  `json:"log,omitempty"`
+}
+
+// Conn is one end of a link.
+type Conn struct {
+	conn    net.Conn
+	dec     *json.Decoder
+	timeout time.Duration
+}
+
+func newConn(conn net.Conn, timeout time.Duration) *Conn {
+	return &Conn{conn: conn, dec: json.NewDecoder(bufio.NewReader(conn)), timeout: timeout}
+}
+
+// Listener is a game hosting a battle. It takes one challenger, and any
+// number of spectators, who are sent the battle's log as it's played.
+type Listener struct {
+	l           net.Listener
+	timeout     time.Duration
+	challengers chan challenger
+
+	mu       sync.Mutex
+	taken    bool
+	closed   bool
+	title    string
+	history  []battle.Entry
+	watchers []*watcher
+}
+
+// watchQueue is how many messages a spectator can fall behind by before
+// it's dropped.
+const watchQueue = 64
+
+// watcher is a spectator's link, written to by its own goroutine so that a
+// slow spectator can't hold up the battle.
+type watcher struct {
+	conn  *Conn
+	queue chan Message
+}
+
+// write sends the queued messages until the queue is closed, then
+// disconnects.
+func (w *watcher) write() {
+	defer w.conn.Close()
+	for m := range w.queue {
+		if w.conn.Send(m) != nil {
+			return
+		}
+	}
+}
+
+type challenger struct {
+	conn  *Conn
+	hello Message
+}
+
+// Listen starts hosting a battle on addr. Reads on the links it accepts
+// give up after timeout.
+func Listen(addr string, timeout time.Duration) (*Listener, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	hl := &Listener{l: l, timeout: timeout, challengers: make(chan challenger, 1)}
+	go hl.serve()
+	return hl, nil
+}
+
+func (l *Listener) Addr() net.Addr {
+	return l.l.Addr()
+}
+
+// serve accepts connections until the listener is closed, sorting out
+// challengers from spectators by their first message.
+func (l *Listener) serve() {
+	for {
+		conn, err := l.l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			c := newConn(conn, l.timeout)
+			m, err := c.Receive()
+			switch {
+			case err != nil:
+				c.Close()
+			case m.Kind == Watch:
+				l.watch(c)
+			case m.Kind == Hello && l.take():
+				l.challengers <- challenger{c, m}
+			default:
+				c.Close()
+			}
+		}()
+	}
+}
+
+// take claims the challenger's seat, reporting whether it was free.
+func (l *Listener) take() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.taken || l.closed {
+		return false
+	}
+	l.taken = true
+	return true
+}
+
+func (l *Listener) watch(c *Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		c.Close()
+		return
+	}
+	w := &watcher{conn: c, queue: make(chan Message, watchQueue)}
+	go w.write()
+	if l.title != "" {
+		w.queue <- Message{Kind: Log, Name: l.title, Log: l.history}
+	}
+	l.watchers = append(l.watchers, w)
+}
+
+// Accept waits for a challenger, giving up after the listener's timeout,
+// and returns the link to them and their hello.
+func (l *Listener) Accept() (*Conn, Message, error) {
+	select {
+	case c := <-l.challengers:
+		return c.conn, c.hello, nil
+	case <-time.After(l.timeout):
+		return nil, Message{}, errors.New("no challenger turned up")
+	}
+}
+
+// Broadcast sends the entries of the battle called title to everyone
+// watching, and keeps them for spectators who join later. Spectators who
+// can't keep up are dropped.
+func (l *Listener) Broadcast(title string, entries []battle.Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.title = title
+	l.history = append(l.history, entries...)
+	l.watchers = slices.DeleteFunc(l.watchers, func(w *watcher) bool {
+		select {
+		case w.queue <- Message{Kind: Log, Name: title, Log: entries}:
+			return false
+		default:
+			close(w.queue)
+			w.conn.Close()
+			return true
+		}
+	})
+}
+
+// Close stops hosting. Spectators are disconnected once they've been sent
+// what was broadcast.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	for _, w := range l.watchers {
+		close(w.queue)
+	}
+	l.watchers = nil
+	return l.l.Close()
+}
+
+// Dial connects to a game hosting a battle at addr. Reads on the link give
+// up after timeout.
+func Dial(addr string, timeout time.Duration) (*Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(conn, timeout), nil
+}
+
+// Spectate connects to a game hosting a battle at addr to watch it. The
+// link then only receives Log messages.
+func Spectate(addr string, timeout time.Duration) (*Conn, error) {
+	c, err := Dial(addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Send(Message{Kind: Watch}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Send writes m to the other side.
+func (c *Conn) Send(m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	_, err = c.conn.Write(append(data, '\n'))
+	return err
+}
+
+// Receive waits for the other side's next message.
+func (c *Conn) Receive() (Message, error) {
+	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	var m Message
+	if err := c.dec.Decode(&m); err != nil {
+		return Message{}, err
+	}
+	return m, nil
+}
+
+// Expect receives the next message, which must be of kind.
+func (c *Conn) Expect(kind Kind) (Message, error) {
+	m, err := c.Receive()
+	if err != nil {
+		return Message{}, err
+	}
+	if m.Kind != kind {
+		return Message{}, fmt.Errorf("expected a %s message, got %q", kind, m.Kind)
+	}
+	return m, nil
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
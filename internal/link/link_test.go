@@ -0,0 +1,125 @@
+package link
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+)
+
+func TestLink(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", time.Second)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	defer l.Close()
+	guest, err := Dial(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Dial() returned error: %v", err)
+	}
+	defer guest.Close()
+
+	team := &battle.Side{Name: "ash", Team: []*battle.Pokemon{{Name: "pikachu", Level: 5, HP: 20}}}
+	if err := guest.Send(Message{Kind: Hello, Name: "ash", Team: team}); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	host, m, err := l.Accept()
+	if err != nil {
+		t.Fatalf("Accept() returned error: %v", err)
+	}
+	defer host.Close()
+	if m.Name != "ash" || m.Team.Team[0].Name != "pikachu" || m.Team.Team[0].HP != 20 {
+		t.Errorf("Expected ash's team, got %+v", m)
+	}
+
+	if err := host.Send(Message{Kind: Turn, Actions: []battle.Action{{Kind: battle.Switch, Switch: 2}}}); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	if _, err := guest.Expect(Start); err == nil {
+		t.Errorf("Expected a turn to be refused in place of the start")
+	}
+}
+
+func TestAcceptTimesOut(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	defer l.Close()
+	if _, _, err := l.Accept(); err == nil {
+		t.Errorf("Expected hosting with nobody connecting to time out")
+	}
+}
+
+func TestSpectate(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", time.Second)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	early, err := Spectate(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Spectate() returned error: %v", err)
+	}
+	defer early.Close()
+	// Give the listener time to take the spectator on before anything is
+	// broadcast.
+	time.Sleep(50 * time.Millisecond)
+
+	l.Broadcast("red vs blue", []battle.Entry{{Kind: battle.SentOut, Trainer: "red", Pokemon: "pikachu"}})
+	m, err := early.Expect(Log)
+	if err != nil {
+		t.Fatalf("Expect() returned error: %v", err)
+	}
+	if m.Name != "red vs blue" || len(m.Log) != 1 || m.Log[0].Pokemon != "pikachu" {
+		t.Errorf("Expected pikachu being sent out, got %+v", m)
+	}
+
+	late, err := Spectate(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Spectate() returned error: %v", err)
+	}
+	defer late.Close()
+	if m, err := late.Expect(Log); err != nil || len(m.Log) != 1 {
+		t.Errorf("Expected a late spectator to catch up on the log, got %+v, %v", m, err)
+	}
+
+	l.Close()
+	if _, err := early.Receive(); err == nil {
+		t.Errorf("Expected closing the listener to disconnect spectators")
+	}
+}
+
+func TestSlowSpectator(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", time.Minute)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	defer l.Close()
+	// A spectator that never reads what it's sent.
+	slow, err := Spectate(l.Addr().String(), time.Minute)
+	if err != nil {
+		t.Fatalf("Spectate() returned error: %v", err)
+	}
+	defer slow.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	entries := []battle.Entry{{Kind: battle.Used, Trainer: strings.Repeat("red", 10000)}}
+	done := make(chan struct{})
+	go func() {
+		for range 1000 {
+			l.Broadcast("red vs blue", entries)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a slow spectator not to hold up the battle")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.watchers) != 0 {
+		t.Errorf("Expected the slow spectator to be dropped, got %d watchers", len(l.watchers))
+	}
+}
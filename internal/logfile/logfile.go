@@ -0,0 +1,161 @@
+// Package logfile writes a log of JSON lines, as slog's JSON handler makes
+// them, that rotates itself: once it grows too big or too old it's renamed
+// aside and a new one started, and only a few old ones are kept.
+package logfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File is a log file open for appending. It's safe to write from several
+// goroutines.
+type File struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+	keep    int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	// started is when the first line of the file was written.
+	started time.Time
+	now     func() time.Time
+}
+
+// Open opens the log at path, making its directory if need be. It's
+// rotated before a write that would take it past maxSize bytes, or once
+// its first line is older than maxAge, and keep rotated files are kept
+// beside it as path.1, path.2 and so on, the newest first.
+func Open(path string, maxSize int64, maxAge time.Duration, keep int) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	l := &File{path: path, maxSize: maxSize, maxAge: maxAge, keep: keep, now: time.Now}
+	return l, l.open()
+}
+
+// open opens the current file, finding out how big and old it is.
+func (l *File) open() error {
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.size, l.started = f, info.Size(), l.now()
+	if t, ok := firstTime(l.path); ok {
+		l.started = t
+	}
+	return nil
+}
+
+// firstTime is when the first line of the log at path was written, from
+// its time field.
+func firstTime(path string) (time.Time, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+	line, _ := bufio.NewReader(f).ReadBytes('\n')
+	var entry struct {
+		Time time.Time `json:"time"`
+	}
+	if json.Unmarshal(line, &entry) != nil || entry.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return entry.Time, true
+}
+
+func (l *File) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return 0, errors.New("log is closed")
+	}
+	if l.size > 0 && (l.size+int64(len(p)) > l.maxSize || l.now().Sub(l.started) > l.maxAge) {
+		if err := l.rotate(); err != nil {
+			return 0, fmt.Errorf("rotating the log: %w", err)
+		}
+	}
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// rotate moves each file kept one along, dropping the oldest, and starts a
+// new one.
+func (l *File) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	l.f = nil
+	os.Remove(fmt.Sprintf("%s.%d", l.path, l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	var err error
+	if l.keep > 0 {
+		err = os.Rename(l.path, l.path+".1")
+	} else {
+		err = os.Remove(l.path)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return l.open()
+}
+
+func (l *File) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// Lines returns the lines of the log at path, oldest first: those of the
+// last file rotated out and then the current one's.
+func Lines(path string) ([]string, error) {
+	var lines []string
+	for _, name := range []string{path + ".1", path} {
+		more, err := readLines(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		lines = append(lines, more...)
+	}
+	return lines, nil
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,58 @@
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "game.log")
+	l, err := Open(path, 100, time.Hour, 2)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer l.Close()
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	for i := range 10 {
+		fmt.Fprintf(l, "{\"time\":%q,\"msg\":\"line %d\"}\n", now.Format(time.RFC3339), i)
+	}
+
+	// Each line is 47 bytes, so each file holds two.
+	lines, err := Lines(path)
+	if err != nil {
+		t.Fatalf("Lines returned error: %v", err)
+	}
+	if len(lines) != 4 || lines[3] != `{"time":"2024-03-01T09:30:00Z","msg":"line 9"}` {
+		t.Errorf("Expected the last four lines, got %q", lines)
+	}
+	if _, err := os.Stat(path + ".2"); err != nil {
+		t.Errorf("Expected two rotated files to be kept, got %v", err)
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Errorf("Expected only two rotated files to be kept")
+	}
+}
+
+func TestRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.log")
+	os.WriteFile(path, []byte(`{"time":"2024-03-01T09:30:00Z","msg":"old"}`+"\n"), 0o644)
+	l, err := Open(path, 1<<20, 24*time.Hour, 1)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer l.Close()
+	l.now = func() time.Time { return time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC) }
+	fmt.Fprintln(l, `{"msg":"same day"}`)
+	if _, err := os.Stat(path + ".1"); err == nil {
+		t.Errorf("Expected a log younger than a day not to rotate")
+	}
+	l.now = func() time.Time { return time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC) }
+	fmt.Fprintln(l, `{"msg":"next day"}`)
+	if data, _ := os.ReadFile(path); string(data) != "{\"msg\":\"next day\"}\n" {
+		t.Errorf("Expected a new log the next day, got %q", data)
+	}
+}
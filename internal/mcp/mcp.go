@@ -0,0 +1,143 @@
+// Package mcp serves tools to AI assistants over the Model Context
+// Protocol: JSON-RPC 2.0 messages, one per line, on stdin and stdout.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// protocolVersion is the revision of the protocol spoken.
+const protocolVersion = "2025-06-18"
+
+// JSON-RPC error codes.
+const (
+	parseError     = -32700
+	methodNotFound = -32601
+	invalidParams  = -32602
+)
+
+// Tool is something a client can call, described by a JSON schema of its
+// arguments.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"inputSchema"`
+}
+
+// Schema is the JSON schema of an object.
+type Schema struct {
+	Type       string              `json:"type"`
+	Properties map[string]Property `json:"properties"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+type Property struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+// Result is what calling a tool gave: its text output, and whether the
+// call failed.
+type Result struct {
+	Text    string
+	IsError bool
+}
+
+// Server answers requests for its tools, calling them with call. Calls are
+// made one at a time.
+type Server struct {
+	name, version string
+	tools         []Tool
+	call          func(tool string, args map[string]any) (Result, error)
+}
+
+// NewServer serves tools under the name and version given. call returns an
+// error for calls that don't fit the tool's schema.
+func NewServer(name, version string, tools []Tool, call func(tool string, args map[string]any) (Result, error)) *Server {
+	return &Server{name: name, version: version, tools: tools, call: call}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends.
+func (sv *Server) Serve(r io.Reader, w io.Writer) error {
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(w)
+	for lines.Scan() {
+		if len(lines.Bytes()) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(lines.Bytes(), &req); err != nil {
+			if err := enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{parseError, err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		result, rerr := sv.handle(req)
+		// Notifications get no answer.
+		if req.ID == nil {
+			continue
+		}
+		if err := enc.Encode(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}); err != nil {
+			return err
+		}
+	}
+	return lines.Err()
+}
+
+func (sv *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": sv.name, "version": sv.version},
+		}, nil
+	case "ping", "notifications/initialized":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": sv.tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{invalidParams, err.Error()}
+		}
+		res, err := sv.call(params.Name, params.Arguments)
+		if err != nil {
+			return nil, &rpcError{invalidParams, err.Error()}
+		}
+		return map[string]any{"content": []content{{"text", res.Text}}, "isError": res.IsError}, nil
+	}
+	return nil, &rpcError{methodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	tools := []Tool{{Name: "inspect", InputSchema: Schema{Type: "object", Properties: map[string]Property{"pokemon": {Type: "string"}}, Required: []string{"pokemon"}}}}
+	sv := NewServer("pokedexcli", "1.0", tools, func(tool string, args map[string]any) (Result, error) {
+		if args["pokemon"] == nil {
+			return Result{}, errors.New("pokemon is required")
+		}
+		return Result{Text: "Details of " + args["pokemon"].(string)}, nil
+	})
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"inspect","arguments":{"pokemon":"pikachu"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"inspect","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+		`not json`,
+	}, "\n")
+	out := &bytes.Buffer{}
+	if err := sv.Serve(strings.NewReader(in), out); err != nil {
+		t.Fatalf("Serve() returned error: %v", err)
+	}
+
+	var responses []map[string]any
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Expected JSON responses, got error %v", err)
+		}
+		responses = append(responses, r)
+	}
+	if len(responses) != 6 {
+		t.Fatalf("Expected 6 responses, leaving out the notification, got %d", len(responses))
+	}
+	if info := responses[0]["result"].(map[string]any)["serverInfo"].(map[string]any); info["name"] != "pokedexcli" {
+		t.Errorf("Expected the server's name in initialize, got %v", info)
+	}
+	if listed := responses[1]["result"].(map[string]any)["tools"].([]any); len(listed) != 1 {
+		t.Errorf("Expected the one tool to be listed, got %v", listed)
+	}
+	text := responses[2]["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"]
+	if text != "Details of pikachu" {
+		t.Errorf("Expected the tool's output, got %v", text)
+	}
+	for i, code := range map[int]float64{3: invalidParams, 4: methodNotFound, 5: parseError} {
+		if err, _ := responses[i]["error"].(map[string]any); err == nil || err["code"] != code {
+			t.Errorf("Expected error %v in response %d, got %v", code, i, responses[i])
+		}
+	}
+}
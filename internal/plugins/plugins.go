@@ -0,0 +1,131 @@
+// Package plugins discovers and runs external command plugins.
+//
+// A plugin is any executable in the plugins directory. Asked with a single
+// --describe argument, it prints a JSON Manifest and exits. To run the
+// command, it is executed with the user's arguments; it gets a JSON Request
+// on stdin, and whatever it writes to stdout is shown to the user. A non-zero
+// exit is reported as an error, using stderr as the message.
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	describeTimeout = 2 * time.Second
+	runTimeout      = 30 * time.Second
+)
+
+// Manifest is what a plugin prints for --describe. A missing max_args, or
+// one below min_args, leaves the number of arguments unlimited (-1).
+type Manifest struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+	MinArgs     int    `json:"min_args"`
+	MaxArgs     int    `json:"max_args"`
+}
+
+// Request is sent to the plugin on stdin when it runs.
+type Request struct {
+	Args    []string `json:"args"`
+	Pokedex []string `json:"pokedex"`
+}
+
+type Plugin struct {
+	Manifest
+	path string
+}
+
+// Discover describes every executable in dir. Plugins that fail to describe
+// themselves are skipped and reported in the returned error.
+func Discover(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Plugin
+	var errs []error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || !executable(entry.Name(), info.Mode()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		p, err := describe(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		found = append(found, p)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, errors.Join(errs...)
+}
+
+func executable(name string, mode os.FileMode) bool {
+	if strings.HasSuffix(strings.ToLower(name), ".exe") {
+		return true
+	}
+	return mode&0o111 != 0
+}
+
+func describe(path string) (Plugin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--describe").Output()
+	if err != nil {
+		return Plugin{}, err
+	}
+	p := Plugin{Manifest: Manifest{MaxArgs: -1}, path: path}
+	if err := json.Unmarshal(out, &p.Manifest); err != nil {
+		return Plugin{}, fmt.Errorf("bad manifest: %w", err)
+	}
+	if p.MaxArgs < p.MinArgs {
+		p.MaxArgs = -1
+	}
+	if p.Name == "" || strings.ContainsAny(p.Name, " \t") {
+		return Plugin{}, fmt.Errorf("bad command name %q", p.Name)
+	}
+	return p, nil
+}
+
+// Run executes the plugin with req, copying its output to out.
+func (p Plugin) Run(out io.Writer, req Request) error {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, p.path, req.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = out
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", p.Name, msg)
+		}
+		return fmt.Errorf("%s: %w", p.Name, err)
+	}
+	return nil
+}
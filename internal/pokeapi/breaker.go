@@ -0,0 +1,66 @@
+package pokeapi
+
+import (
+	"sync"
+	"time"
+)
+
+// breakAfter is how many requests in a row must fail for the breaker to
+// open, and breakFor how long it stays open before a request is let through
+// to see whether the API is back.
+const (
+	breakAfter = 5
+	breakFor   = 30 * time.Second
+)
+
+// breaker stops requests to an API that keeps failing, so commands fail
+// fast through an outage instead of each waiting to time out. Cached
+// responses are still answered, as they never reach it. The zero value is
+// closed.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	// openUntil is when the next request may probe the API; probing is set
+	// while that request runs, and fails the others fast.
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// allow returns ErrUnavailable while the breaker is open. Otherwise the
+// caller makes its request, and reports how it went with done.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < breakAfter {
+		return nil
+	}
+	if b.probing || b.clock().Before(b.openUntil) {
+		return ErrUnavailable
+	}
+	b.probing = true
+	return nil
+}
+
+// done records whether a request allowed through failed: couldn't reach the
+// API or got a server error, not a resource that doesn't exist.
+func (b *breaker) done(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= breakAfter {
+		b.openUntil = b.clock().Add(breakFor)
+	}
+}
+
+func (b *breaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,70 @@
+package pokeapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := &breaker{now: func() time.Time { return now }}
+	for range breakAfter {
+		if err := b.allow(); err != nil {
+			t.Fatalf("Expected requests allowed while closed, got %v", err)
+		}
+		b.done(true)
+	}
+	if err := b.allow(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable once open, got %v", err)
+	}
+
+	now = now.Add(breakFor)
+	if err := b.allow(); err != nil {
+		t.Errorf("Expected a probe allowed after %s, got %v", breakFor, err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected one probe at a time, got %v", err)
+	}
+	b.done(false)
+	if err := b.allow(); err != nil {
+		t.Errorf("Expected a successful probe to close the breaker, got %v", err)
+	}
+}
+
+func TestClientBreaksOnOutage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/pokemon/missingno" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	c := NewClient(server.URL, cache)
+	for range breakAfter {
+		c.Pokemon("missingno")
+	}
+	if _, err := c.Pokemon("missingno"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected missing pokemon not to open the breaker, got %v", err)
+	}
+	for range breakAfter {
+		c.Pokemon("pikachu")
+	}
+	requests = 0
+	if _, err := c.Pokemon("pikachu"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request while open, got %d", requests)
+	}
+}
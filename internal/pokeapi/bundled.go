@@ -0,0 +1,25 @@
+package pokeapi
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+)
+
+//go:generate go run genbundle.go
+
+// kanto is a snapshot of Kanto as Red and Blue have it, enough to play
+// with no network at all: the first 151 pokemon and their species, without
+// learnsets, and the wild pokemon of its locations. genbundle.go writes it.
+//
+//go:embed kanto.zip
+var kanto []byte
+
+// Bundled serves the snapshot built into the game.
+func Bundled() *Offline {
+	r, err := zip.NewReader(bytes.NewReader(kanto), int64(len(kanto)))
+	if err != nil {
+		panic("pokeapi: bundled data: " + err.Error())
+	}
+	return &Offline{fsys: r}
+}
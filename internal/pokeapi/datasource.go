@@ -0,0 +1,52 @@
+package pokeapi
+
+import "errors"
+
+const pageSize = 20
+
+var ErrNotFound = errors.New("resource not found")
+
+// ErrUnavailable is returned without asking while the API has failed too
+// many times in a row; see breaker.
+var ErrUnavailable = errors.New("PokeAPI appears down — using cache/offline data")
+
+// DataSource is everything the commands need from PokeAPI. Page tokens are
+// opaque: each implementation hands out its own Next/Previous values and is
+// the only one expected to understand them. An empty page means the first one.
+type DataSource interface {
+	LocationAreas(page string) (LocationResponse, error)
+	LocationArea(name string) (LocationDetailsResponse, error)
+	Location(name string) (LocationDetail, error)
+	Region(name string) (RegionDetail, error)
+	Pokemon(name string) (PokemonType, error)
+	Species(name string) (PokemonSpecies, error)
+	// PokemonMoves is the pokemon's learnset. It's kept apart from Pokemon
+	// because it's far bigger than everything else about a pokemon.
+	PokemonMoves(name string) ([]PokemonMove, error)
+	Move(name string) (MoveDetail, error)
+	Item(name string) (ItemDetail, error)
+	Machine(id int) (MachineDetail, error)
+	EggGroup(name string) (EggGroupDetail, error)
+	Habitat(name string) (HabitatDetail, error)
+	Shape(name string) (ShapeDetail, error)
+	Nature(name string) (NatureDetail, error)
+}
+
+// Forgetter is implemented by data sources that cache what they fetch, so
+// the resource named can be fetched again as if for the first time.
+// resource is its kind as PokeAPI's REST paths have it, e.g. "pokemon".
+type Forgetter interface {
+	Forget(resource, name string)
+}
+
+// Tracer is implemented by data sources that can follow the fetches made
+// for a trace. WithTrace returns a source sharing the receiver's caches.
+type Tracer interface {
+	WithTrace(t *Trace) DataSource
+}
+
+// Lister is implemented by data sources that can name their resources
+// without a network round trip, e.g. for shell completion.
+type Lister interface {
+	Names(resource string) ([]string, error)
+}
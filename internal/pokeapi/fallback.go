@@ -0,0 +1,136 @@
+package pokeapi
+
+import (
+	"errors"
+	"strings"
+)
+
+// bundledPage marks the page tokens Fallback hands out for its first
+// source's pages, telling them apart from then's.
+const bundledPage = "bundled:"
+
+// Fallback answers from first, such as the bundled snapshot, and asks then,
+// such as the API, for whatever first doesn't have. Location areas are
+// listed by then, being all of them, and by first only when then can't be
+// reached.
+type Fallback struct {
+	first *Offline
+	then  DataSource
+}
+
+func NewFallback(first *Offline, then DataSource) *Fallback {
+	return &Fallback{first: first, then: then}
+}
+
+func (f *Fallback) LocationAreas(page string) (LocationResponse, error) {
+	if offset, ok := strings.CutPrefix(page, bundledPage); ok {
+		return f.bundledAreas(offset)
+	}
+	response, err := f.then.LocationAreas(page)
+	if err != nil && page == "" {
+		return f.bundledAreas("")
+	}
+	return response, err
+}
+
+func (f *Fallback) bundledAreas(page string) (LocationResponse, error) {
+	response, err := f.first.LocationAreas(page)
+	if response.Next != "" {
+		response.Next = bundledPage + response.Next
+	}
+	if response.Previous != "" {
+		response.Previous = bundledPage + response.Previous
+	}
+	return response, err
+}
+
+func (f *Fallback) LocationArea(name string) (LocationDetailsResponse, error) {
+	return either(f.first.LocationArea, f.then.LocationArea, name)
+}
+
+func (f *Fallback) Location(name string) (LocationDetail, error) {
+	return either(f.first.Location, f.then.Location, name)
+}
+
+func (f *Fallback) Region(name string) (RegionDetail, error) {
+	return either(f.first.Region, f.then.Region, name)
+}
+
+func (f *Fallback) Pokemon(name string) (PokemonType, error) {
+	return either(f.first.Pokemon, f.then.Pokemon, name)
+}
+
+func (f *Fallback) Species(name string) (PokemonSpecies, error) {
+	return either(f.first.Species, f.then.Species, name)
+}
+
+// PokemonMoves asks then for learnsets first doesn't have, which, for the
+// bundled snapshot, is all of them.
+func (f *Fallback) PokemonMoves(name string) ([]PokemonMove, error) {
+	moves, err := f.first.PokemonMoves(name)
+	if err == nil && len(moves) > 0 {
+		return moves, nil
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return f.then.PokemonMoves(name)
+}
+
+func (f *Fallback) Move(name string) (MoveDetail, error) {
+	return either(f.first.Move, f.then.Move, name)
+}
+
+func (f *Fallback) Item(name string) (ItemDetail, error) {
+	return either(f.first.Item, f.then.Item, name)
+}
+
+func (f *Fallback) Machine(id int) (MachineDetail, error) {
+	return either(f.first.Machine, f.then.Machine, id)
+}
+
+func (f *Fallback) EggGroup(name string) (EggGroupDetail, error) {
+	return either(f.first.EggGroup, f.then.EggGroup, name)
+}
+
+func (f *Fallback) Habitat(name string) (HabitatDetail, error) {
+	return either(f.first.Habitat, f.then.Habitat, name)
+}
+
+func (f *Fallback) Shape(name string) (ShapeDetail, error) {
+	return either(f.first.Shape, f.then.Shape, name)
+}
+
+func (f *Fallback) Nature(name string) (NatureDetail, error) {
+	return either(f.first.Nature, f.then.Nature, name)
+}
+
+// Names lists first's names, as then may need the network to.
+func (f *Fallback) Names(resource string) ([]string, error) {
+	return f.first.Names(resource)
+}
+
+// WithTrace follows then's fetches; first's are never fetched.
+func (f *Fallback) WithTrace(t *Trace) DataSource {
+	tracer, ok := f.then.(Tracer)
+	if !ok {
+		return f
+	}
+	return &Fallback{first: f.first, then: tracer.WithTrace(t)}
+}
+
+// Forget has then forget the resource; first keeps its copy.
+func (f *Fallback) Forget(resource, name string) {
+	if forgetter, ok := f.then.(Forgetter); ok {
+		forgetter.Forget(resource, name)
+	}
+}
+
+// either is first's answer for key, or then's if first hasn't one.
+func either[K, T any](first, then func(K) (T, error), key K) (T, error) {
+	v, err := first(key)
+	if errors.Is(err, ErrNotFound) {
+		return then(key)
+	}
+	return v, err
+}
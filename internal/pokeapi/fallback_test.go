@@ -0,0 +1,59 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+func TestFallbackServesBundledFirst(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if strings.HasPrefix(r.URL.Path, "/location-area") {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"name": "chikorita", "moves": [{"move": {"name": "tackle"}}]}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	f := NewFallback(Bundled(), NewClient(server.URL, cache))
+
+	pikachu, err := f.Pokemon("pikachu")
+	if err != nil || len(pikachu.Types) != 1 || pikachu.Types[0].Type.Name != "electric" {
+		t.Errorf("Expected the bundled pikachu, got %+v, %v", pikachu, err)
+	}
+	if cave, err := f.Habitat("cave"); err != nil || len(cave.PokemonSpecies) == 0 || cave.PokemonSpecies[0].Name != "zubat" {
+		t.Errorf("Expected the bundled cave habitat, got %+v, %v", cave, err)
+	}
+	area, err := f.LocationArea("viridian-forest-area")
+	if err != nil || len(area.PokemonEncounters) == 0 {
+		t.Errorf("Expected the bundled viridian forest, got %+v, %v", area, err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no requests for bundled data, got %v", requests)
+	}
+
+	if chikorita, err := f.Pokemon("chikorita"); err != nil || chikorita.Name != "chikorita" {
+		t.Errorf("Expected chikorita from the API, got %+v, %v", chikorita, err)
+	}
+	if moves, err := f.PokemonMoves("pikachu"); err != nil || len(moves) != 1 {
+		t.Errorf("Expected pikachu's moves from the API, got %v, %v", moves, err)
+	}
+
+	page, err := f.LocationAreas("")
+	if err != nil || page.Count == 0 || !strings.HasPrefix(page.Next, bundledPage) {
+		t.Fatalf("Expected the bundled areas while the API is down, got %+v, %v", page, err)
+	}
+	next, err := f.LocationAreas(page.Next)
+	if err != nil || next.Previous != bundledPage+"0" {
+		t.Errorf("Expected the bundled areas' second page, got %+v, %v", next, err)
+	}
+}
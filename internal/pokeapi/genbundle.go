@@ -0,0 +1,444 @@
+//go:build ignore
+
+// genbundle writes kanto.zip, the dataset Bundled serves: the first 151
+// pokemon, their species and habitats, and the wild pokemon of Kanto's routes, caves
+// and waters as in Red and Blue. Run it with go generate after changing the
+// tables below.
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pokemonTable has a line per pokemon: national dex number, name, types,
+// base stats (hp/attack/defense/special-attack/special-defense/speed), base
+// experience, height in decimetres, weight in hectograms, ability, capture
+// rate, chance of being female in eighths (-1 for genderless), growth rate,
+// egg groups, effort values given and, for some, legendary or mythical.
+const pokemonTable = `
+1 bulbasaur grass,poison 45/49/49/65/65/45 64 7 69 overgrow 45 1 medium-slow monster,plant sa1
+2 ivysaur grass,poison 60/62/63/80/80/60 142 10 130 overgrow 45 1 medium-slow monster,plant sa1,sd1
+3 venusaur grass,poison 80/82/83/100/100/80 263 20 1000 overgrow 45 1 medium-slow monster,plant sa2,sd1
+4 charmander fire 39/52/43/60/50/65 62 6 85 blaze 45 1 medium-slow monster,dragon s1
+5 charmeleon fire 58/64/58/80/65/80 142 11 190 blaze 45 1 medium-slow monster,dragon sa1,s1
+6 charizard fire,flying 78/84/78/109/85/100 267 17 905 blaze 45 1 medium-slow monster,dragon sa3
+7 squirtle water 44/48/65/50/64/43 63 5 90 torrent 45 1 medium-slow monster,water1 d1
+8 wartortle water 59/63/80/65/80/58 142 10 225 torrent 45 1 medium-slow monster,water1 d1,sd1
+9 blastoise water 79/83/100/85/105/78 265 16 855 torrent 45 1 medium-slow monster,water1 sd3
+10 caterpie bug 45/30/35/20/20/45 39 3 29 shield-dust 255 4 medium-fast bug h1
+11 metapod bug 50/20/55/25/25/30 72 7 99 shed-skin 120 4 medium-fast bug d2
+12 butterfree bug,flying 60/45/50/90/80/70 198 11 320 compound-eyes 45 4 medium-fast bug sa2,sd1
+13 weedle bug,poison 40/35/30/20/20/50 39 3 32 shield-dust 255 4 medium-fast bug s1
+14 kakuna bug,poison 45/25/50/25/25/35 72 6 100 shed-skin 120 4 medium-fast bug d2
+15 beedrill bug,poison 65/90/40/45/80/75 178 10 295 swarm 45 4 medium-fast bug a2,sd1
+16 pidgey normal,flying 40/45/40/35/35/56 50 3 18 keen-eye 255 4 medium-slow flying s1
+17 pidgeotto normal,flying 63/60/55/50/50/71 122 11 300 keen-eye 120 4 medium-slow flying s2
+18 pidgeot normal,flying 83/80/75/70/70/101 216 15 395 keen-eye 45 4 medium-slow flying s3
+19 rattata normal 30/56/35/25/35/72 51 3 35 run-away 255 4 medium-fast ground s1
+20 raticate normal 55/81/60/50/70/97 145 7 185 run-away 127 4 medium-fast ground s2
+21 spearow normal,flying 40/60/30/31/31/70 52 3 20 keen-eye 255 4 medium-fast flying s1
+22 fearow normal,flying 65/90/65/61/61/100 155 12 380 keen-eye 90 4 medium-fast flying s2
+23 ekans poison 35/60/44/40/54/55 58 20 69 intimidate 255 4 medium-fast ground,dragon a1
+24 arbok poison 60/95/69/65/79/80 157 35 650 intimidate 90 4 medium-fast ground,dragon a2
+25 pikachu electric 35/55/40/50/50/90 112 4 60 static 190 4 medium-fast ground,fairy s2
+26 raichu electric 60/90/55/90/80/110 243 8 300 static 75 4 medium-fast ground,fairy s3
+27 sandshrew ground 50/75/85/20/30/40 60 6 120 sand-veil 255 4 medium-fast ground d1
+28 sandslash ground 75/100/110/45/55/65 158 10 295 sand-veil 90 4 medium-fast ground d2
+29 nidoran-f poison 55/47/52/40/40/41 55 4 70 poison-point 235 8 medium-slow monster,ground h1
+30 nidorina poison 70/62/67/55/55/56 128 8 200 poison-point 120 8 medium-slow no-eggs h2
+31 nidoqueen poison,ground 90/92/87/75/85/76 253 13 600 poison-point 45 8 medium-slow no-eggs h3
+32 nidoran-m poison 46/57/40/40/40/50 55 5 90 poison-point 235 0 medium-slow monster,ground a1
+33 nidorino poison 61/72/57/55/55/65 128 9 195 poison-point 120 0 medium-slow monster,ground a2
+34 nidoking poison,ground 81/102/77/85/75/85 253 14 620 poison-point 45 0 medium-slow monster,ground a3
+35 clefairy fairy 70/45/48/60/65/35 113 6 75 cute-charm 150 6 fast fairy h2
+36 clefable fairy 95/70/73/95/90/60 242 13 400 cute-charm 25 6 fast fairy h3
+37 vulpix fire 38/41/40/50/65/65 60 6 99 flash-fire 190 6 medium-fast ground s1
+38 ninetales fire 73/76/75/81/100/100 177 11 199 flash-fire 75 6 medium-fast ground sd1,s1
+39 jigglypuff normal,fairy 115/45/20/45/25/20 95 5 55 cute-charm 170 6 fast fairy h2
+40 wigglytuff normal,fairy 140/70/45/85/50/45 196 10 120 cute-charm 50 6 fast fairy h3
+41 zubat poison,flying 40/45/35/30/40/55 49 8 75 inner-focus 255 4 medium-fast flying s1
+42 golbat poison,flying 75/80/70/65/75/90 159 16 550 inner-focus 90 4 medium-fast flying s2
+43 oddish grass,poison 45/50/55/75/65/30 64 5 54 chlorophyll 255 4 medium-slow plant sa1
+44 gloom grass,poison 60/65/70/85/75/40 138 8 86 chlorophyll 120 4 medium-slow plant sa2
+45 vileplume grass,poison 75/80/85/110/90/50 245 12 186 chlorophyll 45 4 medium-slow plant sa3
+46 paras bug,grass 35/70/55/45/55/25 57 3 54 effect-spore 190 4 medium-fast bug,plant a1
+47 parasect bug,grass 60/95/80/60/80/30 142 10 295 effect-spore 75 4 medium-fast bug,plant a2,d1
+48 venonat bug,poison 60/55/50/40/55/45 61 10 300 compound-eyes 190 4 medium-fast bug sd1
+49 venomoth bug,poison 70/65/60/90/75/90 158 15 125 shield-dust 75 4 medium-fast bug sa1,s1
+50 diglett ground 10/55/25/35/45/95 53 2 8 sand-veil 255 4 medium-fast ground s1
+51 dugtrio ground 35/100/50/50/70/120 149 7 333 sand-veil 50 4 medium-fast ground s2
+52 meowth normal 40/45/35/40/40/90 58 4 42 pickup 255 4 medium-fast ground s1
+53 persian normal 65/70/60/65/65/115 154 10 320 limber 90 4 medium-fast ground s2
+54 psyduck water 50/52/48/65/50/55 64 8 196 damp 190 4 medium-fast water1,ground sa1
+55 golduck water 80/82/78/95/80/85 175 17 766 damp 75 4 medium-fast water1,ground sa2
+56 mankey fighting 40/80/35/35/45/70 61 5 280 vital-spirit 190 4 medium-fast ground a1
+57 primeape fighting 65/105/60/60/70/95 159 10 320 vital-spirit 75 4 medium-fast ground a2
+58 growlithe fire 55/70/45/70/50/60 70 7 190 intimidate 190 2 slow ground a1
+59 arcanine fire 90/110/80/100/80/95 194 19 1550 intimidate 75 2 slow ground a2
+60 poliwag water 40/50/40/40/40/90 60 6 124 water-absorb 255 4 medium-slow water1 s1
+61 poliwhirl water 65/65/65/50/50/90 135 10 200 water-absorb 120 4 medium-slow water1 s2
+62 poliwrath water,fighting 90/95/95/70/90/70 255 13 540 water-absorb 45 4 medium-slow water1 d3
+63 abra psychic 25/20/15/105/55/90 62 9 195 synchronize 200 2 medium-slow humanshape sa1
+64 kadabra psychic 40/35/30/120/70/105 140 13 565 synchronize 100 2 medium-slow humanshape sa2
+65 alakazam psychic 55/50/45/135/95/120 250 15 480 synchronize 50 2 medium-slow humanshape sa3
+66 machop fighting 70/80/50/35/35/35 61 8 195 guts 180 2 medium-slow humanshape a1
+67 machoke fighting 80/100/70/50/60/45 142 15 705 guts 90 2 medium-slow humanshape a2
+68 machamp fighting 90/130/80/65/85/55 253 16 1300 guts 45 2 medium-slow humanshape a3
+69 bellsprout grass,poison 50/75/35/70/30/40 60 7 40 chlorophyll 255 4 medium-slow plant a1
+70 weepinbell grass,poison 65/90/50/85/45/55 137 10 64 chlorophyll 120 4 medium-slow plant a2
+71 victreebel grass,poison 80/105/65/100/70/70 221 17 155 chlorophyll 45 4 medium-slow plant a3
+72 tentacool water,poison 40/40/35/50/100/70 67 9 455 clear-body 190 4 slow water3 sd1
+73 tentacruel water,poison 80/70/65/80/120/100 180 16 550 clear-body 60 4 slow water3 sd2
+74 geodude rock,ground 40/80/100/30/30/20 60 4 200 rock-head 255 4 medium-slow mineral d1
+75 graveler rock,ground 55/95/115/45/45/35 137 10 1050 rock-head 120 4 medium-slow mineral d2
+76 golem rock,ground 80/120/130/55/65/45 248 14 3000 rock-head 45 4 medium-slow mineral d3
+77 ponyta fire 50/85/55/65/65/90 82 10 300 run-away 190 4 medium-fast ground s1
+78 rapidash fire 65/100/70/80/80/105 175 17 950 run-away 60 4 medium-fast ground s2
+79 slowpoke water,psychic 90/65/65/40/40/15 63 12 360 oblivious 190 4 medium-fast monster,water1 h1
+80 slowbro water,psychic 95/75/110/100/80/30 172 16 785 oblivious 75 4 medium-fast monster,water1 d2
+81 magnemite electric,steel 25/35/70/95/55/45 65 3 60 magnet-pull 190 -1 medium-fast mineral sa1
+82 magneton electric,steel 50/60/95/120/70/70 163 10 600 magnet-pull 60 -1 medium-fast mineral sa2
+83 farfetchd normal,flying 52/90/55/58/62/60 132 8 150 keen-eye 45 4 medium-fast flying,ground a1
+84 doduo normal,flying 35/85/45/35/35/75 62 14 392 run-away 190 4 medium-fast flying a1
+85 dodrio normal,flying 60/110/70/60/60/110 165 18 852 run-away 45 4 medium-fast flying a2
+86 seel water 65/45/55/45/70/45 65 11 900 thick-fat 190 4 medium-fast water1,ground sd1
+87 dewgong water,ice 90/70/80/70/95/70 166 17 1200 thick-fat 75 4 medium-fast water1,ground sd2
+88 grimer poison 80/80/50/40/50/25 65 9 300 stench 190 4 medium-fast indeterminate h1
+89 muk poison 105/105/75/65/100/50 175 12 300 stench 75 4 medium-fast indeterminate h1,a1
+90 shellder water 30/65/100/45/25/40 61 3 40 shell-armor 190 4 slow water3 d1
+91 cloyster water,ice 50/95/180/85/45/70 184 15 1325 shell-armor 60 4 slow water3 d2
+92 gastly ghost,poison 30/35/30/100/35/80 62 13 1 levitate 190 4 medium-slow indeterminate sa1
+93 haunter ghost,poison 45/50/45/115/55/95 142 16 1 levitate 90 4 medium-slow indeterminate sa2
+94 gengar ghost,poison 60/65/60/130/75/110 250 15 405 cursed-body 45 4 medium-slow indeterminate sa3
+95 onix rock,ground 35/45/160/30/45/70 77 88 2100 rock-head 45 4 medium-fast mineral d1
+96 drowzee psychic 60/48/45/43/90/42 66 10 324 insomnia 190 4 medium-fast humanshape sd1
+97 hypno psychic 85/73/70/73/115/67 169 16 756 insomnia 75 4 medium-fast humanshape sd2
+98 krabby water 30/105/90/25/25/50 65 4 65 hyper-cutter 225 4 medium-fast water3 a1
+99 kingler water 55/130/115/50/50/75 166 13 600 hyper-cutter 60 4 medium-fast water3 a2
+100 voltorb electric 40/30/50/55/55/100 66 5 104 soundproof 190 -1 medium-fast mineral s1
+101 electrode electric 60/50/70/80/80/150 172 12 666 soundproof 60 -1 medium-fast mineral s2
+102 exeggcute grass,psychic 60/40/80/60/45/40 65 4 25 chlorophyll 90 4 slow plant d1
+103 exeggutor grass,psychic 95/95/85/125/75/55 186 20 1200 chlorophyll 45 4 slow plant sa2
+104 cubone ground 50/50/95/40/50/35 64 4 65 rock-head 190 4 medium-fast monster d1
+105 marowak ground 60/80/110/50/80/45 149 10 450 rock-head 75 4 medium-fast monster d2
+106 hitmonlee fighting 50/120/53/35/110/87 159 15 498 limber 45 0 medium-fast humanshape a2
+107 hitmonchan fighting 50/105/79/35/110/76 159 14 502 keen-eye 45 0 medium-fast humanshape sd2
+108 lickitung normal 90/55/75/60/75/30 77 12 655 own-tempo 45 4 medium-fast monster h2
+109 koffing poison 40/65/95/60/45/35 68 6 10 levitate 190 4 medium-fast indeterminate d1
+110 weezing poison 65/90/120/85/70/60 172 12 95 levitate 60 4 medium-fast indeterminate d2
+111 rhyhorn ground,rock 80/85/95/30/30/25 69 10 1150 lightning-rod 120 4 slow monster,ground d1
+112 rhydon ground,rock 105/130/120/45/45/40 170 19 1200 lightning-rod 60 4 slow monster,ground a2
+113 chansey normal 250/5/5/35/105/50 395 11 346 natural-cure 30 8 fast fairy h2
+114 tangela grass 65/55/115/100/40/60 87 10 350 chlorophyll 45 4 medium-fast plant d1
+115 kangaskhan normal 105/95/80/40/80/90 172 22 800 early-bird 45 8 medium-fast monster h2
+116 horsea water 30/40/70/70/25/60 59 4 80 swift-swim 225 4 medium-fast water1,dragon sa1
+117 seadra water 55/65/95/95/45/85 154 12 250 poison-point 75 4 medium-fast water1,dragon d1,sa1
+118 goldeen water 45/67/60/35/50/63 64 6 150 swift-swim 225 4 medium-fast water2 a1
+119 seaking water 80/92/65/65/80/68 158 13 390 swift-swim 60 4 medium-fast water2 a2
+120 staryu water 30/45/55/70/55/85 68 8 345 illuminate 225 -1 slow water3 s1
+121 starmie water,psychic 60/75/85/100/85/115 182 11 800 illuminate 60 -1 slow water3 s2
+122 mr-mime psychic,fairy 40/45/65/100/120/90 161 13 545 soundproof 45 4 medium-fast humanshape sd2
+123 scyther bug,flying 70/110/80/55/80/105 100 15 560 swarm 45 4 medium-fast bug a1
+124 jynx ice,psychic 65/50/35/115/95/95 159 14 406 oblivious 45 8 medium-fast humanshape sa2
+125 electabuzz electric 65/83/57/95/85/105 172 11 300 static 45 2 medium-fast humanshape s2
+126 magmar fire 65/95/57/100/85/93 173 13 445 flame-body 45 2 medium-fast humanshape sa2
+127 pinsir bug 65/125/100/55/70/85 175 15 550 hyper-cutter 45 4 slow bug a2
+128 tauros normal 75/100/95/40/70/110 172 14 884 intimidate 45 0 slow ground a1,s1
+129 magikarp water 20/10/55/15/20/80 40 9 100 swift-swim 255 4 slow water2,dragon s1
+130 gyarados water,flying 95/125/79/60/100/81 189 65 2350 intimidate 45 4 slow water2,dragon a2
+131 lapras water,ice 130/85/80/85/95/60 187 25 2200 water-absorb 45 4 slow monster,water1 h2
+132 ditto normal 48/48/48/48/48/48 101 3 40 limber 35 -1 medium-fast ditto h1
+133 eevee normal 55/55/50/45/65/55 65 3 65 run-away 45 1 medium-fast ground sd1
+134 vaporeon water 130/65/60/110/95/65 184 10 290 water-absorb 45 1 medium-fast ground h2
+135 jolteon electric 65/65/60/110/95/130 184 8 245 volt-absorb 45 1 medium-fast ground s2
+136 flareon fire 65/130/60/95/110/65 184 9 250 flash-fire 45 1 medium-fast ground a2
+137 porygon normal 65/60/70/85/75/40 79 8 365 trace 45 -1 medium-fast mineral sa1
+138 omanyte rock,water 35/40/100/90/55/35 71 4 75 swift-swim 45 1 medium-fast water1,water3 d1
+139 omastar rock,water 70/60/125/115/70/55 173 10 350 swift-swim 45 1 medium-fast water1,water3 d2
+140 kabuto rock,water 30/80/90/55/45/55 71 5 115 swift-swim 45 1 medium-fast water1,water3 d1
+141 kabutops rock,water 60/115/105/65/70/80 173 13 405 swift-swim 45 1 medium-fast water1,water3 a2
+142 aerodactyl rock,flying 80/105/65/60/75/130 180 18 590 rock-head 45 1 slow flying s2
+143 snorlax normal 160/110/65/65/110/30 189 21 4600 immunity 25 1 slow monster h2
+144 articuno ice,flying 90/85/100/95/125/85 290 17 554 pressure 3 -1 slow no-eggs sd3 legendary
+145 zapdos electric,flying 90/90/85/125/90/100 290 16 526 pressure 3 -1 slow no-eggs sa3 legendary
+146 moltres fire,flying 90/100/90/125/85/90 290 20 600 pressure 3 -1 slow no-eggs sa3 legendary
+147 dratini dragon 41/64/45/50/50/50 60 18 33 shed-skin 45 4 slow water1,dragon a1
+148 dragonair dragon 61/84/65/70/70/70 147 40 165 shed-skin 45 4 slow water1,dragon a2
+149 dragonite dragon,flying 91/134/95/100/100/80 300 22 2100 inner-focus 45 4 slow water1,dragon a3
+150 mewtwo psychic 106/110/90/154/90/130 340 20 1220 pressure 3 -1 slow no-eggs sa3 legendary
+151 mew psychic 100/100/100/100/100/100 300 4 40 synchronize 45 -1 medium-slow no-eggs h3 mythical
+`
+
+// habitatTable has a line per habitat, as the FireRed and LeafGreen
+// pokedex has them: its name, then the pokemon that live there.
+const habitatTable = `
+cave zubat golbat diglett dugtrio gastly haunter gengar onix
+forest caterpie metapod butterfree weedle kakuna beedrill pidgey pidgeotto pidgeot pikachu raichu paras parasect venonat venomoth bellsprout weepinbell victreebel exeggcute exeggutor pinsir
+grassland bulbasaur ivysaur venusaur rattata raticate ekans arbok nidoran-f nidorina nidoqueen nidoran-m nidorino nidoking vulpix ninetales jigglypuff wigglytuff oddish gloom vileplume growlithe arcanine ponyta rapidash farfetchd doduo dodrio drowzee hypno lickitung tangela kangaskhan scyther tauros
+mountain charmander charmeleon charizard clefairy clefable mankey primeape machop machoke machamp geodude graveler golem cubone marowak magmar aerodactyl snorlax
+rare articuno zapdos moltres mewtwo mew
+rough-terrain spearow fearow sandshrew sandslash magnemite magneton rhyhorn rhydon
+sea tentacool tentacruel seel dewgong shellder cloyster horsea seadra staryu starmie lapras omanyte omastar kabuto kabutops
+urban meowth persian abra kadabra alakazam grimer muk voltorb electrode hitmonlee hitmonchan koffing weezing chansey mr-mime jynx electabuzz ditto eevee vaporeon jolteon flareon porygon
+waters-edge squirtle wartortle blastoise psyduck golduck poliwag poliwhirl poliwrath slowpoke slowbro krabby kingler goldeen seaking magikarp gyarados dratini dragonair dragonite
+`
+
+// placesTable lists Kanto's locations in the order they're met, a line
+// each, with the areas of those that have wild pokemon: an area's name,
+// then its pokemon as name:levels:chance, and :method for any way of
+// meeting them but walking in the grass.
+const placesTable = `
+pallet-town pallet-town-area tentacool:5-40:100:surf magikarp:5:100:old-rod poliwag:10:50:good-rod goldeen:10:50:good-rod
+kanto-route-1 kanto-route-1-area pidgey:2-5:50 rattata:2-4:50
+viridian-city viridian-city-area tentacool:5-40:100:surf magikarp:5:100:old-rod poliwag:10:50:good-rod goldeen:10:50:good-rod
+kanto-route-22 kanto-route-22-area rattata:2-5:45 nidoran-m:2-4:25 nidoran-f:2-4:20 spearow:3-5:10
+kanto-route-2 kanto-route-2-area pidgey:3-5:45 rattata:2-5:45 caterpie:3-5:5 weedle:3-5:5
+viridian-forest viridian-forest-area caterpie:3-5:40 weedle:3-5:40 metapod:4-6:10 kakuna:4-6:5 pikachu:3-5:5
+pewter-city
+kanto-route-3 kanto-route-3-area spearow:5-8:35 pidgey:6-8:30 sandshrew:6-8:25 jigglypuff:3-7:10
+mt-moon mt-moon-1f zubat:7-10:69 geodude:7-9:25 paras:8:5 clefairy:8:1
+mt-moon mt-moon-b1f zubat:8-10:50 geodude:9:30 paras:10:15 clefairy:10:5
+mt-moon mt-moon-b2f zubat:9-12:45 geodude:9-10:35 paras:10-12:10 clefairy:10-12:10
+kanto-route-4 kanto-route-4-area rattata:8-12:35 spearow:8-12:35 ekans:6-12:25 mankey:10-12:5
+cerulean-city cerulean-city-area magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod krabby:15-25:50:super-rod shellder:15-25:50:super-rod
+kanto-route-24 kanto-route-24-area oddish:12-14:25 caterpie:7:20 weedle:7:20 pidgey:11-13:15 abra:8-12:15 metapod:8:5
+kanto-route-25 kanto-route-25-area oddish:12-14:25 pidgey:11-13:25 abra:9-12:20 caterpie:8:15 weedle:8:15
+kanto-route-5 kanto-route-5-area pidgey:13-16:40 meowth:10-16:35 oddish:13-16:25
+kanto-route-6 kanto-route-6-area pidgey:13-16:40 meowth:10-16:35 oddish:13-16:25 psyduck:15-30:100:surf
+vermilion-city vermilion-city-area tentacool:5-40:100:surf magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod krabby:15-25:50:super-rod shellder:15-25:50:super-rod
+kanto-route-11 kanto-route-11-area ekans:12-15:40 spearow:13-17:35 drowzee:9-15:25
+digletts-cave digletts-cave-area diglett:15-22:95 dugtrio:29-31:5
+kanto-route-9 kanto-route-9-area rattata:14-17:40 spearow:13-17:35 ekans:11-17:25
+kanto-route-10 kanto-route-10-area spearow:13-17:40 voltorb:14-17:30 ekans:11-17:30
+rock-tunnel rock-tunnel-1f zubat:15-16:35 geodude:15-17:30 machop:15-17:20 onix:13-17:15
+rock-tunnel rock-tunnel-b1f zubat:15-17:30 geodude:15-17:30 machop:16-18:20 onix:14-18:20
+power-plant power-plant-area voltorb:21-23:30 magnemite:21-23:30 pikachu:20-24:25 magneton:32-35:9 electabuzz:33-36:5 zapdos:50:1
+lavender-town
+pokemon-tower pokemon-tower-3f gastly:13-19:90 cubone:15:10
+pokemon-tower pokemon-tower-7f gastly:15-24:75 haunter:23-25:15 cubone:20-22:10
+kanto-route-8 kanto-route-8-area pidgey:17-20:30 growlithe:15-18:25 meowth:18-20:25 ekans:17-19:20
+kanto-route-7 kanto-route-7-area pidgey:19-22:30 oddish:19-22:30 meowth:17-20:25 growlithe:18-20:15
+celadon-city celadon-city-area magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod
+kanto-route-16 kanto-route-16-area rattata:18-22:35 spearow:20-22:35 doduo:18-22:30
+kanto-route-17 kanto-route-17-area raticate:25-29:25 spearow:20-22:25 doduo:24-28:25 ponyta:28-32:15 fearow:25-27:10
+kanto-route-18 kanto-route-18-area raticate:25-29:30 spearow:20-22:30 doduo:24-28:30 fearow:25-29:10
+kanto-route-12 kanto-route-12-area pidgey:23-27:35 oddish:22-26:35 venonat:24-26:25 gloom:28-30:5 magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod
+kanto-route-13 kanto-route-13-area pidgey:25-27:30 oddish:22-26:30 venonat:24-26:25 ditto:25:10 pidgeotto:29:5
+kanto-route-14 kanto-route-14-area pidgey:26:30 oddish:22-26:30 venonat:24-26:25 ditto:23:10 pidgeotto:28:5
+kanto-route-15 kanto-route-15-area pidgey:23-27:30 oddish:22-26:30 venonat:26-28:25 ditto:26:10 pidgeotto:28:5
+fuchsia-city fuchsia-city-area magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod
+safari-zone safari-zone-center nidoran-f:22:15 nidoran-m:22:15 rhyhorn:25:15 exeggcute:24:15 venonat:22:15 paras:22:10 nidorino:31:5 nidorina:31:4 parasect:30:3 scyther:23:2 chansey:23:1 dratini:15-25:40:super-rod dragonair:30:10:super-rod
+safari-zone safari-zone-east nidoran-m:24:20 nidoran-f:24:20 doduo:26:15 exeggcute:23:15 paras:25:10 parasect:30:8 nidorino:33:5 nidorina:33:5 kangaskhan:25:1 chansey:26:1
+safari-zone safari-zone-north nidoran-m:22:20 nidoran-f:22:20 rhyhorn:26:15 exeggcute:24:15 paras:23:10 venomoth:32:8 nidorino:30:5 nidorina:30:5 chansey:26:1 tauros:28:1
+safari-zone safari-zone-west nidoran-m:22:20 nidoran-f:22:20 doduo:26:15 exeggcute:25:15 venonat:23:10 venomoth:32:8 nidorino:30:5 nidorina:30:5 tauros:28:1 kangaskhan:28:1
+kanto-sea-route-19 kanto-sea-route-19-area tentacool:5-40:100:surf shellder:15-25:25:super-rod horsea:15-25:25:super-rod staryu:15-25:25:super-rod goldeen:15-25:25:super-rod
+seafoam-islands seafoam-islands-1f seel:30-34:35 slowpoke:28-33:20 psyduck:30:15 shellder:30:10 horsea:30:10 staryu:30:5 golduck:37:5
+seafoam-islands seafoam-islands-b4f seel:30-34:30 slowpoke:28-33:20 psyduck:30:15 dewgong:37:10 slowbro:37:10 shellder:30:5 golduck:37:5 articuno:50:1
+cinnabar-island cinnabar-island-area tentacool:5-40:100:surf magikarp:5:100:old-rod goldeen:10:50:good-rod poliwag:10:50:good-rod
+pokemon-mansion pokemon-mansion-1f koffing:30:30 grimer:30:20 ponyta:32:15 growlithe:30:15 rattata:28:10 raticate:32:5 weezing:37:3 muk:37:2
+kanto-sea-route-21 kanto-route-21-area tangela:28-32:25 rattata:21-30:25 pidgey:23-30:20 raticate:25-30:15 pidgeotto:30-32:15 tentacool:5-40:100:surf
+kanto-route-23 kanto-route-23-area spearow:26-30:25 ekans:26-30:20 mankey:30:20 fearow:38-43:15 arbok:41:10 primeape:41-43:5 ditto:33-43:5
+victory-road victory-road-1f machop:24-32:30 geodude:26-32:25 zubat:22:15 onix:36-42:15 graveler:40:5 machoke:42:5 marowak:40:4 moltres:50:1
+victory-road victory-road-2f machop:22-30:25 geodude:26-30:25 onix:36-44:20 golbat:40:10 graveler:40-44:10 machoke:41-44:5 marowak:40-43:5
+cerulean-cave cerulean-cave-1f golbat:46:20 hypno:46:15 magneton:46:15 dodrio:49:10 venomoth:49:10 arbok:52:10 kadabra:49:8 parasect:52:5 raichu:53:4 ditto:52:3
+cerulean-cave cerulean-cave-b1f golbat:55:20 rhydon:55:15 marowak:55:15 kadabra:55:15 ditto:55:15 electrode:55:10 chansey:65:5 wigglytuff:55:4 mewtwo:70:1
+`
+
+const (
+	apiURL     = "https://pokeapi.co/api/v2/"
+	spriteURL  = "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/"
+	criesURL   = "https://raw.githubusercontent.com/PokeAPI/cries/main/cries/pokemon/"
+	encounters = "red"
+)
+
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+var effortStats = map[string]string{"h": "hp", "a": "attack", "d": "defense", "sa": "special-attack", "sd": "special-defense", "s": "speed"}
+
+type named struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func ref(resource, name string) named {
+	return named{name, apiURL + resource + "/" + name + "/"}
+}
+
+func main() {
+	f, err := os.Create("kanto.zip")
+	if err != nil {
+		log.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	add := func(resource, name string, v any) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// A fixed time keeps the file the same from one run to the next.
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: resource + "/" + name + ".json", Method: zip.Deflate, Modified: time.Date(1996, 2, 27, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			log.Fatal(err)
+		}
+		w.Write(data)
+	}
+
+	habitats := map[string]string{}
+	for _, line := range lines(habitatTable) {
+		fields := strings.Fields(line)
+		for _, name := range fields[1:] {
+			habitats[name] = fields[0]
+		}
+	}
+	members := map[string][]named{}
+	for _, line := range lines(pokemonTable) {
+		fields := strings.Fields(line)
+		habitat, ok := habitats[fields[1]]
+		if !ok {
+			log.Fatalf("%s has no habitat", fields[1])
+		}
+		addPokemon(add, fields, habitat)
+		members[habitat] = append(members[habitat], ref("pokemon-species", fields[1]))
+	}
+	for _, line := range lines(habitatTable) {
+		habitat := strings.Fields(line)[0]
+		add("pokemon-habitat", habitat, map[string]any{"name": habitat, "pokemon_species": members[habitat]})
+	}
+
+	var locations []named
+	areas := map[string][]named{}
+	for _, line := range lines(placesTable) {
+		fields := strings.Fields(line)
+		location := fields[0]
+		if _, ok := areas[location]; !ok {
+			locations = append(locations, ref("location", location))
+			areas[location] = []named{}
+		}
+		if len(fields) == 1 {
+			continue
+		}
+		area := fields[1]
+		areas[location] = append(areas[location], ref("location-area", area))
+		add("location-area", area, map[string]any{
+			"name":               area,
+			"location":           ref("location", location),
+			"pokemon_encounters": encountersOf(fields[2:]),
+		})
+	}
+	for _, l := range locations {
+		add("location", l.Name, map[string]any{"name": l.Name, "region": ref("region", "kanto"), "areas": areas[l.Name]})
+	}
+	add("region", "kanto", map[string]any{"name": "kanto", "locations": locations})
+
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func lines(table string) []string {
+	return strings.Split(strings.TrimSpace(table), "\n")
+}
+
+func addPokemon(add func(resource, name string, v any), f []string, habitat string) {
+	id, name := f[0], f[1]
+	var types []map[string]any
+	for i, t := range strings.Split(f[2], ",") {
+		types = append(types, map[string]any{"slot": i + 1, "type": ref("type", t)})
+	}
+	effort := map[string]int{}
+	for _, ev := range strings.Split(f[12], ",") {
+		stat := strings.TrimRight(ev, "0123456789")
+		effort[effortStats[stat]] = atoi(ev[len(stat):])
+	}
+	var stats []map[string]any
+	for i, base := range strings.Split(f[3], "/") {
+		stats = append(stats, map[string]any{"base_stat": atoi(base), "effort": effort[statNames[i]], "stat": ref("stat", statNames[i])})
+	}
+	add("pokemon", name, map[string]any{
+		"id":              atoi(id),
+		"name":            name,
+		"species":         ref("pokemon-species", name),
+		"height":          atoi(f[5]),
+		"weight":          atoi(f[6]),
+		"base_experience": atoi(f[4]),
+		"stats":           stats,
+		"types":           types,
+		"abilities":       []map[string]any{{"ability": ref("ability", f[7]), "is_hidden": false, "slot": 1}},
+		"sprites":         map[string]string{"front_default": spriteURL + id + ".png", "front_shiny": spriteURL + "shiny/" + id + ".png"},
+		"cries":           map[string]string{"latest": criesURL + "latest/" + id + ".ogg", "legacy": criesURL + "legacy/" + id + ".ogg"},
+	})
+
+	var eggGroups []named
+	for _, g := range strings.Split(f[11], ",") {
+		eggGroups = append(eggGroups, ref("egg-group", g))
+	}
+	rarity := ""
+	if len(f) > 13 {
+		rarity = f[13]
+	}
+	add("pokemon-species", name, map[string]any{
+		"id":           atoi(id),
+		"name":         name,
+		"capture_rate": atoi(f[8]),
+		"gender_rate":  atoi(f[9]),
+		"growth_rate":  ref("growth-rate", f[10]),
+		"egg_groups":   eggGroups,
+		"habitat":      ref("pokemon-habitat", habitat),
+		"is_legendary": rarity == "legendary",
+		"is_mythical":  rarity == "mythical",
+		"varieties":    []map[string]any{{"is_default": true, "pokemon": ref("pokemon", name)}},
+	})
+}
+
+// encountersOf turns an area's name:levels:chance[:method] entries into
+// PokeAPI's pokemon_encounters.
+func encountersOf(entries []string) []map[string]any {
+	var found []map[string]any
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 {
+			log.Fatalf("encounter %q needs a name, levels and a chance", entry)
+		}
+		low, high, _ := strings.Cut(parts[1], "-")
+		if high == "" {
+			high = low
+		}
+		method := "walk"
+		if len(parts) > 3 {
+			method = parts[3]
+		}
+		chance := atoi(parts[2])
+		found = append(found, map[string]any{
+			"pokemon": ref("pokemon", parts[0]),
+			"version_details": []map[string]any{{
+				"version":    ref("version", encounters),
+				"max_chance": chance,
+				"encounter_details": []map[string]any{{
+					"min_level": atoi(low),
+					"max_level": atoi(high),
+					"chance":    chance,
+					"method":    ref("encounter-method", method),
+				}},
+			}},
+		})
+	}
+	return found
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		log.Fatal(fmt.Errorf("%q isn't a number", s))
+	}
+	return n
+}
@@ -0,0 +1,603 @@
+package pokeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+// post sends a query's body to the endpoint, unless the breaker is open.
+func (g *GraphQLClient) post(body []byte) ([]byte, error) {
+	if err := g.breaker.allow(); err != nil {
+		return nil, err
+	}
+	data, err := g.send(body)
+	g.breaker.done(err != nil)
+	return data, err
+}
+
+func (g *GraphQLClient) send(body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.trace != nil {
+		req.Header.Set(TraceHeader, g.trace.ID)
+	}
+	res, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch data: %s", res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+const DefaultGraphQLUrl = "https://beta.pokeapi.co/graphql/v1beta"
+
+// GraphQLClient talks to the PokeAPI GraphQL beta. Pages are plain offsets.
+type GraphQLClient struct {
+	endpoint   string
+	cache      *pokecache.Cache
+	httpClient *http.Client
+	flights    *flights
+	breaker    *breaker
+	// trace, if set, follows every query.
+	trace *Trace
+}
+
+func NewGraphQLClient(endpoint string, cache *pokecache.Cache) *GraphQLClient {
+	g := &GraphQLClient{
+		endpoint:   endpoint,
+		cache:      cache,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		flights:    &flights{},
+		breaker:    &breaker{},
+	}
+	cache.RefreshWith(func(key string) ([]byte, error) {
+		body, _ := strings.CutPrefix(key, endpoint+"?")
+		return g.post([]byte(body))
+	})
+	return g
+}
+
+const locationAreasQuery = `query($limit: Int!, $offset: Int!) {
+  areas: pokemon_v2_locationarea(limit: $limit, offset: $offset, order_by: {id: asc}) { name }
+  total: pokemon_v2_locationarea_aggregate { aggregate { count } }
+}`
+
+const locationAreaQuery = `query($name: String!) {
+  areas: pokemon_v2_locationarea(where: {name: {_eq: $name}}) {
+    name
+    location: pokemon_v2_location { name }
+    encounters: pokemon_v2_encounters(order_by: {id: asc}) {
+      min_level
+      max_level
+      pokemon: pokemon_v2_pokemon { name }
+      version: pokemon_v2_version { name }
+      slot: pokemon_v2_encounterslot { rarity method: pokemon_v2_encountermethod { name } }
+    }
+  }
+}`
+
+const locationQuery = `query($name: String!) {
+  locations: pokemon_v2_location(where: {name: {_eq: $name}}) {
+    name
+    region: pokemon_v2_region { name }
+    areas: pokemon_v2_locationareas(order_by: {id: asc}) { name }
+  }
+}`
+
+const regionQuery = `query($name: String!) {
+  regions: pokemon_v2_region(where: {name: {_eq: $name}}) {
+    name
+    locations: pokemon_v2_locations(order_by: {id: asc}) { name }
+  }
+}`
+
+const pokemonQuery = `query($name: String!) {
+  pokemon: pokemon_v2_pokemon(where: {name: {_eq: $name}}) {
+    name
+    height
+    weight
+    base_experience
+    species: pokemon_v2_pokemonspecy { name }
+    stats: pokemon_v2_pokemonstats { base_stat effort stat: pokemon_v2_stat { name } }
+    types: pokemon_v2_pokemontypes { slot type: pokemon_v2_type { name } }
+    abilities: pokemon_v2_pokemonabilities { slot is_hidden ability: pokemon_v2_ability { name } }
+  }
+}`
+
+const speciesQuery = `query($name: String!) {
+  species: pokemon_v2_pokemonspecies(where: {name: {_eq: $name}}) {
+    id
+    name
+    capture_rate
+    is_legendary
+    is_mythical
+    gender_rate
+    growth_rate: pokemon_v2_growthrate { name }
+    habitat: pokemon_v2_pokemonhabitat { name }
+    shape: pokemon_v2_pokemonshape { name }
+    egg_groups: pokemon_v2_pokemonegggroups { egg_group: pokemon_v2_egggroup { name } }
+    varieties: pokemon_v2_pokemons(order_by: {id: asc}) { is_default name }
+    names: pokemon_v2_pokemonspeciesnames { name genus language: pokemon_v2_language { name } }
+    flavor_text_entries: pokemon_v2_pokemonspeciesflavortexts(order_by: {id: asc}) { flavor_text language: pokemon_v2_language { name } }
+  }
+}`
+
+const eggGroupQuery = `query($name: String!) {
+  groups: pokemon_v2_egggroup(where: {name: {_eq: $name}}) {
+    name
+    species: pokemon_v2_pokemonegggroups(order_by: {pokemon_species_id: asc}) { species: pokemon_v2_pokemonspecy { name } }
+  }
+}`
+
+const habitatQuery = `query($name: String!) {
+  habitats: pokemon_v2_pokemonhabitat(where: {name: {_eq: $name}}) {
+    name
+    species: pokemon_v2_pokemonspecies(order_by: {id: asc}) { name }
+  }
+}`
+
+const shapeQuery = `query($name: String!) {
+  shapes: pokemon_v2_pokemonshape(where: {name: {_eq: $name}}) {
+    name
+    species: pokemon_v2_pokemonspecies(order_by: {id: asc}) { name }
+  }
+}`
+
+const pokemonMovesQuery = `query($name: String!) {
+  moves: pokemon_v2_pokemonmove(where: {pokemon_v2_pokemon: {name: {_eq: $name}}}, order_by: {id: asc}) {
+    level
+    move: pokemon_v2_move { name }
+    method: pokemon_v2_movelearnmethod { name }
+    version_group: pokemon_v2_versiongroup { id name }
+  }
+}`
+
+const moveQuery = `query($name: String!) {
+  moves: pokemon_v2_move(where: {name: {_eq: $name}}) {
+    name
+    power
+    accuracy
+    pp
+    type: pokemon_v2_type { name }
+    damage_class: pokemon_v2_movedamageclass { name }
+    target: pokemon_v2_movetarget { name }
+    contest_type: pokemon_v2_contesttype { name }
+    machines: pokemon_v2_machines(order_by: {id: asc}) { id version_group: pokemon_v2_versiongroup { name } }
+  }
+}`
+
+const itemQuery = `query($name: String!) {
+  items: pokemon_v2_item(where: {name: {_eq: $name}}) {
+    name
+    cost
+    category: pokemon_v2_itemcategory { name }
+    machines: pokemon_v2_machines(order_by: {id: asc}) { id version_group: pokemon_v2_versiongroup { name } }
+  }
+}`
+
+const machineQuery = `query($id: Int!) {
+  machines: pokemon_v2_machine(where: {id: {_eq: $id}}) {
+    id
+    item: pokemon_v2_item { name }
+    move: pokemon_v2_move { name }
+    version_group: pokemon_v2_versiongroup { name }
+  }
+}`
+
+const natureQuery = `query($name: String!) {
+  natures: pokemon_v2_nature(where: {name: {_eq: $name}}) {
+    name
+    increased_stat: pokemon_v2_statByIncreasedStatId { name }
+    decreased_stat: pokemon_v2_stat { name }
+    likes_flavor: pokemon_v2_berryflavorByLikesFlavorId { name }
+    hates_flavor: pokemon_v2_berryflavor { name }
+  }
+}`
+
+// graphQLMachine is a machine as the GraphQL API lists it: by ID rather than
+// by URL.
+type graphQLMachine struct {
+	ID           int          `json:"id"`
+	VersionGroup VersionGroup `json:"version_group"`
+}
+
+// machineVersions turns machines into the REST shape, pointing each at the
+// URL the REST API would give it.
+func machineVersions(machines []graphQLMachine) []MachineVersion {
+	versions := []MachineVersion{}
+	for _, m := range machines {
+		versions = append(versions, MachineVersion{
+			Machine:      MachineReference{Url: fmt.Sprintf("machine/%d/", m.ID)},
+			VersionGroup: m.VersionGroup,
+		})
+	}
+	return versions
+}
+
+func (g *GraphQLClient) LocationAreas(page string) (LocationResponse, error) {
+	response := LocationResponse{}
+	offset := 0
+	if page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil {
+			return response, fmt.Errorf("invalid page %q", page)
+		}
+		offset = n
+	}
+
+	var data struct {
+		Areas []Location `json:"areas"`
+		Total struct {
+			Aggregate struct {
+				Count int `json:"count"`
+			} `json:"aggregate"`
+		} `json:"total"`
+	}
+	vars := map[string]any{"limit": pageSize, "offset": offset}
+	if err := g.query(locationAreasQuery, vars, &data); err != nil {
+		return response, err
+	}
+
+	response.Count = data.Total.Aggregate.Count
+	response.Locations = data.Areas
+	if offset+pageSize < response.Count {
+		response.Next = strconv.Itoa(offset + pageSize)
+	}
+	if offset > 0 {
+		response.Previous = strconv.Itoa(max(offset-pageSize, 0))
+	}
+	return response, nil
+}
+
+func (g *GraphQLClient) LocationArea(name string) (LocationDetailsResponse, error) {
+	response := LocationDetailsResponse{}
+	var data struct {
+		Areas []struct {
+			Name       string             `json:"name"`
+			Location   Location           `json:"location"`
+			Encounters []graphQLEncounter `json:"encounters"`
+		} `json:"areas"`
+	}
+	if err := g.query(locationAreaQuery, map[string]any{"name": name}, &data); err != nil {
+		return response, err
+	}
+	if len(data.Areas) == 0 {
+		return response, ErrNotFound
+	}
+	response.Name = data.Areas[0].Name
+	response.Location = data.Areas[0].Location
+	response.PokemonEncounters = groupEncounters(data.Areas[0].Encounters)
+	return response, nil
+}
+
+// graphQLEncounter is one encounter slot; REST groups them by pokemon and
+// version.
+type graphQLEncounter struct {
+	MinLevel int     `json:"min_level"`
+	MaxLevel int     `json:"max_level"`
+	Pokemon  Pokemon `json:"pokemon"`
+	Version  Version `json:"version"`
+	Slot     struct {
+		Rarity int             `json:"rarity"`
+		Method EncounterMethod `json:"method"`
+	} `json:"slot"`
+}
+
+// groupEncounters arranges encounter slots the way REST has them, by
+// pokemon and then version, each version's max chance being the sum of its
+// slots'.
+func groupEncounters(slots []graphQLEncounter) []PokemonEncounter {
+	encounters := []PokemonEncounter{}
+	for _, slot := range slots {
+		i := slices.IndexFunc(encounters, func(e PokemonEncounter) bool { return e.Pokemon.Name == slot.Pokemon.Name })
+		if i < 0 {
+			encounters = append(encounters, PokemonEncounter{Pokemon: slot.Pokemon})
+			i = len(encounters) - 1
+		}
+		versions := encounters[i].VersionDetails
+		j := slices.IndexFunc(versions, func(v VersionEncounterDetail) bool { return v.Version.Name == slot.Version.Name })
+		if j < 0 {
+			versions = append(versions, VersionEncounterDetail{Version: slot.Version})
+			j = len(versions) - 1
+		}
+		versions[j].MaxChance += slot.Slot.Rarity
+		versions[j].EncounterDetails = append(versions[j].EncounterDetails, EncounterDetail{
+			MinLevel: slot.MinLevel,
+			MaxLevel: slot.MaxLevel,
+			Chance:   slot.Slot.Rarity,
+			Method:   slot.Slot.Method,
+		})
+		encounters[i].VersionDetails = versions
+	}
+	return encounters
+}
+
+func (g *GraphQLClient) Location(name string) (LocationDetail, error) {
+	var data struct {
+		Locations []LocationDetail `json:"locations"`
+	}
+	if err := g.query(locationQuery, map[string]any{"name": name}, &data); err != nil {
+		return LocationDetail{}, err
+	}
+	if len(data.Locations) == 0 {
+		return LocationDetail{}, ErrNotFound
+	}
+	return data.Locations[0], nil
+}
+
+func (g *GraphQLClient) Region(name string) (RegionDetail, error) {
+	var data struct {
+		Regions []RegionDetail `json:"regions"`
+	}
+	if err := g.query(regionQuery, map[string]any{"name": name}, &data); err != nil {
+		return RegionDetail{}, err
+	}
+	if len(data.Regions) == 0 {
+		return RegionDetail{}, ErrNotFound
+	}
+	return data.Regions[0], nil
+}
+
+func (g *GraphQLClient) Pokemon(name string) (PokemonType, error) {
+	var data struct {
+		Pokemon []PokemonType `json:"pokemon"`
+	}
+	if err := g.query(pokemonQuery, map[string]any{"name": name}, &data); err != nil {
+		return PokemonType{}, err
+	}
+	if len(data.Pokemon) == 0 {
+		return PokemonType{}, ErrNotFound
+	}
+	return data.Pokemon[0], nil
+}
+
+func (g *GraphQLClient) Species(name string) (PokemonSpecies, error) {
+	var data struct {
+		Species []struct {
+			PokemonSpecies
+			EggGroups []struct {
+				EggGroup EggGroup `json:"egg_group"`
+			} `json:"egg_groups"`
+			Varieties []struct {
+				IsDefault bool   `json:"is_default"`
+				Name      string `json:"name"`
+			} `json:"varieties"`
+			// Names has each language's genus as well.
+			Names []struct {
+				Name     string   `json:"name"`
+				Genus    string   `json:"genus"`
+				Language Language `json:"language"`
+			} `json:"names"`
+		} `json:"species"`
+	}
+	if err := g.query(speciesQuery, map[string]any{"name": name}, &data); err != nil {
+		return PokemonSpecies{}, err
+	}
+	if len(data.Species) == 0 {
+		return PokemonSpecies{}, ErrNotFound
+	}
+	species := data.Species[0].PokemonSpecies
+	for _, group := range data.Species[0].EggGroups {
+		species.EggGroups = append(species.EggGroups, group.EggGroup)
+	}
+	for _, v := range data.Species[0].Varieties {
+		species.Varieties = append(species.Varieties, Variety{IsDefault: v.IsDefault, Pokemon: Pokemon{Name: v.Name}})
+	}
+	for _, n := range data.Species[0].Names {
+		species.Names = append(species.Names, LocalizedName{Name: n.Name, Language: n.Language})
+		if n.Genus != "" {
+			species.Genera = append(species.Genera, Genus{Genus: n.Genus, Language: n.Language})
+		}
+	}
+	return species, nil
+}
+
+func (g *GraphQLClient) EggGroup(name string) (EggGroupDetail, error) {
+	var data struct {
+		Groups []struct {
+			Name    string `json:"name"`
+			Species []struct {
+				Species Species `json:"species"`
+			} `json:"species"`
+		} `json:"groups"`
+	}
+	if err := g.query(eggGroupQuery, map[string]any{"name": name}, &data); err != nil {
+		return EggGroupDetail{}, err
+	}
+	if len(data.Groups) == 0 {
+		return EggGroupDetail{}, ErrNotFound
+	}
+	group := EggGroupDetail{Name: data.Groups[0].Name}
+	for _, member := range data.Groups[0].Species {
+		group.PokemonSpecies = append(group.PokemonSpecies, member.Species)
+	}
+	return group, nil
+}
+
+func (g *GraphQLClient) Habitat(name string) (HabitatDetail, error) {
+	var data struct {
+		Habitats []struct {
+			Name    string    `json:"name"`
+			Species []Species `json:"species"`
+		} `json:"habitats"`
+	}
+	if err := g.query(habitatQuery, map[string]any{"name": name}, &data); err != nil {
+		return HabitatDetail{}, err
+	}
+	if len(data.Habitats) == 0 {
+		return HabitatDetail{}, ErrNotFound
+	}
+	return HabitatDetail{Name: data.Habitats[0].Name, PokemonSpecies: data.Habitats[0].Species}, nil
+}
+
+func (g *GraphQLClient) Shape(name string) (ShapeDetail, error) {
+	var data struct {
+		Shapes []struct {
+			Name    string    `json:"name"`
+			Species []Species `json:"species"`
+		} `json:"shapes"`
+	}
+	if err := g.query(shapeQuery, map[string]any{"name": name}, &data); err != nil {
+		return ShapeDetail{}, err
+	}
+	if len(data.Shapes) == 0 {
+		return ShapeDetail{}, ErrNotFound
+	}
+	return ShapeDetail{Name: data.Shapes[0].Name, PokemonSpecies: data.Shapes[0].Species}, nil
+}
+
+func (g *GraphQLClient) PokemonMoves(name string) ([]PokemonMove, error) {
+	var data struct {
+		Moves []struct {
+			Level        int             `json:"level"`
+			Move         Move            `json:"move"`
+			Method       MoveLearnMethod `json:"method"`
+			VersionGroup struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"version_group"`
+		} `json:"moves"`
+	}
+	if err := g.query(pokemonMovesQuery, map[string]any{"name": name}, &data); err != nil {
+		return nil, err
+	}
+
+	// Rows come one per move, method and version group; group them by move
+	// like the REST API does.
+	moves := []PokemonMove{}
+	index := map[string]int{}
+	for _, row := range data.Moves {
+		i, ok := index[row.Move.Name]
+		if !ok {
+			i = len(moves)
+			index[row.Move.Name] = i
+			moves = append(moves, PokemonMove{Move: row.Move})
+		}
+		moves[i].VersionGroupDetails = append(moves[i].VersionGroupDetails, MoveVersionDetail{
+			LevelLearnedAt:  row.Level,
+			MoveLearnMethod: row.Method,
+			VersionGroup:    VersionGroup{Name: row.VersionGroup.Name, Url: fmt.Sprintf("version-group/%d/", row.VersionGroup.ID)},
+		})
+	}
+	return moves, nil
+}
+
+func (g *GraphQLClient) Move(name string) (MoveDetail, error) {
+	var data struct {
+		Moves []struct {
+			MoveDetail
+			Machines []graphQLMachine `json:"machines"`
+		} `json:"moves"`
+	}
+	if err := g.query(moveQuery, map[string]any{"name": name}, &data); err != nil {
+		return MoveDetail{}, err
+	}
+	if len(data.Moves) == 0 {
+		return MoveDetail{}, ErrNotFound
+	}
+	move := data.Moves[0].MoveDetail
+	move.Machines = machineVersions(data.Moves[0].Machines)
+	return move, nil
+}
+
+func (g *GraphQLClient) Item(name string) (ItemDetail, error) {
+	var data struct {
+		Items []struct {
+			ItemDetail
+			Machines []graphQLMachine `json:"machines"`
+		} `json:"items"`
+	}
+	if err := g.query(itemQuery, map[string]any{"name": name}, &data); err != nil {
+		return ItemDetail{}, err
+	}
+	if len(data.Items) == 0 {
+		return ItemDetail{}, ErrNotFound
+	}
+	item := data.Items[0].ItemDetail
+	item.Machines = machineVersions(data.Items[0].Machines)
+	return item, nil
+}
+
+func (g *GraphQLClient) Machine(id int) (MachineDetail, error) {
+	var data struct {
+		Machines []MachineDetail `json:"machines"`
+	}
+	if err := g.query(machineQuery, map[string]any{"id": id}, &data); err != nil {
+		return MachineDetail{}, err
+	}
+	if len(data.Machines) == 0 {
+		return MachineDetail{}, ErrNotFound
+	}
+	return data.Machines[0], nil
+}
+
+func (g *GraphQLClient) Nature(name string) (NatureDetail, error) {
+	var data struct {
+		Natures []NatureDetail `json:"natures"`
+	}
+	if err := g.query(natureQuery, map[string]any{"name": name}, &data); err != nil {
+		return NatureDetail{}, err
+	}
+	if len(data.Natures) == 0 {
+		return NatureDetail{}, ErrNotFound
+	}
+	return data.Natures[0], nil
+}
+
+// WithTrace is g, following t's queries.
+func (g *GraphQLClient) WithTrace(t *Trace) DataSource {
+	traced := *g
+	traced.trace = t
+	return &traced
+}
+
+func (g *GraphQLClient) query(query string, vars map[string]any, v any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+
+	key := g.endpoint + "?" + string(body)
+	raw, ok := g.cache.Get(key)
+	g.trace.record(ok)
+	if !ok {
+		raw, err = g.flights.do(key, func() ([]byte, error) {
+			return g.post(body)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Errors) > 0 {
+		return errors.New(envelope.Errors[0].Message)
+	}
+	if !ok {
+		g.cache.Add(key, raw)
+	}
+	return json.Unmarshal(envelope.Data, v)
+}
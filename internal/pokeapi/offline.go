@@ -0,0 +1,183 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Offline serves a snapshot laid out like the REST API:
+//
+//	<dir>/location-area/<name>.json
+//	<dir>/location/<name>.json
+//	<dir>/region/<name>.json
+//	<dir>/pokemon/<name>.json
+//	<dir>/pokemon-species/<name>.json
+//	<dir>/move/<name>.json
+//	<dir>/item/<name>.json
+//	<dir>/machine/<id>.json
+//	<dir>/egg-group/<name>.json
+//	<dir>/pokemon-habitat/<name>.json
+//	<dir>/pokemon-shape/<name>.json
+//	<dir>/nature/<name>.json
+//
+// Pages are plain offsets into the sorted list of location areas.
+type Offline struct {
+	fsys fs.FS
+}
+
+func NewOffline(dir string) *Offline {
+	return &Offline{fsys: os.DirFS(dir)}
+}
+
+func (o *Offline) LocationAreas(page string) (LocationResponse, error) {
+	response := LocationResponse{}
+	offset := 0
+	if page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil {
+			return response, fmt.Errorf("invalid page %q", page)
+		}
+		offset = n
+	}
+
+	names, err := o.list("location-area")
+	if err != nil {
+		return response, err
+	}
+
+	response.Count = len(names)
+	end := min(offset+pageSize, len(names))
+	for _, name := range names[min(offset, end):end] {
+		response.Locations = append(response.Locations, Location{Name: name})
+	}
+	if end < len(names) {
+		response.Next = strconv.Itoa(end)
+	}
+	if offset > 0 {
+		response.Previous = strconv.Itoa(max(offset-pageSize, 0))
+	}
+	return response, nil
+}
+
+func (o *Offline) LocationArea(name string) (LocationDetailsResponse, error) {
+	response := LocationDetailsResponse{}
+	err := o.read("location-area", name, &response)
+	return response, err
+}
+
+func (o *Offline) Location(name string) (LocationDetail, error) {
+	response := LocationDetail{}
+	err := o.read("location", name, &response)
+	return response, err
+}
+
+func (o *Offline) Region(name string) (RegionDetail, error) {
+	response := RegionDetail{}
+	err := o.read("region", name, &response)
+	return response, err
+}
+
+func (o *Offline) Pokemon(name string) (PokemonType, error) {
+	response := PokemonType{}
+	err := o.read("pokemon", name, &response)
+	return response, err
+}
+
+func (o *Offline) Species(name string) (PokemonSpecies, error) {
+	response := PokemonSpecies{}
+	err := o.read("pokemon-species", name, &response)
+	return response, err
+}
+
+func (o *Offline) PokemonMoves(name string) ([]PokemonMove, error) {
+	response := struct {
+		Moves []PokemonMove `json:"moves"`
+	}{}
+	err := o.read("pokemon", name, &response)
+	return response.Moves, err
+}
+
+func (o *Offline) Move(name string) (MoveDetail, error) {
+	response := MoveDetail{}
+	err := o.read("move", name, &response)
+	return response, err
+}
+
+func (o *Offline) Item(name string) (ItemDetail, error) {
+	response := ItemDetail{}
+	err := o.read("item", name, &response)
+	return response, err
+}
+
+func (o *Offline) Machine(id int) (MachineDetail, error) {
+	response := MachineDetail{}
+	err := o.read("machine", strconv.Itoa(id), &response)
+	return response, err
+}
+
+func (o *Offline) EggGroup(name string) (EggGroupDetail, error) {
+	response := EggGroupDetail{}
+	err := o.read("egg-group", name, &response)
+	return response, err
+}
+
+func (o *Offline) Habitat(name string) (HabitatDetail, error) {
+	response := HabitatDetail{}
+	err := o.read("pokemon-habitat", name, &response)
+	return response, err
+}
+
+func (o *Offline) Shape(name string) (ShapeDetail, error) {
+	response := ShapeDetail{}
+	err := o.read("pokemon-shape", name, &response)
+	return response, err
+}
+
+func (o *Offline) Nature(name string) (NatureDetail, error) {
+	response := NatureDetail{}
+	err := o.read("nature", name, &response)
+	return response, err
+}
+
+// Names lists the snapshot's entries for resource, such as "pokemon".
+func (o *Offline) Names(resource string) ([]string, error) {
+	return o.list(resource)
+}
+
+func (o *Offline) read(resource, name string, v any) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return errors.New("Invalid input")
+	}
+	data, err := fs.ReadFile(o.fsys, resource+"/"+name+".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (o *Offline) list(resource string) ([]string, error) {
+	entries, err := fs.ReadDir(o.fsys, resource)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
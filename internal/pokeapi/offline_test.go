@@ -0,0 +1,28 @@
+package pokeapi
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOfflinePokemon(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pokemon"), 0o755)
+	data := `{"name":"pikachu","base_experience":112}`
+	os.WriteFile(filepath.Join(dir, "pokemon", "pikachu.json"), []byte(data), 0o644)
+
+	source := NewOffline(dir)
+	pokemon, err := source.Pokemon("pikachu")
+	if err != nil {
+		t.Fatalf("Pokemon() returned error: %v", err)
+	}
+	if pokemon.BaseExperience != 112 {
+		t.Errorf("Expected base experience 112, got %d", pokemon.BaseExperience)
+	}
+
+	if _, err := source.Pokemon("mew"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
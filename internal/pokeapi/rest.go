@@ -0,0 +1,219 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+const DefaultUrl = "https://pokeapi.co/api/v2/"
+
+// decodedTTL is how long decoded responses are kept, as long as main keeps
+// their bytes.
+const decodedTTL = 5 * time.Minute
+
+// requestTimeout is the longest a request may take, reading the body
+// included, before it counts as failed.
+const requestTimeout = 20 * time.Second
+
+type Client struct {
+	baseUrl string
+	cache   *pokecache.Cache
+	// decoded has responses already unmarshaled, by type and URL.
+	decoded    *pokecache.Objects
+	httpClient *http.Client
+	flights    *flights
+	breaker    *breaker
+	// trace, if set, follows every fetch.
+	trace *Trace
+}
+
+func NewClient(baseUrl string, cache *pokecache.Cache) *Client {
+	if !strings.HasSuffix(baseUrl, "/") {
+		baseUrl += "/"
+	}
+	c := &Client{
+		baseUrl:    baseUrl,
+		cache:      cache,
+		decoded:    pokecache.NewObjects(decodedTTL),
+		httpClient: &http.Client{Timeout: requestTimeout},
+		flights:    &flights{},
+		breaker:    &breaker{},
+	}
+	cache.RefreshWith(c.request)
+	return c
+}
+
+func (c *Client) LocationAreas(page string) (LocationResponse, error) {
+	url := page
+	if url == "" {
+		url = c.baseUrl + "location-area"
+	}
+	return getDecoded[LocationResponse](c, url)
+}
+
+func (c *Client) LocationArea(name string) (LocationDetailsResponse, error) {
+	return getDecoded[LocationDetailsResponse](c, c.baseUrl+"location-area/"+name)
+}
+
+func (c *Client) Location(name string) (LocationDetail, error) {
+	return getDecoded[LocationDetail](c, c.baseUrl+"location/"+name)
+}
+
+func (c *Client) Region(name string) (RegionDetail, error) {
+	return getDecoded[RegionDetail](c, c.baseUrl+"region/"+name)
+}
+
+func (c *Client) Pokemon(name string) (PokemonType, error) {
+	return getDecoded[PokemonType](c, c.baseUrl+"pokemon/"+name)
+}
+
+func (c *Client) Species(name string) (PokemonSpecies, error) {
+	return getDecoded[PokemonSpecies](c, c.baseUrl+"pokemon-species/"+name)
+}
+
+func (c *Client) PokemonMoves(name string) ([]PokemonMove, error) {
+	response, err := getDecoded[struct {
+		Moves []PokemonMove `json:"moves"`
+	}](c, c.baseUrl+"pokemon/"+name)
+	return response.Moves, err
+}
+
+func (c *Client) Move(name string) (MoveDetail, error) {
+	return getDecoded[MoveDetail](c, c.baseUrl+"move/"+name)
+}
+
+func (c *Client) Item(name string) (ItemDetail, error) {
+	return getDecoded[ItemDetail](c, c.baseUrl+"item/"+name)
+}
+
+func (c *Client) Machine(id int) (MachineDetail, error) {
+	return getDecoded[MachineDetail](c, c.baseUrl+"machine/"+strconv.Itoa(id))
+}
+
+func (c *Client) EggGroup(name string) (EggGroupDetail, error) {
+	return getDecoded[EggGroupDetail](c, c.baseUrl+"egg-group/"+name)
+}
+
+func (c *Client) Habitat(name string) (HabitatDetail, error) {
+	return getDecoded[HabitatDetail](c, c.baseUrl+"pokemon-habitat/"+name)
+}
+
+func (c *Client) Shape(name string) (ShapeDetail, error) {
+	return getDecoded[ShapeDetail](c, c.baseUrl+"pokemon-shape/"+name)
+}
+
+func (c *Client) Nature(name string) (NatureDetail, error) {
+	return getDecoded[NatureDetail](c, c.baseUrl+"nature/"+name)
+}
+
+// WithTrace is c, following t's fetches.
+func (c *Client) WithTrace(t *Trace) DataSource {
+	traced := *c
+	traced.trace = t
+	return &traced
+}
+
+// Forget drops the resource from both caches.
+func (c *Client) Forget(resource, name string) {
+	url := c.baseUrl + resource + "/" + name
+	c.cache.Remove(pokecache.API, url)
+	c.decoded.RemoveFunc(func(key string) bool { return strings.HasSuffix(key, " "+url) })
+}
+
+// getDecoded is the response at url as a T, decoded once and then kept.
+// Values share their slices with the ones kept, so they're read, never
+// changed, as with any response.
+func getDecoded[T any](c *Client, url string) (T, error) {
+	key := reflect.TypeFor[T]().String() + " " + url
+	if v, ok := c.decoded.Get(key); ok {
+		c.trace.record(true)
+		return v.(T), nil
+	}
+	var v T
+	if err := c.get(url, &v); err != nil {
+		return v, err
+	}
+	c.decoded.Add(key, v)
+	return v, nil
+}
+
+func (c *Client) get(url string, v any) error {
+	data, err := c.fetch(url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (c *Client) fetch(url string) ([]byte, error) {
+	if strings.TrimSpace(url) == "" {
+		return []byte{}, errors.New("Invalid input")
+	}
+
+	if data, ok := c.cache.Get(url); ok {
+		c.trace.record(true)
+		return data, nil
+	}
+	c.trace.record(false)
+	return c.flights.do(url, func() ([]byte, error) {
+		return c.download(url)
+	})
+}
+
+// download fetches url and caches it.
+func (c *Client) download(url string) ([]byte, error) {
+	data, err := c.request(url)
+	if err != nil {
+		return []byte{}, err
+	}
+	c.cache.Add(url, data)
+	return data, nil
+}
+
+// request fetches url, unless the breaker is open.
+func (c *Client) request(url string) ([]byte, error) {
+	if err := c.breaker.allow(); err != nil {
+		return []byte{}, err
+	}
+	data, status, err := c.send(url)
+	// Asking for something that isn't there is the caller's mistake, not a
+	// sign the API is down.
+	c.breaker.done(err != nil && (status < 400 || status >= 500))
+	return data, err
+}
+
+// send does request's request, returning the status it got, or 0 if it got
+// no response.
+func (c *Client) send(url string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	if c.trace != nil {
+		req.Header.Set(TraceHeader, c.trace.ID)
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return []byte{}, res.StatusCode, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return []byte{}, res.StatusCode, fmt.Errorf("failed to fetch data: %s", res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	return data, res.StatusCode, err
+}
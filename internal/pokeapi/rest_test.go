@@ -0,0 +1,68 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+func TestClientKeepsDecodedResponses(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"name": "pikachu", "types": [{"slot": 1, "type": {"name": "electric"}}], "moves": [{"move": {"name": "thunder-shock"}}]}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	c := NewClient(server.URL, cache)
+	first, err := c.Pokemon("pikachu")
+	if err != nil {
+		t.Fatalf("Pokemon() returned error: %v", err)
+	}
+	second, _ := c.Pokemon("pikachu")
+	if &first.Types[0] != &second.Types[0] {
+		t.Error("Expected the decoded pokemon to be reused")
+	}
+	moves, err := c.PokemonMoves("pikachu")
+	if err != nil || len(moves) != 1 || moves[0].Move.Name != "thunder-shock" {
+		t.Errorf("Expected the same response decoded as moves, got %v, %v", moves, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request, got %d", requests)
+	}
+
+	c.Forget("pokemon", "pikachu")
+	c.Pokemon("pikachu")
+	c.PokemonMoves("pikachu")
+	if requests != 2 {
+		t.Errorf("Expected a forgotten pokemon fetched once more, got %d requests", requests)
+	}
+}
+
+func TestClientTrace(t *testing.T) {
+	traces := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traces = append(traces, r.Header.Get(TraceHeader))
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	trace := NewTrace()
+	c := NewClient(server.URL, cache).WithTrace(trace)
+	c.Pokemon("pikachu")
+	c.Pokemon("pikachu")
+	c.Species("pikachu")
+	if fetches, cached := trace.Counts(); fetches != 3 || cached != 1 {
+		t.Errorf("Expected 3 fetches, 1 cached, got %d, %d", fetches, cached)
+	}
+	if len(traces) != 2 || traces[0] != trace.ID || trace.ID == "" {
+		t.Errorf("Expected both requests to carry trace %q, got %v", trace.ID, traces)
+	}
+}
@@ -0,0 +1,43 @@
+package pokeapi
+
+import "sync"
+
+// flight is a fetch in progress, for others asking for the same thing to
+// wait for.
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// flights collapses concurrent fetches of the same key into one, as
+// golang.org/x/sync/singleflight does, so prefetchers, front ends and
+// commands asking at once make a single request. The zero value is ready.
+type flights struct {
+	mu       sync.Mutex
+	inFlight map[string]*flight
+}
+
+// do calls fetch for key unless a call for it is already running, in which
+// case it waits for that one and shares its result.
+func (g *flights) do(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if f, ok := g.inFlight[key]; ok {
+		g.mu.Unlock()
+		<-f.done
+		return f.data, f.err
+	}
+	if g.inFlight == nil {
+		g.inFlight = make(map[string]*flight)
+	}
+	f := &flight{done: make(chan struct{})}
+	g.inFlight[key] = f
+	g.mu.Unlock()
+
+	f.data, f.err = fetch()
+	g.mu.Lock()
+	delete(g.inFlight, key)
+	g.mu.Unlock()
+	close(f.done)
+	return f.data, f.err
+}
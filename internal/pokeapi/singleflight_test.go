@@ -0,0 +1,41 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokecache"
+)
+
+func TestConcurrentFetchesShareARequest(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	c := NewClient(server.URL, cache)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if p, err := c.Pokemon("pikachu"); err != nil || p.Name != "pikachu" {
+				t.Errorf("Expected pikachu, got %v, %v", p.Name, err)
+			}
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected one request, got %d", n)
+	}
+}
@@ -0,0 +1,165 @@
+package pokeapi
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// etagsFile sits at the top of a snapshot, keeping the ETag each file was
+// downloaded with as "<resource>/<name>".
+const etagsFile = "etags.json"
+
+// refreshWorkers is how many downloads RefreshSnapshot makes at once.
+const refreshWorkers = 8
+
+// SnapshotChanges counts what RefreshSnapshot changed, by resource.
+type SnapshotChanges struct {
+	// Resources are the resources refreshed, in order.
+	Resources []string
+	// Checked is how many entries of each resource the API has, Updated
+	// how many of those already in the snapshot had changed, and Added how
+	// many weren't in it.
+	Checked, Updated, Added map[string]int
+}
+
+// RefreshSnapshot brings the snapshot in dir, laid out as Offline reads it,
+// up to date with the REST API at baseUrl. Each resource it has a directory
+// for is listed, entries missing from it downloaded, and the rest asked for
+// again with the ETag they were saved with, so only those changed since
+// come back. The first refresh has no ETags yet, so it downloads everything
+// once, but only rewrites the files that differ.
+func RefreshSnapshot(baseUrl, dir string) (SnapshotChanges, error) {
+	if !strings.HasSuffix(baseUrl, "/") {
+		baseUrl += "/"
+	}
+	changes := SnapshotChanges{Checked: map[string]int{}, Updated: map[string]int{}, Added: map[string]int{}}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return changes, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			changes.Resources = append(changes.Resources, entry.Name())
+		}
+	}
+	sort.Strings(changes.Resources)
+
+	etags := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(dir, etagsFile))
+	if err == nil {
+		err = json.Unmarshal(data, &etags)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return changes, fmt.Errorf("%s: %w", etagsFile, err)
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	// The ETags are saved whatever happens, so downloads that finished
+	// aren't made again next time.
+	defer func() {
+		if data, err := json.MarshalIndent(etags, "", "  "); err == nil {
+			os.WriteFile(filepath.Join(dir, etagsFile), append(data, '\n'), 0o644)
+		}
+	}()
+
+	for _, resource := range changes.Resources {
+		var list struct {
+			Results []Location `json:"results"`
+		}
+		if err := getJSON(client, baseUrl+resource+"?limit=100000", &list); err != nil {
+			return changes, err
+		}
+		changes.Checked[resource] = len(list.Results)
+
+		var mu sync.Mutex
+		var firstErr error
+		names := make(chan string)
+		var wg sync.WaitGroup
+		for range refreshWorkers {
+			wg.Go(func() {
+				for name := range names {
+					key := resource + "/" + name
+					mu.Lock()
+					etag := etags[key]
+					mu.Unlock()
+					added, updated, newTag, err := refreshEntry(client, baseUrl+key+"/", filepath.Join(dir, resource, name+".json"), etag)
+					mu.Lock()
+					switch {
+					case err != nil:
+						firstErr = cmp.Or(firstErr, err)
+					case added:
+						changes.Added[resource]++
+					case updated:
+						changes.Updated[resource]++
+					}
+					if err == nil && newTag != "" {
+						etags[key] = newTag
+					}
+					mu.Unlock()
+				}
+			})
+		}
+		for _, result := range list.Results {
+			// Some resources, machines for one, are only listed by URL.
+			name := cmp.Or(result.Name, path.Base(strings.TrimSuffix(result.Url, "/")))
+			if name != "" && !strings.ContainsAny(name, `/\`) {
+				names <- name
+			}
+		}
+		close(names)
+		wg.Wait()
+		if firstErr != nil {
+			return changes, firstErr
+		}
+	}
+	return changes, nil
+}
+
+// refreshEntry downloads url into file, unless the API says etag is still
+// current, and reports whether the file is new or changed, and its ETag.
+func refreshEntry(client *http.Client, url, file, etag string) (added, updated bool, newTag string, err error) {
+	old, err := os.ReadFile(file)
+	exists := err == nil
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, false, "", err
+	}
+	if exists && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return false, false, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return false, false, etag, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return false, false, "", fmt.Errorf("%s answered %s", url, res.Status)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, false, "", err
+	}
+	newTag = res.Header.Get("ETag")
+	if exists && bytes.Equal(old, data) {
+		return false, false, newTag, nil
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return false, false, "", err
+	}
+	return !exists, exists, newTag, nil
+}
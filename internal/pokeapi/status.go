@@ -0,0 +1,83 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Status is how a REST API answered a health check.
+type Status struct {
+	URL string
+	// Latency is how long the API took to list its resources.
+	Latency time.Duration
+	// Version is the API's, from the end of its URL, such as "v2".
+	Version string
+	// Resources is how many kinds of resource it lists, and Pokemon how
+	// many pokemon it has.
+	Resources, Pokemon int
+}
+
+// CheckURL reports whether baseUrl can be a REST API's: an http or https
+// URL with a host.
+func CheckURL(baseUrl string) error {
+	u, err := url.Parse(baseUrl)
+	if err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s isn't an http or https URL", baseUrl)
+	}
+	return nil
+}
+
+// CheckStatus asks the REST API at baseUrl for its list of resources, and
+// how many pokemon it has, bypassing any cache. It's an error if the API
+// doesn't answer like PokeAPI.
+func CheckStatus(baseUrl string) (Status, error) {
+	if err := CheckURL(baseUrl); err != nil {
+		return Status{}, err
+	}
+	if !strings.HasSuffix(baseUrl, "/") {
+		baseUrl += "/"
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	st := Status{URL: baseUrl, Version: path.Base(strings.TrimSuffix(baseUrl, "/"))}
+
+	start := time.Now()
+	var root map[string]string
+	if err := getJSON(client, baseUrl, &root); err != nil {
+		return st, err
+	}
+	st.Latency = time.Since(start)
+	st.Resources = len(root)
+	if _, ok := root["pokemon"]; !ok {
+		return st, fmt.Errorf("%s doesn't list pokemon, so it isn't PokeAPI", baseUrl)
+	}
+
+	var pokemon struct {
+		Count int `json:"count"`
+	}
+	if err := getJSON(client, baseUrl+"pokemon?limit=1", &pokemon); err != nil {
+		return st, err
+	}
+	st.Pokemon = pokemon.Count
+	return st, nil
+}
+
+func getJSON(client *http.Client, url string, v any) error {
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", url, res.Status)
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return errors.New(url + " didn't answer with PokeAPI's JSON")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package pokeapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// TraceHeader carries a trace's ID on the requests made for it, so they can
+// be found in a proxy's or the server's logs.
+const TraceHeader = "X-Trace-Id"
+
+// Trace follows the fetches made for one command.
+type Trace struct {
+	ID string
+
+	mu              sync.Mutex
+	fetches, cached int
+}
+
+// NewTrace starts a trace with a random ID.
+func NewTrace() *Trace {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &Trace{ID: hex.EncodeToString(id)}
+}
+
+// record counts a fetch. It does nothing on a nil trace.
+func (t *Trace) record(cached bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fetches++
+	if cached {
+		t.cached++
+	}
+}
+
+// Counts is how many resources were fetched, and how many of them came from
+// a cache.
+func (t *Trace) Counts() (fetches, cached int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.fetches, t.cached
+}
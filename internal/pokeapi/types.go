@@ -0,0 +1,353 @@
+package pokeapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Location struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type LocationResponse struct {
+	Count     int        `json:"count"`
+	Next      string     `json:"next"`
+	Previous  string     `json:"previous"`
+	Locations []Location `json:"results"`
+}
+
+type Pokemon struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type Version struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type EncounterMethod struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type EncounterDetail struct {
+	MinLevel int             `json:"min_level"`
+	MaxLevel int             `json:"max_level"`
+	Chance   int             `json:"chance"`
+	Method   EncounterMethod `json:"method"`
+}
+
+type VersionEncounterDetail struct {
+	Version          Version           `json:"version"`
+	MaxChance        int               `json:"max_chance"`
+	EncounterDetails []EncounterDetail `json:"encounter_details"`
+}
+
+type PokemonEncounter struct {
+	Pokemon        Pokemon                  `json:"pokemon"`
+	VersionDetails []VersionEncounterDetail `json:"version_details"`
+}
+
+type LocationDetailsResponse struct {
+	Name              string             `json:"name"`
+	Location          Location           `json:"location"`
+	PokemonEncounters []PokemonEncounter `json:"pokemon_encounters"`
+}
+
+type Stat struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+type BerryFlavor struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// NatureDetail is a nature. Neutral natures raise and lower nothing, so their
+// stats and flavors are empty.
+type NatureDetail struct {
+	Name          string      `json:"name"`
+	IncreasedStat Stat        `json:"increased_stat"`
+	DecreasedStat Stat        `json:"decreased_stat"`
+	LikesFlavor   BerryFlavor `json:"likes_flavor"`
+	HatesFlavor   BerryFlavor `json:"hates_flavor"`
+}
+
+type StatDetail struct {
+	BaseStat int `json:"base_stat"`
+	// Effort is the EVs knocking the pokemon out gives in this stat.
+	Effort int  `json:"effort"`
+	Stat   Stat `json:"stat"`
+}
+
+type Type struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type TypeDetails struct {
+	Slot int  `json:"slot"`
+	Type Type `json:"type"`
+}
+type PokemonType struct {
+	Name           string           `json:"name"`
+	Species        Species          `json:"species"`
+	Height         int              `json:"height"`
+	Weight         int              `json:"weight"`
+	Stats          []StatDetail     `json:"stats"`
+	Types          []TypeDetails    `json:"types"`
+	BaseExperience int              `json:"base_experience"`
+	Abilities      []AbilityDetails `json:"abilities"`
+	Sprites        Sprites          `json:"sprites,omitzero"`
+	Cries          Cries            `json:"cries,omitzero"`
+}
+
+// Cries are the URLs of a pokemon's cry, as Ogg Vorbis files: as in the
+// newest games, and as in the oldest it was in.
+type Cries struct {
+	Latest string `json:"latest"`
+	Legacy string `json:"legacy"`
+}
+
+// Sprites are the URLs of a pokemon's pictures, PNGs of 96 by 96 pixels.
+type Sprites struct {
+	FrontDefault string `json:"front_default"`
+	FrontShiny   string `json:"front_shiny"`
+}
+
+type Ability struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type AbilityDetails struct {
+	Ability  Ability `json:"ability"`
+	IsHidden bool    `json:"is_hidden"`
+	Slot     int     `json:"slot"`
+}
+
+type Species struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type PokemonSpecies struct {
+	// ID is the species' national pokedex number.
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	CaptureRate int    `json:"capture_rate"`
+	IsLegendary bool   `json:"is_legendary"`
+	IsMythical  bool   `json:"is_mythical"`
+	// GenderRate is the chance of being female in eighths, or -1 for
+	// genderless species.
+	GenderRate int        `json:"gender_rate"`
+	EggGroups  []EggGroup `json:"egg_groups"`
+	// GrowthRate is the curve of experience it needs to level up, such as
+	// "medium-slow".
+	GrowthRate GrowthRate `json:"growth_rate"`
+	// Habitat is where the species lives, such as "forest", and Shape its
+	// body plan, such as "quadruped". Species from later generations have
+	// no habitat.
+	Habitat Habitat `json:"habitat"`
+	Shape   Shape   `json:"shape"`
+	// Varieties are the pokemon of the species: its default form and any
+	// regional, mega or gigantamax ones.
+	Varieties []Variety `json:"varieties"`
+	// Names, Genera and FlavorTextEntries are in every language PokeAPI
+	// has them in; flavor text has an entry per game.
+	Names             []LocalizedName `json:"names"`
+	Genera            []Genus         `json:"genera"`
+	FlavorTextEntries []FlavorText    `json:"flavor_text_entries"`
+}
+
+type Language struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type LocalizedName struct {
+	Name     string   `json:"name"`
+	Language Language `json:"language"`
+}
+
+// Genus is what the pokedex calls a species, such as "Lizard Pokémon".
+type Genus struct {
+	Genus    string   `json:"genus"`
+	Language Language `json:"language"`
+}
+
+type FlavorText struct {
+	FlavorText string   `json:"flavor_text"`
+	Language   Language `json:"language"`
+}
+
+type Variety struct {
+	IsDefault bool    `json:"is_default"`
+	Pokemon   Pokemon `json:"pokemon"`
+}
+
+type GrowthRate struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type EggGroup struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type EggGroupDetail struct {
+	Name           string    `json:"name"`
+	PokemonSpecies []Species `json:"pokemon_species"`
+}
+
+type Habitat struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type HabitatDetail struct {
+	Name           string    `json:"name"`
+	PokemonSpecies []Species `json:"pokemon_species"`
+}
+
+type Shape struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type ShapeDetail struct {
+	Name           string    `json:"name"`
+	PokemonSpecies []Species `json:"pokemon_species"`
+}
+
+type Region struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// LocationDetail is a place on the map, made of one or more location areas.
+type LocationDetail struct {
+	Name   string     `json:"name"`
+	Region Region     `json:"region"`
+	Areas  []Location `json:"areas"`
+}
+
+type RegionDetail struct {
+	Name      string     `json:"name"`
+	Locations []Location `json:"locations"`
+}
+
+type VersionGroup struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// ID is the version group's ID, taken from its URL. Newer games have
+// higher IDs.
+func (v VersionGroup) ID() int {
+	return urlID(v.Url)
+}
+
+type Move struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type Item struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type MoveDamageClass struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type MoveTarget struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type MoveLearnMethod struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type ItemCategory struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// MachineReference links to a machine, which has no name of its own.
+type MachineReference struct {
+	Url string `json:"url"`
+}
+
+// MachineVersion is the machine that teaches a move, or that an item is, in
+// one version group.
+type MachineVersion struct {
+	Machine      MachineReference `json:"machine"`
+	VersionGroup VersionGroup     `json:"version_group"`
+}
+
+// ID is the machine's ID, taken from its URL.
+func (m MachineVersion) ID() int {
+	return urlID(m.Machine.Url)
+}
+
+// urlID is the ID at the end of a resource URL, or 0 if there isn't one.
+func urlID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, _ := strconv.Atoi(parts[len(parts)-1])
+	return id
+}
+
+// MachineDetail is a TM, TR or HM in one version group.
+type MachineDetail struct {
+	ID           int          `json:"id"`
+	Item         Item         `json:"item"`
+	Move         Move         `json:"move"`
+	VersionGroup VersionGroup `json:"version_group"`
+}
+
+// MoveDetail is a move. Power and accuracy are 0 for moves that have none.
+type MoveDetail struct {
+	Name        string           `json:"name"`
+	Power       int              `json:"power"`
+	Accuracy    int              `json:"accuracy"`
+	PP          int              `json:"pp"`
+	Type        Type             `json:"type"`
+	DamageClass MoveDamageClass  `json:"damage_class"`
+	Target      MoveTarget       `json:"target"`
+	Machines    []MachineVersion `json:"machines"`
+	// ContestType is the contest category the move appeals in, such as
+	// "cool".
+	ContestType ContestType `json:"contest_type"`
+}
+
+type ContestType struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type ItemDetail struct {
+	Name     string           `json:"name"`
+	Cost     int              `json:"cost"`
+	Category ItemCategory     `json:"category"`
+	Machines []MachineVersion `json:"machines"`
+}
+
+// PokemonMove is a move a pokemon can learn, and how, in each version group.
+type PokemonMove struct {
+	Move                Move                `json:"move"`
+	VersionGroupDetails []MoveVersionDetail `json:"version_group_details"`
+}
+
+type MoveVersionDetail struct {
+	LevelLearnedAt  int             `json:"level_learned_at"`
+	MoveLearnMethod MoveLearnMethod `json:"move_learn_method"`
+	VersionGroup    VersionGroup    `json:"version_group"`
+}
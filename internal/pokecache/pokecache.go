@@ -1,58 +1,407 @@
+// Package pokecache keeps API responses, and other downloads, in memory for
+// a while, so pages and pokemon looked at again don't go back to the
+// network.
 package pokecache
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"maps"
+	"strings"
 	"sync"
 	"time"
 )
 
+// Namespace keeps one kind of entry apart from the others, with a ttl of
+// its own, so it can be cleared without clearing the rest.
+type Namespace string
+
+const (
+	API       Namespace = "api"
+	Sprites   Namespace = "sprites"
+	Audio     Namespace = "audio"
+	Snapshots Namespace = "snapshots"
+)
+
+// Namespaces are all of them.
+var Namespaces = []Namespace{API, Sprites, Audio, Snapshots}
+
+// fullKey is where key in ns is kept. Invalidate takes its prefixes.
+func fullKey(ns Namespace, key string) string {
+	return string(ns) + ":" + key
+}
+
 type cacheEntry struct {
+	ns        Namespace
+	key       string
 	createdAt time.Time
+	lastUsed  time.Time
 	val       []byte
+	// size is the length of the value before compression; val is gzipped
+	// when it's shorter.
+	size       int
+	compressed bool
+}
+
+// Stats are the numbers behind a Cache.
+type Stats struct {
+	Entries int
+	// Bytes are held in memory for Size bytes of values; they differ by
+	// what compression saved.
+	Bytes, Size int64
+	// Limit is the most Bytes can be before entries are evicted, 0 for no
+	// limit.
+	Limit                   int64
+	Hits, Misses, Evictions int
+	// ByNamespace counts the entries of each namespace that has any.
+	ByNamespace map[Namespace]int
 }
 
 type Cache struct {
-	mu    sync.RWMutex
+	mu    sync.Mutex
 	cache map[string]cacheEntry
+	// limit bounds the bytes held, evicting the least recently used entries
+	// past it; 0 is no limit. Values of compressAbove bytes or more are
+	// gzipped; 0 compresses nothing.
+	limit         int64
+	compressAbove int
+	stats         Stats
+	// ttl is how long entries live, unless ttls has one for their
+	// namespace. API entries read since they were stored are fetched again
+	// with refresh in their last fifth, so they're never missing while in
+	// use; refreshing has the keys being fetched.
+	ttl        time.Duration
+	ttls       map[Namespace]time.Duration
+	refresh    func(key string) ([]byte, error)
+	refreshing map[string]bool
+	// done stops the reaper when closed; stopped is closed once it has.
+	done, stopped chan struct{}
+	closeOnce     sync.Once
 }
 
-func (p *Cache) Add(key string, value []byte) {
+// SetTTL keeps entries of ns for ttl rather than the cache's interval.
+func (p *Cache) SetTTL(ns Namespace, ttl time.Duration) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.cache[key] = cacheEntry{
-		createdAt: time.Now(),
-		val:       value,
+	p.ttls[ns] = ttl
+}
+
+// ttlFor is how long entries of ns live. The caller holds mu.
+func (p *Cache) ttlFor(ns Namespace) time.Duration {
+	if ttl, ok := p.ttls[ns]; ok {
+		return ttl
 	}
+	return p.ttl
+}
+
+// RefreshWith has API entries in use fetched again with fetch in the
+// background shortly before they expire, rather than missed afterwards.
+func (p *Cache) RefreshWith(fetch func(key string) ([]byte, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refresh = fetch
 }
 
+// Limit caps the bytes the cache holds at maxBytes, 0 for no cap.
+func (p *Cache) Limit(maxBytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = maxBytes
+	p.evict()
+}
+
+// Compress gzips values of minSize bytes or more from now on, as large JSON
+// bodies shrink several times over; 0 turns it off.
+func (p *Cache) Compress(minSize int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.compressAbove = minSize
+}
+
+// Add keeps an API response.
+func (p *Cache) Add(key string, value []byte) {
+	p.AddTo(API, key, value)
+}
+
+// Get returns an API response, if it's kept.
 func (p *Cache) Get(key string) ([]byte, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	val, ok := p.cache[key]
+	return p.GetFrom(API, key)
+}
+
+// AddTo keeps value under key in ns.
+func (p *Cache) AddTo(ns Namespace, key string, value []byte) {
+	entry := cacheEntry{ns: ns, key: key, val: value, size: len(value)}
+	p.mu.Lock()
+	compressAbove := p.compressAbove
+	p.mu.Unlock()
+	if compressAbove > 0 && len(value) >= compressAbove {
+		if packed, err := compress(value); err == nil && len(packed) < len(value) {
+			entry.val, entry.compressed = packed, true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	full := fullKey(ns, key)
+	p.remove(full)
+	entry.createdAt = time.Now()
+	entry.lastUsed = entry.createdAt
+	p.cache[full] = entry
+	p.stats.Bytes += entryBytes(entry)
+	p.stats.Size += int64(entry.size)
+	p.evict()
+}
+
+// GetFrom returns what's kept under key in ns, unless it has expired.
+func (p *Cache) GetFrom(ns Namespace, key string) ([]byte, bool) {
+	p.mu.Lock()
+	full := fullKey(ns, key)
+	entry, ok := p.cache[full]
+	if ok && time.Since(entry.createdAt) > p.ttlFor(ns) {
+		p.remove(full)
+		ok = false
+	}
 	if !ok {
+		p.stats.Misses++
+		p.mu.Unlock()
 		return nil, false
 	}
-	return val.val, true
+	p.stats.Hits++
+	entry.lastUsed = time.Now()
+	p.cache[full] = entry
+	p.mu.Unlock()
+	if !entry.compressed {
+		return entry.val, true
+	}
+	val, err := decompress(entry.val)
+	if err != nil {
+		return nil, false
+	}
+	return val, true
 }
 
-func (p *Cache) reapLoop(interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// Stats returns the cache's numbers so far.
+func (p *Cache) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Entries, stats.Limit = len(p.cache), p.limit
+	stats.ByNamespace = map[Namespace]int{}
+	for _, entry := range p.cache {
+		stats.ByNamespace[entry.ns]++
+	}
+	return stats
+}
+
+// Invalidate drops every entry whose namespace and key, written as
+// "namespace:key", start with prefix, and says how many there were: "api"
+// drops all API responses, "api:https://pokeapi.co/api/v2/pokemon/" just
+// pokemon.
+func (p *Cache) Invalidate(prefix string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for full := range p.cache {
+		if strings.HasPrefix(full, prefix) {
+			p.remove(full)
+			n++
+		}
+	}
+	return n
+}
+
+// Remove drops what's kept under key in ns.
+func (p *Cache) Remove(ns Namespace, key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(fullKey(ns, key))
+}
 
-	for range ticker.C {
-		p.mu.Lock()
+// entryBytes is what an entry takes up, counting its key.
+func entryBytes(entry cacheEntry) int64 {
+	return int64(len(entry.key) + len(entry.val))
+}
+
+// remove deletes the entry kept at full, if there is one. The caller holds
+// mu.
+func (p *Cache) remove(full string) {
+	if entry, ok := p.cache[full]; ok {
+		delete(p.cache, full)
+		p.stats.Bytes -= entryBytes(entry)
+		p.stats.Size -= int64(entry.size)
+	}
+}
+
+// evict drops the least recently used entries until the cache is within
+// its limit. The caller holds mu.
+func (p *Cache) evict() {
+	for p.limit > 0 && p.stats.Bytes > p.limit && len(p.cache) > 0 {
+		oldest, first := "", true
 		for key, entry := range p.cache {
-			if time.Since(entry.createdAt) > interval {
-				delete(p.cache, key)
+			if first || entry.lastUsed.Before(p.cache[oldest].lastUsed) {
+				oldest, first = key, false
 			}
 		}
-		p.mu.Unlock()
+		p.remove(oldest)
+		p.stats.Evictions++
 	}
 }
 
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// refreshAhead is the part of the ttl before expiry in which entries in
+// use are refreshed, and how often the reaper looks.
+const refreshAhead = 5
+
+func (p *Cache) reapLoop() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(p.ttl / refreshAhead)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.reap()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Close stops the reaper, waiting until it has. Entries are still kept, and
+// expire when they're read, but none are refreshed. Closing twice is fine.
+func (p *Cache) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+	<-p.stopped
+}
+
+// reap drops expired entries and starts refreshing those in use that are
+// about to expire.
+func (p *Cache) reap() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for full, entry := range p.cache {
+		age, ttl := time.Since(entry.createdAt), p.ttlFor(entry.ns)
+		switch {
+		case age > ttl:
+			p.remove(full)
+		case entry.ns == API && p.refresh != nil && age > ttl-ttl/refreshAhead && entry.lastUsed.After(entry.createdAt) && !p.refreshing[full]:
+			p.refreshing[full] = true
+			go p.refreshEntry(entry.key, p.refresh)
+		}
+	}
+}
+
+func (p *Cache) refreshEntry(key string, fetch func(string) ([]byte, error)) {
+	data, err := fetch(key)
+	if err == nil {
+		p.Add(key, data)
+	}
+	p.mu.Lock()
+	delete(p.refreshing, fullKey(API, key))
+	p.mu.Unlock()
+}
+
+// NewCache keeps entries for interval, unless their namespace is given a
+// ttl of its own. A goroutine drops them when they expire until the cache is
+// closed.
 func NewCache(interval time.Duration) *Cache {
 	cache := &Cache{
-		cache: make(map[string]cacheEntry),
+		cache:      make(map[string]cacheEntry),
+		ttl:        interval,
+		ttls:       make(map[Namespace]time.Duration),
+		refreshing: make(map[string]bool),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
 	}
-	go cache.reapLoop(interval)
+	go cache.reapLoop()
 	return cache
 }
+
+// maxObjects is how many decoded values Objects keeps at most.
+const maxObjects = 1024
+
+type objectEntry struct {
+	createdAt time.Time
+	val       any
+}
+
+// Objects is a second level over Cache: values already decoded from the
+// bytes it keeps, by resource, so a large payload asked for again isn't
+// unmarshaled again. Entries expire after ttl, checked when they're read,
+// and the oldest go first once there are maxObjects.
+type Objects struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]objectEntry
+}
+
+func NewObjects(ttl time.Duration) *Objects {
+	return &Objects{ttl: ttl, entries: make(map[string]objectEntry)}
+}
+
+func (o *Objects) Add(key string, value any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.entries) >= maxObjects {
+		oldest := ""
+		for k, entry := range o.entries {
+			if time.Since(entry.createdAt) > o.ttl {
+				delete(o.entries, k)
+			} else if oldest == "" || entry.createdAt.Before(o.entries[oldest].createdAt) {
+				oldest = k
+			}
+		}
+		if len(o.entries) >= maxObjects {
+			delete(o.entries, oldest)
+		}
+	}
+	o.entries[key] = objectEntry{createdAt: time.Now(), val: value}
+}
+
+func (o *Objects) Get(key string) (any, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entry, ok := o.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.createdAt) > o.ttl {
+		delete(o.entries, key)
+		return nil, false
+	}
+	return entry.val, true
+}
+
+// RemoveFunc drops the values whose keys match.
+func (o *Objects) RemoveFunc(match func(key string) bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	maps.DeleteFunc(o.entries, func(key string, _ objectEntry) bool { return match(key) })
+}
+
+// Len is how many values are kept.
+func (o *Objects) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
@@ -1,6 +1,8 @@
 package pokecache
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,3 +13,148 @@ func TestNewCache(t *testing.T) {
 		t.Errorf("NewCache() returned nil")
 	}
 }
+
+func TestCacheClose(t *testing.T) {
+	cache := NewCache(5 * time.Second)
+	cache.Close()
+	select {
+	case <-cache.stopped:
+	default:
+		t.Errorf("Expected Close to stop the reaper")
+	}
+	cache.Close()
+}
+
+func TestObjects(t *testing.T) {
+	objects := NewObjects(time.Hour)
+	objects.Add("pokemon/pikachu", 25)
+	if v, ok := objects.Get("pokemon/pikachu"); !ok || v != 25 {
+		t.Errorf("Expected the value added, got %v, %v", v, ok)
+	}
+	for i := range maxObjects {
+		objects.Add(string(rune('a'+i)), i)
+	}
+	if _, ok := objects.Get("pokemon/pikachu"); ok {
+		t.Error("Expected the oldest value to make room")
+	}
+
+	expiring := NewObjects(time.Millisecond)
+	expiring.Add("pokemon/pikachu", 25)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.Get("pokemon/pikachu"); ok {
+		t.Error("Expected the value to expire")
+	}
+}
+
+func TestCacheLimit(t *testing.T) {
+	cache := NewCache(time.Hour)
+	defer cache.Close()
+	cache.Limit(100)
+	cache.Add("a", make([]byte, 40))
+	cache.Add("b", make([]byte, 40))
+	cache.Get("a")
+	cache.Add("c", make([]byte, 40))
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected the entry just read to be kept")
+	}
+	st := cache.Stats()
+	if st.Entries != 2 || st.Bytes != 82 || st.Evictions != 1 || st.Hits != 2 || st.Misses != 1 {
+		t.Errorf("Expected 2 entries of 82 bytes after 1 eviction, 2 hits and a miss, got %+v", st)
+	}
+}
+
+func TestCacheCompress(t *testing.T) {
+	cache := NewCache(time.Hour)
+	defer cache.Close()
+	cache.Compress(1024)
+	body := []byte(strings.Repeat(`{"name": "pikachu"}`, 1000))
+	cache.Add("pikachu", body)
+	got, ok := cache.Get("pikachu")
+	if !ok || !bytes.Equal(got, body) {
+		t.Errorf("Expected the body back as it was")
+	}
+	if st := cache.Stats(); st.Bytes >= st.Size/4 || st.Size != int64(len(body)) {
+		t.Errorf("Expected the body compressed, got %+v", st)
+	}
+}
+
+func TestCacheRefreshesEntriesInUse(t *testing.T) {
+	cache := NewCache(time.Hour)
+	defer cache.Close()
+	refreshed := make(chan string, 2)
+	cache.RefreshWith(func(key string) ([]byte, error) {
+		refreshed <- key
+		return []byte("fresh"), nil
+	})
+	cache.Add("hot", []byte("stale"))
+	cache.Add("cold", []byte("stale"))
+	cache.Get("hot")
+
+	// Age the entries into the last fifth of their time.
+	cache.mu.Lock()
+	for full, entry := range cache.cache {
+		entry.createdAt = entry.createdAt.Add(-55 * time.Minute)
+		entry.lastUsed = entry.createdAt
+		if entry.key == "hot" {
+			entry.lastUsed = time.Now()
+		}
+		cache.cache[full] = entry
+	}
+	cache.mu.Unlock()
+	cache.reap()
+
+	select {
+	case key := <-refreshed:
+		if key != "hot" {
+			t.Errorf("Expected only the entry in use refreshed, got %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the entry in use to be refreshed")
+	}
+	for {
+		if got, _ := cache.Get("hot"); string(got) == "fresh" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, _ := cache.Get("cold"); string(got) != "stale" || len(refreshed) != 0 {
+		t.Errorf("Expected the unused entry left alone, got %q", got)
+	}
+}
+
+func TestCacheNamespaces(t *testing.T) {
+	cache := NewCache(time.Hour)
+	defer cache.Close()
+	cache.SetTTL(Sprites, time.Millisecond)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("pikachu"))
+	cache.Add("https://pokeapi.co/api/v2/item/potion", []byte("potion"))
+	cache.AddTo(Sprites, "https://pokeapi.co/api/v2/pokemon/pikachu", []byte("png"))
+	cache.AddTo(Audio, "25.ogg", []byte("ogg"))
+
+	if got, _ := cache.GetFrom(Sprites, "https://pokeapi.co/api/v2/pokemon/pikachu"); string(got) != "png" {
+		t.Errorf("Expected namespaces to keep the same key apart, got %q", got)
+	}
+	if st := cache.Stats(); st.ByNamespace[API] != 2 || st.ByNamespace[Sprites] != 1 {
+		t.Errorf("Expected entries counted by namespace, got %v", st.ByNamespace)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.GetFrom(Sprites, "https://pokeapi.co/api/v2/pokemon/pikachu"); ok {
+		t.Error("Expected the sprite to expire with its namespace's ttl")
+	}
+
+	if n := cache.Invalidate("api:https://pokeapi.co/api/v2/pokemon/"); n != 1 {
+		t.Errorf("Expected one pokemon dropped, got %d", n)
+	}
+	if _, ok := cache.Get("https://pokeapi.co/api/v2/item/potion"); !ok {
+		t.Error("Expected the item kept")
+	}
+	if n := cache.Invalidate(string(Audio)); n != 1 {
+		t.Errorf("Expected the audio namespace cleared, got %d", n)
+	}
+	if _, ok := cache.Get("https://pokeapi.co/api/v2/item/potion"); !ok {
+		t.Error("Expected clearing audio to leave API responses")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package profile
+
+import "os"
+
+// lockFile does nothing on systems without flock or LockFileEx, such as
+// Solaris and WebAssembly: profiles aren't locked there.
+func lockFile(f *os.File) error {
+	return nil
+}
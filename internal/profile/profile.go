@@ -0,0 +1,562 @@
+// Package profile holds a player's saved game and stores it on disk.
+package profile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const PartySize = 6
+
+// Pokemon is one caught pokemon. A player can own several of a species.
+type Pokemon struct {
+	ID      int    `json:"id"`
+	Species string `json:"species"`
+	// Nickname is the name the player gave it, if any.
+	Nickname string `json:"nickname,omitempty"`
+	Level    int    `json:"level"`
+	// Gender is "male", "female" or, for genderless species, empty.
+	Gender string `json:"gender,omitempty"`
+	// Nature raises one of its stats and lowers another; pokemon caught
+	// before natures existed have none.
+	Nature string `json:"nature,omitempty"`
+	// IVs are its individual values by stat, 0 to 31, set when it's
+	// caught; pokemon caught before IVs existed have none.
+	IVs map[string]int `json:"ivs,omitempty"`
+	// EVs are its effort values by stat, earned by knocking pokemon out
+	// and from vitamins.
+	EVs   map[string]int `json:"evs,omitempty"`
+	Shiny bool           `json:"shiny,omitempty"`
+	// Friendship grows as it's fed berries, up to 255.
+	Friendship int `json:"friendship,omitempty"`
+	// OT is the original trainer, the player who caught it; pokemon caught
+	// before it was recorded have none.
+	OT string `json:"ot,omitempty"`
+	// Damage is the HP it's missing since its last battle; it has fainted
+	// once that's all of it.
+	Damage int `json:"damage,omitempty"`
+	// Exp is the total experience earned; 0 until the first battle.
+	Exp int `json:"exp,omitempty"`
+	// Item is the held item, taken out of the bag.
+	Item string `json:"item,omitempty"`
+	// Moves are the moves it was taught; until then it fights with
+	// battle.DefaultMoves.
+	Moves []battle.Move `json:"moves,omitempty"`
+	// Condition is its contest condition by category, such as "cool", 0 to
+	// 255, raised by feeding it berries.
+	Condition map[string]int `json:"condition,omitempty"`
+	// Ribbons are the ribbons it has won, such as "cool-ribbon".
+	Ribbons []string `json:"ribbons,omitempty"`
+	// Mark is the rare mark it may have been caught with, such as
+	// "dusk-mark".
+	Mark string `json:"mark,omitempty"`
+	// Met is where and when it was caught; pokemon caught before it was
+	// recorded have none.
+	Met *Met `json:"met,omitempty"`
+}
+
+// Met is where and when a pokemon was caught or received.
+type Met struct {
+	// Area is the location area, empty if the player hadn't set off.
+	Area string `json:"area,omitempty"`
+	// Ball is the ball it was caught in; starters have none.
+	Ball  string    `json:"ball,omitempty"`
+	Level int       `json:"level"`
+	Time  time.Time `json:"time"`
+}
+
+// Limits a pokemon is held to, to be one that could have been caught and
+// raised in the game.
+const (
+	maxLevel     = 100
+	maxIV        = 31
+	maxStatEVs   = 252
+	maxTotalEVs  = 510
+	maxHappiness = 255
+	maxCondition = 255
+	maxMoves     = 4
+	maxNickname  = 12
+)
+
+// Validate checks that mon could have been caught and raised in the game,
+// for pokemon that come from other players.
+func (mon Pokemon) Validate() error {
+	switch {
+	case mon.Species == "":
+		return errors.New("missing species")
+	case mon.Level < 1 || mon.Level > maxLevel:
+		return fmt.Errorf("level %d is out of range", mon.Level)
+	case mon.Friendship < 0 || mon.Friendship > maxHappiness:
+		return fmt.Errorf("friendship %d is out of range", mon.Friendship)
+	case len(mon.Moves) > maxMoves:
+		return fmt.Errorf("it knows %d moves; pokemon know %d at most", len(mon.Moves), maxMoves)
+	case utf8.RuneCountInString(mon.Nickname) > maxNickname:
+		return fmt.Errorf("nickname %q is longer than %d letters", mon.Nickname, maxNickname)
+	}
+	for stat, iv := range mon.IVs {
+		if iv < 0 || iv > maxIV {
+			return fmt.Errorf("%s IV %d is out of range", stat, iv)
+		}
+	}
+	total := 0
+	for stat, ev := range mon.EVs {
+		if ev < 0 || ev > maxStatEVs {
+			return fmt.Errorf("%s EVs %d are out of range", stat, ev)
+		}
+		total += ev
+	}
+	if total > maxTotalEVs {
+		return fmt.Errorf("%d EVs in all is more than %d", total, maxTotalEVs)
+	}
+	for category, c := range mon.Condition {
+		if c < 0 || c > maxCondition {
+			return fmt.Errorf("%s condition %d is out of range", category, c)
+		}
+	}
+	return nil
+}
+
+type Profile struct {
+	Name string `json:"name"`
+	// Pokedex has the species data of everything caught so far.
+	Pokedex map[string]pokeapi.PokemonType `json:"pokedex"`
+	// Seen has every pokemon met in the wild or found exploring, caught or
+	// not.
+	Seen    map[string]bool `json:"seen"`
+	Pokemon []Pokemon       `json:"pokemon"`
+	// Party lists the IDs of the pokemon travelling with the player, lead
+	// first.
+	Party   []int  `json:"party"`
+	Starter string `json:"starter,omitempty"`
+	NextID  int    `json:"next_id"`
+	// Inventory counts the items in the bag by PokeAPI item name.
+	Inventory map[string]int `json:"inventory"`
+	Money     int            `json:"money"`
+	// ExpShare is whether the Exp. Share, if the player has one, is on.
+	ExpShare bool `json:"exp_share,omitempty"`
+	// Visited has every location area the player has explored.
+	Visited map[string]bool `json:"visited"`
+	// Location is the area the player is in, empty before they set off.
+	Location string `json:"location,omitempty"`
+	// Steps counts how far the player has walked.
+	Steps int `json:"steps"`
+	// Quests has the progress made on the current quests, by quest ID.
+	Quests map[string]int `json:"quests"`
+	// Combo counts the catches of ComboSpecies in a row; catching anything
+	// else or letting a pokemon flee ends it.
+	ComboSpecies string `json:"combo_species,omitempty"`
+	Combo        int    `json:"combo,omitempty"`
+	// Plots are the berries planted, oldest first.
+	Plots []Plot `json:"plots,omitempty"`
+	// Commands counts the commands run; roaming pokemon and berries move on
+	// with it.
+	Commands int `json:"commands,omitempty"`
+	// RoamersCaught are the roaming pokemon caught, which roam no more.
+	RoamersCaught []string `json:"roamers_caught,omitempty"`
+	// HallOfFame has the teams that beat the Elite Four or won a tournament,
+	// oldest first.
+	HallOfFame []HallOfFameEntry `json:"hall_of_fame,omitempty"`
+	// Rivals has the player's record against each other profile they've
+	// battled, by name.
+	Rivals map[string]Rivalry `json:"rivals,omitempty"`
+	// LastPlayed is the last day the player played, as 2006-01-02, and
+	// Streak how many days in a row they had then.
+	LastPlayed string `json:"last_played,omitempty"`
+	Streak     int    `json:"streak,omitempty"`
+	// LadderSecrets has the secret each ladder server gave the player for
+	// their name, by the server's URL.
+	LadderSecrets map[string]string `json:"ladder_secrets,omitempty"`
+}
+
+// Rivalry is how battles against another profile have gone.
+type Rivalry struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+// Plot is a berry planted in the player's garden.
+type Plot struct {
+	Berry string `json:"berry"`
+	// Command and Time are when it was planted: the value of Commands then,
+	// and the clock time.
+	Command int       `json:"command"`
+	Time    time.Time `json:"time"`
+}
+
+// Traded reports whether p was caught by another trainer.
+func (p *Profile) Traded(mon *Pokemon) bool {
+	return mon.OT != "" && mon.OT != p.Name
+}
+
+type HallOfFameEntry struct {
+	Time time.Time `json:"time"`
+	// Event is what the team won; it's empty for the Elite Four.
+	Event string    `json:"event,omitempty"`
+	Team  []Pokemon `json:"team"`
+}
+
+// StartingItems is the bag every new trainer sets out with.
+var StartingItems = map[string]int{
+	"poke-ball":  10,
+	"great-ball": 3,
+}
+
+func New(name string) *Profile {
+	return &Profile{
+		Name:      name,
+		Pokedex:   map[string]pokeapi.PokemonType{},
+		Seen:      map[string]bool{},
+		Pokemon:   []Pokemon{},
+		Party:     []int{},
+		NextID:    1,
+		Inventory: maps.Clone(StartingItems),
+		Visited:   map[string]bool{},
+		Quests:    map[string]int{},
+	}
+}
+
+// Visit marks area as explored, reporting whether this was the first time.
+func (p *Profile) Visit(area string) bool {
+	if p.Visited[area] {
+		return false
+	}
+	p.Visited[area] = true
+	return true
+}
+
+// CheckIn records playing on the day of t, in t's time zone. It reports the
+// streak of days in a row played, and whether t is the first time today.
+func (p *Profile) CheckIn(t time.Time) (int, bool) {
+	today := t.Format(time.DateOnly)
+	if p.LastPlayed == today {
+		return p.Streak, false
+	}
+	if p.LastPlayed == t.AddDate(0, 0, -1).Format(time.DateOnly) {
+		p.Streak++
+	} else {
+		p.Streak = 1
+	}
+	p.LastPlayed = today
+	return p.Streak, true
+}
+
+// See marks the pokemon as seen, reporting whether it's the first time.
+func (p *Profile) See(name string) bool {
+	if p.Seen[name] {
+		return false
+	}
+	p.Seen[name] = true
+	return true
+}
+
+// Use takes one of item out of the bag, reporting false if there was none.
+func (p *Profile) Use(item string) bool {
+	if p.Inventory[item] <= 0 {
+		return false
+	}
+	p.Inventory[item]--
+	if p.Inventory[item] == 0 {
+		delete(p.Inventory, item)
+	}
+	return true
+}
+
+// Give puts n of item in the bag.
+func (p *Profile) Give(item string, n int) {
+	p.Inventory[item] += n
+}
+
+// Add records a newly caught pokemon, putting it in the party if there is
+// room.
+func (p *Profile) Add(species pokeapi.PokemonType, level int) Pokemon {
+	caught := Pokemon{ID: p.NextID, Species: species.Name, Level: level, OT: p.Name}
+	p.NextID++
+	p.Pokedex[species.Name] = species
+	p.See(species.Name)
+	p.Pokemon = append(p.Pokemon, caught)
+	if len(p.Party) < PartySize {
+		p.Party = append(p.Party, caught.ID)
+	}
+	return caught
+}
+
+// Receive adds a pokemon traded from another player, with a new ID but
+// otherwise as it was: its trainer, where it was met, its ribbons and its
+// held item. species is its pokedex entry, for a species not caught yet.
+func (p *Profile) Receive(mon Pokemon, species pokeapi.PokemonType) Pokemon {
+	mon.ID = p.NextID
+	p.NextID++
+	if _, ok := p.Pokedex[mon.Species]; !ok {
+		p.Pokedex[mon.Species] = species
+	}
+	p.See(mon.Species)
+	p.Pokemon = append(p.Pokemon, mon)
+	if len(p.Party) < PartySize {
+		p.Party = append(p.Party, mon.ID)
+	}
+	return mon
+}
+
+// Get returns a pointer to the pokemon with the given ID, or nil.
+func (p *Profile) Get(id int) *Pokemon {
+	for i := range p.Pokemon {
+		if p.Pokemon[i].ID == id {
+			return &p.Pokemon[i]
+		}
+	}
+	return nil
+}
+
+// Release lets the pokemon with id go, giving its held item back.
+func (p *Profile) Release(id int) {
+	if mon := p.Get(id); mon != nil && mon.Item != "" {
+		p.Give(mon.Item, 1)
+	}
+	p.Remove(id)
+}
+
+// Remove takes the pokemon with id out of the profile and the party,
+// holding whatever it holds. An empty party is refilled with the first
+// pokemon left.
+func (p *Profile) Remove(id int) {
+	p.Pokemon = slices.DeleteFunc(p.Pokemon, func(other Pokemon) bool { return other.ID == id })
+	p.Party = slices.DeleteFunc(p.Party, func(other int) bool { return other == id })
+	if len(p.Party) == 0 && len(p.Pokemon) > 0 {
+		p.Party = append(p.Party, p.Pokemon[0].ID)
+	}
+}
+
+// PartyPokemon returns the party in order.
+func (p *Profile) PartyPokemon() []*Pokemon {
+	party := []*Pokemon{}
+	for _, id := range p.Party {
+		if member := p.Get(id); member != nil {
+			party = append(party, member)
+		}
+	}
+	return party
+}
+
+// ErrCorrupt is returned by Load for a save that changed after it was
+// written, such as one cut short or with bytes flipped on disk.
+var ErrCorrupt = errors.New("save is damaged")
+
+// checksumField ends every save: the SHA-256 of the file without it, so
+// Load can tell a save is exactly as it was written.
+const checksumField = ",\n  \"checksum\": \""
+
+// ErrLocked is returned by Lock for a profile another process has locked.
+var ErrLocked = errors.New("profile is in use")
+
+// Store keeps one JSON file per profile in a directory.
+type Store struct {
+	dir string
+
+	mu sync.Mutex
+	// locks are the lock files of the profiles this process has locked,
+	// held open for as long as it has them.
+	locks map[string]*os.File
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, locks: map[string]*os.File{}}
+}
+
+// Lock takes the named profile for this process, so another game or server
+// saving it at the same time can't overwrite its saves. It fails with
+// ErrLocked while another process has it; locking it again in this one does
+// nothing. The system lets go of it when the process exits, even if it
+// crashes, so there are never stale locks to clean up.
+func (st *Store) Lock(name string) error {
+	path, err := st.path(name)
+	if err != nil {
+		return err
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.locks[name] != nil {
+		return nil
+	}
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return err
+	}
+	path = strings.TrimSuffix(path, ".json") + ".lock"
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if !errors.Is(err, ErrLocked) {
+			return err
+		}
+		// The file has the process ID of whoever holds the lock.
+		if pid, _ := os.ReadFile(path); len(pid) > 0 {
+			return fmt.Errorf("%w by another pokedexcli, process %s", ErrLocked, pid)
+		}
+		return fmt.Errorf("%w by another pokedexcli", ErrLocked)
+	}
+	f.Truncate(0)
+	f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	st.locks[name] = f
+	return nil
+}
+
+// Unlock lets go of a profile Lock took.
+func (st *Store) Unlock(name string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	f := st.locks[name]
+	if f == nil {
+		return nil
+	}
+	delete(st.locks, name)
+	return f.Close()
+}
+
+func (st *Store) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+	return filepath.Join(st.dir, name+".json"), nil
+}
+
+// Load reads the named profile. A profile that was never saved comes back
+// new, with found set to false.
+func (st *Store) Load(name string) (p *Profile, found bool, err error) {
+	path, err := st.path(name)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(name), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := verify(data); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if p, err = Decode(name, data); err != nil {
+		return nil, false, fmt.Errorf("%s: %w: %v", path, ErrCorrupt, err)
+	}
+	return p, true, nil
+}
+
+// verify checks data against the checksum at its end. Saves from before
+// checksums were written have none and pass.
+func verify(data []byte) error {
+	i := bytes.LastIndex(data, []byte(checksumField))
+	if i < 0 {
+		return nil
+	}
+	sum, rest, ok := bytes.Cut(data[i+len(checksumField):], []byte(`"`))
+	if !ok || string(rest) != "\n}" {
+		return ErrCorrupt
+	}
+	written := sha256.Sum256(append(data[:i:i], rest...))
+	if hex.EncodeToString(written[:]) != string(sum) {
+		return ErrCorrupt
+	}
+	return nil
+}
+
+// SetAside renames the named profile's save out of the way, for a damaged
+// save to be replaced while it's kept to look at. It returns where the save
+// now is.
+func (st *Store) SetAside(name string) (string, error) {
+	path, err := st.path(name)
+	if err != nil {
+		return "", err
+	}
+	return path + ".damaged", os.Rename(path, path+".damaged")
+}
+
+// Decode reads a profile saved as JSON, naming it name.
+func Decode(name string, data []byte) (*Profile, error) {
+	p := New(name)
+	// Unmarshaling merges into maps, so start from an empty bag; saves from
+	// before the bag existed get the starting items.
+	p.Inventory = nil
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Inventory == nil {
+		p.Inventory = maps.Clone(StartingItems)
+	}
+	// Saves from before seen was kept have at least seen what they caught.
+	for caught := range p.Pokedex {
+		p.See(caught)
+	}
+	p.Name = name
+	return p, nil
+}
+
+// List names the saved profiles in order.
+func (st *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(st.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Save writes p to a temporary file and renames it into place, so a crash
+// mid-write never leaves a truncated save behind. The save ends with its
+// checksum, for Load to verify.
+func (st *Store) Save(p *Profile) error {
+	path, err := st.path(p.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	end := len(data) - len("\n}")
+	data = slices.Concat(data[:end], []byte(checksumField+hex.EncodeToString(sum[:])+`"`), data[end:])
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(st.dir, p.Name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,106 @@
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	p, found, err := store.Load("ash")
+	if err != nil || found {
+		t.Fatalf("Load() of a new profile = %v, %v", found, err)
+	}
+
+	for range PartySize + 1 {
+		p.Add(pokeapi.PokemonType{Name: "pidgey"}, 3)
+	}
+	if err := store.Save(p); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded, found, err := store.Load("ash")
+	if err != nil || !found {
+		t.Fatalf("Load() = %v, %v", found, err)
+	}
+	if len(loaded.Pokemon) != PartySize+1 {
+		t.Errorf("Expected %d pokemon, got %d", PartySize+1, len(loaded.Pokemon))
+	}
+	if len(loaded.Party) != PartySize {
+		t.Errorf("Expected a full party of %d, got %d", PartySize, len(loaded.Party))
+	}
+	if names, err := store.List(); err != nil || !slices.Equal(names, []string{"ash"}) {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+}
+
+func TestStoreVerifiesChecksum(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	p := New("ash")
+	p.Money = 1234
+	if err := store.Save(p); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	path := filepath.Join(dir, "ash.json")
+	data, _ := os.ReadFile(path)
+
+	damaged := map[string][]byte{
+		"changed":   bytes.Replace(data, []byte("1234"), []byte("9234"), 1),
+		"truncated": data[:len(data)/2],
+		"empty":     nil,
+	}
+	for name, data := range damaged {
+		os.WriteFile(path, data, 0o644)
+		if _, _, err := store.Load("ash"); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Expected a %s save to be damaged, got %v", name, err)
+		}
+	}
+
+	// Saves from before checksums load as they are.
+	os.WriteFile(path, []byte(`{"money": 42}`), 0o644)
+	if loaded, _, err := store.Load("ash"); err != nil || loaded.Money != 42 {
+		t.Errorf("Expected a save without a checksum to load, got %v", err)
+	}
+
+	if _, err := store.SetAside("ash"); err != nil {
+		t.Fatalf("SetAside() returned error: %v", err)
+	}
+	if _, found, err := store.Load("ash"); found || err != nil {
+		t.Errorf("Expected the save to be out of the way, got %v, %v", found, err)
+	}
+}
+
+func TestStoreLock(t *testing.T) {
+	dir := t.TempDir()
+	first, second := NewStore(dir), NewStore(dir)
+	if err := first.Lock("ash"); err != nil {
+		t.Fatalf("Lock() returned error: %v", err)
+	}
+	if err := first.Lock("ash"); err != nil {
+		t.Errorf("Expected locking again to do nothing, got %v", err)
+	}
+	if err := second.Lock("ash"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	if err := second.Lock("misty"); err != nil {
+		t.Errorf("Expected another profile to lock, got %v", err)
+	}
+
+	first.Unlock("ash")
+	if err := second.Lock("ash"); err != nil {
+		t.Errorf("Expected the profile to lock once it was unlocked, got %v", err)
+	}
+}
+
+func TestStoreRejectsBadNames(t *testing.T) {
+	if _, _, err := NewStore(t.TempDir()).Load("../ash"); err == nil {
+		t.Errorf("Expected an error for a path-like profile name")
+	}
+}
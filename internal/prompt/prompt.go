@@ -0,0 +1,88 @@
+// Package prompt renders the REPL prompt from a user template.
+//
+// Templates use text/template syntax over Data, plus a color function that
+// takes the colors themes do:
+//
+//	{{color "cyan" "Pokedex"}} {{if .Area}}[{{.Area}}] {{end}}({{.PartySize}}) ₽{{.Money}} >
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/theme"
+)
+
+const Default = "Pokedex {{if .Area}}[{{.Area}}] {{end}}{{if gt .Combo 1}}({{.ComboSpecies}} combo x{{.Combo}}) {{end}}> "
+
+// Data is what a prompt template can show.
+type Data struct {
+	Profile   string
+	Area      string
+	PartySize int
+	Money     int
+	Steps     int
+	TimeOfDay string
+	// Combo is the length of the current catch combo, of ComboSpecies.
+	Combo        int
+	ComboSpecies string
+}
+
+type Template struct {
+	tmpl *template.Template
+}
+
+// Compile parses a prompt template, checking it renders with sample data so
+// mistakes show up when the config is loaded rather than at every prompt.
+func Compile(text string) (*Template, error) {
+	funcs := template.FuncMap{
+		"color": func(name string, v any) (string, error) {
+			code, err := theme.Code(name)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("\033[%sm%v\033[0m", code, v), nil
+		},
+	}
+	tmpl, err := template.New("prompt").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{tmpl: tmpl}
+	if _, err := t.render(Data{Area: "pallet-town-area", TimeOfDay: "day"}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render fills in the template, falling back to the default prompt if it
+// fails.
+func (t *Template) Render(data Data) string {
+	out, err := t.render(data)
+	if err != nil {
+		return "Pokedex > "
+	}
+	return out
+}
+
+func (t *Template) render(data Data) (string, error) {
+	var b strings.Builder
+	err := t.tmpl.Execute(&b, data)
+	return b.String(), err
+}
+
+// TimeOfDay names the part of the day, as in the games: morning, day,
+// evening or night.
+func TimeOfDay(now time.Time) string {
+	switch h := now.Hour(); {
+	case h >= 4 && h < 10:
+		return "morning"
+	case h >= 10 && h < 18:
+		return "day"
+	case h >= 18 && h < 21:
+		return "evening"
+	}
+	return "night"
+}
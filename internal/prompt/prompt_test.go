@@ -0,0 +1,18 @@
+package prompt
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tmpl, err := Compile(`{{.Profile}} {{color "bold" .Money}} {{if .Area}}[{{.Area}}]{{end}}> `)
+	if err != nil {
+		t.Fatalf("Compile() returned error: %v", err)
+	}
+	got := tmpl.Render(Data{Profile: "ash", Money: 500, Area: "route-1-area"})
+	if got != "ash \033[1m500\033[0m [route-1-area]> " {
+		t.Errorf("Unexpected prompt %q", got)
+	}
+
+	if _, err := Compile(`{{color "mauve" .Area}}`); err == nil {
+		t.Errorf("Expected an unknown color to be rejected")
+	}
+}
@@ -0,0 +1,140 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// HELLO WORLD at 1-M, as worked through in the standard's annex.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("Expected error correction %v, got %v", want, got)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := (0<<10 | bch(0, 0x537, 10)) ^ 0x5412; got != 0b101010000010010 {
+		t.Errorf("Expected M with mask 0 to be 101010000010010, got %015b", got)
+	}
+	if got := 7<<12 | bch(7, 0x1F25, 12); got != 0b000111110010010100 {
+		t.Errorf("Expected version 7 to be 000111110010010100, got %018b", got)
+	}
+	if got := alignmentPositions(7); len(got) != 3 || got[1] != 22 || got[2] != 38 {
+		t.Errorf("Expected version 7's alignment patterns at 6, 22 and 38, got %v", got)
+	}
+}
+
+// readData reads c's data codewords back out, the way a scanner would: it
+// finds the mask in the format bits, unmasks, follows the zigzag and undoes
+// the interleaving.
+func readData(t *testing.T, c *Code) []byte {
+	t.Helper()
+	info := 0
+	for i := range 8 {
+		if c.Dark(c.Size-1-i, 8) {
+			info |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if c.Dark(8, c.Size-15+i) {
+			info |= 1 << i
+		}
+	}
+	info ^= 0x5412
+	if info>>13 != 0 {
+		t.Fatalf("Expected error correction level M, got format bits %015b", info)
+	}
+	mask := info >> 10 & 7
+	if bch(info>>10, 0x537, 10) != info&0x3FF {
+		t.Fatalf("Expected format bits to check out, got %015b", info)
+	}
+	c.applyMask(mask)
+	defer c.applyMask(mask)
+
+	var buf bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for j := range 2 {
+				if x := right - j; !c.function[y][x] {
+					buf = append(buf, c.Dark(x, y))
+				}
+			}
+		}
+	}
+	raw := buf.bytes()
+
+	version := (c.Size - 17) / 4
+	blocks, eccLen := eccBlocks[version], eccPerBlock[version]
+	total := rawModules(version) / 8
+	short := blocks - total%blocks
+	dataLen := total/blocks - eccLen
+	split := make([][]byte, blocks)
+	k := 0
+	for i := range dataLen + 1 {
+		for j := range split {
+			if i < dataLen || j >= short {
+				split[j] = append(split[j], raw[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range split {
+		data = append(data, block...)
+	}
+	return data
+}
+
+func TestEncode(t *testing.T) {
+	for _, size := range []int{11, 100, 300, 600} {
+		payload := bytes.Repeat([]byte("pikachu!"), size/8+1)[:size]
+		c, err := Encode(payload)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes) returned error: %v", size, err)
+		}
+		if !c.Dark(0, 0) || c.Dark(1, 1) || !c.Dark(c.Size-1, 0) || !c.Dark(0, c.Size-1) {
+			t.Errorf("Expected finder patterns in three corners of the %d byte code", size)
+		}
+		data := readData(t, c)
+		header := 2
+		if c.Size >= 10*4+17 {
+			header = 3
+		}
+		// The payload starts four bits into the data, after the mode.
+		var got []byte
+		for i := range size {
+			got = append(got, data[header-1+i]<<4|data[header+i]>>4)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("Expected to read back the %d bytes encoded, got %q", size, got)
+		}
+	}
+
+	if _, err := Encode(make([]byte, 700)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected too much data to fail, got %v", err)
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	c, err := Encode([]byte("HELLO WORLD"))
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	if c.Size != 21 {
+		t.Errorf("Expected a version 1 code, got %d modules a side", c.Size)
+	}
+	lines := bytes.Split(bytes.TrimSuffix([]byte(c.Terminal()), []byte("\n")), []byte("\n"))
+	if len(lines) != 13 {
+		t.Errorf("Expected 25 rows of modules on 13 lines, got %d", len(lines))
+	}
+}
@@ -0,0 +1,308 @@
+// Package query parses and evaluates filters written like a SQL WHERE
+// clause:
+//
+//	type=water and base_attack>90 and gen in (1,2)
+//
+// Conditions compare a field with =, !=, <, <=, > or >=, or test it with
+// in (...) or not in (...), and combine with and, or, not and parentheses.
+// Values are numbers, bare words or quoted strings, and compare as numbers
+// when both sides are.
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Row is what a query is evaluated against: each field's values. A field
+// can have several, such as a pokemon's types; a condition holds if any of
+// them satisfies it, and != and not in only if none of them is excluded.
+type Row map[string][]string
+
+// Query is a parsed filter.
+type Query struct {
+	root   node
+	fields []string
+}
+
+// Fields are the fields the query names, in the order they first appear.
+func (q *Query) Fields() []string {
+	return q.fields
+}
+
+// Match reports whether row satisfies the query.
+func (q *Query) Match(row Row) bool {
+	return q.root.match(row)
+}
+
+type node interface {
+	match(row Row) bool
+}
+
+type and struct{ left, right node }
+type or struct{ left, right node }
+type not struct{ inner node }
+
+// cond is a field compared with op to one of values; in has several.
+type cond struct {
+	field  string
+	op     string
+	values []string
+}
+
+func (n and) match(row Row) bool { return n.left.match(row) && n.right.match(row) }
+func (n or) match(row Row) bool  { return n.left.match(row) || n.right.match(row) }
+func (n not) match(row Row) bool { return !n.inner.match(row) }
+
+func (c cond) match(row Row) bool {
+	switch c.op {
+	case "!=":
+		return !cond{c.field, "=", c.values}.match(row)
+	case "not in":
+		return !cond{c.field, "in", c.values}.match(row)
+	}
+	for _, have := range row[c.field] {
+		for _, want := range c.values {
+			if compare(have, c.op, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compare applies op to a and b, as numbers if both are.
+func compare(a, op, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	var c int
+	if errA == nil && errB == nil {
+		c = cmp.Compare(x, y)
+	} else {
+		c = strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+	switch op {
+	case "=", "in":
+		return c == 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// Parse parses a filter.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != eof {
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+	return &Query{root: root, fields: p.fields}, nil
+}
+
+type kind int
+
+const (
+	eof kind = iota
+	word
+	str
+	punct
+)
+
+type token struct {
+	kind kind
+	text string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case eof:
+		return "end of query"
+	case str:
+		return strconv.Quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+// is reports whether t is the keyword or punctuation s.
+func (t token) is(s string) bool {
+	return t.kind != str && strings.EqualFold(t.text, s)
+}
+
+var operators = []string{"<=", ">=", "!=", "=", "<", ">", "(", ")", ","}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		r := rune(src[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, errors.New("unterminated string")
+			}
+			toks = append(toks, token{str, src[i+1 : i+1+end]})
+			i += end + 2
+		case isWord(r):
+			start := i
+			for i < len(src) && isWord(rune(src[i])) {
+				i++
+			}
+			toks = append(toks, token{word, src[start:i]})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q", src[i:i+1])
+			}
+			toks = append(toks, token{punct, op})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: eof}), nil
+}
+
+func isWord(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) || r == '_' || r == '-' || r == '.'
+}
+
+type parser struct {
+	toks   []token
+	pos    int
+	fields []string
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != eof {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	for err == nil && p.peek().is("or") {
+		p.next()
+		var right node
+		if right, err = p.and(); err == nil {
+			left = or{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	for err == nil && p.peek().is("and") {
+		p.next()
+		var right node
+		if right, err = p.not(); err == nil {
+			left = and{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) not() (node, error) {
+	if p.peek().is("not") {
+		p.next()
+		inner, err := p.not()
+		return not{inner}, err
+	}
+	if p.peek().is("(") {
+		p.next()
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); !t.is(")") {
+			return nil, fmt.Errorf("expected ')', got %s", t)
+		}
+		return inner, nil
+	}
+	return p.cond()
+}
+
+func (p *parser) cond() (node, error) {
+	field := p.next()
+	if field.kind != word || field.is("and") || field.is("or") || field.is("in") {
+		return nil, fmt.Errorf("expected a field, got %s", field)
+	}
+	name := strings.ToLower(field.text)
+	if !slices.Contains(p.fields, name) {
+		p.fields = append(p.fields, name)
+	}
+
+	op := p.next()
+	switch {
+	case op.kind == punct && slices.Contains([]string{"=", "!=", "<", "<=", ">", ">="}, op.text):
+		value, err := p.value()
+		return cond{name, op.text, []string{value}}, err
+	case op.is("in"):
+		values, err := p.list()
+		return cond{name, "in", values}, err
+	case op.is("not") && p.peek().is("in"):
+		p.next()
+		values, err := p.list()
+		return cond{name, "not in", values}, err
+	}
+	return nil, fmt.Errorf("expected a comparison after %s, got %s", name, op)
+}
+
+func (p *parser) value() (string, error) {
+	t := p.next()
+	if t.kind != word && t.kind != str {
+		return "", fmt.Errorf("expected a value, got %s", t)
+	}
+	return t.text, nil
+}
+
+func (p *parser) list() ([]string, error) {
+	if t := p.next(); !t.is("(") {
+		return nil, fmt.Errorf("expected '(', got %s", t)
+	}
+	var values []string
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		switch t := p.next(); {
+		case t.is(")"):
+			return values, nil
+		case !t.is(","):
+			return nil, fmt.Errorf("expected ',' or ')', got %s", t)
+		}
+	}
+}
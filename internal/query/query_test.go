@@ -0,0 +1,49 @@
+package query
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	gyarados := Row{"name": {"gyarados"}, "type": {"water", "flying"}, "base_attack": {"125"}, "gen": {"1"}}
+	totodile := Row{"name": {"totodile"}, "type": {"water"}, "base_attack": {"65"}, "gen": {"2"}}
+	cases := []struct {
+		query string
+		want  []bool
+	}{
+		{"type=water and base_attack>90 and gen in (1,2)", []bool{true, false}},
+		{"type = 'Flying' or base_attack <= 65", []bool{true, true}},
+		{"type != flying", []bool{false, true}},
+		{"not (gen=1) and name not in (croconaw, \"feraligatr\")", []bool{false, true}},
+		{"base_attack>=100", []bool{true, false}},
+		{"weight>10", []bool{false, false}},
+	}
+	for _, c := range cases {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", c.query, err)
+			continue
+		}
+		for i, row := range []Row{gyarados, totodile} {
+			if got := q.Match(row); got != c.want[i] {
+				t.Errorf("Expected %q on %s to be %v, got %v", c.query, row["name"][0], c.want[i], got)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{"", "type=", "type water", "gen in (1,2", "(type=water", "type='water", "type=water and", "gen=1 gen=2", "type~water"} {
+		if _, err := Parse(src); err == nil {
+			t.Errorf("Expected an error parsing %q", src)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	q, err := Parse("type=water and (base_attack>90 or TYPE=fire)")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := q.Fields(); len(got) != 2 || got[0] != "type" || got[1] != "base_attack" {
+		t.Errorf("Expected [type base_attack], got %v", got)
+	}
+}
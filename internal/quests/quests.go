@@ -0,0 +1,139 @@
+// Package quests generates daily and weekly quests. The quests for a given
+// player and period are always the same, so nothing but progress needs to
+// be saved.
+package quests
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+type Kind string
+
+const (
+	CatchAny   Kind = "catch"
+	CatchType  Kind = "catch_type"
+	ExploreNew Kind = "explore_new"
+)
+
+type Period string
+
+const (
+	Daily  Period = "daily"
+	Weekly Period = "weekly"
+)
+
+var types = []string{
+	"normal", "fire", "water", "grass", "electric", "ice", "fighting", "poison", "ground",
+	"flying", "psychic", "bug", "rock", "ghost", "dragon", "dark", "steel", "fairy",
+}
+
+// bonusItems are the rarer rewards some weekly quests add.
+var bonusItems = []string{
+	"ultra-ball", "leftovers", "lucky-egg", "choice-band", "choice-specs", "choice-scarf",
+	"tm13", "tm24", "tm26",
+	"charizardite-y", "gengarite", "gyaradosite", "lucarionite",
+}
+
+type Reward struct {
+	Money int
+	Items map[string]int
+}
+
+type Quest struct {
+	// ID is unique to the quest and its period, e.g. "daily-2026-10-15-1".
+	ID     string
+	Period Period
+	Kind   Kind
+	// Type is the pokemon type to catch for CatchType quests.
+	Type   string
+	Goal   int
+	Reward Reward
+}
+
+func (q Quest) String() string {
+	switch q.Kind {
+	case CatchType:
+		return fmt.Sprintf("Catch %d %s-type pokemon", q.Goal, q.Type)
+	case ExploreNew:
+		if q.Goal == 1 {
+			return "Explore a new area"
+		}
+		return fmt.Sprintf("Explore %d new areas", q.Goal)
+	}
+	return fmt.Sprintf("Catch %d pokemon", q.Goal)
+}
+
+// Counts reports whether e makes progress on q.
+func (q Quest) Counts(e events.Event) bool {
+	switch q.Kind {
+	case CatchAny:
+		return e.Kind == events.Caught
+	case CatchType:
+		return e.Kind == events.Caught && slices.Contains(e.Types, q.Type)
+	case ExploreNew:
+		return e.Kind == events.Explored && e.New
+	}
+	return false
+}
+
+// For returns the daily and weekly quests of player at t.
+func For(player string, t time.Time) []Quest {
+	year, week := t.ISOWeek()
+	return append(
+		generate(player, Daily, t.Format(time.DateOnly)),
+		generate(player, Weekly, fmt.Sprintf("%d-W%02d", year, week))...,
+	)
+}
+
+func generate(player string, period Period, key string) []Quest {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%s", player, period, key)
+	rng := rand.New(rand.NewPCG(h.Sum64(), 0))
+
+	scale := 1
+	if period == Weekly {
+		scale = 5
+	}
+	templates := []Quest{
+		{Kind: CatchAny, Goal: (3 + rng.IntN(3)) * scale},
+		{Kind: CatchType, Type: types[rng.IntN(len(types))], Goal: (2 + rng.IntN(2)) * scale},
+		{Kind: ExploreNew, Goal: (1 + rng.IntN(2)) * scale},
+	}
+	if period == Weekly {
+		// Two of the three, so weeks differ in more than their numbers.
+		drop := rng.IntN(len(templates))
+		templates = slices.Delete(templates, drop, drop+1)
+	}
+
+	quests := make([]Quest, len(templates))
+	for i, q := range templates {
+		q.ID = fmt.Sprintf("%s-%s-%d", period, key, i)
+		q.Period = period
+		q.Reward = reward(q, rng)
+		quests[i] = q
+	}
+	return quests
+}
+
+func reward(q Quest, rng *rand.Rand) Reward {
+	r := Reward{Money: 100 * q.Goal, Items: map[string]int{}}
+	switch q.Kind {
+	case CatchAny:
+		r.Items["poke-ball"] = q.Goal
+	case CatchType:
+		r.Money *= 2
+		r.Items["great-ball"] = 1 + q.Goal/2
+	case ExploreNew:
+		r.Money *= 3
+	}
+	if q.Period == Weekly && rng.IntN(4) == 0 {
+		r.Items[bonusItems[rng.IntN(len(bonusItems))]]++
+	}
+	return r
+}
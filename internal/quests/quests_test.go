@@ -0,0 +1,37 @@
+package quests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+func TestQuestsAreDeterministic(t *testing.T) {
+	day := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	later := day.Add(8 * time.Hour)
+	if !reflect.DeepEqual(For("ash", day), For("ash", later)) {
+		t.Errorf("Expected the same quests all day")
+	}
+	if reflect.DeepEqual(For("ash", day)[0], For("ash", day.AddDate(0, 0, 1))[0]) {
+		t.Errorf("Expected a new daily quest the next day")
+	}
+	if For("ash", day)[3].ID != For("ash", day.AddDate(0, 0, 1))[3].ID {
+		t.Errorf("Expected the weekly quests to last all week")
+	}
+}
+
+func TestCounts(t *testing.T) {
+	q := Quest{Kind: CatchType, Type: "bug", Goal: 3}
+	if !q.Counts(events.Event{Kind: events.Caught, Types: []string{"bug", "flying"}}) {
+		t.Errorf("Expected a bug catch to count")
+	}
+	if q.Counts(events.Event{Kind: events.Caught, Types: []string{"normal"}}) {
+		t.Errorf("Expected a normal catch not to count")
+	}
+	explore := Quest{Kind: ExploreNew, Goal: 1}
+	if explore.Counts(events.Event{Kind: events.Explored}) {
+		t.Errorf("Expected a revisit not to count")
+	}
+}
@@ -0,0 +1,85 @@
+// Package release looks up published releases on GitHub.
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultRepo = "azs06/pokedexcli"
+
+var apiUrl = "https://api.github.com/repos/"
+
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadUrl string `json:"browser_download_url"`
+}
+
+type Release struct {
+	Tag       string  `json:"tag_name"`
+	Url       string  `json:"html_url"`
+	Assets    []Asset `json:"assets"`
+	Draft     bool    `json:"draft"`
+	Published string  `json:"published_at"`
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Latest returns the newest published release of repo ("owner/name").
+func Latest(repo string) (Release, error) {
+	release := Release{}
+	res, err := httpClient.Get(apiUrl + repo + "/releases/latest")
+	if err != nil {
+		return release, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return release, fmt.Errorf("failed to fetch latest release: %s", res.Status)
+	}
+	err = json.NewDecoder(res.Body).Decode(&release)
+	return release, err
+}
+
+// Newer reports whether version a is newer than b. Both are semver strings,
+// with or without a leading "v"; anything after a "-" or "+" is ignored.
+// Versions that don't parse, such as "dev", are never newer.
+func Newer(a, b string) bool {
+	va, okA := parse(a)
+	vb, okB := parse(b)
+	if !okA {
+		return false
+	}
+	if !okB {
+		return true
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] > vb[i]
+		}
+	}
+	return false
+}
+
+func parse(version string) ([3]int, bool) {
+	parsed := [3]int{}
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
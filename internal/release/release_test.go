@@ -0,0 +1,72 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "v1.2.0", b: "v1.1.9", expected: true},
+		{a: "1.10.0", b: "v1.9.0", expected: true},
+		{a: "v1.0.0", b: "v1.0.0", expected: false},
+		{a: "v1.0.0", b: "v1.0.1-rc1", expected: false},
+		{a: "v0.1.0", b: "dev", expected: true},
+		{a: "dev", b: "v0.1.0", expected: false},
+	}
+	for _, c := range cases {
+		if actual := Newer(c.a, c.b); actual != c.expected {
+			t.Errorf("Newer(%q, %q) = %v, expected %v", c.a, c.b, actual, c.expected)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("pokedex")
+	checksums := []byte("5d9fb5e5ddd2a2b6a3e2f1c5a2bfb0e0a8d3cf3d5f31c23a9d2a2b40ad6aaf5c  other\n" +
+		"3f23c79ebdab8c1a5b4d3e5c7d7b8bf04ed3dbd3c0aa1a3a9f86ac27e1d8f8bd  pokedexcli_linux_amd64\n")
+	if err := VerifyChecksum(checksums, "pokedexcli_linux_amd64", data); err == nil {
+		t.Errorf("Expected checksum mismatch")
+	}
+	if err := VerifyChecksum(checksums, "missing", data); err == nil {
+		t.Errorf("Expected missing checksum error")
+	}
+	good := []byte("f829233ddb69db70deac41188f27c8eeb50971fe642de8861afabd41c39bc5dc *pokedexcli_linux_amd64\n")
+	if err := VerifyChecksum(good, "pokedexcli_linux_amd64", data); err != nil {
+		t.Errorf("VerifyChecksum() returned error: %v", err)
+	}
+	if err := VerifyChecksum(good, "missing", data); err == nil {
+		t.Errorf("Expected missing checksum error")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() returned error: %v", err)
+	}
+	checksums := []byte("f829233ddb69db70deac41188f27c8eeb50971fe642de8861afabd41c39bc5dc  pokedexcli_linux_amd64\n")
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, checksums)) + "\n")
+	if err := VerifySignature(public, checksums, signature); err != nil {
+		t.Errorf("VerifySignature() returned error: %v", err)
+	}
+
+	tampered := []byte("0000000000000000000000000000000000000000000000000000000000000000  pokedexcli_linux_amd64\n")
+	if err := VerifySignature(public, tampered, signature); err == nil {
+		t.Errorf("Expected tampered checksums to fail")
+	}
+	other, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() returned error: %v", err)
+	}
+	if err := VerifySignature(other, checksums, signature); err == nil {
+		t.Errorf("Expected a signature by another key to fail")
+	}
+	if err := VerifySignature(public, checksums, []byte("not base64!")); err == nil {
+		t.Errorf("Expected a malformed signature to fail")
+	}
+}
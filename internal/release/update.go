@@ -0,0 +1,124 @@
+package release
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+var downloadClient = &http.Client{Timeout: 5 * time.Minute}
+
+// AssetName is the binary published for an OS and architecture, e.g.
+// pokedexcli_linux_amd64 or pokedexcli_windows_amd64.exe.
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("pokedexcli_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func (r Release) Asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+func Download(url string) ([]byte, error) {
+	res, err := downloadClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// VerifyChecksum checks data against its entry in a sha256sum-style
+// checksums file.
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		want, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return fmt.Errorf("bad checksum for %s: %w", name, err)
+		}
+		got := sha256.Sum256(data)
+		if !bytes.Equal(want, got[:]) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s", name)
+}
+
+// VerifySignature checks a base64 ed25519 signature of the checksums file.
+func VerifySignature(publicKey ed25519.PublicKey, checksums, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("bad signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, checksums, sig) {
+		return errors.New("signature does not match the release key")
+	}
+	return nil
+}
+
+// Replace swaps the executable at exe for data. The new binary is written
+// next to the old one first so the final rename stays on one filesystem.
+// Windows won't overwrite a running executable, but it will rename it, so
+// the old one is moved aside to exe.old there.
+func Replace(exe string, data []byte) error {
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, filepath.Base(exe)+".new-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), exe)
+}
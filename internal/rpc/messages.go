@@ -0,0 +1,288 @@
+package rpc
+
+// Message is a protobuf message of pokedex.proto.
+type Message interface {
+	Marshal() []byte
+	Unmarshal(b []byte) error
+}
+
+type CatchRequest struct {
+	Profile string
+	Pokemon string
+	Ball    string
+}
+
+func (m *CatchRequest) Marshal() []byte {
+	b := appendString(nil, 1, m.Profile)
+	b = appendString(b, 2, m.Pokemon)
+	return appendString(b, 3, m.Ball)
+}
+
+func (m *CatchRequest) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Profile, err = d.string()
+		case field == 2 && wireType == wireBytes:
+			m.Pokemon, err = d.string()
+		case field == 3 && wireType == wireBytes:
+			m.Ball, err = d.string()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type ExploreRequest struct {
+	Profile string
+	Area    string
+}
+
+func (m *ExploreRequest) Marshal() []byte {
+	b := appendString(nil, 1, m.Profile)
+	return appendString(b, 2, m.Area)
+}
+
+func (m *ExploreRequest) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Profile, err = d.string()
+		case field == 2 && wireType == wireBytes:
+			m.Area, err = d.string()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type CommandReply struct {
+	Output string
+	// Encounter is nil when there's no wild pokemon in front of the player.
+	Encounter *Encounter
+}
+
+func (m *CommandReply) Marshal() []byte {
+	b := appendString(nil, 1, m.Output)
+	if m.Encounter != nil {
+		b = appendMessage(b, 2, m.Encounter)
+	}
+	return b
+}
+
+func (m *CommandReply) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Output, err = d.string()
+		case field == 2 && wireType == wireBytes:
+			var data []byte
+			if data, err = d.bytes(); err == nil {
+				m.Encounter = &Encounter{}
+				err = m.Encounter.Unmarshal(data)
+			}
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type ListPokedexRequest struct {
+	Profile string
+}
+
+func (m *ListPokedexRequest) Marshal() []byte {
+	return appendString(nil, 1, m.Profile)
+}
+
+func (m *ListPokedexRequest) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Profile, err = d.string()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type ListPokedexReply struct {
+	Entries []*PokedexEntry
+}
+
+func (m *ListPokedexReply) Marshal() []byte {
+	var b []byte
+	for _, e := range m.Entries {
+		b = appendMessage(b, 1, e)
+	}
+	return b
+}
+
+func (m *ListPokedexReply) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			var data []byte
+			if data, err = d.bytes(); err == nil {
+				e := &PokedexEntry{}
+				err = e.Unmarshal(data)
+				m.Entries = append(m.Entries, e)
+			}
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type PokedexEntry struct {
+	ID    int32
+	Name  string
+	Types []string
+	Owned int32
+}
+
+func (m *PokedexEntry) Marshal() []byte {
+	b := appendInt32(nil, 1, m.ID)
+	b = appendString(b, 2, m.Name)
+	b = appendStrings(b, 3, m.Types)
+	return appendInt32(b, 4, m.Owned)
+}
+
+func (m *PokedexEntry) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireVarint:
+			m.ID, err = d.int32()
+		case field == 2 && wireType == wireBytes:
+			m.Name, err = d.string()
+		case field == 3 && wireType == wireBytes:
+			var t string
+			t, err = d.string()
+			m.Types = append(m.Types, t)
+		case field == 4 && wireType == wireVarint:
+			m.Owned, err = d.int32()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type StreamEncountersRequest struct {
+	Profile string
+}
+
+func (m *StreamEncountersRequest) Marshal() []byte {
+	return appendString(nil, 1, m.Profile)
+}
+
+func (m *StreamEncountersRequest) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Profile, err = d.string()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type Encounter struct {
+	Profile string
+	Pokemon string
+	Level   int32
+	Types   []string
+	Shiny   bool
+}
+
+func (m *Encounter) Marshal() []byte {
+	b := appendString(nil, 1, m.Profile)
+	b = appendString(b, 2, m.Pokemon)
+	b = appendInt32(b, 3, m.Level)
+	b = appendStrings(b, 4, m.Types)
+	return appendBool(b, 5, m.Shiny)
+}
+
+func (m *Encounter) Unmarshal(b []byte) error {
+	d := decoder{b}
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Profile, err = d.string()
+		case field == 2 && wireType == wireBytes:
+			m.Pokemon, err = d.string()
+		case field == 3 && wireType == wireVarint:
+			m.Level, err = d.int32()
+		case field == 4 && wireType == wireBytes:
+			var t string
+			t, err = d.string()
+			m.Types = append(m.Types, t)
+		case field == 5 && wireType == wireVarint:
+			m.Shiny, err = d.bool()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
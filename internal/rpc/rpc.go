@@ -0,0 +1,274 @@
+// Package rpc serves the Pokedex gRPC service of pokedex.proto. It speaks
+// the gRPC wire protocol over cleartext HTTP/2 with just the standard
+// library, and its message types are written to match the .proto, so the
+// game needs neither protoc nor grpc-go while any client generated from the
+// .proto can call it.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ServiceName is the service's full name in pokedex.proto.
+const ServiceName = "pokedex.v1.Pokedex"
+
+// DefaultAddr is where the server listens unless told otherwise.
+const DefaultAddr = ":7780"
+
+// maxMessage is the largest message taken, as in grpc-go.
+const maxMessage = 4 << 20
+
+// Code is a gRPC status code.
+type Code int
+
+const (
+	OK                 Code = 0
+	InvalidArgument    Code = 3
+	NotFound           Code = 5
+	FailedPrecondition Code = 9
+	Unimplemented      Code = 12
+	Internal           Code = 13
+)
+
+// Error is a call failing with a status other than OK.
+type Error struct {
+	Code    Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", e.Code, e.Message)
+}
+
+func Errorf(code Code, format string, args ...any) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Service is the Pokedex service. Errors other than *Error reach clients
+// as Internal.
+type Service interface {
+	Catch(ctx context.Context, req *CatchRequest) (*CommandReply, error)
+	Explore(ctx context.Context, req *ExploreRequest) (*CommandReply, error)
+	ListPokedex(ctx context.Context, req *ListPokedexRequest) (*ListPokedexReply, error)
+	// StreamEncounters sends encounters until ctx is done or send fails.
+	StreamEncounters(ctx context.Context, req *StreamEncountersRequest, send func(*Encounter) error) error
+}
+
+// NewServer serves h on addr over HTTP/1 and over HTTP/2 without TLS, as
+// gRPC clients dialing with insecure credentials expect. h is Handler, or a
+// mux with it at Path.
+func NewServer(addr string, h http.Handler) *http.Server {
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{Addr: addr, Handler: h, Protocols: protocols}
+}
+
+// Path is where Handler serves the service's methods.
+const Path = "/" + ServiceName + "/"
+
+// Handler serves svc's methods at /pokedex.v1.Pokedex/<method>.
+func Handler(svc Service) http.Handler {
+	return handler{svc}
+}
+
+type handler struct {
+	svc Service
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "this is a gRPC server", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	err := h.call(w, r)
+	var status *Error
+	switch {
+	case err == nil:
+		status = &Error{Code: OK}
+	case !errors.As(err, &status):
+		status = &Error{Code: Internal, Message: err.Error()}
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(status.Code)))
+	if status.Message != "" {
+		w.Header().Set("Grpc-Message", encodeMessage(status.Message))
+	}
+}
+
+func (h handler) call(w http.ResponseWriter, r *http.Request) error {
+	service, method, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if service != ServiceName {
+		return Errorf(Unimplemented, "unknown service %s", service)
+	}
+	body, err := readFrame(r.Body)
+	if err != nil {
+		return Errorf(InvalidArgument, "reading the request: %v", err)
+	}
+	send := func(m Message) error {
+		if _, err := w.Write(frame(m.Marshal())); err != nil {
+			return err
+		}
+		return http.NewResponseController(w).Flush()
+	}
+	ctx := r.Context()
+	switch method {
+	case "Catch":
+		return unary(body, &CatchRequest{}, func(req *CatchRequest) (*CommandReply, error) { return h.svc.Catch(ctx, req) }, send)
+	case "Explore":
+		return unary(body, &ExploreRequest{}, func(req *ExploreRequest) (*CommandReply, error) { return h.svc.Explore(ctx, req) }, send)
+	case "ListPokedex":
+		return unary(body, &ListPokedexRequest{}, func(req *ListPokedexRequest) (*ListPokedexReply, error) { return h.svc.ListPokedex(ctx, req) }, send)
+	case "StreamEncounters":
+		req := &StreamEncountersRequest{}
+		if err := req.Unmarshal(body); err != nil {
+			return Errorf(InvalidArgument, "%v", err)
+		}
+		return h.svc.StreamEncounters(ctx, req, func(e *Encounter) error { return send(e) })
+	}
+	return Errorf(Unimplemented, "unknown method %s", method)
+}
+
+// unary decodes body into req, calls the method and sends its reply.
+func unary[Req, Res Message](body []byte, req Req, method func(Req) (Res, error), send func(Message) error) error {
+	if err := req.Unmarshal(body); err != nil {
+		return Errorf(InvalidArgument, "%v", err)
+	}
+	res, err := method(req)
+	if err != nil {
+		return err
+	}
+	return send(res)
+}
+
+// frame prefixes a message with gRPC's uncompressed flag and its length.
+func frame(data []byte) []byte {
+	b := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(data)))
+	return append(b, data...)
+}
+
+// readFrame reads one message, or io.EOF when there are no more.
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed messages aren't supported")
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxMessage {
+		return nil, fmt.Errorf("message of %d bytes is too big", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// encodeMessage percent-encodes a status message as gRPC asks.
+func encodeMessage(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Client calls a Pokedex server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient calls the server at baseURL, such as http://localhost:7780.
+func NewClient(baseURL string) *Client {
+	protocols := &http.Protocols{}
+	protocols.SetUnencryptedHTTP2(true)
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: &http.Client{Transport: &http.Transport{Protocols: protocols}}}
+}
+
+func (c *Client) Catch(ctx context.Context, req *CatchRequest) (*CommandReply, error) {
+	res := &CommandReply{}
+	return res, c.call(ctx, "Catch", req, res.Unmarshal)
+}
+
+func (c *Client) Explore(ctx context.Context, req *ExploreRequest) (*CommandReply, error) {
+	res := &CommandReply{}
+	return res, c.call(ctx, "Explore", req, res.Unmarshal)
+}
+
+func (c *Client) ListPokedex(ctx context.Context, req *ListPokedexRequest) (*ListPokedexReply, error) {
+	res := &ListPokedexReply{}
+	return res, c.call(ctx, "ListPokedex", req, res.Unmarshal)
+}
+
+// StreamEncounters calls recv with each encounter until ctx is done, the
+// server ends the stream or recv fails.
+func (c *Client) StreamEncounters(ctx context.Context, req *StreamEncountersRequest, recv func(*Encounter) error) error {
+	return c.call(ctx, "StreamEncounters", req, func(data []byte) error {
+		e := &Encounter{}
+		if err := e.Unmarshal(data); err != nil {
+			return err
+		}
+		return recv(e)
+	})
+}
+
+func (c *Client) call(ctx context.Context, method string, req Message, recv func([]byte) error) error {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+ServiceName+"/"+method, bytes.NewReader(frame(req.Marshal())))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/grpc")
+	r.Header.Set("TE", "trailers")
+	res, err := c.httpClient.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", method, res.Status)
+	}
+	for {
+		data, err := readFrame(res.Body)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := recv(data); err != nil {
+			return err
+		}
+	}
+	// A call that failed straight away may send its status as headers.
+	status := res.Trailer.Get("Grpc-Status") + res.Header.Get("Grpc-Status")
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return fmt.Errorf("%s: missing status", method)
+	}
+	if code != int(OK) {
+		message, _ := url.PathUnescape(res.Trailer.Get("Grpc-Message") + res.Header.Get("Grpc-Message"))
+		return &Error{Code: Code(code), Message: message}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Package slack answers Slack slash commands, checking each request's
+// signature and replying with Block Kit messages.
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAddr is where the server listens unless told otherwise.
+const DefaultAddr = ":7779"
+
+const (
+	// maxAge is how old a request can be before it's refused as a replay.
+	maxAge = 5 * time.Minute
+	// replyWithin is how long a command can take before it's answered later
+	// through its response URL; Slack gives up after three seconds.
+	replyWithin = 2500 * time.Millisecond
+	// maxSection is the most text a section block holds, leaving room for
+	// the code fence.
+	maxSection = 2900
+	// maxBlocks is the most blocks a message holds.
+	maxBlocks = 50
+)
+
+// Command is a slash command someone ran.
+type Command struct {
+	Team        string
+	User        string
+	UserName    string
+	Channel     string
+	Command     string
+	Text        string
+	ResponseURL string
+}
+
+// Response is a message answering a command. An in_channel response is
+// posted for everyone to see; an ephemeral one only to who ran it.
+type Response struct {
+	ResponseType string  `json:"response_type"`
+	Text         string  `json:"text"`
+	Blocks       []Block `json:"blocks,omitempty"`
+}
+
+type Block struct {
+	Type     string `json:"type"`
+	Text     *Text  `json:"text,omitempty"`
+	Elements []Text `json:"elements,omitempty"`
+}
+
+// Text is a text object, of type mrkdwn or plain_text.
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Section is a block of mrkdwn text.
+func Section(markdown string) Block {
+	return Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: markdown}}
+}
+
+// Context is a line of small print.
+func Context(markdown ...string) Block {
+	b := Block{Type: "context"}
+	for _, m := range markdown {
+		b.Elements = append(b.Elements, Text{Type: "mrkdwn", Text: m})
+	}
+	return b
+}
+
+// Code is text as preformatted sections, split between lines to fit.
+func Code(text string) []Block {
+	var blocks []Block
+	var chunk strings.Builder
+	flush := func() {
+		if chunk.Len() > 0 && len(blocks) < maxBlocks {
+			blocks = append(blocks, Section("```"+chunk.String()+"```"))
+		}
+		chunk.Reset()
+	}
+	for _, line := range strings.SplitAfter(Escape(strings.TrimRight(text, "\n")), "\n") {
+		if chunk.Len()+len(line) > maxSection {
+			flush()
+		}
+		chunk.WriteString(line[:min(len(line), maxSection)])
+	}
+	flush()
+	return blocks
+}
+
+// Escape makes text safe to send as mrkdwn.
+func Escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// Sign is the signature Slack sends with a request made at timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a request's signature against the app's signing secret,
+// and that it was made recently.
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing request timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > maxAge || age < -maxAge {
+		return errors.New("request is too old")
+	}
+	if !hmac.Equal([]byte(header.Get("X-Slack-Signature")), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+// Handler serves slash commands, answering each with run. Commands that
+// take too long are answered with a note, and their response is posted to
+// the command's response URL once it's ready.
+type Handler struct {
+	secret     string
+	run        func(Command) Response
+	httpClient *http.Client
+	wait       time.Duration
+	now        func() time.Time
+}
+
+func NewHandler(secret string, run func(Command) Response) *Handler {
+	return &Handler{
+		secret:     secret,
+		run:        run,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		wait:       replyWithin,
+		now:        time.Now,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "slash commands are POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := Verify(h.secret, r.Header, body, h.now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c := Command{
+		Team:        form.Get("team_id"),
+		User:        form.Get("user_id"),
+		UserName:    form.Get("user_name"),
+		Channel:     form.Get("channel_id"),
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		ResponseURL: form.Get("response_url"),
+	}
+
+	done := make(chan Response, 1)
+	go func() { done <- h.run(c) }()
+	select {
+	case res := <-done:
+		reply(w, res)
+	case <-time.After(h.wait):
+		reply(w, Response{ResponseType: "ephemeral", Text: "Working on it..."})
+		go func() {
+			if err := h.post(c.ResponseURL, <-done); err != nil {
+				log.Printf("slack: %v", err)
+			}
+		}()
+	}
+}
+
+func reply(w http.ResponseWriter, res Response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// post sends a late response to a command's response URL.
+func (h *Handler) post(url string, res Response) error {
+	if url == "" {
+		return errors.New("no response URL to answer on")
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	r, err := h.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", url, r.Status)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// request is a signed slash command request with form.
+func request(form url.Values, secret string, at time.Time) *http.Request {
+	body := form.Encode()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", Sign(secret, timestamp, []byte(body)))
+	return r
+}
+
+func TestHandler(t *testing.T) {
+	var got Command
+	h := NewHandler(secret, func(c Command) Response {
+		got = c
+		return Response{ResponseType: "in_channel", Text: "done", Blocks: Code("caught <pikachu>")}
+	})
+	form := url.Values{"user_id": {"U1"}, "user_name": {"ash"}, "command": {"/pokedex"}, "text": {"catch pikachu"}}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request(form, secret, time.Now()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if got.User != "U1" || got.Text != "catch pikachu" {
+		t.Errorf("Expected ash's command, got %+v", got)
+	}
+	var res Response
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("Expected a JSON response, got %s", w.Body)
+	}
+	if res.ResponseType != "in_channel" || res.Blocks[0].Text.Text != "```caught &lt;pikachu&gt;```" {
+		t.Errorf("Expected the escaped output in a code block, got %+v", res)
+	}
+
+	for name, r := range map[string]*http.Request{
+		"forged": request(form, "wrong", time.Now()),
+		"stale":  request(form, secret, time.Now().Add(-time.Hour)),
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a %s request to be refused, got %d", name, w.Code)
+		}
+	}
+}
+
+func TestHandlerAnswersLate(t *testing.T) {
+	late := make(chan Response, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res Response
+		json.NewDecoder(r.Body).Decode(&res)
+		late <- res
+	}))
+	defer hook.Close()
+
+	release := make(chan struct{})
+	h := NewHandler(secret, func(c Command) Response {
+		<-release
+		return Response{ResponseType: "in_channel", Text: "done"}
+	})
+	h.wait = 10 * time.Millisecond
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request(url.Values{"text": {"explore"}, "response_url": {hook.URL}}, secret, time.Now()))
+	if !strings.Contains(w.Body.String(), "Working on it") {
+		t.Errorf("Expected a slow command to be acknowledged, got %s", w.Body)
+	}
+	close(release)
+	select {
+	case res := <-late:
+		if res.Text != "done" {
+			t.Errorf("Expected the response to be posted later, got %+v", res)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the response to be posted to the response URL")
+	}
+}
+
+func TestCodeSplitsLongOutput(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	blocks := Code(strings.Repeat(line, 100))
+	if len(blocks) != 4 {
+		t.Fatalf("Expected 10000 characters to take 4 sections, got %d", len(blocks))
+	}
+	for _, b := range blocks {
+		if len(b.Text.Text) > 3000 {
+			t.Errorf("Expected every section to fit in a block, got %d characters", len(b.Text.Text))
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package snapshot keeps dated archives of each player's game, a zip file
+// each, so they can go back to how it was at any of them.
+package snapshot
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Layout is how a snapshot's time is written in its file name, in UTC, and
+// how players name it to restore it.
+const Layout = "20060102-150405"
+
+// ErrNotFound is returned by Open for a time there's no snapshot at.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Store keeps each player's snapshots in a directory of their own.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (st *Store) playerDir(player string) (string, error) {
+	if player == "" || strings.ContainsAny(player, `/\.`) {
+		return "", fmt.Errorf("invalid profile name %q", player)
+	}
+	return filepath.Join(st.dir, player), nil
+}
+
+// Take archives files, their contents by name, as player's snapshot at t.
+// A snapshot already taken at that second is replaced.
+func (st *Store) Take(player string, t time.Time, files map[string][]byte) error {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := zip.NewWriter(tmp)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: t})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Close(), tmp.Close()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, t.UTC().Format(Layout)+".zip"))
+}
+
+// List returns the times of player's snapshots, oldest first.
+func (st *Store) List(player string) ([]time.Time, error) {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var times []time.Time
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".zip")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if t, err := time.Parse(Layout, name); err == nil {
+			times = append(times, t)
+		}
+	}
+	slices.SortFunc(times, time.Time.Compare)
+	return times, nil
+}
+
+// Open returns the files in player's snapshot at t.
+func (st *Store) Open(player string, t time.Time) (map[string][]byte, error) {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zip.OpenReader(filepath.Join(dir, t.UTC().Format(Layout)+".zip"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", t.UTC().Format(Layout), err)
+		}
+		files[f.Name] = data
+	}
+	return files, nil
+}
+
+// Prune deletes all but player's keep newest snapshots.
+func (st *Store) Prune(player string, keep int) error {
+	dir, err := st.playerDir(player)
+	if err != nil {
+		return err
+	}
+	times, err := st.List(player)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range times[:max(len(times)-keep, 0)] {
+		errs = append(errs, os.Remove(filepath.Join(dir, t.UTC().Format(Layout)+".zip")))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTakeAndPrune(t *testing.T) {
+	st := NewStore(t.TempDir())
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	for day := range 5 {
+		files := map[string][]byte{"profile.json": []byte{'0' + byte(day)}}
+		if err := st.Take("ash", start.AddDate(0, 0, day), files); err != nil {
+			t.Fatalf("Take returned error: %v", err)
+		}
+	}
+	if err := st.Prune("ash", 3); err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+
+	times, err := st.List("ash")
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(times) != 3 || !times[0].Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("Expected the newest 3 snapshots from the third day, got %v", times)
+	}
+	files, err := st.Open("ash", times[2])
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if string(files["profile.json"]) != "4" {
+		t.Errorf("Expected the last snapshot's file, got %q", files["profile.json"])
+	}
+	if _, err := st.Open("ash", start); err != ErrNotFound {
+		t.Errorf("Expected the oldest snapshot to be gone, got %v", err)
+	}
+	if times, _ := st.List("misty"); len(times) != 0 {
+		t.Errorf("Expected no snapshots for another player, got %v", times)
+	}
+}
@@ -0,0 +1,261 @@
+// Package sprite draws pokemon sprites in the terminal. Terminals that show
+// images get the real picture through the kitty, iTerm2 or sixel graphics
+// protocol; others can have it as ASCII art.
+package sprite
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/png"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Protocol is how a sprite is drawn.
+type Protocol string
+
+const (
+	None  Protocol = "off"
+	Kitty Protocol = "kitty"
+	ITerm Protocol = "iterm"
+	Sixel Protocol = "sixel"
+	ASCII Protocol = "ascii"
+)
+
+// Protocols are the ones Draw takes, for settings.
+var Protocols = []Protocol{Kitty, ITerm, Sixel, ASCII, None}
+
+// scale is how many times bigger than their 96 pixels images are drawn, so
+// they aren't tiny on high resolution screens.
+const scale = 2
+
+// asciiWidth is the most columns ASCII art takes.
+const asciiWidth = 40
+
+// Detect guesses the protocol of the terminal from its environment, None if
+// it shows no images. Terminals can't be asked without putting them in raw
+// mode, which the REPL doesn't, so sixel terminals are only known by name.
+func Detect(getenv func(string) string) Protocol {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case term == "xterm-kitty" || term == "xterm-ghostty" || getenv("KITTY_WINDOW_ID") != "":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.HasPrefix(term, "contour"):
+		return Sixel
+	}
+	return None
+}
+
+// Draw writes the PNG image data to w with protocol p.
+func Draw(w io.Writer, p Protocol, data []byte) error {
+	if p == None {
+		return nil
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("bad sprite: %w", err)
+	}
+	img = crop(img)
+	if img.Bounds().Empty() {
+		return nil
+	}
+	if p == ASCII {
+		_, err := io.WriteString(w, ascii(img))
+		return err
+	}
+	img = enlarge(img, scale)
+	switch p {
+	case Kitty, ITerm:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		if p == Kitty {
+			_, err = io.WriteString(w, kitty(buf.Bytes()))
+		} else {
+			_, err = io.WriteString(w, iterm(buf.Bytes()))
+		}
+	case Sixel:
+		_, err = io.WriteString(w, sixel(img))
+	default:
+		return fmt.Errorf("unknown protocol %q", p)
+	}
+	return err
+}
+
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= 0x8000
+}
+
+// crop trims the transparent border PokeAPI's sprites have.
+func crop(img image.Image) image.Image {
+	b := img.Bounds()
+	r := image.Rectangle{Min: b.Max, Max: b.Min}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if opaque(img.At(x, y)) {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if r.Empty() {
+		return image.NewNRGBA(image.Rectangle{})
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := range r.Dy() {
+		for x := range r.Dx() {
+			out.Set(x, y, img.At(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return out
+}
+
+// enlarge scales img up n times, keeping the pixels sharp.
+func enlarge(img image.Image, n int) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx()*n, b.Dy()*n))
+	for y := range b.Dy() * n {
+		for x := range b.Dx() * n {
+			out.Set(x, y, img.At(b.Min.X+x/n, b.Min.Y+y/n))
+		}
+	}
+	return out
+}
+
+// kitty sends the PNG in the kitty graphics protocol's 4096 byte chunks.
+func kitty(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += 4096 {
+		chunk := encoded[i:min(i+4096, len(encoded))]
+		more := 0
+		if i+4096 < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String() + "\n"
+}
+
+// iterm sends the PNG as an iTerm2 inline file.
+func iterm(data []byte) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;preserveAspectRatio=1:%s\a\n", len(data), base64.StdEncoding.EncodeToString(data))
+}
+
+// sixel draws img six rows at a time. Sprites have few colors, so each gets
+// a register of its own; pictures with more than 256 are matched to the web
+// safe palette.
+func sixel(img image.Image) string {
+	b := img.Bounds()
+	var colors color.Palette
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if c := img.At(x, y); opaque(c) {
+				c := color.RGBAModel.Convert(c)
+				if !slices.Contains(colors, c) {
+					colors = append(colors, c)
+				}
+			}
+		}
+	}
+	if len(colors) > 256 {
+		colors = palette.WebSafe
+	}
+	// index is each pixel's register, -1 for transparent ones.
+	index := make([][]int, b.Dy())
+	for y := range index {
+		index[y] = make([]int, b.Dx())
+		for x := range index[y] {
+			index[y][x] = -1
+			if c := img.At(b.Min.X+x, b.Min.Y+y); opaque(c) {
+				index[y][x] = colors.Index(c)
+			}
+		}
+	}
+
+	var out strings.Builder
+	// P2=1 leaves pixels no color is drawn on transparent.
+	fmt.Fprintf(&out, "\x1bP0;1;0q\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i, c := range colors {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	for top := 0; top < b.Dy(); top += 6 {
+		for i := range colors {
+			var band []byte
+			used := false
+			for x := range b.Dx() {
+				var bits byte
+				for dy := range 6 {
+					if y := top + dy; y < b.Dy() && index[y][x] == i {
+						bits |= 1 << dy
+					}
+				}
+				used = used || bits != 0
+				band = append(band, 63+bits)
+			}
+			if !used {
+				continue
+			}
+			fmt.Fprintf(&out, "#%d", i)
+			writeRuns(&out, band)
+			out.WriteByte('$')
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\\n")
+	return out.String()
+}
+
+// writeRuns writes sixels, repeating runs with !<count>.
+func writeRuns(out *strings.Builder, band []byte) {
+	for i := 0; i < len(band); {
+		j := i
+		for j < len(band) && band[j] == band[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, band[i])
+		} else {
+			out.Write(band[i:j])
+		}
+		i = j
+	}
+}
+
+// ramp is from the darkest character to the brightest; transparent pixels
+// are spaces.
+const ramp = ".:-=+*#%@"
+
+// ascii draws img as characters, each twice as tall as it is wide.
+func ascii(img image.Image) string {
+	b := img.Bounds()
+	step := max(1, (b.Dx()+asciiWidth-1)/asciiWidth)
+	var out strings.Builder
+	for y := b.Min.Y; y < b.Max.Y; y += 2 * step {
+		line := make([]byte, 0, b.Dx()/step)
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := img.At(x, y)
+			if !opaque(c) {
+				line = append(line, ' ')
+				continue
+			}
+			gray := color.GrayModel.Convert(c).(color.Gray).Y
+			line = append(line, ramp[int(gray)*len(ramp)/256])
+		}
+		out.WriteString(strings.TrimRight(string(line), " ") + "\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,76 @@
+package sprite
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testPNG is a 4x4 picture with a transparent border around a red and a
+// white pixel.
+func testPNG(t *testing.T) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	img.Set(2, 1, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, ITerm},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-256color"}, None},
+	}
+	for _, c := range cases {
+		if got := Detect(func(key string) string { return c.env[key] }); got != c.want {
+			t.Errorf("Expected %s for %v, got %s", c.want, c.env, got)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	data := testPNG(t)
+	var buf bytes.Buffer
+	if err := Draw(&buf, ASCII, data); err != nil {
+		t.Fatalf("Draw() returned error: %v", err)
+	}
+	if got := buf.String(); got != "-@\n" {
+		t.Errorf("Expected the two cropped pixels, got %q", got)
+	}
+
+	buf.Reset()
+	Draw(&buf, Sixel, data)
+	if got := buf.String(); !strings.HasPrefix(got, "\x1bP0;1;0q\"1;1;4;2#0;2;100;0;0#1;2;100;100;100") || !strings.HasSuffix(got, "\x1b\\\n") {
+		t.Errorf("Expected a sixel image of the enlarged pixels, got %q", got)
+	}
+
+	buf.Reset()
+	Draw(&buf, Kitty, data)
+	if got := buf.String(); !strings.HasPrefix(got, "\x1b_Ga=T,f=100,m=0;") {
+		t.Errorf("Expected a kitty image, got %q", got)
+	}
+
+	buf.Reset()
+	if err := Draw(&buf, None, data); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected nothing drawn, got %q, %v", buf.String(), err)
+	}
+}
+
+func TestWriteRuns(t *testing.T) {
+	var b strings.Builder
+	writeRuns(&b, []byte("??????~~A"))
+	if got := b.String(); got != "!6?~~A" {
+		t.Errorf("Expected the run of six repeated, got %q", got)
+	}
+}
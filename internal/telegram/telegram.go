@@ -0,0 +1,135 @@
+// Package telegram is a small client for the Telegram Bot API: enough to
+// long-poll for updates and answer them with messages and inline keyboards.
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultURL is the Bot API.
+const DefaultURL = "https://api.telegram.org"
+
+// MaxText is the longest message Telegram takes.
+const MaxText = 4096
+
+type Update struct {
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int    `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	From      *User  `json:"from,omitempty"`
+	Text      string `json:"text"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// CallbackQuery is a press of an inline keyboard button.
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data"`
+}
+
+// Keyboard is an inline keyboard, in rows of buttons.
+type Keyboard struct {
+	Rows [][]Button `json:"inline_keyboard"`
+}
+
+// Button sends Data back in a callback query when pressed.
+type Button struct {
+	Text string `json:"text"`
+	Data string `json:"callback_data"`
+}
+
+// Client calls the Bot API as one bot.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient talks to the Bot API at apiURL with the bot's token.
+func NewClient(apiURL, token string) *Client {
+	return &Client{
+		baseURL: apiURL + "/bot" + token + "/",
+		// Long polls hold the request open for up to a minute.
+		httpClient: &http.Client{Timeout: 70 * time.Second},
+	}
+}
+
+// call invokes method with params, decoding its result into result.
+func (c *Client) call(method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(c.baseURL+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The error's URL has the token in it.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer res.Body.Close()
+	var reply struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	if !reply.OK {
+		return fmt.Errorf("telegram %s: %s", method, reply.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// Updates waits up to timeout for updates after offset, the ID of the last
+// update handled plus one.
+func (c *Client) Updates(offset int, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call("getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+// Send posts text to a chat, with a keyboard under it if there is one.
+func (c *Client) Send(chat int64, text string, keyboard *Keyboard) error {
+	params := map[string]any{"chat_id": chat, "text": text}
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+	return c.call("sendMessage", params, nil)
+}
+
+// Answer acknowledges a button press, which Telegram shows as loading until
+// it is.
+func (c *Client) Answer(query string) error {
+	return c.call("answerCallbackQuery", map[string]any{"callback_query_id": query}, nil)
+}
@@ -0,0 +1,47 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botsecret/getUpdates":
+			w.Write([]byte(`{"ok":true,"result":[{"update_id":7,"message":{"message_id":1,"chat":{"id":42},"text":"/explore"}}]}`))
+		case "/botsecret/sendMessage":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.Write([]byte(`{"ok":true,"result":{}}`))
+		default:
+			w.Write([]byte(`{"ok":false,"description":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "secret")
+
+	updates, err := c.Updates(0, time.Second)
+	if err != nil {
+		t.Fatalf("Updates() returned error: %v", err)
+	}
+	if len(updates) != 1 || updates[0].UpdateID != 7 || updates[0].Message.Chat.ID != 42 || updates[0].Message.Text != "/explore" {
+		t.Errorf("Expected an /explore message in chat 42, got %+v", updates)
+	}
+
+	keyboard := &Keyboard{Rows: [][]Button{{{Text: "Run", Data: "run"}}}}
+	if err := c.Send(42, "A wild pidgey appeared!", keyboard); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	markup, _ := sent["reply_markup"].(map[string]any)
+	if sent["text"] != "A wild pidgey appeared!" || markup["inline_keyboard"] == nil {
+		t.Errorf("Expected the message with its keyboard, got %v", sent)
+	}
+
+	if err := c.Answer("1"); err == nil || err.Error() != "telegram answerCallbackQuery: Not Found" {
+		t.Errorf("Expected the API's error, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package theme colors the game's output. A theme is a small TOML file:
+//
+//	prompt = "cyan"
+//	highlight = "bold yellow"
+//
+//	[types]
+//	fire = "red"
+//	water = "#3890f0"
+//
+// Colors are the names black, red, green, yellow, blue, magenta, cyan and
+// white, the styles bold, dim and underline, or #rrggbb, and can be
+// combined with spaces. Only this much of TOML is understood: comments,
+// one level of tables, and keys set to strings.
+package theme
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default is the theme used until another is picked. It colors nothing.
+const Default = "default"
+
+//go:embed themes/*.toml
+var builtins embed.FS
+
+// Theme is a set of colors, each given as Code takes them.
+type Theme struct {
+	Name string
+	// Prompt colors the REPL prompt.
+	Prompt string
+	// Highlight colors what stands out, such as shiny pokemon and catches.
+	Highlight string
+	// Types colors type names, by PokeAPI name.
+	Types map[string]string
+}
+
+var names = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"bold":      "1",
+	"dim":       "2",
+	"underline": "4",
+}
+
+// Code is the ANSI SGR parameters for color, such as "1;33" for
+// "bold yellow".
+func Code(color string) (string, error) {
+	var codes []string
+	for _, word := range strings.Fields(color) {
+		if hex, ok := strings.CutPrefix(word, "#"); ok {
+			rgb, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil || len(hex) != 6 {
+				return "", fmt.Errorf("bad color %q", word)
+			}
+			codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", rgb>>16, rgb>>8&0xff, rgb&0xff))
+			continue
+		}
+		code, ok := names[word]
+		if !ok {
+			return "", fmt.Errorf("unknown color %q", word)
+		}
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, ";"), nil
+}
+
+// Paint wraps text in color's escape codes. Colors that don't parse, and
+// empty ones, leave text as it is.
+func Paint(color, text string) string {
+	code, err := Code(color)
+	if err != nil || code == "" {
+		return text
+	}
+	return "\033[" + code + "m" + text + "\033[0m"
+}
+
+// Type paints a type's name in its color.
+func (t Theme) Type(name string) string {
+	return Paint(t.Types[name], name)
+}
+
+// Load finds the theme called name: a file name.toml in dir, if dir is
+// set, or else a built-in one.
+func Load(dir, name string) (Theme, error) {
+	if name == Default {
+		return Theme{Name: Default}, nil
+	}
+	data, err := []byte(nil), os.ErrNotExist
+	if dir != "" {
+		data, err = os.ReadFile(filepath.Join(dir, name+".toml"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = builtins.ReadFile("themes/" + name + ".toml")
+		if err != nil {
+			return Theme{}, fmt.Errorf("there's no %s theme", name)
+		}
+	}
+	if err != nil {
+		return Theme{}, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s theme: %w", name, err)
+	}
+	t.Name = name
+	return t, nil
+}
+
+// List is the names of the built-in themes and those in dir, sorted.
+func List(dir string) []string {
+	list := []string{Default}
+	entries, _ := builtins.ReadDir("themes")
+	files, _ := os.ReadDir(dir)
+	for _, e := range append(entries, files...) {
+		if name, ok := strings.CutSuffix(e.Name(), ".toml"); ok && !slices.Contains(list, name) {
+			list = append(list, name)
+		}
+	}
+	slices.Sort(list)
+	return list
+}
+
+// Parse reads a theme file, checking its colors.
+func Parse(data []byte) (Theme, error) {
+	t := Theme{Types: map[string]string{}}
+	table := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "["); ok {
+			name, ok = strings.CutSuffix(name, "]")
+			if !ok {
+				return Theme{}, fmt.Errorf("line %d: bad table header", i+1)
+			}
+			table = strings.TrimSpace(name)
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Theme{}, fmt.Errorf("line %d: expected key = \"value\"", i+1)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value, err := parseString(strings.TrimSpace(value))
+		if err != nil {
+			return Theme{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if _, err := Code(value); err != nil {
+			return Theme{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case table == "types":
+			t.Types[key] = value
+		case table == "" && key == "prompt":
+			t.Prompt = value
+		case table == "" && key == "highlight":
+			t.Highlight = value
+		default:
+			return Theme{}, fmt.Errorf("line %d: unknown setting %s", i+1, strings.TrimPrefix(table+"."+key, "."))
+		}
+	}
+	return t, nil
+}
+
+// parseString reads a quoted TOML string, and what may follow it: a
+// comment.
+func parseString(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", errors.New("expected a quoted string")
+	}
+	end := strings.Index(s[1:], `"`)
+	if end < 0 {
+		return "", errors.New("unterminated string")
+	}
+	if rest := strings.TrimSpace(s[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected %s after the string", rest)
+	}
+	return s[1 : end+1], nil
+}
@@ -0,0 +1,76 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	cases := map[string]string{
+		"red":         "31",
+		"bold yellow": "1;33",
+		"#8bac0f":     "38;2;139;172;15",
+		"":            "",
+	}
+	for color, want := range cases {
+		if got, err := Code(color); err != nil || got != want {
+			t.Errorf("Code(%q): expected %q, got %q, %v", color, want, got, err)
+		}
+	}
+	for _, bad := range []string{"mauve", "#12345", "#gggggg"} {
+		if _, err := Code(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+	if got := Paint("red", "fire"); got != "\033[31mfire\033[0m" {
+		t.Errorf("Expected fire in red, got %q", got)
+	}
+	if got := Paint("", "normal"); got != "normal" {
+		t.Errorf("Expected no color to leave the text alone, got %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	th, err := Parse([]byte(`# mine
+prompt = "green"   # calm
+highlight = "bold #ff0000"
+
+[types]
+"fire" = "red"
+`))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if th.Prompt != "green" || th.Highlight != "bold #ff0000" || th.Types["fire"] != "red" {
+		t.Errorf("Expected the theme's colors, got %+v", th)
+	}
+	for _, bad := range []string{`prompt = green`, `prompt = "mauve"`, `[types`, `accent = "red"`, `prompt = "red" extra`} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "sunset.toml"), []byte(`prompt = "magenta"`), 0o644)
+	os.WriteFile(filepath.Join(dir, "dark.toml"), []byte(`prompt = "white"`), 0o644)
+
+	for _, name := range []string{"dark", "light", "gameboy-green"} {
+		if _, err := Load("", name); err != nil {
+			t.Errorf("Expected the built-in %s theme to load, got %v", name, err)
+		}
+	}
+	if th, err := Load(dir, "dark"); err != nil || th.Prompt != "white" || th.Name != "dark" {
+		t.Errorf("Expected the user's theme over the built-in one, got %+v, %v", th, err)
+	}
+	if _, err := Load(dir, "neon"); err == nil {
+		t.Errorf("Expected a missing theme to be an error")
+	}
+	want := []string{"dark", "default", "gameboy-green", "light", "sunset"}
+	if got := List(dir); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
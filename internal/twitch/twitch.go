@@ -0,0 +1,133 @@
+// Package twitch reads and writes a Twitch channel's chat over IRC, and
+// tallies the votes viewers cast in it.
+package twitch
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAddr is Twitch's chat server.
+const DefaultAddr = "irc.chat.twitch.tv:6697"
+
+// anonymousNick logs in read-only, for when there's no token to chat with.
+const anonymousNick = "justinfan1337"
+
+// Message is something a viewer said in chat.
+type Message struct {
+	User string
+	Text string
+}
+
+// Client is a connection to one channel's chat.
+type Client struct {
+	conn    io.ReadWriteCloser
+	r       *bufio.Reader
+	channel string
+	// readOnly is set when logged in anonymously, which can't chat.
+	readOnly bool
+
+	mu sync.Mutex
+}
+
+// Dial connects to the chat server at addr over TLS and joins channel.
+// Without a token it joins anonymously and can only read.
+func Dial(addr, nick, token, channel string) (*Client, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(conn, nick, token, channel)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// New logs in on an open connection to the chat server and joins channel.
+func New(conn io.ReadWriteCloser, nick, token, channel string) (*Client, error) {
+	c := &Client{conn: conn, r: bufio.NewReader(conn), channel: "#" + strings.TrimPrefix(strings.ToLower(channel), "#")}
+	if token == "" {
+		nick, c.readOnly = anonymousNick, true
+	} else {
+		if err := c.send("PASS oauth:" + strings.TrimPrefix(token, "oauth:")); err != nil {
+			return nil, err
+		}
+	}
+	for _, line := range []string{"NICK " + strings.ToLower(nick), "JOIN " + c.channel} {
+		if err := c.send(line); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Client) send(line string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := io.WriteString(c.conn, line+"\r\n")
+	return err
+}
+
+// Read waits for the next chat message in the channel, answering the
+// server's pings along the way.
+func (c *Client) Read() (Message, error) {
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return Message{}, err
+		}
+		prefix, command, params, text := parse(strings.TrimRight(line, "\r\n"))
+		switch command {
+		case "PING":
+			if err := c.send("PONG :" + text); err != nil {
+				return Message{}, err
+			}
+		case "NOTICE":
+			// Twitch's only notice before joining is a failed login.
+			if params == "*" {
+				return Message{}, fmt.Errorf("twitch: %s", text)
+			}
+		case "PRIVMSG":
+			if params != c.channel {
+				continue
+			}
+			user, _, _ := strings.Cut(prefix, "!")
+			return Message{User: strings.ToLower(user), Text: text}, nil
+		}
+	}
+}
+
+// parse splits an IRC line into its prefix, command, middle parameters and
+// trailing text, skipping any IRCv3 tags.
+func parse(line string) (prefix, command, params, text string) {
+	if strings.HasPrefix(line, "@") {
+		_, line, _ = strings.Cut(line, " ")
+	}
+	if strings.HasPrefix(line, ":") {
+		prefix, line, _ = strings.Cut(line[1:], " ")
+	}
+	line, text, _ = strings.Cut(line, " :")
+	command, params, _ = strings.Cut(line, " ")
+	return prefix, command, params, text
+}
+
+// Say sends text to the channel. It does nothing when logged in
+// anonymously.
+func (c *Client) Say(text string) error {
+	if c.readOnly {
+		return nil
+	}
+	return c.send("PRIVMSG " + c.channel + " :" + text)
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
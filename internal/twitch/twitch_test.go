@@ -0,0 +1,100 @@
+package twitch
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	lines := bufio.NewScanner(server)
+	joined := make(chan []string, 1)
+	go func() {
+		var got []string
+		for len(got) < 3 && lines.Scan() {
+			got = append(got, lines.Text())
+		}
+		joined <- got
+	}()
+	c, err := New(conn, "Bot", "secret", "#Stream")
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer c.Close()
+	if got := strings.Join(<-joined, "|"); got != "PASS oauth:secret|NICK bot|JOIN #stream" {
+		t.Errorf("Expected to log in and join #stream, got %q", got)
+	}
+
+	go func() {
+		server.Write([]byte("PING :tmi.twitch.tv\r\n"))
+		lines.Scan()
+		if lines.Text() != "PONG :tmi.twitch.tv" {
+			t.Errorf("Expected a pong, got %q", lines.Text())
+		}
+		server.Write([]byte(":ash!ash@ash.tmi.twitch.tv PRIVMSG #elsewhere :hi\r\n"))
+		server.Write([]byte("@badges=moderator/1;display-name=Ash :ash!ash@ash.tmi.twitch.tv PRIVMSG #stream :!fight tackle\r\n"))
+	}()
+	m, err := c.Read()
+	if err != nil {
+		t.Fatalf("Read() returned error: %v", err)
+	}
+	if m.User != "ash" || m.Text != "!fight tackle" {
+		t.Errorf("Expected ash's vote, got %+v", m)
+	}
+}
+
+func TestAnonymousClientCantSay(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	go func() {
+		for lines := bufio.NewScanner(server); lines.Scan(); {
+		}
+	}()
+	c, err := New(conn, "", "", "stream")
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if !c.readOnly {
+		t.Errorf("Expected a client without a token to be read-only")
+	}
+	if err := c.Say("hello"); err != nil {
+		t.Errorf("Expected Say() to do nothing, got %v", err)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	p := NewPoll()
+	p.Vote("ash", "run")
+	p.Vote("misty", "catch")
+	p.Vote("brock", "catch")
+	p.Vote("brock", "run")
+	if choice, votes, total := p.Result(); choice != "run" || votes != 2 || total != 3 {
+		t.Errorf("Expected run to win 2 of 3 votes, got %s %d of %d", choice, votes, total)
+	}
+	p.Vote("misty", "run")
+	p.Vote("brock", "catch")
+	p.Reset()
+	p.Vote("gary", "catch")
+	p.Vote("ash", "run")
+	if choice, _, _ := p.Result(); choice != "catch" {
+		t.Errorf("Expected the first choice voted for to win a tie, got %s", choice)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(time.Second)
+	now := time.Now()
+	if !l.Allow("ash", now) || !l.Allow("misty", now) {
+		t.Errorf("Expected everyone's first message to be allowed")
+	}
+	if l.Allow("ash", now.Add(time.Second/2)) {
+		t.Errorf("Expected a second message within the interval to be dropped")
+	}
+	if !l.Allow("ash", now.Add(time.Second)) {
+		t.Errorf("Expected a message after the interval to be allowed")
+	}
+}
@@ -0,0 +1,69 @@
+package twitch
+
+import "time"
+
+// Poll tallies one round of votes. Every viewer has one vote; voting again
+// changes it.
+type Poll struct {
+	votes map[string]string
+	// order is the choices in the order they were first voted for, which
+	// breaks ties.
+	order []string
+}
+
+func NewPoll() *Poll {
+	return &Poll{votes: map[string]string{}}
+}
+
+// Vote records user's vote for choice.
+func (p *Poll) Vote(user, choice string) {
+	p.votes[user] = choice
+	for _, c := range p.order {
+		if c == choice {
+			return
+		}
+	}
+	p.order = append(p.order, choice)
+}
+
+// Result is the choice with the most votes and how many it got, and the
+// number of votes cast. On a tie, the choice voted for first wins.
+func (p *Poll) Result() (choice string, votes, total int) {
+	counts := map[string]int{}
+	for _, c := range p.votes {
+		counts[c]++
+	}
+	for _, c := range p.order {
+		if counts[c] > votes {
+			choice, votes = c, counts[c]
+		}
+	}
+	return choice, votes, len(p.votes)
+}
+
+// Reset clears the poll for the next round.
+func (p *Poll) Reset() {
+	clear(p.votes)
+	p.order = p.order[:0]
+}
+
+// Limiter lets each user through at most once per interval, so nobody can
+// flood the poll.
+type Limiter struct {
+	interval time.Duration
+	last     map[string]time.Time
+}
+
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{interval: interval, last: map[string]time.Time{}}
+}
+
+// Allow reports whether user may be heard at now, and if so starts their
+// next interval.
+func (l *Limiter) Allow(user string, now time.Time) bool {
+	if last, ok := l.last[user]; ok && now.Sub(last) < l.interval {
+		return false
+	}
+	l.last[user] = now
+	return true
+}
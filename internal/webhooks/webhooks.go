@@ -0,0 +1,115 @@
+// Package webhooks posts game events to user-configured URLs.
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+const queueSize = 64
+
+// Payload is the JSON body of a webhook request. Text and Content carry the
+// same summary so Slack and Discord incoming webhooks can take it as is.
+type Payload struct {
+	events.Event
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+type delivery struct {
+	url     string
+	payload Payload
+}
+
+// Notifier sends events in the background so a slow endpoint never holds up
+// a command. When the queue is full, new deliveries are dropped.
+type Notifier struct {
+	hooks      []config.Webhook
+	httpClient *http.Client
+	queue      chan delivery
+}
+
+func New(hooks []config.Webhook) *Notifier {
+	n := &Notifier{
+		hooks:      hooks,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		queue:      make(chan delivery, queueSize),
+	}
+	go n.sendLoop()
+	return n
+}
+
+// Notify queues e for every webhook that wants it. It's an events.Handler.
+func (n *Notifier) Notify(e events.Event) {
+	for _, hook := range n.hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, string(e.Kind)) {
+			continue
+		}
+		summary := Summary(e)
+		select {
+		case n.queue <- delivery{url: hook.URL, payload: Payload{Event: e, Text: summary, Content: summary}}:
+		default:
+			log.Printf("webhook queue full, dropping %s event", e.Kind)
+		}
+	}
+}
+
+func (n *Notifier) sendLoop() {
+	for d := range n.queue {
+		if err := n.send(d); err != nil {
+			log.Printf("webhook %s: %v", d.url, err)
+		}
+	}
+}
+
+func (n *Notifier) send(d delivery) error {
+	body, err := json.Marshal(d.payload)
+	if err != nil {
+		return err
+	}
+	res, err := n.httpClient.Post(d.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	return nil
+}
+
+// Summary is a one-line, human readable description of e.
+func Summary(e events.Event) string {
+	switch e.Kind {
+	case events.Caught:
+		return fmt.Sprintf("%s caught %s!", e.Session, e.Pokemon)
+	case events.Escaped:
+		return fmt.Sprintf("%s escaped from %s.", e.Pokemon, e.Session)
+	case events.Explored:
+		return fmt.Sprintf("%s explored %s.", e.Session, e.Area)
+	case events.Encountered:
+		return fmt.Sprintf("A wild %s (Lv. %d) appeared in front of %s!", e.Pokemon, e.Level, e.Session)
+	case events.Fled:
+		return fmt.Sprintf("The wild %s fled from %s.", e.Pokemon, e.Session)
+	case events.LeveledUp:
+		return fmt.Sprintf("%s's %s grew to level %d!", e.Session, e.Pokemon, e.Level)
+	case events.ShinyFound:
+		return fmt.Sprintf("%s found a shiny %s!", e.Session, e.Pokemon)
+	case events.Milestone:
+		return fmt.Sprintf("%s has caught %d kinds of pokemon!", e.Session, e.Count)
+	case events.HallOfFame:
+		if e.Competition != "" {
+			return fmt.Sprintf("%s won the %s!", e.Session, e.Competition)
+		}
+		return fmt.Sprintf("%s entered the Hall of Fame!", e.Session)
+	}
+	return fmt.Sprintf("%s: %s", e.Session, e.Kind)
+}
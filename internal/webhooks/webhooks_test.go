@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+func TestNotifyFiltersEvents(t *testing.T) {
+	received := make(chan Payload, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		received <- p
+	}))
+	defer server.Close()
+
+	n := New([]config.Webhook{{URL: server.URL, Events: []string{"milestone"}}})
+	n.Notify(events.Event{Kind: events.Caught, Session: "ash", Pokemon: "pidgey"})
+	n.Notify(events.Event{Kind: events.Milestone, Session: "ash", Count: 10})
+
+	select {
+	case p := <-received:
+		if p.Kind != events.Milestone || p.Count != 10 {
+			t.Errorf("Unexpected payload %+v", p)
+		}
+		if p.Text != "ash has caught 10 kinds of pokemon!" {
+			t.Errorf("Unexpected text %q", p.Text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was never called")
+	}
+}
@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+	// The example from RFC 6455.
+	if got := accept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Expected the RFC's accept key, got %s", got)
+	}
+}
+
+func TestConn(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		c.WriteText([]byte("hello"))
+		c.WriteText([]byte(strings.Repeat("x", 70000)))
+		_, payload, err := c.Read()
+		if err == nil {
+			received <- string(payload)
+		}
+		if _, _, err := c.Read(); err != io.EOF {
+			t.Errorf("Expected the client's close, got %v", err)
+		}
+	}))
+	defer server.Close()
+
+	c, err := Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/events")
+	if err != nil {
+		t.Fatalf("Dial() returned error: %v", err)
+	}
+	if _, payload, err := c.Read(); err != nil || string(payload) != "hello" {
+		t.Errorf("Expected hello, got %q, %v", payload, err)
+	}
+	if _, payload, err := c.Read(); err != nil || len(payload) != 70000 {
+		t.Errorf("Expected a long message, got %d bytes, %v", len(payload), err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping() returned error: %v", err)
+	}
+	c.WriteText([]byte("bye"))
+	if got := <-received; got != "bye" {
+		t.Errorf("Expected the server to read the client's message, got %q", got)
+	}
+	c.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a plain request to be refused, got %s", res.Status)
+	}
+}
@@ -0,0 +1,53 @@
+package wondertrade
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to a wonder trade server.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Trade sends d, returning what it was traded for, if anything was waiting.
+func (c *Client) Trade(d Deposit) (Trade, error) {
+	var t Trade
+	err := c.post("/trade", d, &t)
+	return t, err
+}
+
+// Collect takes what trainer's earlier deposits were traded for since they
+// last collected.
+func (c *Client) Collect(trainer string) ([]Deposit, error) {
+	var mail []Deposit
+	err := c.post("/collect", map[string]string{"trainer": trainer}, &mail)
+	return mail, err
+}
+
+func (c *Client) post(path string, req, into any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(c.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("wonder trade server: %s", strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
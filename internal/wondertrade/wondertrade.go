@@ -0,0 +1,172 @@
+// Package wondertrade is a server for surprise trades. A player sends a
+// pokemon and gets back one another player sent earlier, at random; if
+// nobody else's is waiting, theirs waits for the next trader instead and
+// what they get for it is kept until they collect it.
+package wondertrade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// DefaultAddr is where the server listens unless told otherwise, and
+// DefaultURL where clients look for it.
+const (
+	DefaultAddr = ":7782"
+	DefaultURL  = "http://localhost:7782"
+)
+
+// Deposit is a pokemon sent for trade: who sent it, when, and its pokedex
+// entry, so whoever gets it knows what it is.
+type Deposit struct {
+	Trainer string              `json:"trainer"`
+	Time    time.Time           `json:"time"`
+	Pokemon profile.Pokemon     `json:"pokemon"`
+	Species pokeapi.PokemonType `json:"species"`
+}
+
+// Trade is the server's answer to a deposit: the pokemon received for it,
+// or nil if it's waiting for another trader.
+type Trade struct {
+	Received *Deposit `json:"received,omitempty"`
+}
+
+// Validate checks that d is from someone, and could be a real pokemon,
+// caught and raised in the game, and so can be traded.
+func Validate(d Deposit) error {
+	switch {
+	case d.Trainer == "":
+		return errors.New("missing trainer")
+	case d.Pokemon.Species != d.Species.Name:
+		return errors.New("the pokemon doesn't match its pokedex entry")
+	}
+	return d.Pokemon.Validate()
+}
+
+// Server keeps the pokemon waiting for a trade and those waiting to be
+// collected, saving them to a JSON file after every change.
+type Server struct {
+	mu   sync.Mutex
+	path string
+	// Waiting are deposits nobody has traded for yet, oldest first.
+	Waiting []Deposit `json:"waiting"`
+	// Mail has what each trainer got for their deposits, to collect.
+	Mail map[string][]Deposit `json:"mail"`
+}
+
+// NewServer loads the trades saved at path. An empty path keeps them in
+// memory only.
+func NewServer(path string) (*Server, error) {
+	sv := &Server{path: path, Mail: map[string][]Deposit{}}
+	if path == "" {
+		return sv, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sv, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, sv); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if sv.Mail == nil {
+		sv.Mail = map[string][]Deposit{}
+	}
+	return sv, nil
+}
+
+func (sv *Server) save() error {
+	if sv.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(sv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sv.path, data, 0o644)
+}
+
+// Handler serves the trades:
+//
+//	POST /trade    a Deposit: trade it, or leave it waiting
+//	POST /collect  {"trainer"}: take what the trainer's deposits got
+func (sv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /trade", sv.trade)
+	mux.HandleFunc("POST /collect", sv.collect)
+	return mux
+}
+
+func (sv *Server) trade(w http.ResponseWriter, r *http.Request) {
+	var d Deposit
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := Validate(d); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d.Time = time.Now()
+
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	var others []int
+	for i, waiting := range sv.Waiting {
+		if waiting.Trainer == d.Trainer {
+			http.Error(w, "you already have a pokemon waiting for a trade", http.StatusConflict)
+			return
+		}
+		others = append(others, i)
+	}
+	var t Trade
+	if len(others) == 0 {
+		sv.Waiting = append(sv.Waiting, d)
+	} else {
+		i := others[rand.IntN(len(others))]
+		received := sv.Waiting[i]
+		sv.Waiting = append(sv.Waiting[:i], sv.Waiting[i+1:]...)
+		sv.Mail[received.Trainer] = append(sv.Mail[received.Trainer], d)
+		t.Received = &received
+	}
+	respond(w, sv, t)
+}
+
+func (sv *Server) collect(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Trainer string `json:"trainer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Trainer == "" {
+		http.Error(w, "missing trainer", http.StatusBadRequest)
+		return
+	}
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	mail := sv.Mail[req.Trainer]
+	delete(sv.Mail, req.Trainer)
+	if mail == nil {
+		mail = []Deposit{}
+	}
+	respond(w, sv, mail)
+}
+
+// respond saves the trades and answers with body; sv.mu must be held.
+func respond(w http.ResponseWriter, sv *Server, body any) {
+	if err := sv.save(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,96 @@
+package wondertrade
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func deposit(trainer, species string, level int) Deposit {
+	return Deposit{
+		Trainer: trainer,
+		Pokemon: profile.Pokemon{Species: species, Level: level, OT: trainer},
+		Species: pokeapi.PokemonType{Name: species},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(deposit("red", "pikachu", 5)); err != nil {
+		t.Errorf("Expected a plain pikachu to be valid, got %v", err)
+	}
+	bad := map[string]func(d *Deposit){
+		"level 0":          func(d *Deposit) { d.Pokemon.Level = 0 },
+		"level 101":        func(d *Deposit) { d.Pokemon.Level = 101 },
+		"no trainer":       func(d *Deposit) { d.Trainer = "" },
+		"wrong entry":      func(d *Deposit) { d.Species.Name = "raichu" },
+		"IV 32":            func(d *Deposit) { d.Pokemon.IVs = map[string]int{"hp": 32} },
+		"253 EVs":          func(d *Deposit) { d.Pokemon.EVs = map[string]int{"attack": 253} },
+		"511 EVs in all":   func(d *Deposit) { d.Pokemon.EVs = map[string]int{"attack": 252, "speed": 252, "hp": 7} },
+		"friendship 256":   func(d *Deposit) { d.Pokemon.Friendship = 256 },
+		"condition 256":    func(d *Deposit) { d.Pokemon.Condition = map[string]int{"cool": 256} },
+		"five moves known": func(d *Deposit) { d.Pokemon.Moves = make([]battle.Move, 5) },
+	}
+	for name, spoil := range bad {
+		d := deposit("red", "pikachu", 5)
+		spoil(&d)
+		if err := Validate(d); err == nil {
+			t.Errorf("Expected a pokemon with %s to be refused", name)
+		}
+	}
+}
+
+func TestTrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wondertrade.json")
+	sv, err := NewServer(path)
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+	c := NewClient(ts.URL)
+
+	tr, err := c.Trade(deposit("red", "pikachu", 5))
+	if err != nil {
+		t.Fatalf("Trade() returned error: %v", err)
+	}
+	if tr.Received != nil {
+		t.Errorf("Expected the first deposit to wait, got %+v", tr.Received)
+	}
+	if _, err := c.Trade(deposit("red", "zubat", 3)); err == nil {
+		t.Errorf("Expected a second deposit from red to be refused while one waits")
+	}
+	if _, err := c.Trade(deposit("blue", "pikachu", 0)); err == nil {
+		t.Errorf("Expected an invalid deposit to be refused")
+	}
+
+	tr, err = c.Trade(deposit("blue", "eevee", 10))
+	if err != nil {
+		t.Fatalf("Trade() returned error: %v", err)
+	}
+	if tr.Received == nil || tr.Received.Pokemon.Species != "pikachu" || tr.Received.Trainer != "red" {
+		t.Errorf("Expected blue to receive red's pikachu, got %+v", tr.Received)
+	}
+
+	// The mail outlives the server.
+	sv, err = NewServer(path)
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	ts = httptest.NewServer(sv.Handler())
+	defer ts.Close()
+	c = NewClient(ts.URL)
+	mail, err := c.Collect("red")
+	if err != nil {
+		t.Fatalf("Collect() returned error: %v", err)
+	}
+	if len(mail) != 1 || mail[0].Pokemon.Species != "eevee" || mail[0].Trainer != "blue" {
+		t.Errorf("Expected red to collect blue's eevee, got %+v", mail)
+	}
+	if mail, _ := c.Collect("red"); len(mail) != 0 {
+		t.Errorf("Expected the mail to be collected once, got %+v", mail)
+	}
+}
@@ -0,0 +1,93 @@
+// Package world works out which location areas are next to each other.
+//
+// PokeAPI has no map connections, so adjacency is derived from what it does
+// have: areas of the same location are a short walk apart, and a location
+// borders the ones listed just before and after it in its region.
+package world
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const (
+	// SameLocationSteps is the walk between two areas of one location.
+	SameLocationSteps = 10
+	// NextLocationSteps is the walk to a bordering location.
+	NextLocationSteps = 50
+	// maxSkip bounds how many area-less locations are skipped looking for a
+	// neighbour, so one lookup can't walk the whole region.
+	maxSkip = 5
+)
+
+type Neighbour struct {
+	Area     string
+	Location string
+	Steps    int
+}
+
+// Neighbours lists the areas directly reachable from area.
+func Neighbours(src pokeapi.DataSource, area string) ([]Neighbour, error) {
+	details, err := src.LocationArea(area)
+	if err != nil {
+		return nil, err
+	}
+	if details.Location.Name == "" {
+		return nil, errors.New("the data source doesn't say where " + area + " is")
+	}
+	location, err := src.Location(details.Location.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbours := []Neighbour{}
+	for _, a := range location.Areas {
+		if a.Name != area {
+			neighbours = append(neighbours, Neighbour{Area: a.Name, Location: location.Name, Steps: SameLocationSteps})
+		}
+	}
+	if location.Region.Name == "" {
+		return neighbours, nil
+	}
+
+	region, err := src.Region(location.Region.Name)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(region.Locations, func(l pokeapi.Location) bool { return l.Name == location.Name })
+	if i < 0 {
+		return neighbours, nil
+	}
+	// Some locations have no areas; walk past them to the next one that does.
+	for _, step := range []int{-1, 1} {
+		for j := i + step; j >= 0 && j < len(region.Locations) && abs(j-i) <= maxSkip; j += step {
+			next, err := src.Location(region.Locations[j].Name)
+			if err != nil {
+				return nil, err
+			}
+			if len(next.Areas) > 0 {
+				neighbours = append(neighbours, Neighbour{Area: next.Areas[0].Name, Location: next.Name, Steps: NextLocationSteps})
+				break
+			}
+		}
+	}
+	return neighbours, nil
+}
+
+// Find returns the neighbour for area, if it is one.
+func Find(neighbours []Neighbour, area string) (Neighbour, bool) {
+	i := slices.IndexFunc(neighbours, func(n Neighbour) bool { return n.Area == area })
+	if i < 0 {
+		return Neighbour{}, false
+	}
+	return neighbours[i], true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
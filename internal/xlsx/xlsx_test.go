@@ -0,0 +1,65 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := Column(i); got != want {
+			t.Errorf("Column(%d) = %s, expected %s", i, got, want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]Cell{
+		{{Text: "Box 1", Style: Bold}},
+		{{Text: "001 Bulbasaur ●", Style: Green}, {}, {Text: "<Mr. Mime & co>"}},
+	}
+	if err := Write(&buf, "Living dex", rows); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected a zip file, got %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(data)
+		var doc struct{ XMLName xml.Name }
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			t.Errorf("Expected %s to be well-formed XML, got %v", f.Name, err)
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Expected the workbook to have %s", name)
+		}
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Box 1</t></is></c>`,
+		`<c r="A2" t="inlineStr" s="2">`,
+		`<c r="C2" t="inlineStr"><is><t xml:space="preserve">&lt;Mr. Mime &amp; co&gt;</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Expected the sheet to have %s, got %s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, `r="B2"`) {
+		t.Errorf("Expected empty cells to be left out")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// levelUpGroup is the version group whose level-up learnset is used out of
+// moves: the configured one, or else the newest one that has any.
+func levelUpGroup(s *session, moves []pokeapi.PokemonMove) string {
+	if s.versionGroup != "" {
+		return s.versionGroup
+	}
+	group, newest := "", -1
+	for _, m := range moves {
+		for _, detail := range m.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && detail.VersionGroup.ID() > newest {
+				group, newest = detail.VersionGroup.Name, detail.VersionGroup.ID()
+			}
+		}
+	}
+	return group
+}
+
+// levelUpMoves are the moves species learns on reaching level.
+func levelUpMoves(s *session, species string, level int) ([]string, error) {
+	moves, err := s.source.PokemonMoves(species)
+	if err != nil {
+		return nil, err
+	}
+	group := levelUpGroup(s, moves)
+	var names []string
+	for _, m := range moves {
+		for _, detail := range m.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && detail.VersionGroup.Name == group && detail.LevelLearnedAt == level {
+				names = append(names, m.Move.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// learnLevelUpMoves teaches p the moves of its new level. Once it knows
+// four, the player is asked which to forget, if the front end can ask.
+func learnLevelUpMoves(s *session, p *profile.Pokemon) {
+	names, err := levelUpMoves(s, p.Species, p.Level)
+	if err != nil {
+		fmt.Fprintf(s.out, "Couldn't look up the moves %s learns: %v\n", p.Species, err)
+		return
+	}
+	for _, name := range names {
+		moves := slices.Clone(knownMoves(s, p))
+		if slices.ContainsFunc(moves, func(m battle.Move) bool { return m.Name == name }) {
+			continue
+		}
+		details, err := s.source.Move(name)
+		if err != nil {
+			fmt.Fprintf(s.out, "Couldn't look up %s: %v\n", name, err)
+			continue
+		}
+		move := battleMove(details)
+		if len(moves) < battle.MaxMoves {
+			p.Moves = append(moves, move)
+			fmt.Fprintf(s.out, "%s learned %s!\n", p.Species, name)
+			continue
+		}
+		i, ok := askForget(s, p.Species, name, moves)
+		if !ok {
+			fmt.Fprintf(s.out, "%s did not learn %s.\n", p.Species, name)
+			continue
+		}
+		fmt.Fprintf(s.out, "%s forgot %s and learned %s!\n", p.Species, moves[i].Name, name)
+		moves[i] = move
+		p.Moves = moves
+	}
+}
+
+// askForget asks which of moves to forget for name, until the player picks
+// one by number or name or skips it.
+func askForget(s *session, species, name string, moves []battle.Move) (int, bool) {
+	known := make([]string, len(moves))
+	for i, m := range moves {
+		known[i] = fmt.Sprintf("%d. %s", i+1, m.Name)
+	}
+	fmt.Fprintf(s.out, "%s wants to learn %s, but it already knows %d moves: %s\n", species, name, battle.MaxMoves, strings.Join(known, ", "))
+	for {
+		answer, ok := s.ask(fmt.Sprintf("Forget which move? (1-%d, or skip) ", len(moves)))
+		if !ok || answer == "skip" || answer == "" {
+			return 0, false
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(moves) {
+			return n - 1, true
+		}
+		if i := slices.IndexFunc(moves, func(m battle.Move) bool { return m.Name == answer }); i >= 0 {
+			return i, true
+		}
+		fmt.Fprintf(s.out, "%s doesn't know %s\n", species, answer)
+	}
+}
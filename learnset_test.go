@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// levelTo gains p just enough experience to reach level.
+func levelTo(s *session, id, level int) {
+	p := s.profile.Get(id)
+	gainExp(s, p, expForLevel("", level)-max(p.Exp, expForLevel("", p.Level)))
+}
+
+func TestLearnOnLevelUp(t *testing.T) {
+	s := newTestSession(t)
+	pikachu := s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 11)
+	out := &bytes.Buffer{}
+	s.out = out
+	levelTo(s, pikachu.ID, 12)
+	p := s.profile.Get(pikachu.ID)
+	if !strings.Contains(out.String(), "pikachu learned thunderbolt!") || p.Moves[len(p.Moves)-1].Name != "thunderbolt" {
+		t.Errorf("Expected pikachu to learn thunderbolt at 12 in the newest game, got %q", out.String())
+	}
+
+	// Red and Blue teach it at 26 instead.
+	s.versionGroup = "red-blue"
+	p.Moves = nil
+	levelTo(s, pikachu.ID, 25)
+	if len(p.Moves) > 0 {
+		t.Errorf("Expected pikachu not to learn anything before 26, got %v", p.Moves)
+	}
+	levelTo(s, pikachu.ID, 26)
+	if len(p.Moves) == 0 {
+		t.Errorf("Expected pikachu to learn thunderbolt at 26")
+	}
+}
+
+func TestLearnAsksWhatToForget(t *testing.T) {
+	s := newTestSession(t)
+	pikachu := s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 11)
+	p := s.profile.Get(pikachu.ID)
+	full := []battle.Move{{Name: "tackle"}, {Name: "growl"}, {Name: "tail-whip"}, {Name: "quick-attack"}}
+	p.Moves = append([]battle.Move{}, full...)
+
+	// Without a way to ask, the move is passed over.
+	out := &bytes.Buffer{}
+	s.out = out
+	levelTo(s, pikachu.ID, 12)
+	if !strings.Contains(out.String(), "pikachu did not learn thunderbolt.") || p.Moves[1].Name != "growl" {
+		t.Errorf("Expected pikachu not to learn thunderbolt, got %q", out.String())
+	}
+
+	p.Level, p.Exp, p.Moves = 11, 0, append([]battle.Move{}, full...)
+	answers := []string{"9", "growl"}
+	s.input = func() (string, bool) {
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, true
+	}
+	out.Reset()
+	levelTo(s, pikachu.ID, 12)
+	if !strings.Contains(out.String(), "pikachu forgot growl and learned thunderbolt!") || p.Moves[1].Name != "thunderbolt" {
+		t.Errorf("Expected pikachu to swap growl for thunderbolt, got %q", out.String())
+	}
+}
@@ -2,146 +2,124 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"cmp"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"math/rand/v2"
 	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/azs06/pokedexcli/internal/audio"
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/battlelog"
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/console"
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/filecache"
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/journal"
+	"github.com/azs06/pokedexcli/internal/ladder"
+	"github.com/azs06/pokedexcli/internal/logfile"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
 	"github.com/azs06/pokedexcli/internal/pokecache"
+	"github.com/azs06/pokedexcli/internal/profile"
+	"github.com/azs06/pokedexcli/internal/snapshot"
+	"github.com/azs06/pokedexcli/internal/sprite"
+	"github.com/azs06/pokedexcli/internal/webhooks"
+	"github.com/azs06/pokedexcli/internal/wondertrade"
 )
 
-type config struct {
-	Url      string
-	Next     string
-	Previous string
-	Cache    *pokecache.Cache
-}
-
-type cliCommand struct {
-	name        string
-	description string
-	callback    func(c *config, args ...string) error
-}
-
-type Location struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
-}
-
-type LocationResponse struct {
-	Count     int        `json:"count"`
-	Next      string     `json:"next"`
-	Previous  string     `json:"previous"`
-	Locations []Location `json:"results"`
-}
-
-type Pokemon struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
-}
-
-type PokemonEncounter struct {
-	Pokemon Pokemon `json:"pokemon"`
-}
-
-type LocationDetailsResponse struct {
-	PokemonEncounters []PokemonEncounter `json:"pokemon_encounters"`
-}
+// wildLevel is the level of pokemon caught in the wild.
+const wildLevel = 5
 
-type Stat struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
-}
-type StatDetail struct {
-	BaseStat int  `json:"base_stat"`
-	Stat     Stat `json:"stat"`
-}
-
-type Type struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
-}
-
-type TypeDetails struct {
-	Slot int  `json:"slot"`
-	Type Type `json:"type"`
-}
-type PokemonType struct {
-	Name           string        `json:"name"`
-	Height         int           `json:"height"`
-	Weight         int           `json:"weight"`
-	Stats          []StatDetail  `json:"stats"`
-	Types          []TypeDetails `json:"types"`
-	BaseExperience int           `json:"base_experience"`
-}
+// firstVisitBonus is the money found when exploring an area for the first
+// time.
+const firstVisitBonus = 200
 
-var apiUrl = "https://pokeapi.co/api/v2/"
-var pokeDex = map[string]PokemonType{}
-
-var commands = map[string]cliCommand{
-	"exit": {
+func init() {
+	registerCommand(cliCommand{
 		name:        "exit",
 		description: "Exit the Pokedex",
 		callback:    commandExit,
-	},
-	"help": {
+	})
+	registerCommand(cliCommand{
 		name:        "help",
 		description: "Display available commands",
 		callback:    commandHelp,
-	},
-	"map": {
+		paged:       true,
+	})
+	registerCommand(cliCommand{
 		name:        "map",
+		usage:       "map [--unvisited]",
 		description: "Display next maps",
+		maxArgs:     1,
 		callback:    commandMap,
-	},
-	"mapb": {
-		name:        "map",
+		paged:       true,
+	})
+	registerCommand(cliCommand{
+		name:        "mapb",
+		usage:       "mapb [--unvisited]",
 		description: "Display previous maps",
+		maxArgs:     1,
 		callback:    commandPrevMap,
-	},
-	"explore": {
+	})
+	registerCommand(cliCommand{
 		name:        "explore",
-		description: "Explore a location",
+		usage:       "explore [area]",
+		description: "Explore a location, by default the one you're in",
+		maxArgs:     1,
 		callback:    commandExplore,
-	},
-	"catch": {
+		complete:    completeKnown("location-area"),
+	})
+	registerCommand(cliCommand{
 		name:        "catch",
-		description: "Catch a pokemon",
+		usage:       "catch [pokemon] [ball]",
+		description: "Catch a pokemon, or the wild pokemon in front of you",
+		maxArgs:     2,
 		callback:    commandCatch,
-	},
-	"inspect": {
+		complete:    completeKnown("pokemon"),
+	})
+	registerCommand(cliCommand{
 		name:        "inspect",
-		description: "Inspect a caught pokemon",
+		usage:       "inspect [pokemon] [--team] [--box <n>]",
+		description: "Inspect a caught pokemon, or the ones picked out like release does",
+		minArgs:     1,
+		maxArgs:     -1,
 		callback:    commandInspect,
-	},
-	"pokedex": {
-		name:        "pokedex",
-		description: "View your pokedex",
-		callback:    commandPokedex,
-	},
+		complete: func(s *session, args []string) []string {
+			if len(args) == 0 {
+				return append(completeCaught(s, args), "--team", "--box")
+			}
+			return nil
+		},
+	})
 }
 
-func commandPokedex(c *config, args ...string) error {
-
-	fmt.Println("Your Pokedex:")
-
-	for k := range pokeDex {
-		fmt.Print(" - ")
-		fmt.Println(k)
+func commandCatch(s *session, args ...string) error {
+	if s.encounter != nil {
+		// Naming the pokemon in front of the player throws at it too.
+		if len(args) > 0 && args[0] == s.encounter.species.Name {
+			args = args[1:]
+		}
+		if len(args) > 1 {
+			return errors.New("usage: catch [ball]")
+		}
+		return throwAtEncounter(s, args...)
+	}
+	if len(args) == 0 {
+		return errors.New("usage: catch <pokemon> [ball]")
 	}
-
-	return nil
-}
-
-func commandCatch(c *config, args ...string) error {
 	toCatch := args[0]
-	catchPokemon(toCatch, c)
-	return nil
+	return catchPokemon(toCatch, s, args[1:]...)
 }
 
 func cleanInput(text string) []string {
@@ -151,234 +129,584 @@ func cleanInput(text string) []string {
 	return words
 }
 
-func catchPokemon(p string, c *config) error {
-	printMsg := fmt.Sprintf("Throwing a Pokeball at %s...", p)
-	fmt.Println(printMsg)
-	response := PokemonType{}
-	url := c.Url + "pokemon/" + p
-
-	decodedData, err := fetchData(url, c)
-	if err != nil {
-		fmt.Println("failed to catch", err)
+// catchPokemon meets p in the wild and throws a first ball at it.
+func catchPokemon(p string, s *session, ball ...string) error {
+	if _, err := chooseBall(s, ball...); err != nil {
 		return err
 	}
-	err = json.Unmarshal(decodedData, &response)
-
+	response, err := resolvePokemon(s, p)
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(s.out, "failed to catch", err)
+		return err
+	}
+	if r, ok := roamerOf(response); ok {
+		return fmt.Errorf("%s roams %s; track it down and meet it in the wild", r.name, r.region)
+	}
+	if err := meetPokemon(s, response, wildLevel); err != nil {
 		return err
 	}
+	return throwAtEncounter(s, ball...)
+}
+
+func typeNames(p pokeapi.PokemonType) []string {
+	names := make([]string, len(p.Types))
+	for i, t := range p.Types {
+		names[i] = t.Type.Name
+	}
+	return names
+}
 
-	baseExperience := response.BaseExperience
-	chance := rand.IntN(baseExperience)
-	willGotCaught := baseExperience - chance
+func commandExit(s *session, args ...string) error {
+	fmt.Fprint(s.out, "Closing the Pokedex... Goodbye!")
+	return errExit
+}
 
-	if willGotCaught > baseExperience/2 {
-		fmt.Println(p + " was caught")
-		pokeDex[p] = response
-	} else {
-		fmt.Println(p + " escaped")
+func commandHelp(s *session, args ...string) error {
+	fmt.Fprintln(s.out, "Welcome to the Pokedex!")
+	fmt.Fprintln(s.out, "Usage:")
+	for _, cmd := range sortedCommands() {
+		if cmd.hidden || s.readOnly && !guestCommands[cmd.name] {
+			continue
+		}
+		fmt.Fprintf(s.out, "%s: %s\n", cmd.usageLine(), cmd.description)
 	}
 	return nil
 }
 
-func fetchData(url string, c *config) ([]byte, error) {
-	if strings.TrimSpace(url) == "" {
-		return []byte{}, errors.New("Invalid input")
+func commandExplore(s *session, args ...string) error {
+	area := s.profile.Location
+	if len(args) > 0 {
+		area = args[0]
 	}
-
-	decodedData, ok := c.Cache.Get(url)
-	if ok {
-		return decodedData, nil
+	if area == "" {
+		return errors.New("usage: explore <area>, or travel somewhere first")
 	}
-
-	res, err := http.Get(url)
+	response, err := s.source.LocationArea(area)
+	pokemonEncounters := response.PokemonEncounters
 	if err != nil {
-		return []byte{}, err
+		return err
+	}
+	names := []string{}
+	if len(pokemonEncounters) > 0 {
+		for _, pokemonEncounter := range pokemonEncounters {
+			fmt.Fprintln(s.out, pokemonEncounter.Pokemon.Name)
+			names = append(names, pokemonEncounter.Pokemon.Name)
+		}
+	}
+	// Once the player is somewhere, other areas can be looked up but only
+	// the current one can be explored for real.
+	if s.profile.Location != "" && area != s.profile.Location {
+		fmt.Fprintf(s.out, "You're not in %s, travel there to meet these pokemon\n", area)
+		return nil
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return []byte{}, fmt.Errorf("failed to fetch data: %s", res.Status)
+	for _, name := range names {
+		s.profile.See(name)
+	}
+	firstVisit := s.profile.Visit(area)
+	if firstVisit {
+		s.profile.Money += firstVisitBonus
+		fmt.Fprintf(s.out, "First visit to %s! You found %d Pokédollars.\n", area, firstVisitBonus)
 	}
+	s.publish(events.Event{Kind: events.Explored, Area: area, Encounters: names, New: firstVisit})
 
-	decodedData, err = io.ReadAll(res.Body)
-	c.Cache.Add(url, decodedData)
+	if r, ok := roamerIn(s, response.Location.Name); ok && rand.Float64() < roamerChance {
+		return meetRoamer(s, r)
+	}
+	if enc, ok := pickEncounter(onLand(pokemonEncounters)); ok && rand.Float64() < encounterChance {
+		return spawnEncounter(s, enc)
+	}
+	return nil
+}
 
+func commandMap(s *session, args ...string) error {
+	unvisited, err := parseMapArgs(args)
 	if err != nil {
-		return []byte{}, err
+		return err
 	}
-	return decodedData, nil
-}
+	response, err := s.source.LocationAreas(s.next)
 
-func commandExit(c *config, args ...string) error {
-	fmt.Print("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
-	return nil
-}
+	if err != nil {
+		return err
+	}
 
-func commandHelp(c *config, args ...string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	fmt.Println("help: Displays a help message")
-	fmt.Println("exit: Exit the Pokedex")
+	s.next = response.Next
+	s.previous = response.Previous
+	printLocations(s, response.Locations, unvisited)
 	return nil
 }
 
-func fetchLocationDetails(url string, c *config) (LocationDetailsResponse, error) {
-	response := LocationDetailsResponse{}
-	decodedData, err := fetchData(url, c)
-
+func commandPrevMap(s *session, args ...string) error {
+	unvisited, err := parseMapArgs(args)
 	if err != nil {
-		return response, err
+		return err
 	}
-
-	err = json.Unmarshal(decodedData, &response)
+	if s.previous == "" {
+		fmt.Fprintln(s.out, "you're on the first page")
+		return nil
+	}
+	response, err := s.source.LocationAreas(s.previous)
 
 	if err != nil {
-		return response, err
+		return err
 	}
 
-	return response, nil
+	s.next = response.Next
+	s.previous = response.Previous
+	printLocations(s, response.Locations, unvisited)
+	return nil
 }
 
-func commandExplore(c *config, args ...string) error {
-	area := args[0]
-	response, err := fetchLocationDetails(c.Url+"location-area/"+area, c)
-	pokemonEncounters := response.PokemonEncounters
-	if err != nil {
-		return err
+func parseMapArgs(args []string) (unvisited bool, err error) {
+	if len(args) == 0 {
+		return false, nil
 	}
-	if len(pokemonEncounters) > 0 {
-		for _, pokemonEncounter := range pokemonEncounters {
-			fmt.Println(pokemonEncounter.Pokemon.Name)
-		}
+	if args[0] != "--unvisited" {
+		return false, errors.New("usage: map [--unvisited]")
 	}
-	return nil
+	return true, nil
 }
 
-func commandMap(c *config, args ...string) error {
-	locations := []Location{}
-	response := LocationResponse{}
-	mapUrl := fmt.Sprintf("%s/location-area", c.Url)
-	if c.Next != "" {
-		mapUrl = c.Next
+// printLocations lists a page of areas, marking the ones already explored.
+// With unvisited set, explored areas are left out.
+func printLocations(s *session, locations []pokeapi.Location, unvisited bool) {
+	shown := 0
+	for _, location := range locations {
+		visited := s.profile.Visited[location.Name]
+		if unvisited && visited {
+			continue
+		}
+		shown++
+		switch {
+		case location.Name == s.profile.Location:
+			fmt.Fprintln(s.out, iconPrefix(s.icons().here, location.Name), "(you are here)")
+		case visited:
+			fmt.Fprintln(s.out, iconPrefix(s.icons().visited, location.Name), "(visited)")
+		default:
+			fmt.Fprintln(s.out, location.Name)
+		}
+	}
+	if shown == 0 && unvisited {
+		fmt.Fprintln(s.out, "You've explored every area on this page")
 	}
-	response, err := fetchLocations(mapUrl, c)
+}
 
+func commandInspect(s *session, args ...string) error {
+	if len(args) > 1 || strings.HasPrefix(args[0], "--") {
+		return inspectSelected(s, args)
+	}
+	pokemonName, err := match(s, "pokemon you've caught", formName(args[0]), slices.Collect(maps.Keys(s.profile.Pokedex)))
+	if errors.Is(err, errNoMatch) {
+		fmt.Fprintln(s.out, "You haven't caught", formName(args[0]))
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	pokemon := s.profile.Pokedex[pokemonName]
 
-	locations = response.Locations
-	c.Next = response.Next
-	c.Previous = response.Previous
+	if s.a11y {
+		fmt.Fprintln(s.out, spokenSummary(pokemon))
+	} else {
+		fmt.Fprintf(s.out, "Details of %s:\n", pokemonName)
+	}
+	drawSprite(s, pokemon, slices.ContainsFunc(s.profile.Pokemon, func(p profile.Pokemon) bool { return p.Species == pokemonName && p.Shiny }))
+	if f := form(pokemon); f != "" {
+		fmt.Fprintf(s.out, "Form: %s of %s\n", f, pokemon.Species.Name)
+	}
+	fmt.Fprintf(s.out, "Height: %d\n", pokemon.Height)
+	fmt.Fprintf(s.out, "Weight: %d\n", pokemon.Weight)
+	fmt.Fprintf(s.out, "Base Experience: %d\n", pokemon.BaseExperience)
+	species, err := s.source.Species(speciesName(pokemon))
+	if err != nil && !errors.Is(err, pokeapi.ErrNotFound) {
+		return err
+	}
+	if species.Habitat.Name != "" {
+		fmt.Fprintf(s.out, "Habitat: %s\n", species.Habitat.Name)
+	}
 
-	for _, location := range locations {
-		fmt.Println(location.Name)
+	if !s.a11y {
+		fmt.Fprintln(s.out, "Types:")
+		for _, t := range pokemon.Types {
+			fmt.Fprintf(s.out, "- %s%s (Slot %d)\n", typeIcons(s, []string{t.Type.Name}), s.paint(s.theme.Types[t.Type.Name], t.Type.Name), t.Slot)
+		}
 	}
 
+	fmt.Fprintln(s.out, "Stats:")
+	for _, stat := range pokemon.Stats {
+		fmt.Fprintf(s.out, "- %s: %d\n", stat.Stat.Name, stat.BaseStat)
+	}
+
+	fmt.Fprintln(s.out, "Yours:")
+	for i := range s.profile.Pokemon {
+		if p := &s.profile.Pokemon[i]; p.Species == pokemonName {
+			if err := inspectOwned(s, p, false); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-func fetchLocations(url string, c *config) (LocationResponse, error) {
-	response := LocationResponse{}
-	decodedData, err := fetchData(url, c)
-
+// inspectSelected shows each of the pokemon a selector picks out.
+func inspectSelected(s *session, args []string) error {
+	picked, rest, err := pickOwned(s, args)
 	if err != nil {
-		return response, err
+		return err
 	}
+	if len(rest) > 0 {
+		return fmt.Errorf("usage: inspect <pokemon>, or inspect %s", selectorUsage)
+	}
+	fmt.Fprintf(s.out, "%d pokemon:\n", len(picked))
+	for _, p := range picked {
+		if err := inspectOwned(s, p, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	err = json.Unmarshal(decodedData, &response)
-
+// inspectOwned shows one of the player's own pokemon, naming its species
+// when it's listed among others.
+func inspectOwned(s *session, p *profile.Pokemon, named bool) error {
+	pokemon := s.profile.Pokedex[p.Species]
+	nature, err := natureOf(s, p.Nature)
 	if err != nil {
-		return response, err
+		return err
 	}
-
-	return response, nil
+	if named {
+		fmt.Fprintf(s.out, "- %s", ownedText(s, p))
+	} else {
+		fmt.Fprintf(s.out, "- #%d%s%s Lv. %d", p.ID, genderSymbol(s, p.Gender), shinyMark(s, p.Shiny), p.Level)
+	}
+	if p.Nickname != "" {
+		fmt.Fprintf(s.out, " %q", p.Nickname)
+	}
+	if p.Exp > 0 {
+		fmt.Fprintf(s.out, " (%d exp)", p.Exp)
+	}
+	if p.Nature != "" {
+		fmt.Fprintf(s.out, ", %s nature (%s)", p.Nature, natureText(nature))
+	}
+	if p.Item != "" {
+		fmt.Fprintf(s.out, ", holding %s", ballName(p.Item))
+	}
+	if p.Friendship > 0 {
+		fmt.Fprintf(s.out, ", friendship %d/%d", p.Friendship, maxFriendship)
+	}
+	fmt.Fprintln(s.out)
+	if met := metText(s, p); met != "" {
+		fmt.Fprintf(s.out, "  OT/met: %s\n", met)
+	}
+	fmt.Fprintf(s.out, "  Stats: %s\n", statsText(ownStats(pokemon, p, nature), nature))
+	fmt.Fprintf(s.out, "  Exp: %s\n", expBar(s, p))
+	if p.IVs != nil {
+		fmt.Fprintf(s.out, "  IVs: %s\n", ivsText(p.IVs))
+	}
+	if ribbons := ribbonsText(p); ribbons != "" {
+		fmt.Fprintf(s.out, "  Ribbons: %s\n", ribbons)
+	}
+	if len(p.Condition) > 0 {
+		var condition []string
+		for _, category := range contestCategories {
+			condition = append(condition, fmt.Sprintf("%s %d", category, p.Condition[category]))
+		}
+		fmt.Fprintf(s.out, "  Condition: %s\n", strings.Join(condition, ", "))
+	}
+	return nil
 }
 
-func commandPrevMap(c *config, args ...string) error {
-	locations := []Location{}
-	response := LocationResponse{}
-	mapUrl := ""
-	if c.Previous == "" {
-		fmt.Println("you're on the first page")
-		return nil
-	} else {
-		mapUrl = c.Previous
+func newDataSource(kind, dir string, cfg config.Config, cache *pokecache.Cache) (pokeapi.DataSource, error) {
+	switch kind {
+	// The network sources start from the bundled snapshot, so the game can
+	// be played before anything is fetched, or with nothing to fetch from.
+	case "rest":
+		return pokeapi.NewFallback(pokeapi.Bundled(), pokeapi.NewClient(cmp.Or(cfg.API, pokeapi.DefaultUrl), cache)), nil
+	case "graphql":
+		return pokeapi.NewFallback(pokeapi.Bundled(), pokeapi.NewGraphQLClient(cmp.Or(cfg.GraphQL, pokeapi.DefaultGraphQLUrl), cache)), nil
+	case "offline":
+		if dir == "" {
+			return nil, errors.New("offline source needs -offline-dir")
+		}
+		return pokeapi.NewOffline(dir), nil
 	}
-	response, err := fetchLocations(mapUrl, c)
+	return nil, fmt.Errorf("unknown data source %q", kind)
+}
 
+// dataDir is where save data lives by default: ~/.local/share/pokedexcli
+// (respecting XDG_DATA_HOME), or beside the config on macOS and Windows,
+// which keep both in the same place.
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "pokedexcli")
+	}
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return configDir()
+	}
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return ".pokedexcli"
 	}
+	return filepath.Join(home, ".local", "share", "pokedexcli")
+}
 
-	locations = response.Locations
-	c.Next = response.Next
-	c.Previous = response.Previous
-
-	for _, location := range locations {
-		fmt.Println(location.Name)
+// configDir is where user configuration lives, e.g. ~/.config/pokedexcli.
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".pokedexcli"
 	}
+	return filepath.Join(dir, "pokedexcli")
+}
 
-	return nil
+// cacheDir is where files that can be fetched again live by default, e.g.
+// ~/.cache/pokedexcli.
+func cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".pokedexcli", "cache")
+	}
+	return filepath.Join(dir, "pokedexcli")
 }
 
-func commandInspect(c *config, args ...string) error {
-	pokemonName := args[0]
-	pokemon, exists := pokeDex[pokemonName]
-	if !exists {
-		fmt.Println("You haven't caught", pokemonName)
-		return nil
+func main() {
+	configPath := flag.String("config", filepath.Join(configDir(), "config.json"), "path to the config file")
+	profileName := flag.String("profile", "default", "name of the save profile to play")
+	sourceKind := flag.String("source", "rest", "data source: rest, graphql or offline")
+	offlineDir := flag.String("offline-dir", "", "snapshot directory for the offline source")
+	hooksDir := flag.String("hooks-dir", filepath.Join(configDir(), "hooks"), "directory of Starlark hook scripts")
+	pluginsDir := flag.String("plugins-dir", filepath.Join(configDir(), "plugins"), "directory of command plugins")
+	dataPath := flag.String("data-dir", dataDir(), "directory of saved games, battles and ladder standings")
+	cachePath := flag.String("cache-dir", cacheDir(), "directory of downloaded files that can be fetched again")
+	eventsAddr := flag.String("events-addr", "", "serve game events as a WebSocket at /events on this address")
+	readOnly := flag.Bool("read-only", false, "only allow commands that don't change the game, and never save")
+	fast := flag.Bool("fast", false, "skip animations")
+	overlayPath := flag.String("overlay", "", "keep a JSON, or .html, file of the game up to date for stream overlays")
+	flag.Parse()
+
+	// Completion scripts pass the whole command line after __complete, so
+	// parse it again to pick up flags such as -source.
+	completing := flag.Arg(0) == "__complete"
+	if completing {
+		words := flag.Args()[1:]
+		if len(words) > 0 && strings.HasPrefix(words[len(words)-1], "-") {
+			return
+		}
+		flag.CommandLine.Parse(words)
+	}
+	// Anything printed while completing would be offered as a completion,
+	// so problems with the setup are only reported otherwise.
+	var warnings io.Writer = os.Stdout
+	if completing {
+		warnings = io.Discard
 	}
 
-	fmt.Printf("Details of %s:\n", pokemonName)
-	fmt.Printf("Height: %d\n", pokemon.Height)
-	fmt.Printf("Weight: %d\n", pokemon.Weight)
-	fmt.Printf("Base Experience: %d\n", pokemon.BaseExperience)
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(warnings, "Config error:", err)
+	}
+	for _, u := range []*string{&cfg.API, &cfg.GraphQL} {
+		if *u == "" {
+			continue
+		}
+		if err := pokeapi.CheckURL(*u); err != nil {
+			fmt.Fprintln(warnings, "Config error:", err)
+			*u = ""
+		}
+	}
+	// Every client without a transport of its own, the API's, downloads,
+	// webhooks and the rest, uses the default one.
+	if t, err := transport(cfg.Network); err != nil {
+		fmt.Fprintln(warnings, "Config error:", err)
+	} else {
+		http.DefaultTransport = t
+	}
 
-	fmt.Println("Types:")
-	for _, t := range pokemon.Types {
-		fmt.Printf("- %s (Slot %d)\n", t.Type.Name, t.Slot)
+	cache := pokecache.NewCache(5 * time.Minute)
+	defer cache.Close()
+	cache.Limit(int64(cmp.Or(cfg.Cache.MaxMB, defaultCacheMB)) << 20)
+	if cfg.Cache.Compress {
+		cache.Compress(compressAbove)
+	}
+	for ns, seconds := range cfg.Cache.TTL {
+		if !slices.Contains(pokecache.Namespaces, pokecache.Namespace(ns)) {
+			fmt.Fprintf(warnings, "Config error: no cache namespace %q\n", ns)
+			continue
+		}
+		cache.SetTTL(pokecache.Namespace(ns), time.Duration(seconds)*time.Second)
+	}
+	source, err := newDataSource(*sourceKind, *offlineDir, cfg, cache)
+	if err != nil {
+		fmt.Fprintln(warnings, "Error:", err)
+		os.Exit(1)
 	}
 
-	fmt.Println("Stats:")
-	for _, s := range pokemon.Stats {
-		fmt.Printf("- %s: %d\n", s.Stat.Name, s.BaseStat)
+	runner, err := hooks.Load(*hooksDir)
+	if err != nil {
+		fmt.Fprintln(warnings, "Hook error:", err)
 	}
 
-	return nil
-}
+	if err := registerPlugins(*pluginsDir); err != nil {
+		fmt.Fprintln(warnings, "Plugin error:", err)
+	}
 
-func main() {
-	cache := pokecache.NewCache(5 * time.Minute)
+	a := newApp(source, runner)
+	a.cache = cache
+	a.dataDir, a.cacheDir = *dataPath, *cachePath
+	a.themesDir = filepath.Join(configDir(), "themes")
+	if *sourceKind == "offline" {
+		a.snapshotDir = *offlineDir
+	}
+	a.plain = !console.Enable(os.Stdout)
+	a.graphics = sprite.Detect(os.Getenv)
+	a.sprites = filecache.New(filepath.Join(a.cacheDir, "sprites"))
+	a.player = audio.Find()
+	a.cries = filecache.New(filepath.Join(a.cacheDir, "cries"))
+	a.store = profile.NewStore(filepath.Join(a.dataDir, "profiles"))
+	a.battles = battlelog.NewStore(filepath.Join(a.dataDir, "battles"))
+	a.journals = journal.NewStore(filepath.Join(a.dataDir, "journals"))
+	a.snapshots = snapshot.NewStore(filepath.Join(a.dataDir, "snapshots"))
+	logPath := filepath.Join(a.dataDir, "logs", "pokedexcli.log")
+	maxAge := time.Duration(cmp.Or(cfg.Log.MaxDays, defaultLogDays)) * 24 * time.Hour
+	logFile, err := logfile.Open(logPath, int64(cmp.Or(cfg.Log.MaxMB, defaultLogMB))<<20, maxAge, cmp.Or(cfg.Log.Keep, defaultLogKeep))
+	if err != nil {
+		fmt.Fprintln(warnings, "Log error:", err)
+	} else {
+		defer logFile.Close()
+		a.log, a.logPath = slog.New(slog.NewJSONHandler(logFile, nil)), logPath
+		http.DefaultTransport = loggedTransport{next: http.DefaultTransport, log: a.log}
+	}
+	a.paths = []gamePath{
+		{"config", *configPath},
+		{"hooks", *hooksDir},
+		{"plugins", *pluginsDir},
+		{"themes", a.themesDir},
+		{"profiles", filepath.Join(a.dataDir, "profiles")},
+		{"battles", filepath.Join(a.dataDir, "battles")},
+		{"journals", filepath.Join(a.dataDir, "journals")},
+		{"snapshots", filepath.Join(a.dataDir, "snapshots")},
+		{"log", logPath},
+		{"ladder standings", filepath.Join(a.dataDir, "ladder.json")},
+		{"wonder trades", filepath.Join(a.dataDir, "wondertrade.json")},
+		{"cache", a.cacheDir},
+		{"sprites", filepath.Join(a.cacheDir, "sprites")},
+		{"cries", filepath.Join(a.cacheDir, "cries")},
+	}
+	a.config, a.configPath = cfg, *configPath
+	if len(cfg.Webhooks) > 0 {
+		a.bus.SubscribeAll(webhooks.New(cfg.Webhooks).Notify)
+	}
+	for name, rules := range cfg.Battles {
+		format, ok := battleFormats[name]
+		if !ok {
+			fmt.Fprintf(warnings, "Config error: unknown battle format %q\n", name)
+			continue
+		}
+		a.rules[format] = battle.Rules{Mega: rules.Mega, Dynamax: rules.Dynamax}
+	}
+	if _, ok := snapshotPeriods[cmp.Or(cfg.Snapshots.Every, "daily")]; !ok {
+		fmt.Fprintf(warnings, "Config error: unknown snapshot schedule %q, snapshotting daily\n", cfg.Snapshots.Every)
+	}
+	a.readOnly = *readOnly
+	a.fast = *fast
+	a.ladder = cmp.Or(cfg.Ladder, ladder.DefaultURL)
+	a.wonderTrade = cmp.Or(cfg.WonderTrade, wondertrade.DefaultURL)
+	a.twitch = cfg.Twitch
+	a.twitch.Token = cmp.Or(a.twitch.Token, os.Getenv("TWITCH_TOKEN"))
+	a.slack = cfg.Slack
+	a.slack.SigningSecret = cmp.Or(a.slack.SigningSecret, os.Getenv("SLACK_SIGNING_SECRET"))
 
 	scanner := bufio.NewScanner(os.Stdin)
-	apiConfig := config{
-		Url:      apiUrl,
-		Next:     "",
-		Previous: "",
-		Cache:    cache,
+	input := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return scanner.Text(), true
+	}
+	// Completion never changes the game, so it doesn't take the profile's
+	// lock, and neither do one-shot commands that could run read-only while
+	// another game has it.
+	if completing {
+		a.readOnly = true
+	}
+	session, err := a.session(*profileName)
+	if errors.Is(err, profile.ErrLocked) && flag.NArg() > 0 && guestCommands[strings.ToLower(flag.Arg(0))] {
+		a.readOnly = true
+		session, err = a.session(*profileName)
+	}
+	if err != nil && completing {
+		os.Exit(1)
+	}
+	if errors.Is(err, profile.ErrLocked) {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if errors.Is(err, profile.ErrCorrupt) {
+		fmt.Println("Error:", err)
+		restored, rerr := recoverSave(a, *profileName, os.Stdout, input)
+		if rerr != nil {
+			fmt.Println("Error:", rerr)
+			os.Exit(1)
+		}
+		if !restored {
+			fmt.Println("Your save was left as it is.")
+			os.Exit(1)
+		}
+		session, err = a.session(*profileName)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if err := session.applySettings(cfg.For(session.id)); err != nil {
+		fmt.Fprintln(warnings, "Config error:", err)
+	}
+	if !a.plain {
+		session.tty = os.Stdout
+		a.termHeight = func() int { return console.Height(os.Stdout) }
+	}
+	if *overlayPath != "" && !completing {
+		subscribeOverlay(session, *overlayPath)
+	}
+	if *eventsAddr != "" && !completing {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/events", eventsHandler(a.bus))
+			fmt.Println("Events error:", http.ListenAndServe(*eventsAddr, mux))
+		}()
+	}
+	if completing {
+		for _, candidate := range completions(session, flag.Args()) {
+			fmt.Println(candidate)
+		}
+		return
+	}
+	if flag.NArg() > 0 {
+		err := session.run(strings.Join(flag.Args(), " "), os.Stdout)
+		if err != nil && !errors.Is(err, errExit) {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
 	}
 
+	session.input = input
+	if !a.plain {
+		a.keys = os.Stdin
+	}
+	session.start(os.Stdout)
+
 	for {
-		fmt.Print("Pokedex > ")
-		scanner.Scan()
-		text := scanner.Text()
-		words := cleanInput(text)
-		if len(words) == 0 {
-			continue
+		fmt.Print(session.prompt())
+		if !scanner.Scan() {
+			return
 		}
-		command := words[0]
-
-		if cmd, ok := commands[command]; ok {
-			err := cmd.callback(&apiConfig, words[1:]...)
-			if err != nil {
-				fmt.Println("Error:", err)
-			}
-		} else {
-			fmt.Println("Unknown command:", command)
+		err := session.run(scanner.Text(), os.Stdout)
+		if errors.Is(err, errExit) {
+			return
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
 		}
 	}
 
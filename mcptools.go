@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/mcp"
+)
+
+// mcpCommands are the commands served as tools to AI assistants.
+var mcpCommands = []string{
+	"map", "mapb", "explore", "travel", "whereami",
+	"catch", "throw", "bait", "run",
+	"inspect", "pokedex", "party", "bag", "starter",
+}
+
+// toolParam is an argument of a tool, and how it goes back on the command
+// line.
+type toolParam struct {
+	name     string
+	required bool
+	// flag is set for --flag arguments; option for --flag <value> ones.
+	flag, option bool
+}
+
+// mcpTools describes mcpCommands as tools, with schemas built from their
+// usage lines.
+func mcpTools() ([]mcp.Tool, map[string][]toolParam) {
+	tools, params := []mcp.Tool{}, map[string][]toolParam{}
+	for _, name := range mcpCommands {
+		cmd, ok := commands[name]
+		if !ok {
+			continue
+		}
+		schema, ps, ok := usageSchema(cmd.usageLine())
+		if !ok {
+			continue
+		}
+		tools = append(tools, mcp.Tool{Name: cmd.name, Description: cmd.description + ". Usage: " + cmd.usageLine(), InputSchema: schema})
+		params[cmd.name] = ps
+	}
+	return tools, params
+}
+
+// usageSchema reads a usage line such as "catch [pokemon] [ball]" into a
+// schema: <x> is a required argument, [x] an optional one, [a|b] a choice,
+// [--x] a switch and [--x <y>] an option. ok is false for anything more
+// involved.
+func usageSchema(usage string) (schema mcp.Schema, params []toolParam, ok bool) {
+	schema = mcp.Schema{Type: "object", Properties: map[string]mcp.Property{}}
+	for _, token := range usageTokens(usage)[1:] {
+		p, prop := toolParam{}, mcp.Property{Type: "string"}
+		switch {
+		case strings.HasPrefix(token, "<") && strings.HasSuffix(token, ">"):
+			p.required, token = true, token[1:len(token)-1]
+		case strings.HasPrefix(token, "[") && strings.HasSuffix(token, "]"):
+			token = token[1 : len(token)-1]
+		default:
+			return schema, nil, false
+		}
+		if strings.ContainsAny(token, "[]<>") && !strings.HasPrefix(token, "--") {
+			return schema, nil, false
+		}
+		switch flag, value, _ := strings.Cut(token, " "); {
+		case strings.HasPrefix(token, "--") && value == "":
+			if strings.Contains(flag, "|") {
+				return schema, nil, false
+			}
+			p.name, p.flag, prop.Type = flag[2:], true, "boolean"
+		case strings.HasPrefix(token, "--"):
+			if strings.Count(value, "<") != 1 || !strings.HasPrefix(value, "<") || !strings.HasSuffix(value, ">") {
+				return schema, nil, false
+			}
+			p.name, p.option = flag[2:], true
+			prop.Description = value[1 : len(value)-1]
+		case strings.Contains(token, "|"):
+			choices := strings.Split(token, "|")
+			p.name, prop.Enum = strings.Join(choices, "_or_"), choices
+		default:
+			p.name = strings.ReplaceAll(token, " ", "_")
+		}
+		params = append(params, p)
+		schema.Properties[p.name] = prop
+		if p.required {
+			schema.Required = append(schema.Required, p.name)
+		}
+	}
+	return schema, params, true
+}
+
+// usageTokens splits a usage line on spaces outside of brackets.
+func usageTokens(usage string) []string {
+	var tokens []string
+	depth, start := 0, 0
+	for i, r := range usage + " " {
+		switch r {
+		case '[', '<':
+			depth++
+		case ']', '>':
+			depth--
+		case ' ':
+			if depth == 0 {
+				if i > start {
+					tokens = append(tokens, usage[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	return tokens
+}
+
+// toolLine is the command line calling a tool with args.
+func toolLine(name string, params []toolParam, args map[string]any) (string, error) {
+	words, skipped := []string{name}, ""
+	known := map[string]bool{}
+	for _, p := range params {
+		known[p.name] = true
+		value, given := args[p.name]
+		switch {
+		case !given && p.required:
+			return "", fmt.Errorf("%s is required", p.name)
+		case !given:
+			if !p.flag && !p.option && skipped == "" {
+				skipped = p.name
+			}
+		case p.flag:
+			if on, ok := value.(bool); !ok {
+				return "", fmt.Errorf("%s must be true or false", p.name)
+			} else if on {
+				words = append(words, "--"+p.name)
+			}
+		case p.option:
+			words = append(words, "--"+p.name, fmt.Sprint(value))
+		default:
+			// Arguments are taken in order, so one can't be left out before
+			// another.
+			if skipped != "" {
+				return "", fmt.Errorf("%s can't be given without %s", p.name, skipped)
+			}
+			words = append(words, fmt.Sprint(value))
+		}
+	}
+	for arg := range args {
+		if !known[arg] {
+			return "", fmt.Errorf("%s takes no argument %s", name, arg)
+		}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// serveMCP serves the game's tools over r and w, playing the session's
+// profile. The caller holds s.mu, as commands do.
+func serveMCP(s *session, r io.Reader, w io.Writer) error {
+	tools, params := mcpTools()
+	sv := mcp.NewServer("pokedexcli", version, tools, func(tool string, args map[string]any) (mcp.Result, error) {
+		ps, ok := params[tool]
+		if !ok {
+			return mcp.Result{}, fmt.Errorf("there's no tool %q", tool)
+		}
+		line, err := toolLine(tool, ps, args)
+		if err != nil {
+			return mcp.Result{}, err
+		}
+		out, old := &bytes.Buffer{}, s.out
+		s.out = out
+		err = s.exec(cleanInput(line))
+		s.out = old
+		if err != nil && !errors.Is(err, errExit) {
+			fmt.Fprintln(out, "Error:", err)
+			return mcp.Result{Text: out.String(), IsError: true}, nil
+		}
+		return mcp.Result{Text: out.String()}, nil
+	})
+	return sv.Serve(r, w)
+}
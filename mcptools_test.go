@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/mcp"
+)
+
+func TestUsageSchema(t *testing.T) {
+	schema, params, ok := usageSchema("catch [pokemon] [ball]")
+	if !ok || len(schema.Properties) != 2 || len(schema.Required) != 0 {
+		t.Errorf("Expected two optional arguments, got %+v", schema)
+	}
+	if line, err := toolLine("catch", params, map[string]any{"pokemon": "pikachu", "ball": "great"}); err != nil || line != "catch pikachu great" {
+		t.Errorf("Expected catch pikachu great, got %q, %v", line, err)
+	}
+	if _, err := toolLine("catch", params, map[string]any{"ball": "great"}); err == nil {
+		t.Errorf("Expected a ball without a pokemon to be refused")
+	}
+
+	schema, params, ok = usageSchema("train <pokemon> --stat <stat> [--auto <battles>]")
+	if ok {
+		t.Errorf("Expected a bare option not to be understood, got %+v", schema)
+	}
+	schema, params, ok = usageSchema("replay <id> [--fast]")
+	if !ok || !reflect.DeepEqual(schema.Required, []string{"id"}) || schema.Properties["fast"].Type != "boolean" {
+		t.Errorf("Expected a required id and a fast switch, got %+v", schema)
+	}
+	if line, err := toolLine("replay", params, map[string]any{"id": 3, "fast": true}); err != nil || line != "replay 3 --fast" {
+		t.Errorf("Expected replay 3 --fast, got %q, %v", line, err)
+	}
+	if _, err := toolLine("replay", params, map[string]any{"id": 3, "slow": true}); err == nil {
+		t.Errorf("Expected an unknown argument to be refused")
+	}
+
+	schema, _, ok = usageSchema("fish <old|good|super>")
+	if prop := schema.Properties["old_or_good_or_super"]; !ok || len(prop.Enum) != 3 {
+		t.Errorf("Expected a choice of rods, got %+v", schema)
+	}
+	if _, _, ok := usageSchema(commands["battle"].usage); ok {
+		t.Errorf("Expected battle's usage to be too involved for a schema")
+	}
+}
+
+func TestServeMCP(t *testing.T) {
+	s := newTestSession(t)
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"starter","arguments":{"pokemon":"bulbasaur"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"starter","arguments":{"pokemon":"charmander"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"reset","arguments":{}}}`,
+	}, "\n")
+	out := &bytes.Buffer{}
+	if err := serveMCP(s, strings.NewReader(in), out); err != nil {
+		t.Fatalf("serveMCP returned error: %v", err)
+	}
+
+	type response struct {
+		Result struct {
+			Tools   []mcp.Tool `json:"tools"`
+			IsError bool       `json:"isError"`
+		} `json:"result"`
+		Error *struct{} `json:"error"`
+	}
+	var responses []response
+	for dec := json.NewDecoder(out); dec.More(); {
+		var r response
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Expected JSON responses, got error %v", err)
+		}
+		responses = append(responses, r)
+	}
+	if len(responses) != 4 || len(responses[0].Result.Tools) != len(mcpCommands) {
+		t.Fatalf("Expected every tool to be listed, got %+v", responses)
+	}
+	if len(s.profile.Party) != 1 || responses[1].Result.IsError {
+		t.Errorf("Expected the starter tool to pick bulbasaur")
+	}
+	if !responses[2].Result.IsError {
+		t.Errorf("Expected a second starter to be reported as an error")
+	}
+	if responses[3].Error == nil {
+		t.Errorf("Expected reset not to be a tool")
+	}
+}
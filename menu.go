@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/console"
+)
+
+// errNoMatch is match's error when name is none of the candidates and starts
+// none of them.
+var errNoMatch = errors.New("no match")
+
+// match finds name among candidates: the candidate it is, the only one it
+// starts, or the one the player chooses from a menu when it starts several.
+// noun is what candidates are, for messages.
+func match(s *session, noun, name string, candidates []string) (string, error) {
+	if slices.Contains(candidates, name) {
+		return name, nil
+	}
+	matches := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, name) {
+			matches = append(matches, c)
+		}
+	}
+	slices.Sort(matches)
+	matches = slices.Compact(matches)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("there's no %s called %s: %w", noun, name, errNoMatch)
+	case 1:
+		return matches[0], nil
+	}
+	choice, ok := s.choose(fmt.Sprintf("%s could be more than one %s:", name, noun), matches)
+	if !ok {
+		return "", fmt.Errorf("%s could be %s", name, strings.Join(matches, ", "))
+	}
+	return choice, nil
+}
+
+// choose asks the player to pick one of options: with the arrow keys on
+// the REPL's terminal, or by number from anywhere that can answer
+// questions. ok is false when nothing was picked.
+func (s *session) choose(question string, options []string) (choice string, ok bool) {
+	if s.app.keys != nil && s.tty != nil && s.out == s.tty && !s.a11y {
+		if restore, err := console.Raw(s.app.keys); err == nil {
+			defer restore()
+			return arrowMenu(s.out, s.app.keys, question, options)
+		}
+	}
+	if s.input == nil {
+		return "", false
+	}
+	fmt.Fprintln(s.out, question)
+	for i, option := range options {
+		fmt.Fprintf(s.out, "%d. %s\n", i+1, option)
+	}
+	answer, ok := s.ask(fmt.Sprintf("Which one? (1-%d, or enter for none) ", len(options)))
+	n, err := strconv.Atoi(answer)
+	if !ok || err != nil || n < 1 || n > len(options) {
+		return "", false
+	}
+	return options[n-1], true
+}
+
+// arrowMenu draws options with the chosen one highlighted, moving it with
+// the arrow keys, or j and k, until Enter picks it. A number picks that
+// option; Escape, q or Ctrl-C picks nothing. keys is read in raw mode, so
+// lines end in \r\n.
+func arrowMenu(out io.Writer, keys io.Reader, question string, options []string) (string, bool) {
+	fmt.Fprint(out, question+"\r\n")
+	chosen := 0
+	draw := func() {
+		for i, option := range options {
+			if i == chosen {
+				fmt.Fprintf(out, "\r\x1b[K> \x1b[7m%s\x1b[0m\r\n", option)
+			} else {
+				fmt.Fprintf(out, "\r\x1b[K  %s\r\n", option)
+			}
+		}
+	}
+	draw()
+	buf := make([]byte, 8)
+	for {
+		n, err := keys.Read(buf)
+		if err != nil {
+			return "", false
+		}
+		// Terminals send an arrow key's escape code all at once, so a lone
+		// escape is the Escape key.
+		switch key := string(buf[:n]); key {
+		case "\x1b[A", "\x1bOA", "k":
+			chosen = (chosen + len(options) - 1) % len(options)
+		case "\x1b[B", "\x1bOB", "j":
+			chosen = (chosen + 1) % len(options)
+		case "\r", "\n":
+			return options[chosen], true
+		case "\x1b", "q", "\x03":
+			return "", false
+		default:
+			if i, err := strconv.Atoi(key); err == nil && i >= 1 && i <= len(options) {
+				return options[i-1], true
+			}
+			continue
+		}
+		fmt.Fprintf(out, "\x1b[%dA", len(options))
+		draw()
+	}
+}
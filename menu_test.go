@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// keyPresses reads one key press at a time, as a terminal in raw mode does.
+type keyPresses []string
+
+func (k *keyPresses) Read(p []byte) (int, error) {
+	if len(*k) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, (*k)[0])
+	*k = (*k)[1:]
+	return n, nil
+}
+
+func TestArrowMenu(t *testing.T) {
+	options := []string{"pichu", "pikachu", "pidgey"}
+	out := &bytes.Buffer{}
+	keys := &keyPresses{"\x1b[B", "\x1b[B", "\x1b[A", "\r"}
+	if got, ok := arrowMenu(out, keys, "Which one?", options); !ok || got != "pikachu" {
+		t.Errorf("Expected pikachu, got %q, %v", got, ok)
+	}
+	if !strings.Contains(out.String(), "> \x1b[7mpikachu\x1b[0m") {
+		t.Errorf("Expected the chosen option highlighted, got %q", out.String())
+	}
+	if got, ok := arrowMenu(out, &keyPresses{"3"}, "Which one?", options); !ok || got != "pidgey" {
+		t.Errorf("Expected a number to pick that option, got %q, %v", got, ok)
+	}
+	if _, ok := arrowMenu(out, &keyPresses{"\x1b"}, "Which one?", options); ok {
+		t.Error("Expected escape to pick nothing")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	s.out = out
+	candidates := []string{"pichu", "pikachu", "raichu"}
+
+	if got, err := match(s, "pokemon", "rai", candidates); err != nil || got != "raichu" {
+		t.Errorf("Expected the only match, got %q, %v", got, err)
+	}
+	if _, err := match(s, "pokemon", "pi", candidates); err == nil || !strings.Contains(err.Error(), "pichu, pikachu") {
+		t.Errorf("Expected the matches listed without a way to ask, got %v", err)
+	}
+	s.input = func() (string, bool) { return "2", true }
+	if got, err := match(s, "pokemon", "pi", candidates); err != nil || got != "pikachu" {
+		t.Errorf("Expected the second match picked by number, got %q, %v", got, err)
+	}
+	if !strings.Contains(out.String(), "1. pichu\n2. pikachu\n") {
+		t.Errorf("Expected a numbered menu, got %q", out.String())
+	}
+	if _, err := match(s, "pokemon", "mew", candidates); err == nil {
+		t.Error("Expected no match")
+	}
+}
+
+func TestInspectPrefix(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+	out := &bytes.Buffer{}
+	if err := s.run("inspect pika", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Details of pikachu:") {
+		t.Errorf("Expected pika to find pikachu, got %q", out.String())
+	}
+}
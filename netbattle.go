@@ -0,0 +1,294 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/link"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// linkTimeout is how long to wait on the other player, whether for them to
+// connect or to pick their actions.
+const linkTimeout = 5 * time.Minute
+
+// linkBattle hosts a battle for another player's game to connect to,
+// connects to one, or watches one.
+func linkBattle(s *session, role string, args []string) error {
+	addr := link.DefaultAddr
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		addr, args = args[0], args[1:]
+	} else if role != "host" {
+		return fmt.Errorf("usage: battle %s <addr>", role)
+	}
+	if role == "watch" {
+		if len(args) > 0 {
+			return errors.New("usage: battle watch <addr>")
+		}
+		return watchBattle(s, addr)
+	}
+	if role == "connect" {
+		if len(args) > 0 {
+			return errors.New("the host picks the format of the battle")
+		}
+		b, err := joinBattle(s, addr, "")
+		if err != nil {
+			return err
+		}
+		b.onEnd = linkResult
+		openBattle(s, b)
+		return nil
+	}
+	_, format, err := parseBattleArgs(args, "easy")
+	if err != nil {
+		return err
+	}
+	b, _, err := hostBattle(s, addr, format, nil)
+	if err != nil {
+		return err
+	}
+	b.onEnd = linkResult
+	openBattle(s, b)
+	return nil
+}
+
+// linkSide is the player's party for a link battle, at full health.
+func linkSide(s *session) (*battle.Side, error) {
+	if s.encounter != nil {
+		return nil, fmt.Errorf("deal with the wild %s first", s.encounter.species.Name)
+	}
+	if len(s.profile.Party) == 0 {
+		return nil, errors.New("you don't have any pokemon to battle with")
+	}
+	side, err := partySide(s, s.profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range side.Team {
+		p.HP = p.Stats.HP
+	}
+	return side, nil
+}
+
+// hostBattle waits on addr for another player's game to connect, returning
+// the battle, ready to open, and the challenger's hello. listening, if set,
+// is told the address it's waiting on. Both games run the
+// same battle from the host's seed, sending each other only the actions
+// picked each turn. Like a rival battle it's friendly: both teams start at
+// full health, and it gives no experience.
+func hostBattle(s *session, addr string, format battle.Format, listening func(net.Addr) error) (*activeBattle, link.Message, error) {
+	mine, err := linkSide(s)
+	if err != nil {
+		return nil, link.Message{}, err
+	}
+	l, err := link.Listen(addr, linkTimeout)
+	if err != nil {
+		return nil, link.Message{}, err
+	}
+	fmt.Fprintf(s.out, "Waiting for a challenger on %s...\n", l.Addr())
+	if listening != nil {
+		if err := listening(l.Addr()); err != nil {
+			l.Close()
+			return nil, link.Message{}, err
+		}
+	}
+	conn, hello, err := l.Accept()
+	if err != nil {
+		l.Close()
+		return nil, link.Message{}, err
+	}
+	seed, rules := [2]uint64{rand.Uint64(), rand.Uint64()}, s.rules[format]
+	err = conn.Send(link.Message{Kind: link.Start, Name: s.profile.Name, Team: mine, Seed: seed, Format: format, Rules: rules})
+	if err != nil {
+		conn.Close()
+		l.Close()
+		return nil, link.Message{}, fmt.Errorf("the challenger didn't connect properly: %w", err)
+	}
+	b, err := newLinkBattle(s, conn, [2]*battle.Side{mine, hello.Team}, 0, seed, format, rules)
+	if err != nil {
+		l.Close()
+		return nil, link.Message{}, err
+	}
+	// The listener stays open for spectators until the battle is over.
+	b.spectators = l
+	broadcast(b, b.Log)
+	return b, hello, nil
+}
+
+// broadcast sends entries of a battle the player is hosting to its
+// spectators.
+func broadcast(b *activeBattle, entries []battle.Entry) {
+	if b.spectators != nil {
+		b.spectators.Broadcast(b.Sides[0].Name+" vs "+b.Sides[1].Name, entries)
+	}
+}
+
+// watchBattle follows the log of a battle hosted at addr until it's over.
+func watchBattle(s *session, addr string) error {
+	conn, err := link.Spectate(addr, linkTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(s.out, "Connected to %s. Waiting for the battle...\n", addr)
+	title, turn := "", 0
+	for {
+		m, err := conn.Expect(link.Log)
+		if err != nil {
+			// The host hangs up once the battle is over.
+			fmt.Fprintln(s.out, "The battle is over.")
+			return nil
+		}
+		if title == "" {
+			title = m.Name
+			fmt.Fprintf(s.out, "Watching %s\n", title)
+		}
+		for _, e := range m.Log {
+			if e.Turn != turn {
+				turn = e.Turn
+				fmt.Fprintf(s.out, "-- Turn %d --\n", turn)
+			}
+			fmt.Fprintln(s.out, e)
+		}
+	}
+}
+
+// joinBattle connects to a battle hosted at addr, saying it's for match
+// when the ladder set it up.
+func joinBattle(s *session, addr, match string) (*activeBattle, error) {
+	mine, err := linkSide(s)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := link.Dial(addr, linkTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var start link.Message
+	err = conn.Send(link.Message{Kind: link.Hello, Name: s.profile.Name, Team: mine, Match: match})
+	if err == nil {
+		start, err = conn.Expect(link.Start)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("couldn't join the battle at %s: %w", addr, err)
+	}
+	return newLinkBattle(s, conn, [2]*battle.Side{start.Team, mine}, 1, start.Seed, start.Format, start.Rules)
+}
+
+func newLinkBattle(s *session, conn *link.Conn, sides [2]*battle.Side, seat int, seed [2]uint64, format battle.Format, rules battle.Rules) (*activeBattle, error) {
+	if err := checkLinkTeam(sides[1-seat]); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	b := &activeBattle{
+		Battle:   battle.New(sides[0], sides[1], format, rand.New(rand.NewPCG(seed[0], seed[1]))),
+		friendly: true,
+		seat:     seat,
+		link:     conn,
+	}
+	b.Rules = rules
+	fmt.Fprintf(s.out, "You're battling %s over the link! It's a friendly battle, so both teams are at full health.\n", b.foes().Name)
+	return b, nil
+}
+
+// linkTurn sends the player's actions for the turn and waits for the other
+// player's. If they gave up instead, the player wins.
+func linkTurn(s *session, b *activeBattle, mine []battle.Action) ([]battle.Action, error) {
+	foe := b.foes().Name
+	fmt.Fprintf(s.out, "Waiting for %s...\n", foe)
+	if err := b.link.Send(link.Message{Kind: link.Turn, Actions: mine}); err != nil {
+		return nil, fmt.Errorf("lost the link to %s: %w", foe, err)
+	}
+	m, err := b.link.Receive()
+	if err != nil {
+		return nil, fmt.Errorf("lost the link to %s: %w", foe, err)
+	}
+	switch m.Kind {
+	case link.Turn:
+		if err := checkLinkActions(b.foes(), m.Actions); err != nil {
+			return nil, fmt.Errorf("%s %w", foe, err)
+		}
+		return m.Actions, nil
+	case link.Forfeit:
+		fmt.Fprintf(s.out, "%s gave up the battle.\n", foe)
+		b.Winner = b.seat
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%s sent a %s message in the middle of the battle", foe, m.Kind)
+}
+
+// checkLinkActions makes sure the other player only fought or switched to a
+// pokemon of theirs that can come in, as their own game would have let them:
+// the actions come from the network, so a modified game could send anything.
+func checkLinkActions(side *battle.Side, actions []battle.Action) error {
+	var switching []int
+	for _, a := range actions {
+		switch a.Kind {
+		case battle.Fight:
+		case battle.Switch:
+			if a.Switch < 0 || a.Switch >= len(side.Team) || side.Team[a.Switch].Fainted() ||
+				slices.Contains(side.Active, a.Switch) || slices.Contains(switching, a.Switch) {
+				return errors.New("tried to switch to a pokemon that can't come in")
+			}
+			switching = append(switching, a.Switch)
+		default:
+			return errors.New("sent an action link battles don't allow")
+		}
+	}
+	return nil
+}
+
+// maxBaseStat is the highest base stat of any species.
+const maxBaseStat = 255
+
+// checkLinkTeam makes sure the other player's team is one their game could
+// have sent: like their actions, it comes from the network, and a rigged
+// team would be played by both games alike.
+func checkLinkTeam(side *battle.Side) error {
+	if side == nil || len(side.Team) == 0 {
+		return errors.New("the other player didn't bring any pokemon")
+	}
+	if len(side.Team) > profile.PartySize {
+		return fmt.Errorf("the other player brought %d pokemon; a party has %d at most", len(side.Team), profile.PartySize)
+	}
+	for i, p := range side.Team {
+		switch {
+		case p == nil:
+			return fmt.Errorf("the other player's pokemon %d is missing", i+1)
+		case p.Level < 1 || p.Level > maxLevel:
+			return fmt.Errorf("the other player's %s is level %d", p.Name, p.Level)
+		case !linkStats(p.Stats, p.Level) || p.Mega != nil && !linkStats(p.Mega.Stats, p.Level):
+			return fmt.Errorf("the other player's %s has stats it can't have at level %d", p.Name, p.Level)
+		case p.HP <= 0 || p.HP > p.Stats.HP:
+			return fmt.Errorf("the other player's %s has %d/%d HP", p.Name, p.HP, p.Stats.HP)
+		case len(p.Moves) > battle.MaxMoves:
+			return fmt.Errorf("the other player's %s knows %d moves; pokemon know %d at most", p.Name, len(p.Moves), battle.MaxMoves)
+		}
+	}
+	return nil
+}
+
+// linkStats reports whether any species could have stats at level, with
+// perfect IVs, all the EVs a stat can take and a nature that helps it.
+func linkStats(stats battle.Stats, level int) bool {
+	top := statAt(maxBaseStat, maxIV, maxStatEVs, level) * 110 / 100
+	for _, v := range []int{stats.Attack, stats.Defense, stats.SpAttack, stats.SpDefense, stats.Speed} {
+		if v < 1 || v > top {
+			return false
+		}
+	}
+	return stats.HP >= 1 && stats.HP <= hpAt(maxBaseStat, maxIV, maxStatEVs, level)
+}
+
+// linkResult has nothing to add: the log says who won, and link battles
+// change nothing.
+func linkResult(s *session, b *battle.Battle) error {
+	return nil
+}
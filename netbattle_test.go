@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/link"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// linkSessions starts a link battle between two new sessions, hosted by the
+// first, with args passed to battle host, and returns them and the address
+// it's hosted on.
+func linkSessions(t *testing.T, args string) (*session, *session, string) {
+	t.Helper()
+	addr := freeAddr(t)
+	host, guest := newTestSession(t), newTestSession(t)
+	host.profile.Name, guest.profile.Name = "red", "blue"
+	host.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	guest.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 50)
+	guest.profile.Pokemon[0].Damage = 40
+
+	hosted := make(chan error, 1)
+	go func() { hosted <- host.run("battle host "+addr+args, &bytes.Buffer{}) }()
+	for try := 0; ; try++ {
+		err := guest.run("battle connect "+addr, &bytes.Buffer{})
+		if err == nil {
+			break
+		}
+		if try == 50 {
+			t.Fatalf("battle connect returned error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := <-hosted; err != nil {
+		t.Fatalf("battle host returned error: %v", err)
+	}
+	return host, guest, addr
+}
+
+// freeAddr is a local address nothing is listening on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// playBoth runs command in both sessions at once, as the two players would.
+func playBoth(t *testing.T, host, guest *session, command string) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for _, s := range []*session{host, guest} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.run(command, &bytes.Buffer{}); err != nil {
+				t.Errorf("%s returned error: %v", command, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLinkBattle(t *testing.T) {
+	host, guest, _ := linkSessions(t, "")
+	hb, gb := host.battle.Battle, guest.battle.Battle
+	if guest.battle.mine().Team[0].Name != "eevee" || guest.battle.foes().Name != "red" {
+		t.Fatalf("Expected blue to fight red with eevee, got %+v", guest.battle.mine())
+	}
+	if p := guest.battle.mine().Team[0]; p.HP != p.Stats.HP {
+		t.Errorf("Expected eevee to start at full health, got %d/%d", p.HP, p.Stats.HP)
+	}
+
+	for turn := 0; host.battle != nil || guest.battle != nil; turn++ {
+		if turn == 100 {
+			t.Fatalf("Expected the battle to end")
+		}
+		playBoth(t, host, guest, "fight tackle")
+	}
+	if !hb.Over() || hb.Winner != gb.Winner {
+		t.Errorf("Expected both games to agree on the winner, got %d and %d", hb.Winner, gb.Winner)
+	}
+	if !reflect.DeepEqual(hb.Log, gb.Log) {
+		t.Errorf("Expected both games to play the same battle, got\n%v\nand\n%v", hb.Log, gb.Log)
+	}
+	if p := guest.profile.Pokemon[0]; p.Damage != 40 {
+		t.Errorf("Expected a link battle to leave eevee as it was, got %d damage", p.Damage)
+	}
+}
+
+func TestLinkBattleForfeit(t *testing.T) {
+	host, guest, _ := linkSessions(t, " --double")
+	if host.battle.Format != 2 || guest.battle.Format != 2 {
+		t.Errorf("Expected the host's format to be used")
+	}
+	guest.profile.Give("potion", 1)
+	guest.battle.mine().Team[0].HP = 10
+	if err := guest.run("use potion eevee", &bytes.Buffer{}); err == nil || guest.profile.Inventory["potion"] != 1 {
+		t.Errorf("Expected items to be banned in link battles")
+	}
+	hb := host.battle.Battle
+	if err := guest.run("forfeit", &bytes.Buffer{}); err != nil {
+		t.Fatalf("forfeit returned error: %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := host.run("fight tackle", out); err != nil {
+		t.Fatalf("fight returned error: %v", err)
+	}
+	if host.battle != nil || hb.Winner != 0 || !bytes.Contains(out.Bytes(), []byte("blue gave up the battle.")) {
+		t.Errorf("Expected red to win when blue gave up, got %q", out.String())
+	}
+}
+
+func TestCheckLinkActions(t *testing.T) {
+	side := &battle.Side{Team: []*battle.Pokemon{{HP: 10}, {HP: 10}, {HP: 0}, {HP: 10}}, Active: []int{0}}
+	cases := []struct {
+		actions []battle.Action
+		ok      bool
+	}{
+		{[]battle.Action{{Kind: battle.Fight, Move: 1}}, true},
+		{[]battle.Action{{Kind: battle.Switch, Switch: 1}}, true},
+		{[]battle.Action{{Kind: battle.UseItem, Member: 0, Heal: 999}}, false},
+		{[]battle.Action{{Kind: battle.Switch, Switch: 0}}, false},
+		{[]battle.Action{{Kind: battle.Switch, Switch: 2}}, false},
+		{[]battle.Action{{Kind: battle.Switch, Switch: 4}}, false},
+		{[]battle.Action{{Kind: battle.Switch, Switch: -1}}, false},
+		{[]battle.Action{{Kind: battle.Switch, Switch: 1}, {Kind: battle.Switch, Switch: 1}}, false},
+	}
+	for _, c := range cases {
+		if err := checkLinkActions(side, c.actions); (err == nil) != c.ok {
+			t.Errorf("Expected %+v to be allowed: %v, got error %v", c.actions, c.ok, err)
+		}
+	}
+}
+
+func TestLinkBattleRejectsBadTeams(t *testing.T) {
+	pikachu := func(hp, attack int) *battle.Pokemon {
+		return &battle.Pokemon{Name: "pikachu", Level: 50, HP: hp, Stats: battle.Stats{HP: 110, Attack: attack, Defense: 60, SpAttack: 70, SpDefense: 70, Speed: 110}}
+	}
+	teams := map[string][]*battle.Pokemon{
+		"null":        {nil},
+		"all-fainted": {pikachu(0, 75), pikachu(0, 75)},
+		"rigged":      {pikachu(110, 9999)},
+	}
+	for name, team := range teams {
+		// The other player hosts, sending the team in its start message.
+		l, err := link.Listen("127.0.0.1:0", time.Second)
+		if err != nil {
+			t.Fatalf("Listen returned error: %v", err)
+		}
+		go func() {
+			if c, _, err := l.Accept(); err == nil {
+				c.Send(link.Message{Kind: link.Start, Name: "blue", Team: &battle.Side{Name: "blue", Team: team}, Format: battle.Single})
+			}
+		}()
+		s := newTestSession(t)
+		s.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 50)
+		if err := s.run("battle connect "+l.Addr().String(), &bytes.Buffer{}); err == nil || s.battle != nil {
+			t.Errorf("Expected a %s team to be refused by the guest, got %v", name, err)
+		}
+		l.Close()
+
+		// The other player connects, sending the team in its hello.
+		host := newTestSession(t)
+		host.profile.Add(pokeapi.PokemonType{Name: "eevee"}, 50)
+		addr := freeAddr(t)
+		hosted := make(chan error, 1)
+		go func() { hosted <- host.run("battle host "+addr, &bytes.Buffer{}) }()
+		for try := 0; ; try++ {
+			c, err := link.Dial(addr, time.Second)
+			if err == nil {
+				c.Send(link.Message{Kind: link.Hello, Name: "blue", Team: &battle.Side{Name: "blue", Team: team}})
+				defer c.Close()
+				break
+			}
+			if try == 50 {
+				t.Fatalf("Dial returned error: %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err := <-hosted; err == nil || host.battle != nil {
+			t.Errorf("Expected a %s team to be refused by the host, got %v", name, err)
+		}
+	}
+}
+
+func TestWatchLinkBattle(t *testing.T) {
+	host, guest, addr := linkSessions(t, "")
+	hb := host.battle.Battle
+	playBoth(t, host, guest, "fight tackle")
+
+	// The spectator joins a turn late, and catches up on the log so far.
+	out := &bytes.Buffer{}
+	watched := make(chan error, 1)
+	go func() { watched <- newTestSession(t).run("battle watch "+addr, out) }()
+	time.Sleep(50 * time.Millisecond)
+	for turn := 0; host.battle != nil || guest.battle != nil; turn++ {
+		if turn == 100 {
+			t.Fatalf("Expected the battle to end")
+		}
+		playBoth(t, host, guest, "fight tackle")
+	}
+	if err := <-watched; err != nil {
+		t.Fatalf("battle watch returned error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "Watching red vs blue") || !strings.HasSuffix(got, "The battle is over.\n") {
+		t.Errorf("Expected to watch red vs blue to the end, got %q", got)
+	}
+	for _, e := range hb.Log {
+		if !strings.Contains(got, e.String()) {
+			t.Errorf("Expected the spectator to see %q, got %q", e, got)
+		}
+	}
+}
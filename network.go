@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// transport is the transport every request goes through, set up for the
+// network the config describes: through its proxy, or the environment's,
+// and trusting its certificates.
+func transport(n config.Network) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if n.Proxy != "" {
+		proxy, err := url.Parse(n.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy %q isn't a URL", n.Proxy)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if n.CABundle == "" && !n.Insecure {
+		return t, nil
+	}
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: n.Insecure}
+	if n.CABundle != "" {
+		pem, err := os.ReadFile(n.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s has no PEM certificates", n.CABundle)
+		}
+		t.TLSClientConfig.RootCAs = roots
+	}
+	return t, nil
+}
+
+// loggedTransport logs every request made through next. Requests made for
+// a command, which carry its trace, are logged with their URL; others, such
+// as webhooks' and chat bots', only with their host, as their URLs can hold
+// tokens.
+type loggedTransport struct {
+	next http.RoundTripper
+	log  *slog.Logger
+}
+
+func (t loggedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	attrs := []any{"method", req.Method, "host", req.URL.Host}
+	if trace := req.Header.Get(pokeapi.TraceHeader); trace != "" {
+		attrs = append(attrs, "url", req.URL.Redacted(), "trace", trace)
+	}
+	attrs = append(attrs, "ms", time.Since(start).Milliseconds())
+	if err != nil {
+		t.log.Error("request failed", append(attrs, "error", err.Error())...)
+		return res, err
+	}
+	t.log.Info("request", append(attrs, "status", res.StatusCode)...)
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestTransport(t *testing.T) {
+	tr, err := transport(config.Network{Proxy: "http://proxy.example:3128"})
+	if err != nil {
+		t.Fatalf("transport returned error: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://pokeapi.co/api/v2/", nil)
+	if proxy, _ := tr.Proxy(req); proxy == nil || proxy.Host != "proxy.example:3128" {
+		t.Errorf("Expected the configured proxy, got %v", proxy)
+	}
+	if _, err := transport(config.Network{Proxy: "not a url"}); err == nil {
+		t.Errorf("Expected an error for a bad proxy")
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	plain, _ := transport(config.Network{})
+	if _, err := (&http.Client{Transport: plain}).Get(server.URL); err == nil {
+		t.Errorf("Expected the test server's certificate not to be trusted")
+	}
+	insecure, _ := transport(config.Network{Insecure: true})
+	if _, err := (&http.Client{Transport: insecure}).Get(server.URL); err != nil {
+		t.Errorf("Expected any certificate trusted, got %v", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o644)
+	trusting, err := transport(config.Network{CABundle: bundle})
+	if err != nil {
+		t.Fatalf("transport returned error: %v", err)
+	}
+	if _, err := (&http.Client{Transport: trusting}).Get(server.URL); err != nil {
+		t.Errorf("Expected the bundle's certificate trusted, got %v", err)
+	}
+
+	os.WriteFile(bundle, []byte("not a certificate"), 0o644)
+	if _, err := transport(config.Network{CABundle: bundle}); err == nil {
+		t.Errorf("Expected an error for a bundle without certificates")
+	}
+}
+
+func TestLoggedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	var log bytes.Buffer
+	client := &http.Client{Transport: loggedTransport{next: http.DefaultTransport, log: slog.New(slog.NewJSONHandler(&log, nil))}}
+
+	client.Get(server.URL + "/bot-token/send")
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v2/pokemon/eevee", nil)
+	req.Header.Set(pokeapi.TraceHeader, "abc")
+	client.Do(req)
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], "bot-token") || !strings.Contains(lines[0], `"status":200`) {
+		t.Errorf("Expected a request without a trace to be logged by host, got %q", log.String())
+	}
+	if !strings.Contains(lines[len(lines)-1], `/api/v2/pokemon/eevee","trace":"abc"`) {
+		t.Errorf("Expected a command's request to be logged with its URL, got %q", log.String())
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/events"
+)
+
+// overlayRefresh is how often, in seconds, the HTML overlay reloads itself.
+const overlayRefresh = 1
+
+// overlayState is what the overlay file shows. Streaming software reads it
+// as JSON, or shows the HTML version in a browser source.
+type overlayState struct {
+	Profile string `json:"profile"`
+	// Encounter is the wild pokemon in front of the player, if any.
+	Encounter *overlayPokemon `json:"encounter"`
+	LastCatch *overlayPokemon `json:"last_catch"`
+	Shinies   int             `json:"shinies"`
+	Combo     overlayCombo    `json:"combo"`
+	Updated   time.Time       `json:"updated"`
+}
+
+type overlayPokemon struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	Shiny bool   `json:"shiny"`
+}
+
+type overlayCombo struct {
+	Species string `json:"species,omitempty"`
+	Count   int    `json:"count"`
+}
+
+var overlayHTML = template.Must(template.New("overlay").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<style>body { font-family: sans-serif; color: white; text-shadow: 1px 1px 2px black; }</style>
+</head>
+<body>
+{{with .Encounter}}<p class="encounter">Wild {{if .Shiny}}shiny {{end}}{{.Name}} Lv. {{.Level}}</p>
+{{end}}{{with .LastCatch}}<p class="catch">Last catch: {{.Name}}{{if .Shiny}} ★{{end}}</p>
+{{end}}<p class="shinies">Shinies: {{.Shinies}}</p>
+{{if gt .Combo.Count 1}}<p class="combo">Combo: {{.Combo.Species}} x{{.Combo.Count}}</p>
+{{end}}</body>
+</html>
+`))
+
+// subscribeOverlay keeps the file at path up to date with s's game, as
+// JSON or, if path ends in .html, as a page that reloads itself.
+func subscribeOverlay(s *session, path string) {
+	state := &overlayState{Profile: s.id}
+	s.bus.SubscribeAll(func(e events.Event) {
+		if e.Kind == events.Caught {
+			state.LastCatch = &overlayPokemon{Name: e.Pokemon, Level: e.Level, Shiny: e.Shiny}
+		}
+		// Running from a pokemon publishes nothing, so the encounter is read
+		// from the session rather than kept from events.
+		state.Encounter = nil
+		if enc := s.encounter; enc != nil {
+			state.Encounter = &overlayPokemon{Name: enc.species.Name, Level: enc.level, Shiny: enc.shiny}
+		}
+		state.Shinies = 0
+		for _, p := range s.profile.Pokemon {
+			if p.Shiny {
+				state.Shinies++
+			}
+		}
+		state.Combo = overlayCombo{Species: s.profile.ComboSpecies, Count: s.profile.Combo}
+		state.Updated = e.Time
+		if err := writeOverlay(path, state); err != nil {
+			fmt.Fprintln(s.out, "Overlay error:", err)
+		}
+	})
+}
+
+// writeOverlay replaces the file at path in one go, so overlays never read
+// half of it.
+func writeOverlay(path string, state *overlayState) error {
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".html") {
+		err := overlayHTML.Execute(&buf, struct {
+			*overlayState
+			Refresh int
+		}{state, overlayRefresh})
+		if err != nil {
+			return err
+		}
+	} else {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(state); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".overlay-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 2)
+	path := filepath.Join(t.TempDir(), "overlay.json")
+	subscribeOverlay(s, path)
+
+	s.run("catch pikachu master", io.Discard)
+	s.run("catch pikachu master", io.Discard)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the overlay file to be written: %v", err)
+	}
+	var got overlayState
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Expected JSON, got %s", data)
+	}
+	if got.Profile != "local" || got.LastCatch == nil || got.LastCatch.Name != "pikachu" || got.Encounter != nil {
+		t.Errorf("Expected pikachu as the last catch, got %s", data)
+	}
+	if got.Combo.Species != "pikachu" || got.Combo.Count != 2 {
+		t.Errorf("Expected a pikachu combo of 2, got %+v", got.Combo)
+	}
+}
+
+func TestOverlayHTML(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	path := filepath.Join(t.TempDir(), "overlay.html")
+	subscribeOverlay(s, path)
+
+	s.run("catch pikachu master", io.Discard)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the overlay file to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "Last catch: pikachu") || !strings.Contains(string(data), `http-equiv="refresh"`) {
+		t.Errorf("Expected a page showing the last catch, got %s", data)
+	}
+}
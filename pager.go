@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/console"
+)
+
+// paging reports whether output on s goes through page: on the REPL's
+// terminal, unless the pager setting is off.
+func (s *session) paging() bool {
+	return s.tty != nil && s.out == s.tty && s.app.termHeight != nil && s.pager != "off"
+}
+
+// capture runs f with its output held back, then pages it. Colors are kept,
+// since the output still ends up on the terminal.
+func (s *session) capture(f func() error) error {
+	out, tty := s.out, s.tty
+	var buf bytes.Buffer
+	s.out, s.tty = &buf, &buf
+	defer func() {
+		s.out, s.tty = out, tty
+		s.page(buf.String())
+	}()
+	return f()
+}
+
+// page writes text, a screen at a time when it's taller than the terminal:
+// through the pager the setting names, $PAGER, less, or the game's own.
+func (s *session) page(text string) {
+	height := s.app.termHeight()
+	if height <= 0 || strings.Count(text, "\n") < height {
+		fmt.Fprint(s.out, text)
+		return
+	}
+	if s.pager != "internal" {
+		if err := s.runPager(text); err == nil {
+			return
+		}
+	}
+	s.internalPager(strings.SplitAfter(text, "\n"), height)
+}
+
+// runPager shows text with an external pager.
+func (s *session) runPager(text string) error {
+	command := s.pager
+	if command == "auto" {
+		command = os.Getenv("PAGER")
+	}
+	if command == "" {
+		if _, err := exec.LookPath("less"); err != nil {
+			return err
+		}
+		command = "less"
+	}
+	out, ok := s.out.(*os.File)
+	if !ok {
+		return fmt.Errorf("%s needs a terminal", command)
+	}
+	args := strings.Fields(command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = strings.NewReader(text), out, os.Stderr
+	// Unless told otherwise, less keeps colors, and leaves the text on the
+	// screen when it's done.
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=RX")
+	}
+	return cmd.Run()
+}
+
+// internalPager shows lines a screen at a time, asking between screens
+// whether to go on, search with /text or quit.
+func (s *session) internalPager(lines []string, height int) {
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	top := 0
+	for {
+		end := min(top+height-1, len(lines))
+		for _, line := range lines[top:end] {
+			fmt.Fprint(s.out, line)
+		}
+		if end == len(lines) || s.input == nil {
+			return
+		}
+		top = end
+		for asking := true; asking; {
+			fmt.Fprintf(s.out, "-- more (%d%%) -- enter, /text or q: ", 100*end/len(lines))
+			answer, ok := s.input()
+			answer = strings.TrimSpace(answer)
+			switch {
+			case !ok || answer == "q":
+				return
+			case strings.HasPrefix(answer, "/") && len(answer) > 1:
+				if found := search(lines, top, answer[1:]); found >= 0 {
+					top, asking = found, false
+				} else {
+					fmt.Fprintf(s.out, "%s isn't below.\n", answer[1:])
+				}
+			default:
+				asking = false
+			}
+		}
+	}
+}
+
+// search is the first line from start on with text in it, ignoring case
+// and colors, or -1.
+func search(lines []string, start int, text string) int {
+	text = strings.ToLower(text)
+	for i := start; i < len(lines); i++ {
+		if strings.Contains(strings.ToLower(console.Strip(lines[i])), text) {
+			return i
+		}
+	}
+	return -1
+}
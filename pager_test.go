@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	s.out, s.tty = out, out
+	s.pager = "internal"
+	s.app.termHeight = func() int { return 4 }
+	answers := []string{"", "/line 7", "/nowhere", "q"}
+	s.input = func() (string, bool) {
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, true
+	}
+
+	var text strings.Builder
+	for i := range 12 {
+		fmt.Fprintf(&text, "line %d\n", i)
+	}
+	s.page(text.String())
+	got := out.String()
+	for _, want := range []string{"line 0\nline 1\nline 2\n-- more (25%)", "line 3\nline 4\nline 5\n-- more (50%)", "line 7\nline 8\nline 9\n-- more (83%)", "nowhere isn't below.\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in the pages, got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "line 6") || strings.Contains(got, "line 10") || len(answers) != 0 {
+		t.Errorf("Expected the search to skip ahead, got %q with answers %v left", got, answers)
+	}
+
+	out.Reset()
+	s.page("short\n")
+	if got := out.String(); got != "short\n" {
+		t.Errorf("Expected output that fits to be written as is, got %q", got)
+	}
+}
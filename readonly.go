@@ -0,0 +1,37 @@
+package main
+
+// guestCommands are the commands left in read-only mode: browsing and
+// lookups, but nothing that catches, spends, trains or moves pokemon
+// around.
+var guestCommands = map[string]bool{
+	"help":       true,
+	"exit":       true,
+	"clear":      true,
+	"version":    true,
+	"completion": true,
+	"paths":      true,
+	"map":        true,
+	"mapb":       true,
+	"whereami":   true,
+	"track":      true,
+	"inspect":    true,
+	"pokedex":    true,
+	"party":      true,
+	"bag":        true,
+	"ev":         true,
+	"garden":     true,
+	"quests":     true,
+	"shop":       true,
+	"nature":     true,
+	"machine":    true,
+	"egg-group":  true,
+	"compatible": true,
+	"habitat":    true,
+	"shape":      true,
+	"export":     true,
+	"battles":    true,
+	"replay":     true,
+	"halloffame": true,
+	"journal":    true,
+	"snapshots":  true,
+}
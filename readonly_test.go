@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func TestReadOnly(t *testing.T) {
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	a.store = profile.NewStore(t.TempDir())
+	a.readOnly = true
+	s, err := a.session("guest")
+	if err != nil {
+		t.Fatalf("session() returned error: %v", err)
+	}
+	s.profile.Give("master-ball", 1)
+
+	var out bytes.Buffer
+	if err := s.run("catch pikachu master", &out); err != nil {
+		t.Fatalf("run() returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "catch is disabled in read-only mode") || len(s.profile.Pokedex) != 0 {
+		t.Errorf("Expected catching to be refused, got %q", out.String())
+	}
+
+	out.Reset()
+	s.run("help", &out)
+	if !strings.Contains(out.String(), "\npokedex") || strings.Contains(out.String(), "\ncatch") {
+		t.Errorf("Expected help to list only the commands left, got %q", out.String())
+	}
+	s.run("pokedex", &out)
+	if _, found, _ := a.store.Load("guest"); found {
+		t.Errorf("Expected a read-only session never to save")
+	}
+}
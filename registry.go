@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/azs06/pokedexcli/internal/plugins"
+)
+
+type cliCommand struct {
+	name        string
+	usage       string
+	description string
+	// minArgs and maxArgs bound the number of arguments; a negative maxArgs
+	// means there is no upper limit.
+	minArgs  int
+	maxArgs  int
+	callback func(s *session, args ...string) error
+	// complete, if set, offers candidates for the next argument given the
+	// ones already typed.
+	complete func(s *session, args []string) []string
+	// paged commands can write more than fits on the screen, so on the
+	// terminal their output goes through session.page.
+	paged bool
+	// hidden commands are left out of help and completion, for those who
+	// know to look for them.
+	hidden bool
+}
+
+var commands = map[string]cliCommand{}
+
+// registerCommand adds cmd to the dispatcher and to help. Built-in commands
+// register from init; plugins register at startup. Names are first come,
+// first served, so a plugin can't shadow a built-in.
+func registerCommand(cmd cliCommand) error {
+	if cmd.name == "" || cmd.callback == nil {
+		return fmt.Errorf("command needs a name and a callback")
+	}
+	if _, ok := commands[cmd.name]; ok {
+		return fmt.Errorf("command %q is already registered", cmd.name)
+	}
+	commands[cmd.name] = cmd
+	return nil
+}
+
+func sortedCommands() []cliCommand {
+	sorted := make([]cliCommand, 0, len(commands))
+	for _, cmd := range commands {
+		sorted = append(sorted, cmd)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
+
+func (cmd cliCommand) usageLine() string {
+	if cmd.usage == "" {
+		return cmd.name
+	}
+	return cmd.usage
+}
+
+func (cmd cliCommand) checkArgs(args []string) error {
+	if len(args) < cmd.minArgs || (cmd.maxArgs >= 0 && len(args) > cmd.maxArgs) {
+		return fmt.Errorf("usage: %s", cmd.usageLine())
+	}
+	return nil
+}
+
+// registerPlugins registers every plugin found in dir as a command.
+func registerPlugins(dir string) error {
+	found, err := plugins.Discover(dir)
+	errs := []error{err}
+	for _, p := range found {
+		errs = append(errs, registerCommand(cliCommand{
+			name:        p.Name,
+			usage:       p.Usage,
+			description: p.Description,
+			minArgs:     p.MinArgs,
+			maxArgs:     p.MaxArgs,
+			callback: func(s *session, args ...string) error {
+				names := make([]string, 0, len(s.profile.Pokedex))
+				for name := range s.profile.Pokedex {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return p.Run(s.out, plugins.Request{Args: args, Pokedex: names})
+			},
+		}))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestCommandArgValidation(t *testing.T) {
+	s := newTestSession(t)
+	err := s.run("catch", &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "catch <pokemon>") {
+		t.Errorf("Expected usage error, got %v", err)
+	}
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin fixture is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "--describe" ]; then
+  echo '{"name":"echo-test","usage":"echo-test <word>","description":"Echo a word","min_args":1,"max_args":1}'
+  exit 0
+fi
+echo "plugin says $1"
+`
+	os.WriteFile(filepath.Join(dir, "echo-test"), []byte(script), 0o755)
+	defer delete(commands, "echo-test")
+
+	if err := registerPlugins(dir); err != nil {
+		t.Fatalf("registerPlugins() returned error: %v", err)
+	}
+
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	if err := s.run("echo-test hello", out); err != nil {
+		t.Fatalf("run() returned error: %v", err)
+	}
+	if got := out.String(); got != "plugin says hello\n" {
+		t.Errorf("Unexpected plugin output %q", got)
+	}
+
+	out.Reset()
+	s.run("help", out)
+	if !strings.Contains(out.String(), "echo-test <word>: Echo a word") {
+		t.Errorf("Expected plugin in help, got %q", out.String())
+	}
+}
+
+func TestPluginMaxArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin fixture is a shell script")
+	}
+	dir := t.TempDir()
+	for name, limits := range map[string]string{
+		"no-max":    `"min_args":1`,
+		"below-min": `"min_args":2,"max_args":1`,
+	} {
+		script := "#!/bin/sh\nif [ \"$1\" = \"--describe\" ]; then\n  echo '{\"name\":\"" + name + "\"," + limits + "}'\n  exit 0\nfi\necho \"$#\"\n"
+		os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755)
+		defer delete(commands, name)
+	}
+	if err := registerPlugins(dir); err != nil {
+		t.Fatalf("registerPlugins() returned error: %v", err)
+	}
+
+	s := newTestSession(t)
+	for _, name := range []string{"no-max", "below-min"} {
+		out := &bytes.Buffer{}
+		if err := s.run(name+" a b c", out); err != nil || out.String() != "3\n" {
+			t.Errorf("Expected %s to take any number of arguments, got %q, %v", name, out.String(), err)
+		}
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+	s.profile.Add(pokeapi.PokemonType{Name: "pidgey"}, 5)
+
+	cases := []struct {
+		words    []string
+		expected []string
+	}{
+		{words: []string{"ex"}, expected: []string{"exit", "explore", "export", "expshare"}},
+		{words: []string{"inspect", "pik"}, expected: []string{"pikachu"}},
+		{words: []string{"inspect", "pikachu", ""}, expected: []string{}},
+		{words: []string{"completion", ""}, expected: []string{"bash", "fish", "zsh"}},
+	}
+	for _, c := range cases {
+		actual := completions(s, c.words)
+		if strings.Join(actual, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("completions(%q) = %q, expected %q", c.words, actual, c.expected)
+		}
+	}
+}
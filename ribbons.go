@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+const (
+	// championRibbon is for beating the Elite Four, and tournamentRibbon for
+	// winning a tournament; the whole party gets one.
+	championRibbon   = "champion-ribbon"
+	tournamentRibbon = "tournament-ribbon"
+)
+
+// marks are what a pokemon can be caught with, tried in order, each with a
+// one in odds chance. Time marks only come up during their hours, from
+// first up to but not including last.
+var marks = []struct {
+	name        string
+	odds        int
+	first, last int
+}{
+	{name: "rare-mark", odds: 1000},
+	{name: "dawn-mark", odds: 50, first: 5, last: 7},
+	{name: "lunchtime-mark", odds: 50, first: 12, last: 14},
+	{name: "dusk-mark", odds: 50, first: 17, last: 19},
+	{name: "sleepy-time-mark", odds: 50, first: 0, last: 5},
+	{name: "uncommon-mark", odds: 50},
+}
+
+// rollMark picks the mark, if any, a pokemon caught at t has, with roll
+// returning a number from 0 up to its argument.
+func rollMark(t time.Time, roll func(n int) int) string {
+	for _, m := range marks {
+		if m.first != m.last && (t.Hour() < m.first || t.Hour() >= m.last) {
+			continue
+		}
+		if roll(m.odds) == 0 {
+			return m.name
+		}
+	}
+	return ""
+}
+
+// awardRibbon gives p the ribbon, reporting false if it already had it.
+func awardRibbon(p *profile.Pokemon, ribbon string) bool {
+	if slices.Contains(p.Ribbons, ribbon) {
+		return false
+	}
+	p.Ribbons = append(p.Ribbons, ribbon)
+	return true
+}
+
+// ribbonName is a ribbon or mark as it's written, e.g. "Cool Ribbon".
+func ribbonName(ribbon string) string {
+	words := strings.Split(ribbon, "-")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ribbonsText lists p's mark and ribbons for inspect, or "" if it has none.
+func ribbonsText(p *profile.Pokemon) string {
+	var names []string
+	if p.Mark != "" {
+		names = append(names, ribbonName(p.Mark))
+	}
+	for _, r := range p.Ribbons {
+		names = append(names, ribbonName(r))
+	}
+	return strings.Join(names, ", ")
+}
+
+// awardTeamRibbon gives the party the ribbon, saying who got one.
+func awardTeamRibbon(s *session, ribbon string) {
+	var got []string
+	for _, p := range s.profile.PartyPokemon() {
+		if awardRibbon(p, ribbon) {
+			got = append(got, p.Species)
+		}
+	}
+	if len(got) > 0 {
+		fmt.Fprintf(s.out, "%s got the %s!\n", strings.Join(got, ", "), ribbonName(ribbon))
+	}
+}
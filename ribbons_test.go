@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+func TestRollMark(t *testing.T) {
+	noon := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	always := func(n int) int { return 0 }
+	never := func(n int) int { return n - 1 }
+	if got := rollMark(noon, always); got != "rare-mark" {
+		t.Errorf("Expected the rare mark to be tried first, got %q", got)
+	}
+	if got := rollMark(noon, never); got != "" {
+		t.Errorf("Expected no mark, got %q", got)
+	}
+	// Only the lunchtime mark's odds come up at noon.
+	onlyFifty := func(n int) int {
+		if n == 50 {
+			return 0
+		}
+		return 1
+	}
+	if got := rollMark(noon, onlyFifty); got != "lunchtime-mark" {
+		t.Errorf("Expected the lunchtime mark at noon, got %q", got)
+	}
+	if got := rollMark(noon.Add(3*time.Hour), onlyFifty); got != "uncommon-mark" {
+		t.Errorf("Expected the uncommon mark mid-afternoon, got %q", got)
+	}
+}
+
+func TestInspectShowsRibbons(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 10)
+	p := &s.profile.Pokemon[0]
+	p.Mark = "dusk-mark"
+	awardRibbon(p, championRibbon)
+	if awardRibbon(p, championRibbon) {
+		t.Errorf("Expected a ribbon to be won only once")
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("inspect pikachu", out); err != nil {
+		t.Fatalf("inspect returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "  Ribbons: Dusk Mark, Champion Ribbon\n") {
+		t.Errorf("Expected the mark and ribbon, got %q", out.String())
+	}
+}
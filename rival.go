@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// completeRivals are the other saved profiles.
+func completeRivals(s *session) []string {
+	if s.store == nil {
+		return nil
+	}
+	names, err := s.store.List()
+	if err != nil {
+		return nil
+	}
+	return slices.DeleteFunc(names, func(name string) bool { return name == s.profile.Name })
+}
+
+// rivalBattle fights the party of another saved profile, which the
+// type-aware AI plays unless a difficulty is asked for. It's a friendly
+// battle: both teams start at full health, and it gives no experience.
+func rivalBattle(s *session, name string, args []string) error {
+	if s.encounter != nil {
+		return fmt.Errorf("deal with the wild %s first", s.encounter.species.Name)
+	}
+	if len(s.profile.Party) == 0 {
+		return errors.New("you don't have any pokemon to battle with")
+	}
+	if s.store == nil {
+		return errors.New("profiles aren't being saved, so there's no one to battle")
+	}
+	if name == s.profile.Name {
+		return errors.New("you can't battle yourself")
+	}
+	ai, format, err := parseBattleArgs(args, "hard")
+	if err != nil {
+		return err
+	}
+	rival, found, err := s.store.Load(name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("there's no saved profile named %s", name)
+	}
+	if len(rival.Party) == 0 {
+		return fmt.Errorf("%s doesn't have any pokemon to battle with", name)
+	}
+
+	player, err := partySide(s, s.profile)
+	if err != nil {
+		return err
+	}
+	opponent, err := partySide(s, rival)
+	if err != nil {
+		return err
+	}
+	for _, side := range []*battle.Side{player, opponent} {
+		for _, p := range side.Team {
+			p.HP = p.Stats.HP
+		}
+	}
+	fmt.Fprintf(s.out, "%s wants to battle! It's a friendly battle, so both teams are at full health and there's no experience to be had.\n", name)
+	startBattle(s, player, opponent, format, ai, rivalResult)
+	s.battle.friendly = true
+	return nil
+}
+
+// rivalResult keeps the player's record against the rival.
+func rivalResult(s *session, b *battle.Battle) error {
+	name := b.Sides[1].Name
+	if s.profile.Rivals == nil {
+		s.profile.Rivals = map[string]profile.Rivalry{}
+	}
+	r := s.profile.Rivals[name]
+	if b.Winner == 0 {
+		r.Wins++
+		fmt.Fprintf(s.out, "You beat %s!\n", name)
+	} else {
+		r.Losses++
+		fmt.Fprintf(s.out, "%s won this time.\n", name)
+	}
+	s.profile.Rivals[name] = r
+	fmt.Fprintf(s.out, "Your record against %s: %d wins, %d losses\n", name, r.Wins, r.Losses)
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+func TestRivalBattle(t *testing.T) {
+	s := newTestSession(t)
+	s.store = profile.NewStore(t.TempDir())
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	s.profile.Pokemon[0].Damage = 30
+	if err := s.run("battle --vs gary", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected battling a profile that was never saved to fail")
+	}
+	if err := s.run("battle --vs local", &bytes.Buffer{}); err == nil {
+		t.Errorf("Expected battling yourself to fail")
+	}
+
+	gary := profile.New("gary")
+	gary.Add(pokeapi.PokemonType{Name: "magikarp"}, 5)
+	if err := s.store.Save(gary); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if got := completeRivals(s); !slices.Equal(got, []string{"gary"}) {
+		t.Errorf("Expected gary to be offered, got %v", got)
+	}
+	out := &bytes.Buffer{}
+	if err := s.run("battle --vs gary", out); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if s.state() != stateBattle || s.battle.Sides[1].Team[0].Name != "magikarp" {
+		t.Fatalf("Expected a battle against gary's magikarp, got %q", out.String())
+	}
+	if p := s.battle.Sides[0].Team[0]; p.HP != p.Stats.HP {
+		t.Errorf("Expected pikachu to start at full health, got %d/%d", p.HP, p.Stats.HP)
+	}
+
+	out.Reset()
+	for turn := 0; s.battle != nil; turn++ {
+		if turn == 100 {
+			t.Fatalf("Expected the battle to end")
+		}
+		if err := s.run("fight tackle", out); err != nil {
+			t.Fatalf("fight returned error: %v", err)
+		}
+	}
+	if !strings.Contains(out.String(), "Your record against gary: 1 wins, 0 losses") {
+		t.Errorf("Expected the win to be recorded, got %q", out.String())
+	}
+	if p := s.profile.Pokemon[0]; p.Damage != 30 || p.Exp != 0 {
+		t.Errorf("Expected a friendly battle to leave pikachu as it was, got %d damage and %d exp", p.Damage, p.Exp)
+	}
+}
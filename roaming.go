@@ -0,0 +1,119 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+const (
+	// roamEvery is how many commands a roaming pokemon stays in one
+	// location.
+	roamEvery = 20
+	// roamerChance is how likely exploring where a roaming pokemon is turns
+	// it up.
+	roamerChance = 1.0 / 3
+	roamerLevel  = 40
+)
+
+// roamer is a legendary that wanders its region rather than waiting in one
+// place.
+type roamer struct {
+	name   string
+	region string
+}
+
+var roamers = []roamer{
+	{"raikou", "johto"},
+	{"entei", "johto"},
+	{"suicune", "johto"},
+	{"latias", "hoenn"},
+	{"latios", "hoenn"},
+	{"mesprit", "sinnoh"},
+	{"cresselia", "sinnoh"},
+	{"tornadus", "unova"},
+	{"thundurus", "unova"},
+}
+
+func init() {
+	registerCommand(cliCommand{
+		name:        "track",
+		description: "Hear the latest rumors of roaming legendary pokemon",
+		callback:    commandTrack,
+	})
+}
+
+// roamerLocation is the location of its region r is in now. It moves every
+// roamEvery commands, to a location picked from the player's name and the
+// time, so it's different for every player but doesn't need saving.
+func roamerLocation(s *session, r roamer) (string, error) {
+	region, err := s.source.Region(r.region)
+	if err != nil {
+		return "", err
+	}
+	if len(region.Locations) == 0 {
+		return "", fmt.Errorf("%s has no locations", r.region)
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%d", s.profile.Name, r.name, s.profile.Commands/roamEvery)
+	return region.Locations[h.Sum64()%uint64(len(region.Locations))].Name, nil
+}
+
+// roamerIn is the roaming pokemon in location, if there is one the player
+// hasn't caught. Roamers whose region can't be looked up are left out.
+func roamerIn(s *session, location string) (roamer, bool) {
+	for _, r := range roamers {
+		if slices.Contains(s.profile.RoamersCaught, r.name) {
+			continue
+		}
+		if at, err := roamerLocation(s, r); err == nil && at == location {
+			return r, true
+		}
+	}
+	return roamer{}, false
+}
+
+// roamerOf is the roaming pokemon p is, in any of its forms, if it's one.
+// Roamers can only be met where they roam, not caught by name.
+func roamerOf(p pokeapi.PokemonType) (roamer, bool) {
+	species := cmp.Or(p.Species.Name, p.Name)
+	i := slices.IndexFunc(roamers, func(r roamer) bool { return r.name == species })
+	if i < 0 {
+		return roamer{}, false
+	}
+	return roamers[i], true
+}
+
+// meetRoamer starts an encounter with a roaming pokemon. It flees after the
+// first turn unless it's caught.
+func meetRoamer(s *session, r roamer) error {
+	p, err := s.source.Pokemon(r.name)
+	if err != nil {
+		return err
+	}
+	if err := meetPokemon(s, p, roamerLevel); err != nil {
+		return err
+	}
+	s.encounter.roamer = true
+	fmt.Fprintf(s.out, "%s looks ready to flee at any moment!\n", r.name)
+	return nil
+}
+
+func commandTrack(s *session, args ...string) error {
+	fmt.Fprintln(s.out, "Rumors of roaming pokemon:")
+	for _, r := range roamers {
+		if slices.Contains(s.profile.RoamersCaught, r.name) {
+			fmt.Fprintf(s.out, " - %s: caught\n", r.name)
+			continue
+		}
+		at, err := roamerLocation(s, r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, " - %s is roaming %s; it was last seen around %s\n", r.name, r.region, at)
+	}
+	return nil
+}
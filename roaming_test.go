@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+)
+
+// roamKanto has raikou roam the fake source's kanto, the only region it
+// has, for a test.
+func roamKanto(t *testing.T) {
+	saved := roamers
+	roamers = []roamer{{"raikou", "kanto"}}
+	t.Cleanup(func() { roamers = saved })
+}
+
+func TestRoamersMove(t *testing.T) {
+	roamKanto(t)
+	s := newTestSession(t)
+	seen := map[string]bool{}
+	for s.profile.Commands = 0; s.profile.Commands < 50*roamEvery; s.profile.Commands += roamEvery {
+		at, err := roamerLocation(s, roamers[0])
+		if err != nil {
+			t.Fatalf("roamerLocation returned error: %v", err)
+		}
+		seen[at] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected raikou to move between locations, only saw %v", seen)
+	}
+
+	at, _ := roamerLocation(s, roamers[0])
+	out := &bytes.Buffer{}
+	if err := s.run("track", out); err != nil {
+		t.Fatalf("track returned error: %v", err)
+	}
+	// Running track moved the clock on, but not by a whole roamEvery.
+	if !strings.Contains(out.String(), " - raikou is roaming kanto; it was last seen around "+at+"\n") {
+		t.Errorf("Expected a rumor of raikou around %s, got %q", at, out.String())
+	}
+}
+
+func TestRoamerFleesAfterOneTurn(t *testing.T) {
+	roamKanto(t)
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 50)
+	if err := meetRoamer(s, roamers[0]); err != nil {
+		t.Fatalf("meetRoamer returned error: %v", err)
+	}
+	if err := s.run("bait", &bytes.Buffer{}); err != nil {
+		t.Fatalf("bait returned error: %v", err)
+	}
+	if s.encounter != nil {
+		t.Errorf("Expected raikou to flee after a turn")
+	}
+
+	if err := meetRoamer(s, roamers[0]); err != nil {
+		t.Fatalf("meetRoamer returned error: %v", err)
+	}
+	if err := s.run("battle", &bytes.Buffer{}); err != nil {
+		t.Fatalf("battle returned error: %v", err)
+	}
+	if s.encounter != nil || s.battle != nil {
+		t.Errorf("Expected raikou to flee rather than battle")
+	}
+}
+
+func TestCaughtRoamerStopsRoaming(t *testing.T) {
+	roamKanto(t)
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	if err := meetRoamer(s, roamers[0]); err != nil {
+		t.Fatalf("meetRoamer returned error: %v", err)
+	}
+	if err := s.run("throw master", &bytes.Buffer{}); err != nil {
+		t.Fatalf("throw returned error: %v", err)
+	}
+	if !slices.Contains(s.profile.RoamersCaught, "raikou") {
+		t.Fatalf("Expected raikou to be caught for good")
+	}
+	at, _ := roamerLocation(s, roamers[0])
+	if r, ok := roamerIn(s, at); ok && r.name == "raikou" {
+		t.Errorf("Expected a caught raikou not to roam")
+	}
+	out := &bytes.Buffer{}
+	s.run("track", out)
+	if !strings.Contains(out.String(), " - raikou: caught\n") {
+		t.Errorf("Expected track to show raikou caught, got %q", out.String())
+	}
+}
+
+func TestCatchRefusesRoamers(t *testing.T) {
+	roamKanto(t)
+	s := newTestSession(t)
+	s.profile.Give("master-ball", 1)
+	if err := s.run("catch raikou master", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "roams") {
+		t.Errorf("Expected catch to refuse a roaming pokemon, got %v", err)
+	}
+	if s.encounter != nil || s.profile.Inventory["master-ball"] != 1 {
+		t.Errorf("Expected no encounter and no ball thrown, got %v", s.profile.Inventory)
+	}
+}
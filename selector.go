@@ -0,0 +1,170 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// boxSize is how many pokemon fit in a PC box. The pokemon not in the party
+// fill the boxes in the order they were caught.
+const boxSize = 30
+
+// selectorUsage names the flags that pick pokemon out, for usage messages.
+const selectorUsage = "[species] [--type <type>] [--shiny|--not-shiny] [--team] [--box <n>] [--keep-best <n>]"
+
+// selector picks out pokemon the player owns, for commands that act on
+// several at once. Each flag narrows the choice down; keepBest then leaves
+// the best of each species out, by IVs and then level.
+type selector struct {
+	species  string
+	typ      string
+	shiny    *bool
+	team     bool
+	box      int
+	keepBest int
+}
+
+// parseSelector reads a selector from args, returning the args it didn't
+// recognise for the command to deal with.
+func parseSelector(args []string) (sel selector, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--type", "--box", "--keep-best":
+			if i+1 == len(args) {
+				return sel, nil, fmt.Errorf("%s needs a value", arg)
+			}
+			i++
+			value := args[i]
+			if arg == "--type" {
+				sel.typ = value
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 && arg == "--box" || n < 0 {
+				return sel, nil, fmt.Errorf("%s needs a number, got %s", arg, value)
+			}
+			if arg == "--box" {
+				sel.box = n
+			} else {
+				sel.keepBest = n
+			}
+		case "--shiny", "--not-shiny":
+			shiny := arg == "--shiny"
+			sel.shiny = &shiny
+		case "--team":
+			sel.team = true
+		default:
+			if strings.HasPrefix(arg, "--") || sel.species != "" {
+				rest = append(rest, arg)
+				continue
+			}
+			sel.species = formName(arg)
+		}
+	}
+	return sel, rest, nil
+}
+
+// empty reports whether sel picks nothing out, and so would pick every
+// pokemon.
+func (sel selector) empty() bool {
+	return sel.species == "" && sel.typ == "" && sel.shiny == nil && !sel.team && sel.box == 0
+}
+
+// boxOf is which PC box p is in, or 0 if it's in the party.
+func boxOf(s *session, p *profile.Pokemon) int {
+	if slices.Contains(s.profile.Party, p.ID) {
+		return 0
+	}
+	stored := 0
+	for i := range s.profile.Pokemon {
+		other := &s.profile.Pokemon[i]
+		if other.ID == p.ID {
+			break
+		}
+		if !slices.Contains(s.profile.Party, other.ID) {
+			stored++
+		}
+	}
+	return stored/boxSize + 1
+}
+
+func (sel selector) matches(s *session, p *profile.Pokemon) bool {
+	entry := s.profile.Pokedex[p.Species]
+	switch {
+	case sel.species != "" && p.Species != sel.species && speciesName(entry) != sel.species:
+		return false
+	case sel.typ != "" && !slices.Contains(typeNames(entry), sel.typ):
+		return false
+	case sel.shiny != nil && p.Shiny != *sel.shiny:
+		return false
+	case sel.team && !slices.Contains(s.profile.Party, p.ID):
+		return false
+	case sel.box != 0 && boxOf(s, p) != sel.box:
+		return false
+	}
+	return true
+}
+
+// pick is the pokemon sel picks out, in the order they were caught.
+func (sel selector) pick(s *session) []*profile.Pokemon {
+	var picked []*profile.Pokemon
+	for i := range s.profile.Pokemon {
+		if p := &s.profile.Pokemon[i]; sel.matches(s, p) {
+			picked = append(picked, p)
+		}
+	}
+	if sel.keepBest == 0 {
+		return picked
+	}
+
+	best := slices.Clone(picked)
+	slices.SortStableFunc(best, func(a, b *profile.Pokemon) int {
+		return cmp.Or(cmp.Compare(ivTotal(b), ivTotal(a)), cmp.Compare(b.Level, a.Level))
+	})
+	kept, bySpecies := map[int]bool{}, map[string]int{}
+	for _, p := range best {
+		if bySpecies[p.Species] < sel.keepBest {
+			bySpecies[p.Species]++
+			kept[p.ID] = true
+		}
+	}
+	return slices.DeleteFunc(picked, func(p *profile.Pokemon) bool { return kept[p.ID] })
+}
+
+func ivTotal(p *profile.Pokemon) int {
+	total := 0
+	for _, iv := range p.IVs {
+		total += iv
+	}
+	return total
+}
+
+// pickOwned picks the pokemon args select, failing if they select nothing
+// in particular or nothing at all. Args the selector doesn't recognise are
+// returned.
+func pickOwned(s *session, args []string) ([]*profile.Pokemon, []string, error) {
+	sel, rest, err := parseSelector(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sel.empty() {
+		return nil, nil, errors.New("pick pokemon out with " + selectorUsage)
+	}
+	picked := sel.pick(s)
+	if len(picked) == 0 {
+		return nil, nil, errors.New("none of your pokemon match")
+	}
+	return picked, rest, nil
+}
+
+// ownedText is p as a line of a list of several pokemon.
+func ownedText(s *session, p *profile.Pokemon) string {
+	return fmt.Sprintf("#%d %s%s%s Lv. %d", p.ID, p.Species, genderSymbol(s, p.Gender), shinyMark(s, p.Shiny), p.Level)
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azs06/pokedexcli/internal/audio"
+	"github.com/azs06/pokedexcli/internal/battle"
+	"github.com/azs06/pokedexcli/internal/battlelog"
+	"github.com/azs06/pokedexcli/internal/config"
+	"github.com/azs06/pokedexcli/internal/console"
+	"github.com/azs06/pokedexcli/internal/events"
+	"github.com/azs06/pokedexcli/internal/filecache"
+	"github.com/azs06/pokedexcli/internal/fulltext"
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/journal"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/pokecache"
+	"github.com/azs06/pokedexcli/internal/profile"
+	"github.com/azs06/pokedexcli/internal/prompt"
+	"github.com/azs06/pokedexcli/internal/snapshot"
+	"github.com/azs06/pokedexcli/internal/sprite"
+	"github.com/azs06/pokedexcli/internal/theme"
+)
+
+// errExit is returned by the exit command. The REPL treats it as a request to
+// quit; other front ends can just drop the session.
+var errExit = errors.New("exit requested")
+
+// app holds what all sessions share. It is safe for concurrent use, so a
+// server or bot can hand every user their own session.
+type app struct {
+	source pokeapi.DataSource
+	hooks  *hooks.Runner
+	// bus sees the events of every session, so its handlers may be called
+	// from several goroutines at once.
+	bus *events.Bus
+	// store persists each session's profile, named after the session. Without
+	// one, progress only lives as long as the session.
+	store *profile.Store
+	// battles records every finished battle for replays. It may be nil.
+	battles *battlelog.Store
+	// journals keeps each player's journal. It may be nil.
+	journals *journal.Store
+	// snapshots archives each player's game now and then. It may be nil.
+	snapshots *snapshot.Store
+	// cache keeps API responses in memory. It may be nil.
+	cache *pokecache.Cache
+	// log records commands, requests and errors in the file at logPath. It
+	// discards them when there's no log file, and logPath is empty.
+	log     *slog.Logger
+	logPath string
+	// dataDir is where saved data lives and cacheDir where files that can
+	// be fetched again do; see the -data-dir and -cache-dir flags.
+	dataDir  string
+	cacheDir string
+	// snapshotDir is the offline source's snapshot, which sync refresh
+	// brings up to date; it's empty for the other sources.
+	snapshotDir string
+	// themesDir has the user's color themes.
+	themesDir string
+	// paths are the files and directories the paths command lists.
+	paths []gamePath
+	// config has the settings each session plays with, merged for its
+	// profile. The config command changes it under mu, and writes it back
+	// to configPath.
+	config     config.Config
+	configPath string
+	// rules are the gimmicks battles of each format allow.
+	rules map[battle.Format]battle.Rules
+	// ladder is the URL of the ladder server.
+	ladder string
+	// wonderTrade is the URL of the wonder trade server.
+	wonderTrade string
+	// twitch is where the twitch command finds chat.
+	twitch config.Twitch
+	// slack is the app the slack server answers for.
+	slack config.Slack
+	// readOnly limits sessions to guestCommands and stops them saving.
+	readOnly bool
+	// plain is set when the terminal can't show colors or move the cursor.
+	plain bool
+	// fast skips animations for every session, as the -fast flag asks.
+	fast bool
+	// keys is the terminal the REPL reads from, for menus that take single
+	// key presses; it's nil elsewhere.
+	keys *os.File
+	// termHeight is how many lines the terminal shows, 0 if unknown. It's
+	// nil without a terminal.
+	termHeight func() int
+	// graphics is how the terminal shows images, if it does, and sprites
+	// keeps the sprites drawn with it. sprites may be nil.
+	graphics sprite.Protocol
+	sprites  *filecache.Cache
+	// player plays cries, which cries keeps; either may be nil.
+	player *audio.Player
+	cries  *filecache.Cache
+	// pprof serves profiles while debug pprof is on. It's guarded by mu.
+	pprof *http.Server
+	// indexes are search's, by language, grown as it finds species it
+	// hasn't indexed. They're guarded by mu.
+	indexes map[string]*fulltext.Index
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newApp(source pokeapi.DataSource, hooks *hooks.Runner) *app {
+	return &app{
+		source:   source,
+		hooks:    hooks,
+		bus:      events.NewBus(),
+		log:      slog.New(slog.DiscardHandler),
+		rules:    maps.Clone(defaultRules),
+		graphics: sprite.None,
+		sessions: map[string]*session{},
+	}
+}
+
+// session returns the session for id, creating it and loading its profile
+// on first use. The profile stays locked to this process from then on.
+func (a *app) session(id string) (*session, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if s, ok := a.sessions[id]; ok {
+		return s, nil
+	}
+
+	p, found := profile.New(id), false
+	if a.store != nil {
+		// Read-only sessions never save, so they can share a profile.
+		if !a.readOnly {
+			if err := a.store.Lock(id); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if p, found, err = a.store.Load(id); err != nil {
+			return nil, err
+		}
+	}
+	s := newSession(id, a, p)
+	s.newPlayer = !found
+	a.sessions[id] = s
+	return s, nil
+}
+
+// session is one player's state. Commands only run through run, which holds
+// mu for the whole command, so a session never executes two at once.
+type session struct {
+	id string
+	// app is what the session came from, for commands that serve other
+	// players.
+	app    *app
+	source pokeapi.DataSource
+	// trace follows the fetches of the command running, if one is.
+	trace *pokeapi.Trace
+	hooks *hooks.Runner
+	// bus carries this session's events; they are forwarded to the app bus.
+	bus          *events.Bus
+	store        *profile.Store
+	battles      *battlelog.Store
+	journals     *journal.Store
+	snapshots    *snapshot.Store
+	promptFormat *prompt.Template
+	rules        map[battle.Format]battle.Rules
+	versionGroup string
+	// shinyOdds is the one-in-n chance of wild pokemon being shiny, before
+	// combos.
+	shinyOdds int
+	// a11y is the accessibility mode; see config.Settings.
+	a11y bool
+	// fast skips animations, sound plays cries and timing reports on each
+	// command; see config.Settings.
+	fast   bool
+	sound  bool
+	timing bool
+	// pager is the pager setting.
+	pager string
+	// language is what search reads species' text in.
+	language string
+	theme    theme.Theme
+	// iconStyle names the iconSets entry shown.
+	iconStyle string
+	// sprites is how sprites are drawn on the tty.
+	sprites sprite.Protocol
+	// tty is where the REPL shows output, if it takes colors; output
+	// anywhere else stays plain.
+	tty         io.Writer
+	ladder      string
+	wonderTrade string
+	twitch      config.Twitch
+	readOnly    bool
+
+	mu  sync.Mutex
+	out io.Writer
+	// input reads a line from the player for prompts. It is nil for front
+	// ends that can't ask follow-up questions.
+	input func() (string, bool)
+	// line is the line being run as it was typed, before it was
+	// lowercased; see verbatim.
+	line      string
+	next      string
+	previous  string
+	encounter *encounter
+	battle    *activeBattle
+	// challenge is the Elite Four run in progress, if any.
+	challenge *challenge
+	// tournament is the tournament in progress, if any.
+	tournament *tournament
+	profile    *profile.Profile
+	// newPlayer is set when the profile had never been saved before.
+	newPlayer bool
+	// saved is the profile as last written, to skip saves that change
+	// nothing.
+	saved []byte
+	// snapshotted is when the profile was last archived, zero until the
+	// snapshot store has been asked.
+	snapshotted time.Time
+	// now is the clock for anything that depends on the date, so tests can
+	// fix it.
+	now func() time.Time
+}
+
+func newSession(id string, a *app, p *profile.Profile) *session {
+	s := &session{
+		id:          id,
+		app:         a,
+		source:      a.source,
+		hooks:       a.hooks,
+		bus:         events.NewBus(),
+		store:       a.store,
+		battles:     a.battles,
+		journals:    a.journals,
+		snapshots:   a.snapshots,
+		out:         io.Discard,
+		rules:       a.rules,
+		ladder:      a.ladder,
+		wonderTrade: a.wonderTrade,
+		twitch:      a.twitch,
+		readOnly:    a.readOnly,
+		profile:     p,
+		now:         time.Now,
+	}
+	s.saved, _ = json.Marshal(p)
+	// A prompt that doesn't compile falls back to the default; main reports
+	// it.
+	s.applySettings(a.config.For(id))
+	s.bus.SubscribeAll(a.bus.Publish)
+	s.subscribeHooks()
+	s.subscribeQuests()
+	s.subscribeJournal()
+	return s
+}
+
+// applySettings makes the session play with st. If st's prompt doesn't
+// compile or its theme doesn't load, the default is used and the error
+// returned.
+func (s *session) applySettings(st config.Settings) error {
+	s.versionGroup = st.VersionGroup
+	s.shinyOdds = cmp.Or(st.ShinyOdds, defaultShinyOdds)
+	s.a11y = st.A11y != nil && *st.A11y
+	s.fast = st.Fast != nil && *st.Fast
+	s.sound = st.Sound != nil && *st.Sound
+	s.timing = st.Timing != nil && *st.Timing
+	s.pager = cmp.Or(st.Pager, "auto")
+	s.language = cmp.Or(st.Language, fulltext.DefaultLanguage)
+	var errs []error
+	s.iconStyle = cmp.Or(st.Icons, "none")
+	if _, ok := iconSets[s.iconStyle]; !ok {
+		errs = append(errs, fmt.Errorf("unknown icons %q", s.iconStyle))
+		s.iconStyle = "none"
+	}
+	s.sprites = s.app.graphics
+	if st.Sprites != "" && st.Sprites != "auto" {
+		s.sprites = sprite.Protocol(st.Sprites)
+	}
+	if !slices.Contains(sprite.Protocols, s.sprites) {
+		errs = append(errs, fmt.Errorf("unknown sprites %q", s.sprites))
+		s.sprites = sprite.None
+	}
+	var err error
+	if s.theme, err = theme.Load(s.app.themesDir, cmp.Or(st.Theme, theme.Default)); err != nil {
+		s.theme, _ = theme.Load("", theme.Default)
+		errs = append(errs, err)
+	}
+	if s.promptFormat, err = prompt.Compile(cmp.Or(st.Prompt, prompt.Default)); err != nil {
+		s.promptFormat, _ = prompt.Compile(prompt.Default)
+		errs = append(errs, fmt.Errorf("bad prompt: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// start publishes Started. Front ends call it once, when a player first
+// shows up.
+func (s *session) start(out io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out = out
+	defer func() { s.out = io.Discard }()
+	if s.newPlayer {
+		fmt.Fprintln(s.out, "Welcome, new trainer! Choose your first partner with the starter command.")
+	}
+	checkIn(s)
+	s.publish(events.Event{Kind: events.Started})
+	if err := s.save(); err != nil {
+		fmt.Fprintln(s.out, "Error:", err)
+	}
+}
+
+// publish stamps e with the session and sends it. Callers hold s.mu, so
+// handlers may read session state and write to s.out.
+func (s *session) publish(e events.Event) {
+	e.Session = s.id
+	if e.Time.IsZero() {
+		e.Time = s.now()
+	}
+	if s.trace != nil {
+		e.Trace = s.trace.ID
+	}
+	s.bus.Publish(e)
+}
+
+func (s *session) subscribeHooks() {
+	s.bus.Subscribe(events.Started, func(e events.Event) {
+		s.reportHookError(s.hooks.Start(s.hookContext()))
+	})
+	s.bus.Subscribe(events.Caught, func(e events.Event) {
+		s.reportHookError(s.hooks.Catch(s.hookContext(), s.profile.Pokedex[e.Pokemon]))
+	})
+	s.bus.Subscribe(events.Explored, func(e events.Event) {
+		s.reportHookError(s.hooks.Explore(s.hookContext(), e.Area, e.Encounters))
+	})
+}
+
+func (s *session) hookContext() hooks.Context {
+	return hooks.Context{Out: s.out, Pokedex: s.profile.Pokedex}
+}
+
+func (s *session) reportHookError(err error) {
+	if err != nil {
+		fmt.Fprintln(s.out, "Hook error:", err)
+	}
+}
+
+// ask prints question and waits for the player's answer. ok is false when
+// the front end can't take an answer.
+func (s *session) ask(question string) (answer string, ok bool) {
+	if s.input == nil {
+		return "", false
+	}
+	fmt.Fprint(s.out, question)
+	line, ok := s.input()
+	return strings.TrimSpace(strings.ToLower(line)), ok
+}
+
+// resetState drops all game progress. Configuration and caches are kept.
+func (s *session) resetState() {
+	s.next = ""
+	s.previous = ""
+	s.encounter = nil
+	s.battle = nil
+	s.challenge = nil
+	s.tournament = nil
+	s.profile = profile.New(s.profile.Name)
+}
+
+// save writes the profile if it changed since it was last written.
+func (s *session) save() error {
+	if s.store == nil || s.readOnly {
+		return nil
+	}
+	data, err := json.Marshal(s.profile)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, s.saved) {
+		return nil
+	}
+	if err := s.store.Save(s.profile); err != nil {
+		return fmt.Errorf("failed to save progress: %w", err)
+	}
+	s.saved = data
+	if err := autoSnapshot(s); err != nil {
+		return fmt.Errorf("failed to snapshot progress: %w", err)
+	}
+	return nil
+}
+
+// prompt is what the REPL shows before reading a command.
+func (s *session) prompt() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.promptFormat.Render(prompt.Data{
+		Profile:      s.profile.Name,
+		Area:         s.profile.Location,
+		PartySize:    len(s.profile.Party),
+		Money:        s.profile.Money,
+		Steps:        s.profile.Steps,
+		TimeOfDay:    prompt.TimeOfDay(s.now()),
+		Combo:        s.profile.Combo,
+		ComboSpecies: s.profile.ComboSpecies,
+	})
+	if s.app.plain || s.a11y {
+		return console.Strip(p)
+	}
+	return theme.Paint(s.theme.Prompt, p)
+}
+
+// run executes one line of input, writing any output to out.
+func (s *session) run(line string, out io.Writer) error {
+	words := cleanInput(line)
+	if len(words) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out, s.line = out, line
+	defer func() { s.out, s.line = io.Discard, "" }()
+	return s.exec(words)
+}
+
+// verbatim is arg as it was typed, before the line was lowercased, for
+// arguments such as file paths where case matters. Front ends that run
+// commands without a line get arg back as it is.
+func (s *session) verbatim(arg string) string {
+	for _, word := range strings.Fields(s.line) {
+		if strings.ToLower(word) == arg {
+			return word
+		}
+	}
+	return arg
+}
+
+// exec runs a command line split into words, for run and for commands that
+// run others. The caller holds s.mu.
+func (s *session) exec(words []string) error {
+	cmd, ok := commands[words[0]]
+	if !ok {
+		fmt.Fprintln(s.out, "Unknown command:", words[0])
+		return nil
+	}
+	if s.readOnly && !guestCommands[cmd.name] {
+		fmt.Fprintf(s.out, "%s is disabled in read-only mode\n", cmd.name)
+		return nil
+	}
+	switch st := s.state(); {
+	case st.allows(cmd.name):
+	case st == stateBattle:
+		fmt.Fprintln(s.out, "You're in a battle! Use fight, switch or forfeit.")
+		return nil
+	default:
+		fmt.Fprintf(s.out, "A wild %s is in your way! Use catch, battle, bait or run.\n", s.encounter.species.Name)
+		return nil
+	}
+	if err := cmd.checkArgs(words[1:]); err != nil {
+		s.app.log.Error("command failed", "session", s.id, "command", cmd.name, "args", logArgs(words[1:]), "error", err.Error())
+		return err
+	}
+	s.profile.Commands++
+
+	// Each command gets a trace of its own, put back when it's done, as
+	// commands can run others.
+	trace, source := s.trace, s.source
+	s.trace = pokeapi.NewTrace()
+	if tracer, ok := s.source.(pokeapi.Tracer); ok {
+		s.source = tracer.WithTrace(s.trace)
+	}
+	defer func() { s.trace, s.source = trace, source }()
+	start := time.Now()
+
+	var err error
+	if cmd.paged && s.paging() {
+		err = s.capture(func() error { return cmd.callback(s, words[1:]...) })
+	} else {
+		err = cmd.callback(s, words[1:]...)
+	}
+	if s.timing {
+		fetches, cached := s.trace.Counts()
+		fmt.Fprintf(s.out, "fetched %d resources, %d from cache, %s total (trace %s)\n", fetches, cached, time.Since(start).Round(time.Millisecond), s.trace.ID)
+	}
+	err = errors.Join(err, s.save())
+	attrs := []any{"session", s.id, "command", cmd.name, "args", logArgs(words[1:]), "trace", s.trace.ID, "ms", time.Since(start).Milliseconds()}
+	if err != nil && !errors.Is(err, errExit) {
+		s.app.log.Error("command failed", append(attrs, "error", err.Error())...)
+	} else {
+		s.app.log.Info("command", attrs...)
+	}
+	return err
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// fakeSource is a tiny Kanto: pallet-town, route-1 and viridian-city, in
+// that order, with one area each.
+type fakeSource struct{}
+
+var fakeLocations = []string{"pallet-town", "route-1", "viridian-city"}
+
+func (fakeSource) LocationAreas(page string) (pokeapi.LocationResponse, error) {
+	return pokeapi.LocationResponse{Locations: []pokeapi.Location{{Name: "pallet-town-area"}}}, nil
+}
+
+// fakeEncounters are the wild pokemon of the only areas that have any: a
+// tunnel to train in and a route with water.
+var fakeEncounters = map[string][]pokeapi.PokemonEncounter{
+	"rock-tunnel-1f": {{Pokemon: pokeapi.Pokemon{Name: "zubat"}}, {Pokemon: pokeapi.Pokemon{Name: "machop"}}, {Pokemon: pokeapi.Pokemon{Name: "geodude"}}},
+	"route-12-area": {
+		fakeEncounter("magikarp", "old-rod", 5),
+		fakeEncounter("poliwag", "good-rod", 15),
+		fakeEncounter("tentacool", "surf", 25),
+		fakeEncounter("snorlax", "walk", 30),
+	},
+}
+
+func fakeEncounter(pokemon, method string, level int) pokeapi.PokemonEncounter {
+	return pokeapi.PokemonEncounter{
+		Pokemon: pokeapi.Pokemon{Name: pokemon},
+		VersionDetails: []pokeapi.VersionEncounterDetail{{
+			Version:   pokeapi.Version{Name: "firered"},
+			MaxChance: 100,
+			EncounterDetails: []pokeapi.EncounterDetail{
+				{MinLevel: level, MaxLevel: level, Chance: 100, Method: pokeapi.EncounterMethod{Name: method}},
+			},
+		}},
+	}
+}
+
+func (fakeSource) LocationArea(name string) (pokeapi.LocationDetailsResponse, error) {
+	location, _ := strings.CutSuffix(name, "-area")
+	return pokeapi.LocationDetailsResponse{Name: name, Location: pokeapi.Location{Name: location}, PokemonEncounters: fakeEncounters[name]}, nil
+}
+
+func (fakeSource) Location(name string) (pokeapi.LocationDetail, error) {
+	if !slices.Contains(fakeLocations, name) {
+		return pokeapi.LocationDetail{}, pokeapi.ErrNotFound
+	}
+	return pokeapi.LocationDetail{
+		Name:   name,
+		Region: pokeapi.Region{Name: "kanto"},
+		Areas:  []pokeapi.Location{{Name: name + "-area"}},
+	}, nil
+}
+
+func (fakeSource) Region(name string) (pokeapi.RegionDetail, error) {
+	if name != "kanto" {
+		return pokeapi.RegionDetail{}, pokeapi.ErrNotFound
+	}
+	region := pokeapi.RegionDetail{Name: name}
+	for _, location := range fakeLocations {
+		region.Locations = append(region.Locations, pokeapi.Location{Name: location})
+	}
+	return region, nil
+}
+
+// fakeYields are the EVs a few pokemon give.
+var fakeYields = map[string]pokeapi.StatDetail{
+	"zubat":   {Effort: 1, Stat: pokeapi.Stat{Name: "speed"}},
+	"machop":  {Effort: 1, Stat: pokeapi.Stat{Name: "attack"}},
+	"geodude": {Effort: 1, Stat: pokeapi.Stat{Name: "defense"}},
+}
+
+// Pokemon knows every pokemon but missingno; those in their Alolan form are of the species
+// before the suffix.
+func (fakeSource) Pokemon(name string) (pokeapi.PokemonType, error) {
+	if name == "missingno" {
+		return pokeapi.PokemonType{}, pokeapi.ErrNotFound
+	}
+	species, _ := strings.CutSuffix(name, "-alola")
+	p := pokeapi.PokemonType{Name: name, Species: pokeapi.Species{Name: species}, BaseExperience: 1}
+	if yield, ok := fakeYields[name]; ok {
+		p.Stats = []pokeapi.StatDetail{yield}
+	}
+	return p, nil
+}
+
+func newTestSession(t *testing.T) *session {
+	t.Helper()
+	s, err := newApp(fakeSource{}, &hooks.Runner{}).session("local")
+	if err != nil {
+		t.Fatalf("session() returned error: %v", err)
+	}
+	return s
+}
+
+// fakeBreeding are the egg groups and gender rates of a few species; every
+// other species is in the field group and can be either gender.
+var fakeBreeding = map[string]struct {
+	groups     []string
+	genderRate int
+}{
+	"ditto":     {[]string{"ditto"}, -1},
+	"magnemite": {[]string{"mineral"}, -1},
+	"mewtwo":    {[]string{"no-eggs"}, -1},
+	"tauros":    {[]string{"field"}, 0},
+	"miltank":   {[]string{"field"}, 8},
+	"pidgey":    {[]string{"flying"}, 4},
+}
+
+// fakeDexNumbers are the national pokedex numbers of a few species.
+var fakeDexNumbers = map[string]int{"bulbasaur": 1, "pikachu": 25, "raichu": 26, "chikorita": 152}
+
+func (fakeSource) Species(name string) (pokeapi.PokemonSpecies, error) {
+	species := pokeapi.PokemonSpecies{ID: fakeDexNumbers[name], Name: name, CaptureRate: 255, GenderRate: 4, EggGroups: []pokeapi.EggGroup{{Name: "field"}}}
+	if name == "magikarp" {
+		species.GrowthRate.Name = "slow"
+	}
+	if name == "zubat" {
+		species.Habitat.Name = "cave"
+	}
+	if breeding, ok := fakeBreeding[name]; ok {
+		species.GenderRate, species.EggGroups = breeding.genderRate, nil
+		for _, group := range breeding.groups {
+			species.EggGroups = append(species.EggGroups, pokeapi.EggGroup{Name: group})
+		}
+	}
+	return species, nil
+}
+
+// Nature knows adamant and modest; every other nature is neutral.
+func (fakeSource) Nature(name string) (pokeapi.NatureDetail, error) {
+	n := pokeapi.NatureDetail{Name: name}
+	switch name {
+	case "adamant":
+		n.IncreasedStat, n.DecreasedStat = pokeapi.Stat{Name: "attack"}, pokeapi.Stat{Name: "special-attack"}
+		n.LikesFlavor, n.HatesFlavor = pokeapi.BerryFlavor{Name: "spicy"}, pokeapi.BerryFlavor{Name: "dry"}
+	case "modest":
+		n.IncreasedStat, n.DecreasedStat = pokeapi.Stat{Name: "special-attack"}, pokeapi.Stat{Name: "attack"}
+		n.LikesFlavor, n.HatesFlavor = pokeapi.BerryFlavor{Name: "dry"}, pokeapi.BerryFlavor{Name: "spicy"}
+	}
+	return n, nil
+}
+
+func (fakeSource) EggGroup(name string) (pokeapi.EggGroupDetail, error) {
+	if name != "field" {
+		return pokeapi.EggGroupDetail{}, pokeapi.ErrNotFound
+	}
+	return pokeapi.EggGroupDetail{Name: name, PokemonSpecies: []pokeapi.Species{{Name: "pikachu"}, {Name: "tauros"}, {Name: "miltank"}}}, nil
+}
+
+func (fakeSource) Habitat(name string) (pokeapi.HabitatDetail, error) {
+	if name != "cave" {
+		return pokeapi.HabitatDetail{}, pokeapi.ErrNotFound
+	}
+	return pokeapi.HabitatDetail{Name: name, PokemonSpecies: []pokeapi.Species{{Name: "zubat"}, {Name: "golbat"}, {Name: "diglett"}}}, nil
+}
+
+func (fakeSource) Shape(name string) (pokeapi.ShapeDetail, error) {
+	if name != "quadruped" {
+		return pokeapi.ShapeDetail{}, pokeapi.ErrNotFound
+	}
+	return pokeapi.ShapeDetail{Name: name, PokemonSpecies: []pokeapi.Species{{Name: "vulpix"}, {Name: "growlithe"}}}, nil
+}
+
+// fakeMachines are TM24 and HM03 as FireRed and LeafGreen have them, plus
+// TM24 from Red and Blue.
+var fakeMachines = map[int]pokeapi.MachineDetail{
+	1: {ID: 1, Item: pokeapi.Item{Name: "tm24"}, Move: pokeapi.Move{Name: "thunderbolt"}, VersionGroup: pokeapi.VersionGroup{Name: "red-blue"}},
+	2: {ID: 2, Item: pokeapi.Item{Name: "tm24"}, Move: pokeapi.Move{Name: "thunderbolt"}, VersionGroup: pokeapi.VersionGroup{Name: "firered-leafgreen"}},
+	3: {ID: 3, Item: pokeapi.Item{Name: "hm03"}, Move: pokeapi.Move{Name: "surf"}, VersionGroup: pokeapi.VersionGroup{Name: "firered-leafgreen"}},
+}
+
+func fakeMachineVersions(ids ...int) []pokeapi.MachineVersion {
+	versions := []pokeapi.MachineVersion{}
+	for _, id := range ids {
+		versions = append(versions, pokeapi.MachineVersion{
+			Machine:      pokeapi.MachineReference{Url: fmt.Sprintf("https://pokeapi.co/api/v2/machine/%d/", id)},
+			VersionGroup: fakeMachines[id].VersionGroup,
+		})
+	}
+	return versions
+}
+
+// PokemonMoves has pikachu learn thunderbolt from a TM; nothing else learns
+// anything from a machine.
+func (fakeSource) PokemonMoves(name string) ([]pokeapi.PokemonMove, error) {
+	moves := []pokeapi.PokemonMove{{
+		Move: pokeapi.Move{Name: "tackle"},
+		VersionGroupDetails: []pokeapi.MoveVersionDetail{
+			{LevelLearnedAt: 1, MoveLearnMethod: pokeapi.MoveLearnMethod{Name: "level-up"}},
+		},
+	}}
+	if name == "pikachu" {
+		moves = append(moves, pokeapi.PokemonMove{
+			Move: pokeapi.Move{Name: "thunderbolt"},
+			VersionGroupDetails: []pokeapi.MoveVersionDetail{
+				{MoveLearnMethod: pokeapi.MoveLearnMethod{Name: "machine"}, VersionGroup: pokeapi.VersionGroup{Name: "firered-leafgreen"}},
+				{LevelLearnedAt: 26, MoveLearnMethod: pokeapi.MoveLearnMethod{Name: "level-up"}, VersionGroup: pokeapi.VersionGroup{Name: "red-blue", Url: "version-group/1/"}},
+				{LevelLearnedAt: 12, MoveLearnMethod: pokeapi.MoveLearnMethod{Name: "level-up"}, VersionGroup: pokeapi.VersionGroup{Name: "sword-shield", Url: "version-group/20/"}},
+			},
+		})
+	}
+	return moves, nil
+}
+
+func (fakeSource) Move(name string) (pokeapi.MoveDetail, error) {
+	switch name {
+	case "thunderbolt":
+		return pokeapi.MoveDetail{
+			Name: name, Power: 90, Accuracy: 100, Type: pokeapi.Type{Name: "electric"},
+			DamageClass: pokeapi.MoveDamageClass{Name: "special"},
+			Target:      pokeapi.MoveTarget{Name: "selected-pokemon"},
+			Machines:    fakeMachineVersions(1, 2),
+		}, nil
+	case "surf":
+		return pokeapi.MoveDetail{
+			Name: name, Power: 90, Accuracy: 100, Type: pokeapi.Type{Name: "water"},
+			DamageClass: pokeapi.MoveDamageClass{Name: "special"},
+			Target:      pokeapi.MoveTarget{Name: "all-other-pokemon"},
+			Machines:    fakeMachineVersions(3),
+		}, nil
+	}
+	return pokeapi.MoveDetail{}, pokeapi.ErrNotFound
+}
+
+func (fakeSource) Item(name string) (pokeapi.ItemDetail, error) {
+	switch name {
+	case "tm24":
+		return pokeapi.ItemDetail{Name: name, Cost: 3000, Machines: fakeMachineVersions(1, 2)}, nil
+	case "hm03":
+		return pokeapi.ItemDetail{Name: name, Machines: fakeMachineVersions(3)}, nil
+	}
+	if cost, ok := fakeItemCosts[name]; ok {
+		return pokeapi.ItemDetail{Name: name, Cost: cost}, nil
+	}
+	return pokeapi.ItemDetail{}, pokeapi.ErrNotFound
+}
+
+// fakeItemCosts are the prices of the items the fake source knows. Oran
+// Berries have none, so the shop can't sell them.
+var fakeItemCosts = map[string]int{
+	"poke-ball":   200,
+	"great-ball":  600,
+	"ultra-ball":  800,
+	"potion":      200,
+	"revive":      2000,
+	"protein":     10000,
+	"carbos":      10000,
+	"cheri-berry": 80,
+	"oran-berry":  0,
+}
+
+func (fakeSource) Machine(id int) (pokeapi.MachineDetail, error) {
+	m, ok := fakeMachines[id]
+	if !ok {
+		return pokeapi.MachineDetail{}, pokeapi.ErrNotFound
+	}
+	return m, nil
+}
+
+func TestSessionsAreIsolated(t *testing.T) {
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, _ := a.session(fmt.Sprintf("user-%d", i%2))
+			for range 50 {
+				s.run("catch pikachu", &bytes.Buffer{})
+				s.run("map", &bytes.Buffer{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(a.sessions) != 2 {
+		t.Errorf("Expected 2 sessions, got %d", len(a.sessions))
+	}
+	s0, _ := a.session("user-0")
+	s1, _ := a.session("user-1")
+	if s0 == s1 {
+		t.Errorf("Expected distinct sessions per id")
+	}
+}
+
+func TestSessionExit(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.run("exit", &bytes.Buffer{}); !errors.Is(err, errExit) {
+		t.Errorf("Expected errExit, got %v", err)
+	}
+}
+
+func TestResetAsksForConfirmation(t *testing.T) {
+	s := newTestSession(t)
+	s.profile.Add(pokeapi.PokemonType{Name: "pikachu"}, 5)
+
+	answers := []string{"no", "yes"}
+	s.input = func() (string, bool) {
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, true
+	}
+
+	s.run("reset", &bytes.Buffer{})
+	if len(s.profile.Pokedex) != 1 {
+		t.Errorf("Expected reset to be cancelled")
+	}
+	s.run("reset", &bytes.Buffer{})
+	if len(s.profile.Pokedex) != 0 {
+		t.Errorf("Expected pokedex to be empty after reset, got %d", len(s.profile.Pokedex))
+	}
+}
+
+func TestTimingFooter(t *testing.T) {
+	s := newTestSession(t)
+	out := &bytes.Buffer{}
+	s.run("version", out)
+	if strings.Contains(out.String(), "fetched") {
+		t.Errorf("Expected no timing without the setting, got %q", out.String())
+	}
+
+	s.timing = true
+	out.Reset()
+	s.run("version", out)
+	footer := regexp.MustCompile(`fetched 0 resources, 0 from cache, \w+ total \(trace [0-9a-f]{16}\)\n$`)
+	if !footer.MatchString(out.String()) {
+		t.Errorf("Expected a timing footer, got %q", out.String())
+	}
+	if s.trace != nil {
+		t.Errorf("Expected the trace to end with the command")
+	}
+}
+
+func TestProfileLocked(t *testing.T) {
+	dir := t.TempDir()
+	first := newApp(fakeSource{}, &hooks.Runner{})
+	first.store = profile.NewStore(dir)
+	if _, err := first.session("ash"); err != nil {
+		t.Fatalf("session() returned error: %v", err)
+	}
+
+	second := newApp(fakeSource{}, &hooks.Runner{})
+	second.store = profile.NewStore(dir)
+	if _, err := second.session("ash"); !errors.Is(err, profile.ErrLocked) {
+		t.Errorf("Expected a profile open elsewhere to be refused, got %v", err)
+	}
+	second.readOnly = true
+	if _, err := second.session("ash"); err != nil {
+		t.Errorf("Expected a read-only session to open it, got %v", err)
+	}
+}
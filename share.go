@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"compress/flate"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/azs06/pokedexcli/internal/profile"
+)
+
+// Share codes are a version byte, a signature of shareMACSize bytes and
+// the pokemon as deflated JSON, in base32: commands are read lowercased,
+// so codes can't depend on case the way base64 does.
+const (
+	shareVersion = 1
+	shareMACSize = 12
+	// maxShared is as large as a pokemon's JSON may inflate to.
+	maxShared = 64 << 10
+)
+
+// defaultShareKey signs share codes for players who haven't set a key of
+// their own. It's in the source for anyone to read, so it catches codes
+// that were mistyped or edited by hand, not a determined forger.
+const defaultShareKey = "pokedexcli share codes"
+
+var shareEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errNotShareCode = errors.New("that isn't a share code")
+
+// shareKey is the key share codes are signed with.
+func shareKey(s *session) []byte {
+	s.app.mu.Lock()
+	defer s.app.mu.Unlock()
+	return []byte(cmp.Or(s.app.config.ShareKey, defaultShareKey))
+}
+
+func shareMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)[:shareMACSize]
+}
+
+// encodeShare makes a share code for mon, leaving out its ID, which is
+// only its number in this profile.
+func encodeShare(key []byte, mon profile.Pokemon) (string, error) {
+	mon.ID = 0
+	data, err := json.Marshal(mon)
+	if err != nil {
+		return "", err
+	}
+	var deflated bytes.Buffer
+	w, err := flate.NewWriter(&deflated, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	body := append([]byte{shareVersion}, deflated.Bytes()...)
+	code := append([]byte{shareVersion}, shareMAC(key, body)...)
+	code = append(code, body[1:]...)
+	return strings.ToLower(shareEncoding.EncodeToString(code)), nil
+}
+
+// decodeShare reads the pokemon in a share code, refusing codes changed
+// since they were signed and pokemon that couldn't exist.
+func decodeShare(key []byte, code string) (profile.Pokemon, error) {
+	var mon profile.Pokemon
+	raw, err := shareEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil || len(raw) <= 1+shareMACSize {
+		return mon, errNotShareCode
+	}
+	if raw[0] != shareVersion {
+		return mon, fmt.Errorf("the share code is version %d; this pokedexcli reads version %d", raw[0], shareVersion)
+	}
+	mac, deflated := raw[1:1+shareMACSize], raw[1+shareMACSize:]
+	if !hmac.Equal(mac, shareMAC(key, append([]byte{shareVersion}, deflated...))) {
+		return mon, errors.New("the share code has been changed since it was made, or was signed with another key")
+	}
+	data, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(deflated)), maxShared))
+	if err != nil {
+		return mon, errNotShareCode
+	}
+	if err := json.Unmarshal(data, &mon); err != nil {
+		return mon, errNotShareCode
+	}
+	if err := mon.Validate(); err != nil {
+		return mon, fmt.Errorf("the shared %s can't be imported: %w", mon.Species, err)
+	}
+	return mon, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/azs06/pokedexcli/internal/slack"
+)
+
+// slackCommands are the commands Slack users can run: playing their own
+// game, but nothing that reads or writes the host's files, changes its
+// config or starts servers.
+var slackCommands = map[string]bool{
+	"help":       true,
+	"map":        true,
+	"mapb":       true,
+	"whereami":   true,
+	"travel":     true,
+	"explore":    true,
+	"catch":      true,
+	"throw":      true,
+	"bait":       true,
+	"run":        true,
+	"fish":       true,
+	"surf":       true,
+	"track":      true,
+	"starter":    true,
+	"inspect":    true,
+	"pokedex":    true,
+	"party":      true,
+	"bag":        true,
+	"team":       true,
+	"shop":       true,
+	"buy":        true,
+	"heal":       true,
+	"use":        true,
+	"hold":       true,
+	"take":       true,
+	"release":    true,
+	"train":      true,
+	"ev":         true,
+	"nature":     true,
+	"quests":     true,
+	"elitefour":  true,
+	"tournament": true,
+	"fight":      true,
+	"switch":     true,
+	"forfeit":    true,
+	"halloffame": true,
+	"battles":    true,
+	"search":     true,
+	"habitat":    true,
+}
+
+// slackHelp lists the commands Slack users can run.
+func slackHelp() string {
+	var b strings.Builder
+	b.WriteString("Usage: /pokedex <command>\n")
+	for _, cmd := range sortedCommands() {
+		if slackCommands[cmd.name] && cmd.name != "help" && !cmd.hidden {
+			fmt.Fprintf(&b, "%s: %s\n", cmd.usageLine(), cmd.description)
+		}
+	}
+	return b.String()
+}
+
+// serveSlack answers Slack slash commands such as /pokedex catch pikachu.
+// Every Slack user plays their own profile.
+func serveSlack(s *session, addr string) error {
+	secret := s.app.slack.SigningSecret
+	if secret == "" {
+		return errors.New("set slack.signing_secret in the config, or SLACK_SIGNING_SECRET, to answer Slack")
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", slack.NewHandler(secret, slackCommand(s.app)))
+	mux.Handle("/events", eventsHandler(s.app.bus))
+	fmt.Fprintf(s.out, "Slack server listening on %s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// slackCommand runs slash commands in the session of the Slack user who
+// sent them. Output is posted to the channel; errors only to the user.
+func slackCommand(a *app) func(slack.Command) slack.Response {
+	var mu sync.Mutex
+	started := map[string]bool{}
+	return func(c slack.Command) slack.Response {
+		line := cmp.Or(strings.TrimSpace(c.Text), "help")
+		name := cleanInput(line)[0]
+		if name == "help" {
+			return slack.Response{ResponseType: "ephemeral", Text: slackHelp(), Blocks: slack.Code(slackHelp())}
+		}
+		if !slackCommands[name] {
+			text := fmt.Sprintf("There's no /pokedex %s here.\n%s", name, slackHelp())
+			return slack.Response{ResponseType: "ephemeral", Text: text, Blocks: slack.Code(text)}
+		}
+		s, err := a.session(strings.ToLower("slack-" + c.Team + "-" + c.User))
+		if err != nil {
+			return slack.Response{ResponseType: "ephemeral", Text: "Error: " + err.Error()}
+		}
+		out := &bytes.Buffer{}
+		mu.Lock()
+		first := !started[s.id]
+		started[s.id] = true
+		mu.Unlock()
+		if first {
+			s.start(out)
+		}
+
+		if err := s.run(line, out); err != nil && !errors.Is(err, errExit) {
+			fmt.Fprintln(out, "Error:", err)
+			return slack.Response{ResponseType: "ephemeral", Text: out.String(), Blocks: slack.Code(out.String())}
+		}
+		s.mu.Lock()
+		status := fmt.Sprintf("%s · party %d/6 · ₽%d", cmp.Or(s.profile.Location, "not travelling yet"), len(s.profile.Party), s.profile.Money)
+		s.mu.Unlock()
+
+		blocks := slack.Code(out.String())
+		if len(blocks) == 0 {
+			blocks = []slack.Block{slack.Section("Done.")}
+		}
+		return slack.Response{
+			ResponseType: "in_channel",
+			Text:         out.String(),
+			Blocks:       append(blocks, slack.Context("*"+slack.Escape(cmp.Or(c.UserName, c.User))+"*", slack.Escape(status))),
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azs06/pokedexcli/internal/hooks"
+	"github.com/azs06/pokedexcli/internal/slack"
+)
+
+func TestSlackCommand(t *testing.T) {
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	run := slackCommand(a)
+
+	res := run(slack.Command{Team: "T1", User: "U1", UserName: "ash", Text: "starter bulbasaur"})
+	if res.ResponseType != "in_channel" || !strings.Contains(res.Text, "bulbasaur") {
+		t.Errorf("Expected the starter to be picked in the channel, got %+v", res)
+	}
+	if last := res.Blocks[len(res.Blocks)-1]; last.Type != "context" || last.Elements[0].Text != "*ash*" {
+		t.Errorf("Expected the output to end with ash's status, got %+v", last)
+	}
+
+	res = run(slack.Command{Team: "T1", User: "U2", UserName: "misty", Text: "party"})
+	if res.ResponseType != "in_channel" || strings.Contains(res.Text, "bulbasaur") {
+		t.Errorf("Expected each Slack user to have their own profile, got %+v", res)
+	}
+	res = run(slack.Command{Team: "T1", User: "U1", Text: "starter charmander"})
+	if res.ResponseType != "ephemeral" || !strings.Contains(res.Text, "Error:") {
+		t.Errorf("Expected an error to be shown only to ash, got %+v", res)
+	}
+	if len(a.sessions) != 2 {
+		t.Errorf("Expected a session per Slack user, got %d", len(a.sessions))
+	}
+}
+
+func TestSlackCommandAllowlist(t *testing.T) {
+	a := newApp(fakeSource{}, &hooks.Runner{})
+	run := slackCommand(a)
+
+	for _, text := range []string{"config api http://evil.example/", "server --mode grpc", "self-update", "debug pprof on"} {
+		res := run(slack.Command{Team: "T1", User: "U1", Text: text})
+		if res.ResponseType != "ephemeral" || !strings.Contains(res.Text, "There's no /pokedex") {
+			t.Errorf("Expected %q to be refused, got %+v", text, res)
+		}
+	}
+	if a.config.API != "" || len(a.sessions) != 0 {
+		t.Errorf("Expected nothing to run, got API %q and %d sessions", a.config.API, len(a.sessions))
+	}
+	res := run(slack.Command{Team: "T1", User: "U1"})
+	if !strings.Contains(res.Text, "catch [pokemon]") || strings.Contains(res.Text, "config") {
+		t.Errorf("Expected help to list only the commands Slack can run, got %q", res.Text)
+	}
+}
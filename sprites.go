@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/azs06/pokedexcli/internal/pokeapi"
+	"github.com/azs06/pokedexcli/internal/pokecache"
+	"github.com/azs06/pokedexcli/internal/sprite"
+)
+
+// drawSprite draws p on the REPL's terminal as the sprites setting says.
+// Output going anywhere else, accessibility mode, and sprites that can't
+// be had draw nothing, since a picture is never all a command has to say.
+func drawSprite(s *session, p pokeapi.PokemonType, shiny bool) {
+	if s.tty == nil || s.out != s.tty || s.a11y || s.sprites == sprite.None || s.app.sprites == nil || s.app.cache == nil {
+		return
+	}
+	url := p.Sprites.FrontDefault
+	if shiny && p.Sprites.FrontShiny != "" {
+		url = p.Sprites.FrontShiny
+	}
+	if url == "" {
+		return
+	}
+	// Sprites seen lately are kept in memory as well as on disk.
+	data, ok := s.app.cache.GetFrom(pokecache.Sprites, url)
+	if !ok {
+		var err error
+		if data, err = s.app.sprites.Get(url); err != nil {
+			return
+		}
+		s.app.cache.AddTo(pokecache.Sprites, url, data)
+	}
+	sprite.Draw(s.out, s.sprites, data)
+}